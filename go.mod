@@ -0,0 +1,19 @@
+module github.com/kasiyo/4600-project1
+
+go 1.18
+
+require (
+	github.com/mattn/go-sqlite3 v1.14.16
+	github.com/olekukonko/tablewriter v0.0.5
+	google.golang.org/grpc v1.56.3
+	google.golang.org/protobuf v1.31.0
+)
+
+require (
+	github.com/golang/protobuf v1.5.3 // indirect
+	github.com/mattn/go-runewidth v0.0.9 // indirect
+	golang.org/x/net v0.17.0 // indirect
+	golang.org/x/sys v0.13.0 // indirect
+	golang.org/x/text v0.13.0 // indirect
+	google.golang.org/genproto v0.0.0-20230410155749-daa745c078e1 // indirect
+)
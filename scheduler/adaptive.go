@@ -0,0 +1,89 @@
+package scheduler
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+func init() {
+	Register("adaptive", Factory{
+		Title:       "Adaptive round-robin",
+		Description: "round-robin whose quantum each round is the median CPU time left of the ready processes",
+		New:         func([]Process, AlgorithmOptions) Policy { return newAdaptiveRRPolicy() },
+	})
+}
+
+// adaptiveRRPolicy is round-robin that picks its quantum afresh each round,
+// a round giving every process ready when it starts one turn. The quantum
+// is the median of their remaining CPU bursts, so about half of them finish
+// within a turn while long ones still take turns, without a quantum tuned
+// in advance to the workload.
+type adaptiveRRPolicy struct {
+	quantum int64
+	// due holds the processes still to have their turn this round, and
+	// slice the quantum each running process was dispatched with.
+	due    map[int64]bool
+	slice  map[int64]int64
+	rounds []adaptiveRound
+}
+
+// adaptiveRound is the quantum chosen for a round starting at Time with
+// Ready processes.
+type adaptiveRound struct {
+	Time    int64
+	Quantum int64
+	Ready   int
+}
+
+func newAdaptiveRRPolicy() *adaptiveRRPolicy {
+	return &adaptiveRRPolicy{due: map[int64]bool{}, slice: map[int64]int64{}}
+}
+
+func (p *adaptiveRRPolicy) Adapt(t *Task, ready []*Task) bool {
+	round := !p.due[t.ProcessID]
+	if round {
+		// A process that was not ready when the round began starts the
+		// next one.
+		remaining := []int64{t.Remaining}
+		p.due = map[int64]bool{}
+		for _, r := range ready {
+			remaining = append(remaining, r.Remaining)
+			p.due[r.ProcessID] = true
+		}
+		sort.Slice(remaining, func(i, j int) bool { return remaining[i] < remaining[j] })
+		p.quantum = remaining[(len(remaining)-1)/2]
+		if p.quantum < 1 {
+			p.quantum = 1
+		}
+		p.rounds = append(p.rounds, adaptiveRound{Time: t.sliceStart, Quantum: p.quantum, Ready: len(remaining)})
+	}
+	delete(p.due, t.ProcessID)
+	p.slice[t.ProcessID] = p.quantum
+	return round
+}
+
+func (p *adaptiveRRPolicy) Less(a, b *Task) bool { return a.Seq < b.Seq }
+func (p *adaptiveRRPolicy) Preemptive() bool     { return false }
+func (p *adaptiveRRPolicy) Quantum() int64       { return p.quantum }
+func (p *adaptiveRRPolicy) StableOrder() bool    { return true }
+
+// TaskQuantum keeps the quantum a process was dispatched with, even if
+// another CPU starts a round while it runs.
+func (p *adaptiveRRPolicy) TaskQuantum(t *Task) int64 { return p.slice[t.ProcessID] }
+
+// Report lists the quantum of each round.
+func (p *adaptiveRRPolicy) Report(w io.Writer) {
+	if len(p.rounds) == 0 {
+		return
+	}
+	var sum int64
+	quanta := make([]string, len(p.rounds))
+	for i, r := range p.rounds {
+		sum += r.Quantum
+		quanta[i] = fmt.Sprintf("t=%d %d (%d ready)", r.Time, r.Quantum, r.Ready)
+	}
+	_, _ = fmt.Fprintf(w, "Adaptive quantum: %d rounds, mean %.2f\n  %s\n",
+		len(p.rounds), float64(sum)/float64(len(p.rounds)), strings.Join(quanta, ", "))
+}
@@ -0,0 +1,49 @@
+package scheduler
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAdaptiveRR_rounds(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 10},
+		{ProcessID: 2, BurstDuration: 4},
+		{ProcessID: 3, ArrivalTime: 1, BurstDuration: 7},
+		{ProcessID: 4, ArrivalTime: 2, BurstDuration: 2},
+	}
+	policy := newAdaptiveRRPolicy()
+	result := Simulate(processes, policy, EngineOptions{})
+	// The first round has P1 and P2 ready, so the quantum is the lower
+	// median of 10 and 4. P3 and P4 arrive during it and start the next,
+	// with P1's 6 left, and P3 is alone for the last.
+	want := []adaptiveRound{{Time: 0, Quantum: 4, Ready: 2}, {Time: 8, Quantum: 6, Ready: 3}, {Time: 22, Quantum: 1, Ready: 1}}
+	if !reflect.DeepEqual(policy.rounds, want) {
+		t.Errorf("rounds = %+v, want %+v", policy.rounds, want)
+	}
+	gantt := []TimeSlice{
+		{PID: 1, Start: 0, Stop: 4}, {PID: 2, Start: 4, Stop: 8}, {PID: 3, Start: 8, Stop: 14},
+		{PID: 4, Start: 14, Stop: 16}, {PID: 1, Start: 16, Stop: 22}, {PID: 3, Start: 22, Stop: 23},
+	}
+	if got := mergeGantt(result.Gantt); !reflect.DeepEqual(got, gantt) {
+		t.Errorf("Gantt = %v, want %v", got, gantt)
+	}
+	var w bytes.Buffer
+	policy.Report(&w)
+	if got := w.String(); !strings.Contains(got, "3 rounds, mean 3.67") || !strings.Contains(got, "t=8 6 (3 ready)") {
+		t.Errorf("Report() = %q", got)
+	}
+}
+
+func TestAdaptiveRR_logsRounds(t *testing.T) {
+	t.Parallel()
+	processes := []Process{{ProcessID: 1, BurstDuration: 3}, {ProcessID: 2, BurstDuration: 5}}
+	var log bytes.Buffer
+	Simulate(processes, newAdaptiveRRPolicy(), EngineOptions{Log: NewLogger(&log)})
+	if got, want := log.String(), "t=0 msg=round pid=1 quantum=3 ready=2\n"; !strings.Contains(got, want) {
+		t.Errorf("log = %q, want it to contain %q", got, want)
+	}
+}
@@ -0,0 +1,106 @@
+package scheduler
+
+import (
+	"fmt"
+	"io"
+)
+
+// ageWaiting raises by a level the effective priority of each process that
+// has waited another EngineOptions.Aging ticks in a ready queue, so that a
+// steady stream of better processes cannot starve it. The boost lasts until
+// the process next joins a ready queue, after it has had the CPU. Aging
+// stops once there is no better level for the process to reach.
+func (e *engine) ageWaiting() {
+	interval := e.opts.Aging
+	if interval <= 0 {
+		return
+	}
+	for _, t := range e.tasks {
+		if !t.queued {
+			continue
+		}
+		for aged := (e.now - t.readySince) / interval; t.aged < aged && e.canAge(t); {
+			t.aged++
+			before := t.EffectivePriority
+			t.EffectivePriority = e.effectivePriority(t)
+			// A level that changes nothing, under an inherited priority,
+			// is not worth an event.
+			if _, ok := e.policy.(AgeLeveler); t.EffectivePriority == before && !ok {
+				continue
+			}
+			e.recordEvent(Event{Time: e.now, Kind: EventAge, PID: t.ProcessID, CPU: t.cpu, Priority: t.EffectivePriority})
+			e.opts.Log.Log(e.now, "age", "pid", t.ProcessID, "waited", e.now-t.readySince, "priority", t.EffectivePriority)
+			e.queues[t.queue].Fix(t)
+		}
+	}
+}
+
+// nextAging is when the next waiting process ages, if any will.
+func (e *engine) nextAging() (int64, bool) {
+	var (
+		next  int64
+		found bool
+	)
+	if e.opts.Aging <= 0 {
+		return 0, false
+	}
+	for _, t := range e.tasks {
+		if !t.queued || !e.canAge(t) {
+			continue
+		}
+		if at := t.readySince + (t.aged+1)*e.opts.Aging; !found || at < next {
+			next, found = at, true
+		}
+	}
+	return next, found
+}
+
+// An AgeLeveler is a policy with levels of its own that aging climbs, such
+// as mlfq's queues, saying how many more t could climb.
+type AgeLeveler interface {
+	AgeHeadroom(t *Task) int64
+}
+
+// canAge reports whether t has a better level to age to: a better
+// effective priority than the workload's best, or a level of the policy's.
+func (e *engine) canAge(t *Task) bool {
+	if e.effectivePriority(t) > e.topPriority {
+		return true
+	}
+	l, ok := e.policy.(AgeLeveler)
+	return ok && l.AgeHeadroom(t) > 0
+}
+
+// unage drops the levels t gained by aging.
+func (e *engine) unage(t *Task) {
+	if t.aged == 0 {
+		return
+	}
+	t.aged = 0
+	t.EffectivePriority = e.effectivePriority(t)
+}
+
+// outputAging sums up aging in a line: how many processes aged, and the
+// most levels one climbed in a single wait. Each step is logged by -v.
+func outputAging(w io.Writer, events []Event) {
+	var (
+		levels = map[int64]int{}
+		aged   = map[int64]bool{}
+		most   int
+	)
+	for _, ev := range events {
+		switch ev.Kind {
+		case EventAge:
+			aged[ev.PID] = true
+			levels[ev.PID]++
+			if levels[ev.PID] > most {
+				most = levels[ev.PID]
+			}
+		case EventDispatch:
+			levels[ev.PID] = 0
+		}
+	}
+	if len(aged) > 0 {
+		_, _ = fmt.Fprintf(w, "Aged processes: %d, most levels gained in one wait: %d\n", len(aged), most)
+	}
+}
@@ -0,0 +1,89 @@
+package scheduler
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSimulate_aging(t *testing.T) {
+	t.Parallel()
+	// A stream of priority 1 processes starves P1 at priority 5 unless it
+	// ages.
+	processes := []Process{
+		NewProcess(1, 0, 2, 5),
+		NewProcess(2, 0, 3, 1),
+		NewProcess(3, 3, 3, 1),
+		NewProcess(4, 6, 3, 1),
+		NewProcess(5, 9, 3, 1),
+	}
+	tests := []struct {
+		name  string
+		aging int64
+		exit  int64
+		aged  []int64
+	}{
+		{name: "off", exit: 14},
+		// P1 gains a level every 2 ticks and, level with P4 at t=8, takes
+		// the CPU as the longer waiting.
+		{name: "every 2 ticks", aging: 2, exit: 10, aged: []int64{4, 3, 2, 1}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := Simulate(processes, priorityPolicy{}, EngineOptions{Aging: tt.aging})
+			for _, row := range got.Schedule {
+				if row.ProcessID == 1 && row.Exit != tt.exit {
+					t.Errorf("P1 exits at %d, want %d", row.Exit, tt.exit)
+				}
+			}
+			var aged []int64
+			for _, ev := range got.Events {
+				if ev.Kind == EventAge && ev.PID == 1 {
+					aged = append(aged, ev.Priority)
+				}
+			}
+			if !reflect.DeepEqual(aged, tt.aged) {
+				t.Errorf("P1 aged to %v, want %v", aged, tt.aged)
+			}
+		})
+	}
+}
+
+func Test_mlfqPolicy_aging(t *testing.T) {
+	t.Parallel()
+	p := newMLFQPolicy(MLFQOptions{}).(*mlfqPolicy)
+	for _, kind := range []EventKind{EventExpire, EventExpire, EventAge, EventAge, EventAge} {
+		p.Observe(Event{Kind: kind, PID: 1})
+	}
+	if p.level[1] != 0 {
+		t.Errorf("level after dropping 2 and aging 3 = %d, want 0", p.level[1])
+	}
+}
+
+func TestSimulate_agingStopsAtTop(t *testing.T) {
+	t.Parallel()
+	// P2 holds the CPU for 100 ticks; P1 ages a level a tick, but no
+	// further than priority 1, the best in the workload.
+	processes := []Process{
+		NewProcess(1, 1, 2, 5),
+		NewProcess(2, 0, 100, 1),
+	}
+	got := Simulate(processes, priorityPolicy{}, EngineOptions{Aging: 1})
+	var aged []int64
+	for _, ev := range got.Events {
+		if ev.Kind == EventAge {
+			aged = append(aged, ev.Priority)
+		}
+	}
+	if want := []int64{4, 3, 2, 1}; !reflect.DeepEqual(aged, want) {
+		t.Errorf("P1 aged to %v, want %v", aged, want)
+	}
+
+	var w strings.Builder
+	outputAging(&w, got.Events)
+	if want := "Aged processes: 1, most levels gained in one wait: 4\n"; w.String() != want {
+		t.Errorf("outputAging() = %q, want %q", w.String(), want)
+	}
+}
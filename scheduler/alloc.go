@@ -0,0 +1,462 @@
+package scheduler
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+var ErrInvalidAllocation = errors.New("invalid allocation")
+
+type (
+	// AllocRequest asks for Size units of memory for Name, or, if Free is
+	// set, gives back what Name holds.
+	AllocRequest struct {
+		Name string
+		Size int64
+		Free bool
+	}
+	// AllocOptions describe the memory, Memory units long and empty at the
+	// start. Compact slides every block down to the bottom when a request
+	// fails for want of a hole big enough, though enough is free in all,
+	// and tries again.
+	AllocOptions struct {
+		Memory  int64
+		Compact bool
+	}
+	// Block is Size units of memory from Start, held by Name or, if Name is
+	// empty, free.
+	Block struct {
+		Name  string
+		Start int64
+		Size  int64
+	}
+	// AllocStep is the memory after a request: where it was placed, if it
+	// was an allocation that succeeded, and whether compacting it first
+	// moved Moved units. Largest is the largest hole, and Fragmentation
+	// the share of the free memory outside it.
+	AllocStep struct {
+		Request       AllocRequest
+		Start         int64
+		Failed        bool
+		Compacted     bool
+		Moved         int64
+		Blocks        []Block
+		Free          int64
+		Holes         int
+		Largest       int64
+		Fragmentation float64
+	}
+	// AllocResult is one fit's run over the requests.
+	AllocResult struct {
+		Algorithm            string
+		Steps                []AllocStep
+		Failed               int
+		Compactions          int
+		Moved                int64
+		AverageFragmentation float64
+		PeakFragmentation    float64
+	}
+)
+
+// A fitPolicy picks the hole to place size units in, from memory's blocks
+// in address order, returning its index or -1 if none will do.
+type fitPolicy func(blocks []Block, size int64) int
+
+// fitAlgorithms are the placement algorithms, in the order they are
+// compared.
+var fitAlgorithms = []struct {
+	name, title string
+	policy      fitPolicy
+}{
+	{"first", "First fit", firstFit},
+	{"best", "Best fit", bestFit},
+	{"worst", "Worst fit", worstFit},
+}
+
+// firstFit takes the first hole big enough.
+func firstFit(blocks []Block, size int64) int {
+	for i, b := range blocks {
+		if b.Name == "" && b.Size >= size {
+			return i
+		}
+	}
+	return -1
+}
+
+// bestFit takes the smallest hole big enough, leaving the smallest hole
+// behind.
+func bestFit(blocks []Block, size int64) int {
+	best := -1
+	for i, b := range blocks {
+		if b.Name == "" && b.Size >= size && (best < 0 || b.Size < blocks[best].Size) {
+			best = i
+		}
+	}
+	return best
+}
+
+// worstFit takes the largest hole, leaving the largest hole behind.
+func worstFit(blocks []Block, size int64) int {
+	worst := -1
+	for i, b := range blocks {
+		if b.Name == "" && b.Size >= size && (worst < 0 || b.Size > blocks[worst].Size) {
+			worst = i
+		}
+	}
+	return worst
+}
+
+// SimulateAllocation runs policy over requests, which must have passed
+// checkAllocations.
+func SimulateAllocation(requests []AllocRequest, policy fitPolicy, opts AllocOptions) AllocResult {
+	var result AllocResult
+	blocks := []Block{{Start: 0, Size: opts.Memory}}
+	for _, req := range requests {
+		step := AllocStep{Request: req, Start: -1}
+		if req.Free {
+			blocks = freeBlock(blocks, req.Name)
+		} else {
+			i := policy(blocks, req.Size)
+			if i < 0 && opts.Compact && freeMemory(blocks) >= req.Size {
+				blocks, step.Moved = compact(blocks, opts.Memory)
+				step.Compacted = true
+				result.Compactions++
+				result.Moved += step.Moved
+				i = policy(blocks, req.Size)
+			}
+			if i < 0 {
+				step.Failed = true
+				result.Failed++
+			} else {
+				step.Start = blocks[i].Start
+				blocks = place(blocks, i, req)
+			}
+		}
+		step.Blocks = append([]Block(nil), blocks...)
+		for _, b := range blocks {
+			if b.Name == "" {
+				step.Free += b.Size
+				step.Holes++
+				step.Largest = maxInt64(step.Largest, b.Size)
+			}
+		}
+		if step.Free > 0 {
+			step.Fragmentation = 1 - float64(step.Largest)/float64(step.Free)
+		}
+		result.AverageFragmentation += step.Fragmentation
+		if step.Fragmentation > result.PeakFragmentation {
+			result.PeakFragmentation = step.Fragmentation
+		}
+		result.Steps = append(result.Steps, step)
+	}
+	if len(result.Steps) > 0 {
+		result.AverageFragmentation /= float64(len(result.Steps))
+	}
+	return result
+}
+
+// place gives req the start of the hole blocks[i], leaving the rest of it
+// a smaller hole.
+func place(blocks []Block, i int, req AllocRequest) []Block {
+	hole := blocks[i]
+	placed := []Block{{Name: req.Name, Start: hole.Start, Size: req.Size}}
+	if hole.Size > req.Size {
+		placed = append(placed, Block{Start: hole.Start + req.Size, Size: hole.Size - req.Size})
+	}
+	return append(blocks[:i:i], append(placed, blocks[i+1:]...)...)
+}
+
+// freeBlock gives back name's block, if it has one, merging it with the
+// holes either side.
+func freeBlock(blocks []Block, name string) []Block {
+	var merged []Block
+	for _, b := range blocks {
+		if b.Name == name {
+			b.Name = ""
+		}
+		if n := len(merged); n > 0 && b.Name == "" && merged[n-1].Name == "" {
+			merged[n-1].Size += b.Size
+			continue
+		}
+		merged = append(merged, b)
+	}
+	return merged
+}
+
+// compact slides every block down to the bottom of memory, keeping their
+// order, to leave one hole at the top, and reports how much it moved.
+func compact(blocks []Block, memory int64) ([]Block, int64) {
+	var compacted []Block
+	var next, moved int64
+	for _, b := range blocks {
+		if b.Name == "" {
+			continue
+		}
+		if b.Start != next {
+			moved += b.Size
+		}
+		b.Start = next
+		next += b.Size
+		compacted = append(compacted, b)
+	}
+	if next < memory {
+		compacted = append(compacted, Block{Start: next, Size: memory - next})
+	}
+	return compacted, moved
+}
+
+func freeMemory(blocks []Block) int64 {
+	var free int64
+	for _, b := range blocks {
+		if b.Name == "" {
+			free += b.Size
+		}
+	}
+	return free
+}
+
+// parseAllocations reads a list of requests: name=size to allocate size
+// units to name, and -name to free them.
+func parseAllocations(r io.Reader) ([]AllocRequest, error) {
+	fields, err := readListFields(r)
+	if err != nil {
+		return nil, err
+	}
+	requests := make([]AllocRequest, 0, len(fields))
+	for _, f := range fields {
+		if name := strings.TrimPrefix(f, "-"); name != f {
+			if name == "" || strings.Contains(name, "=") {
+				return nil, fmt.Errorf("%w: %q is not -name", ErrInvalidAllocation, f)
+			}
+			requests = append(requests, AllocRequest{Name: name, Free: true})
+			continue
+		}
+		name, n, ok := strings.Cut(f, "=")
+		size, err := strconv.ParseInt(n, 10, 64)
+		if !ok || name == "" || err != nil || size < 1 {
+			return nil, fmt.Errorf("%w: %q is not name=size with a size of 1 or more", ErrInvalidAllocation, f)
+		}
+		requests = append(requests, AllocRequest{Name: name, Size: size})
+	}
+	return requests, nil
+}
+
+// checkAllocations makes sure every request fits in memory, nothing is
+// allocated while it already holds memory, and only what holds memory is
+// freed, counting allocations as if none failed.
+func checkAllocations(requests []AllocRequest, opts AllocOptions) error {
+	if opts.Memory < 1 {
+		return fmt.Errorf("%w: memory must be at least 1 unit", ErrInvalidArgs)
+	}
+	if len(requests) == 0 {
+		return fmt.Errorf("%w: no requests", ErrInvalidAllocation)
+	}
+	held := map[string]bool{}
+	for i, req := range requests {
+		switch {
+		case req.Free && !held[req.Name]:
+			return fmt.Errorf("%w: request %d frees %s, which holds no memory", ErrInvalidAllocation, i+1, req.Name)
+		case !req.Free && held[req.Name]:
+			return fmt.Errorf("%w: request %d allocates to %s, which already holds memory", ErrInvalidAllocation, i+1, req.Name)
+		case req.Size > opts.Memory:
+			return fmt.Errorf("%w: request %d wants %d for %s, more than the %d of memory", ErrInvalidAllocation, i+1, req.Size, req.Name, opts.Memory)
+		}
+		held[req.Name] = !req.Free
+	}
+	return nil
+}
+
+// allocLabels gives each name the character memory maps draw its blocks
+// with: the name itself if every name is a single character, otherwise a
+// letter in order of first request.
+func allocLabels(requests []AllocRequest) map[string]byte {
+	labels := map[string]byte{}
+	short := true
+	for _, req := range requests {
+		short = short && len(req.Name) == 1 && req.Name != "."
+	}
+	const letters = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"
+	for _, req := range requests {
+		if _, ok := labels[req.Name]; ok {
+			continue
+		}
+		label := letters[len(labels)%len(letters)]
+		if short {
+			label = req.Name[0]
+		}
+		labels[req.Name] = label
+	}
+	return labels
+}
+
+func describeAllocStep(s AllocStep) string {
+	req := s.Request
+	switch {
+	case req.Free:
+		return fmt.Sprintf("free %s", req.Name)
+	case s.Failed:
+		return fmt.Sprintf("%s=%d failed", req.Name, req.Size)
+	case s.Compacted:
+		return fmt.Sprintf("%s=%d at %d after compacting %d", req.Name, req.Size, s.Start, s.Moved)
+	default:
+		return fmt.Sprintf("%s=%d at %d", req.Name, req.Size, s.Start)
+	}
+}
+
+// outputAllocation tabulates the memory after each request: how much is
+// free, in how many holes, the largest of them, and the external
+// fragmentation, the share of the free memory outside the largest hole.
+func outputAllocation(w io.Writer, result AllocResult) {
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Step", "Request", "Free", "Holes", "Largest hole", "Fragmentation"})
+	for i, s := range result.Steps {
+		table.Append([]string{
+			fmt.Sprint(i + 1),
+			describeAllocStep(s),
+			fmt.Sprint(s.Free),
+			fmt.Sprint(s.Holes),
+			fmt.Sprint(s.Largest),
+			fmt.Sprintf("%.1f%%", s.Fragmentation*100),
+		})
+	}
+	table.SetFooter([]string{"", "", "", "", "Average", fmt.Sprintf("%.1f%%", result.AverageFragmentation*100)})
+	table.Render()
+	_, _ = fmt.Fprintf(w, "Failed: %d, compactions: %d, moved: %d\n", result.Failed, result.Compactions, result.Moved)
+}
+
+// outputMemoryMap draws memory after each request, a line each, across a
+// line width columns wide: each column shows the block holding the address
+// it starts at, by its label, with holes dotted.
+func outputMemoryMap(w io.Writer, result AllocResult, memory int64, labels map[string]byte, width int) {
+	label := func(step int) string { return fmt.Sprintf("%4d |", step) }
+	cols := width - len(label(0)) - 1
+	if cols < 10 {
+		cols = 10
+	}
+	if int64(cols) > memory {
+		cols = int(memory)
+	}
+	_, _ = fmt.Fprintf(w, "Memory map (0-%d):\n", memory)
+	for i, s := range result.Steps {
+		line := make([]byte, cols)
+		b := 0
+		for c := range line {
+			addr := int64(c) * memory / int64(cols)
+			for s.Blocks[b].Start+s.Blocks[b].Size <= addr {
+				b++
+			}
+			line[c] = '.'
+			if name := s.Blocks[b].Name; name != "" {
+				line[c] = labels[name]
+			}
+		}
+		_, _ = fmt.Fprintf(w, "%s%s|\n", label(i+1), line)
+	}
+	var legend []string
+	for name, l := range labels {
+		if name != string(l) {
+			legend = append(legend, fmt.Sprintf("%c=%s", l, name))
+		}
+	}
+	if len(legend) > 0 {
+		sort.Strings(legend)
+		_, _ = fmt.Fprintf(w, "%s\n", strings.Join(legend, " "))
+	}
+}
+
+func outputAllocationComparison(w io.Writer, results []AllocResult) {
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Algorithm", "Failed", "Compactions", "Moved", "Average fragmentation", "Peak fragmentation"})
+	for _, r := range results {
+		table.Append([]string{
+			r.Algorithm,
+			fmt.Sprint(r.Failed),
+			fmt.Sprint(r.Compactions),
+			fmt.Sprint(r.Moved),
+			fmt.Sprintf("%.1f%%", r.AverageFragmentation*100),
+			fmt.Sprintf("%.1f%%", r.PeakFragmentation*100),
+		})
+	}
+	table.Render()
+}
+
+// selectFitAlgorithms picks the indexes in fitAlgorithms of those named in
+// a comma-separated list, keeping the list's order. An empty list selects
+// them all.
+func selectFitAlgorithms(spec string) ([]int, error) {
+	var selected []int
+	if spec == "" {
+		for i := range fitAlgorithms {
+			selected = append(selected, i)
+		}
+		return selected, nil
+	}
+	for _, name := range strings.Split(spec, ",") {
+		i := 0
+		for i < len(fitAlgorithms) && fitAlgorithms[i].name != strings.TrimSpace(name) {
+			i++
+		}
+		if i == len(fitAlgorithms) {
+			return nil, fmt.Errorf("%w: unknown fit %q", ErrInvalidArgs, name)
+		}
+		selected = append(selected, i)
+	}
+	return selected, nil
+}
+
+// runAlloc is the alloc subcommand: simulate contiguous memory allocation
+// over a file of requests.
+func runAlloc(w io.Writer, args []string) error {
+	var opts AllocOptions
+	fs := flag.NewFlagSet("alloc", flag.ContinueOnError)
+	algos := fs.String("algo", "", "comma-separated fits: first, best, worst (default all)")
+	fs.Int64Var(&opts.Memory, "memory", 1000, "units of memory to allocate from")
+	fs.BoolVar(&opts.Compact, "compact", false, "compact memory when a request fails for want of a big enough hole")
+	width := fs.Int("width", terminalWidth(), "widest memory map line, from $COLUMNS if set")
+	noChart := fs.Bool("no-chart", false, "leave out the memory maps")
+	if err := parseFlags(fs, "alloc", args); err != nil {
+		return err
+	}
+	if fs.NArg() > 1 {
+		return fmt.Errorf("%w: usage: alloc [flags] file", ErrInvalidArgs)
+	}
+	policies, err := selectFitAlgorithms(*algos)
+	if err != nil {
+		return err
+	}
+	f, closeFile, err := openProcessingFile(append([]string{"alloc"}, fs.Args()...)...)
+	if err != nil {
+		return err
+	}
+	defer closeFile()
+	requests, err := parseAllocations(f)
+	if err != nil {
+		return err
+	}
+	if err := checkAllocations(requests, opts); err != nil {
+		return err
+	}
+	labels := allocLabels(requests)
+	results := make([]AllocResult, len(policies))
+	for i, p := range policies {
+		results[i] = SimulateAllocation(requests, fitAlgorithms[p].policy, opts)
+		results[i].Algorithm = fitAlgorithms[p].title
+		outputTitle(w, results[i].Algorithm+" allocation")
+		outputAllocation(w, results[i])
+		if !*noChart {
+			outputMemoryMap(w, results[i], opts.Memory, labels, *width)
+		}
+	}
+	if len(results) > 1 {
+		outputTitle(w, "Allocation comparison")
+		outputAllocationComparison(w, results)
+	}
+	return nil
+}
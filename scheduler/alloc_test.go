@@ -0,0 +1,144 @@
+package scheduler
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSimulateAllocation(t *testing.T) {
+	t.Parallel()
+	requests := []AllocRequest{
+		{Name: "A", Size: 300}, {Name: "B", Size: 200}, {Name: "C", Size: 250},
+		{Name: "B", Free: true}, {Name: "D", Size: 100}, {Name: "A", Free: true},
+		{Name: "E", Size: 350}, {Name: "F", Size: 150},
+	}
+	tests := []struct {
+		name    string
+		policy  fitPolicy
+		compact bool
+		starts  []int64
+		failed  int
+		moved   int64
+	}{
+		{name: "first", policy: firstFit, starts: []int64{0, 300, 500, -1, 300, -1, -1, 0}, failed: 1},
+		{name: "best", policy: bestFit, starts: []int64{0, 300, 500, -1, 300, -1, -1, 750}, failed: 1},
+		{name: "worst", policy: worstFit, starts: []int64{0, 300, 500, -1, 750, -1, 0, 350}},
+		// Compacting slides D down to 0 and C to 100, leaving 650 free at
+		// 350.
+		{name: "first compacting", policy: firstFit, compact: true, starts: []int64{0, 300, 500, -1, 300, -1, 350, 700}, moved: 350},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := SimulateAllocation(requests, tt.policy, AllocOptions{Memory: 1000, Compact: tt.compact})
+			var starts []int64
+			for _, s := range got.Steps {
+				starts = append(starts, s.Start)
+				var covered int64
+				for _, b := range s.Blocks {
+					if b.Start != covered {
+						t.Errorf("block %+v does not start where the one before ended, at %d", b, covered)
+					}
+					covered += b.Size
+				}
+				if covered != 1000 {
+					t.Errorf("blocks cover %d of 1000", covered)
+				}
+			}
+			if !reflect.DeepEqual(starts, tt.starts) {
+				t.Errorf("starts = %v, want %v", starts, tt.starts)
+			}
+			if got.Failed != tt.failed || got.Moved != tt.moved {
+				t.Errorf("Failed, Moved = %d, %d, want %d, %d", got.Failed, got.Moved, tt.failed, tt.moved)
+			}
+		})
+	}
+}
+
+func TestSimulateAllocation_fragmentation(t *testing.T) {
+	t.Parallel()
+	got := SimulateAllocation([]AllocRequest{
+		{Name: "A", Size: 100}, {Name: "B", Size: 100}, {Name: "A", Free: true},
+	}, firstFit, AllocOptions{Memory: 400})
+	last := got.Steps[2]
+	// 100 free at 0 and 200 at 200: a third of the free memory is outside
+	// the largest hole.
+	if last.Free != 300 || last.Holes != 2 || last.Largest != 200 {
+		t.Errorf("Free, Holes, Largest = %d, %d, %d, want 300, 2, 200", last.Free, last.Holes, last.Largest)
+	}
+	if last.Fragmentation != 0.33333333333333337 || got.PeakFragmentation != last.Fragmentation || got.AverageFragmentation != 0.11111111111111112 {
+		t.Errorf("Fragmentation %v, peak %v, average %v, want a third, a third and a ninth", last.Fragmentation, got.PeakFragmentation, got.AverageFragmentation)
+	}
+}
+
+func Test_freeBlock(t *testing.T) {
+	t.Parallel()
+	got := freeBlock([]Block{{Start: 0, Size: 10}, {Name: "A", Start: 10, Size: 5}, {Start: 15, Size: 5}, {Name: "B", Start: 20, Size: 5}}, "A")
+	want := []Block{{Start: 0, Size: 20}, {Name: "B", Start: 20, Size: 5}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("freeBlock() = %+v, want %+v", got, want)
+	}
+}
+
+func Test_parseAllocations(t *testing.T) {
+	t.Parallel()
+	got, err := parseAllocations(strings.NewReader("P1=300, P2=200\n-P1 # gone\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []AllocRequest{{Name: "P1", Size: 300}, {Name: "P2", Size: 200}, {Name: "P1", Free: true}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseAllocations() = %+v, want %+v", got, want)
+	}
+	for _, bad := range []string{"P1", "P1=0", "=3", "P1=x", "-", "-P1=3"} {
+		if _, err := parseAllocations(strings.NewReader(bad)); !errors.Is(err, ErrInvalidAllocation) {
+			t.Errorf("parseAllocations(%q) error = %v, want ErrInvalidAllocation", bad, err)
+		}
+	}
+}
+
+func Test_checkAllocations(t *testing.T) {
+	t.Parallel()
+	opts := AllocOptions{Memory: 100}
+	tests := []struct {
+		name     string
+		requests []AllocRequest
+		opts     AllocOptions
+		want     error
+	}{
+		{name: "valid", requests: []AllocRequest{{Name: "A", Size: 100}, {Name: "A", Free: true}, {Name: "A", Size: 10}}, opts: opts},
+		{name: "no memory", requests: []AllocRequest{{Name: "A", Size: 1}}, want: ErrInvalidArgs},
+		{name: "no requests", opts: opts, want: ErrInvalidAllocation},
+		{name: "too big", requests: []AllocRequest{{Name: "A", Size: 101}}, opts: opts, want: ErrInvalidAllocation},
+		{name: "allocated twice", requests: []AllocRequest{{Name: "A", Size: 1}, {Name: "A", Size: 1}}, opts: opts, want: ErrInvalidAllocation},
+		{name: "freed unallocated", requests: []AllocRequest{{Name: "A", Free: true}}, opts: opts, want: ErrInvalidAllocation},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if err := checkAllocations(tt.requests, tt.opts); !errors.Is(err, tt.want) {
+				t.Errorf("checkAllocations() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func Test_outputMemoryMap(t *testing.T) {
+	t.Parallel()
+	requests := []AllocRequest{{Name: "P1", Size: 4}, {Name: "P2", Size: 2}, {Name: "P1", Free: true}}
+	result := SimulateAllocation(requests, firstFit, AllocOptions{Memory: 10})
+	var w strings.Builder
+	outputMemoryMap(&w, result, 10, allocLabels(requests), 80)
+	want := "Memory map (0-10):\n" +
+		"   1 |AAAA......|\n" +
+		"   2 |AAAABB....|\n" +
+		"   3 |....BB....|\n" +
+		"A=P1 B=P2\n"
+	if got := w.String(); got != want {
+		t.Errorf("outputMemoryMap() = %q, want %q", got, want)
+	}
+}
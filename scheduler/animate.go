@@ -0,0 +1,90 @@
+package scheduler
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// defaultAnimateSpeed is how many ticks -animate plays a second.
+const defaultAnimateSpeed = 4
+
+// AnimateOptions controls how a schedule is played back.
+type AnimateOptions struct {
+	// Speed is how many ticks play a second.
+	Speed float64
+	// Clear redraws each frame in place, for a terminal; otherwise the
+	// frames follow one another.
+	Clear bool
+	Gantt GanttOptions
+	// Sleep waits between frames; nil uses time.Sleep.
+	Sleep func(time.Duration)
+}
+
+// Animate replays result in real time: each frame shows the clock, what is
+// running, the ready queue and the Gantt chart so far, drawn to the scale of
+// the whole chart so that it grows across the screen. A long schedule
+// advances a column of the chart a frame rather than a tick.
+func Animate(w io.Writer, title string, result Result, opts AnimateOptions) {
+	if len(result.Gantt) == 0 {
+		return
+	}
+	sleep := opts.Sleep
+	if sleep == nil {
+		sleep = time.Sleep
+	}
+	speed := opts.Speed
+	if speed <= 0 {
+		speed = defaultAnimateSpeed
+	}
+	width := opts.Gantt.width()
+	origin, end := ganttSpan(result.Gantt)
+	col := ganttColumns(origin, end, width, opts.Gantt)
+	step := int64(1)
+	for step < end-origin && col(origin+step) == col(origin) {
+		step++
+	}
+	cpus := len(ganttLanes(result.Gantt))
+	frame := time.Duration(float64(step) / speed * float64(time.Second))
+
+	for t := origin; ; t += step {
+		if t > end {
+			t = end
+		}
+		if opts.Clear {
+			_, _ = io.WriteString(w, "\x1b[H\x1b[2J")
+		}
+		outputTitle(w, title)
+		snap := SnapshotAt(result, t)
+		outputAnimationFrame(w, snap, origin, col, width, cpus, opts.Gantt)
+		if t == end {
+			return
+		}
+		sleep(frame)
+	}
+}
+
+// outputAnimationFrame prints one frame: the state line, then the Gantt
+// chart up to the snapshot's time, one lane per CPU if there are several.
+func outputAnimationFrame(w io.Writer, snap Snapshot, origin int64, col func(int64) int, width, cpus int, opts GanttOptions) {
+	running := "idle"
+	if len(snap.Running) > 0 {
+		running = formatPIDList(snap.Running)
+	}
+	ready := "empty"
+	if len(snap.Ready) > 0 {
+		ready = formatPIDList(snap.Ready)
+	}
+	_, _ = fmt.Fprintf(w, "t=%d  running: %s  ready: %s\n", snap.Time, running, ready)
+	if len(snap.Gantt) == 0 {
+		_, _ = fmt.Fprintln(w)
+		return
+	}
+	for _, lane := range ganttLanes(snap.Gantt) {
+		if cpus > 1 {
+			_, _ = fmt.Fprintf(w, "CPU %d\n", lane[0].CPU)
+		}
+		outputGanttLane(w, lane, origin, snap.Time, col, width, opts)
+	}
+	_, _ = fmt.Fprintln(w)
+}
@@ -0,0 +1,64 @@
+package scheduler
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAnimate(t *testing.T) {
+	t.Parallel()
+	result := FCFS([]Process{NewProcess(1, 0, 2, 0), NewProcess(2, 1, 2, 0)})
+	tests := []struct {
+		name   string
+		opts   AnimateOptions
+		frames int
+		slept  time.Duration
+	}{
+		{
+			name:   "a frame a tick",
+			opts:   AnimateOptions{Speed: 2, Gantt: GanttOptions{Width: 80}},
+			frames: 5,
+			slept:  2 * time.Second,
+		},
+		{
+			// Two ticks a column makes two ticks a frame.
+			name:   "scaled",
+			opts:   AnimateOptions{Speed: 4, Gantt: GanttOptions{Width: 80, Scale: 2}},
+			frames: 3,
+			slept:  time.Second,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var (
+				w     bytes.Buffer
+				slept time.Duration
+			)
+			tt.opts.Sleep = func(d time.Duration) { slept += d }
+			Animate(&w, "FCFS", result, tt.opts)
+			if got := strings.Count(w.String(), "t="); got != tt.frames {
+				t.Errorf("Animate() drew %d frames, want %d:\n%s", got, tt.frames, w.String())
+			}
+			if slept != tt.slept {
+				t.Errorf("Animate() slept %v, want %v", slept, tt.slept)
+			}
+		})
+	}
+}
+
+func Test_outputAnimationFrame(t *testing.T) {
+	t.Parallel()
+	result := FCFS([]Process{NewProcess(1, 0, 2, 0), NewProcess(2, 1, 2, 0)})
+	var w bytes.Buffer
+	opts := GanttOptions{Width: 9}
+	col := ganttColumns(0, 4, opts.Width, opts)
+	outputAnimationFrame(&w, SnapshotAt(result, 1), 0, col, opts.Width, 1, opts)
+	want := "t=1  running: 1  ready: 2\n|1|\n0 1\n\n"
+	if w.String() != want {
+		t.Errorf("outputAnimationFrame() = %q, want %q", w.String(), want)
+	}
+}
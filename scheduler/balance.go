@@ -0,0 +1,303 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Balance is how a multi-CPU engine shares ready processes out between its
+// CPUs.
+type Balance int
+
+const (
+	// BalanceGlobal keeps one ready queue for every CPU. Load is always
+	// balanced, but a process runs on whichever CPU frees up first.
+	BalanceGlobal Balance = iota
+	// BalancePerCore gives each CPU its own ready queue. A process goes back
+	// to the queue of the CPU it last ran on, and a CPU whose queue is empty
+	// steals work from the others.
+	BalancePerCore
+)
+
+// StealOptions tunes how BalancePerCore CPUs with nothing to run take work
+// from other CPUs' queues.
+type StealOptions struct {
+	// Threshold is the fewest ready processes a queue must hold to be
+	// stolen from; zero means one. A high threshold leaves CPUs idle
+	// rather than moving processes away from their caches.
+	Threshold int
+	// Half moves every other process the thief may run to its own queue,
+	// instead of taking only the one it runs next.
+	Half bool
+}
+
+func (b Balance) String() string {
+	if b == BalancePerCore {
+		return "percore"
+	}
+	return "global"
+}
+
+// parseSteal reads how much an idle CPU steals: one or half.
+func parseSteal(s string) (half bool, err error) {
+	switch s {
+	case "", "one":
+		return false, nil
+	case "half":
+		return true, nil
+	default:
+		return false, fmt.Errorf("%w: unknown steal amount %q", ErrInvalidArgs, s)
+	}
+}
+
+func parseBalance(s string) (Balance, error) {
+	switch s {
+	case "", "global":
+		return BalanceGlobal, nil
+	case "percore":
+		return BalancePerCore, nil
+	default:
+		return BalanceGlobal, fmt.Errorf("%w: unknown load balancing %q", ErrInvalidArgs, s)
+	}
+}
+
+// parseAffinity reads the affinity column: CPU numbers separated by spaces.
+func parseAffinity(s string) ([]int, error) {
+	var cpus []int
+	for _, f := range strings.Fields(s) {
+		n, err := strconv.Atoi(f)
+		if err != nil {
+			return nil, errors.Unwrap(err)
+		}
+		cpus = append(cpus, n)
+	}
+	return cpus, nil
+}
+
+func formatAffinity(cpus []int) string {
+	fields := make([]string, len(cpus))
+	for i, c := range cpus {
+		fields[i] = strconv.Itoa(c)
+	}
+	return strings.Join(fields, " ")
+}
+
+// affinityMask marks which of cpus CPUs a process may use, or returns nil
+// if it may use any. CPUs the machine does not have are ignored, and an
+// affinity naming none it does have is dropped.
+func affinityMask(affinity []int, cpus int) []bool {
+	var mask []bool
+	for _, c := range affinity {
+		if c < 0 || c >= cpus {
+			continue
+		}
+		if mask == nil {
+			mask = make([]bool, cpus)
+		}
+		mask[c] = true
+	}
+	return mask
+}
+
+func (t *Task) canRun(cpu int) bool { return t.mask == nil || t.mask[cpu] }
+
+// usable counts the cpus t may run on.
+func (t *Task) usable(cpus []int) int {
+	n := 0
+	for _, c := range cpus {
+		if t.canRun(c) {
+			n++
+		}
+	}
+	return n
+}
+
+// idleFor counts the idle CPUs t may run on.
+func (e *engine) idleFor(t *Task) int {
+	n := 0
+	for c, r := range e.cores {
+		if r == nil && t.canRun(c) {
+			n++
+		}
+	}
+	return n
+}
+
+// fit picks idle CPUs for every thread of t, starting with first unless it
+// is -1, or returns nil if there are not enough it may use.
+func (e *engine) fit(t *Task, first int) []int {
+	cpus := t.cpus[:0]
+	if first >= 0 {
+		if e.cores[first] != nil || !t.canRun(first) {
+			return nil
+		}
+		cpus = append(cpus, first)
+	}
+	for c, r := range e.cores {
+		if len(cpus) == t.threads {
+			break
+		}
+		if r == nil && c != first && t.canRun(c) {
+			cpus = append(cpus, c)
+		}
+	}
+	if len(cpus) < t.threads {
+		return nil
+	}
+	return cpus
+}
+
+// queueFor is the ready queue t joins: the only one, or under
+// BalancePerCore the queue of the CPU it last ran on, whose cache may
+// still hold its data. A task that has not run yet, or may not use that
+// CPU, joins the least loaded queue of a CPU it may use.
+func (e *engine) queueFor(t *Task) int {
+	if len(e.queues) == 1 {
+		return 0
+	}
+	if t.ran && t.canRun(t.cpu) {
+		return t.cpu
+	}
+	best, least := 0, -1
+	for c, q := range e.queues {
+		if !t.canRun(c) {
+			continue
+		}
+		load := q.Len()
+		if e.cores[c] != nil {
+			load++
+		}
+		if least < 0 || load < least {
+			best, least = c, load
+		}
+	}
+	return best
+}
+
+// dispatchPerCore gives each idle CPU the best task in its own queue that
+// fits, or failing that one stolen from another CPU's queue.
+func (e *engine) dispatchPerCore() {
+	for c := range e.cores {
+		if e.cores[c] != nil {
+			continue
+		}
+		candidates := e.queues[c].Len()
+		if t, cpus := e.takeFitting(e.queues[c], c); t != nil {
+			e.start(t, cpus, candidates)
+		}
+	}
+	// Only steal once every CPU has had the pick of its own queue.
+	for c := range e.cores {
+		if e.cores[c] == nil {
+			e.steal(c)
+		}
+	}
+}
+
+// takeFitting takes the best task in q that can start on CPU c, with
+// enough other idle CPUs for the rest of its threads.
+func (e *engine) takeFitting(q readyQueue, c int) (*Task, []int) {
+	var (
+		skipped []*Task
+		t       *Task
+		cpus    []int
+	)
+	for q.Len() > 0 {
+		next := q.Take()
+		if cpus = e.fit(next, c); cpus != nil {
+			t = next
+			break
+		}
+		skipped = append(skipped, next)
+	}
+	for _, s := range skipped {
+		q.Add(s)
+	}
+	return t, cpus
+}
+
+// steal has idle CPU c take work from the longest other queue holding at
+// least the steal threshold and something it may run: the best task it can
+// start, or with Steal.Half every other task it may run, which join its
+// own queue.
+func (e *engine) steal(c int) {
+	threshold := e.opts.Steal.Threshold
+	if threshold < 1 {
+		threshold = 1
+	}
+	var victims []int
+	for v, q := range e.queues {
+		if v != c && q.Len() >= threshold {
+			victims = append(victims, v)
+		}
+	}
+	sort.SliceStable(victims, func(i, j int) bool { return e.queues[victims[i]].Len() > e.queues[victims[j]].Len() })
+	for _, v := range victims {
+		if e.opts.Steal.Half {
+			if e.stealHalf(v, c) == 0 {
+				continue
+			}
+			candidates := e.queues[c].Len()
+			if t, cpus := e.takeFitting(e.queues[c], c); t != nil {
+				e.start(t, cpus, candidates)
+			}
+			return
+		}
+		candidates := e.queues[v].Len()
+		if t, cpus := e.takeFitting(e.queues[v], c); t != nil {
+			e.steals++
+			e.opts.Log.Log(e.now, "steal", "pid", t.ProcessID, "from", v, "to", c)
+			e.start(t, cpus, candidates)
+			return
+		}
+	}
+}
+
+// stealHalf moves every other task CPU c may run from queue v to c's own,
+// starting with the best, so both queues keep a similar mix. It returns
+// how many it moved.
+func (e *engine) stealHalf(v, c int) int {
+	from, to := e.queues[v], e.queues[c]
+	var kept []*Task
+	moved, eligible := 0, 0
+	for from.Len() > 0 {
+		t := from.Take()
+		if !t.canRun(c) {
+			kept = append(kept, t)
+			continue
+		}
+		eligible++
+		if eligible%2 == 0 {
+			kept = append(kept, t)
+			continue
+		}
+		t.queue = c
+		to.Add(t)
+		moved++
+		e.opts.Log.Log(e.now, "steal", "pid", t.ProcessID, "from", v, "to", c)
+	}
+	for _, t := range kept {
+		from.Add(t)
+	}
+	e.steals += moved
+	return moved
+}
+
+// preemptPerCore lets the best task in each CPU's queue take that CPU from
+// a task the policy ranks behind it. Gangs wait for free CPUs instead.
+func (e *engine) preemptPerCore() bool {
+	for c, q := range e.queues {
+		running := e.cores[c]
+		if q.Len() == 0 || running == nil || running.threads > 1 {
+			continue
+		}
+		if best := q.Best(); best.threads == 1 && best.canRun(c) && e.policy.Less(best, running) {
+			e.preemptTask(running, best)
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,163 @@
+package scheduler
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSimulate_balance(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name           string
+		processes      []Process
+		policy         Policy
+		balance        Balance
+		wantGantt      []TimeSlice
+		wantMigrations int
+	}{
+		{
+			name: "affinity",
+			processes: []Process{
+				{ProcessID: 1, BurstDuration: 2, Affinity: []int{1}},
+				{ProcessID: 2, BurstDuration: 2},
+			},
+			policy: fcfsPolicy{},
+			wantGantt: []TimeSlice{
+				{PID: 2, Start: 0, Stop: 2, CPU: 0},
+				{PID: 1, Start: 0, Stop: 2, CPU: 1},
+			},
+		},
+		{
+			name: "idle CPU steals",
+			processes: []Process{
+				{ProcessID: 1, BurstDuration: 4},
+				{ProcessID: 2, BurstDuration: 2},
+				{ProcessID: 3, ArrivalTime: 1, BurstDuration: 2},
+			},
+			policy:  fcfsPolicy{},
+			balance: BalancePerCore,
+			// 3 joins CPU 0's queue, and CPU 1 takes it when 2 finishes.
+			wantGantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 4, CPU: 0},
+				{PID: 2, Start: 0, Stop: 2, CPU: 1},
+				{PID: 3, Start: 2, Stop: 4, CPU: 1},
+			},
+		},
+		{
+			name: "global queue migrates",
+			processes: []Process{
+				{ProcessID: 1, BurstDuration: 2},
+				{ProcessID: 2, BurstDuration: 2},
+				{ProcessID: 3, BurstDuration: 2},
+			},
+			policy: rrPolicy{quantum: 1},
+			wantGantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 1, CPU: 0},
+				{PID: 2, Start: 0, Stop: 1, CPU: 1},
+				{PID: 3, Start: 1, Stop: 2, CPU: 0},
+				{PID: 1, Start: 1, Stop: 2, CPU: 1},
+				{PID: 2, Start: 2, Stop: 3, CPU: 0},
+				{PID: 3, Start: 2, Stop: 3, CPU: 1},
+			},
+			wantMigrations: 3,
+		},
+		{
+			name: "per-core queues keep processes in place",
+			processes: []Process{
+				{ProcessID: 1, BurstDuration: 2},
+				{ProcessID: 2, BurstDuration: 2},
+				{ProcessID: 3, BurstDuration: 2},
+			},
+			policy:  rrPolicy{quantum: 1},
+			balance: BalancePerCore,
+			// Only 3, stolen by CPU 1 once 2 is done, moves.
+			wantGantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 1, CPU: 0},
+				{PID: 2, Start: 0, Stop: 1, CPU: 1},
+				{PID: 3, Start: 1, Stop: 2, CPU: 0},
+				{PID: 2, Start: 1, Stop: 2, CPU: 1},
+				{PID: 1, Start: 2, Stop: 3, CPU: 0},
+				{PID: 3, Start: 2, Stop: 3, CPU: 1},
+			},
+			wantMigrations: 1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := Simulate(tt.processes, tt.policy, EngineOptions{CPUs: 2, Balance: tt.balance})
+			if !reflect.DeepEqual(got.Gantt, tt.wantGantt) {
+				t.Errorf("Gantt = %v, want %v", got.Gantt, tt.wantGantt)
+			}
+			if got.Cores.Migrations != tt.wantMigrations {
+				t.Errorf("Migrations = %d, want %d", got.Cores.Migrations, tt.wantMigrations)
+			}
+		})
+	}
+}
+
+func Test_affinityMask(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		affinity []int
+		want     []bool
+	}{
+		{name: "any", want: nil},
+		{name: "some", affinity: []int{0, 2}, want: []bool{true, false, true}},
+		{name: "missing CPUs ignored", affinity: []int{1, 7}, want: []bool{false, true, false}},
+		{name: "no CPU it has", affinity: []int{5}, want: nil},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := affinityMask(tt.affinity, 3); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("affinityMask() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSimulate_steal(t *testing.T) {
+	t.Parallel()
+	// Joining the shorter queue in turn, 1, 3, 5, 7 and 9 share CPU 0's
+	// queue and 2, 4, 6 and 8 CPU 1's. CPU 1 is held by 2 until 20, so CPU 0
+	// steals 4, 6 and 8 once its own queue is empty at 9.
+	processes := []Process{{ProcessID: 1, BurstDuration: 1}, {ProcessID: 2, BurstDuration: 20}}
+	for pid := int64(3); pid <= 9; pid++ {
+		processes = append(processes, Process{ProcessID: pid, BurstDuration: 2})
+	}
+	tests := []struct {
+		name       string
+		steal      StealOptions
+		wantCPU0   []int64
+		wantSteals int
+	}{
+		{name: "one", wantCPU0: []int64{1, 3, 5, 7, 9, 4, 6, 8}, wantSteals: 3},
+		// 4 and 8 move over together, leaving 6 to be stolen after them.
+		{name: "half", steal: StealOptions{Half: true}, wantCPU0: []int64{1, 3, 5, 7, 9, 4, 8, 6}, wantSteals: 3},
+		// The last process left in CPU 1's queue stays there.
+		{name: "threshold", steal: StealOptions{Threshold: 2}, wantCPU0: []int64{1, 3, 5, 7, 9, 4, 6}, wantSteals: 2},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := Simulate(processes, fcfsPolicy{}, EngineOptions{CPUs: 2, Balance: BalancePerCore, Steal: tt.steal})
+			var cpu0 []int64
+			for _, s := range got.Gantt {
+				if s.CPU == 0 {
+					cpu0 = append(cpu0, s.PID)
+				}
+			}
+			if !reflect.DeepEqual(cpu0, tt.wantCPU0) {
+				t.Errorf("CPU 0 ran %v, want %v", cpu0, tt.wantCPU0)
+			}
+			if got.Cores.Steals != tt.wantSteals {
+				t.Errorf("Steals = %d, want %d", got.Cores.Steals, tt.wantSteals)
+			}
+		})
+	}
+}
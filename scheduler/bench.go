@@ -0,0 +1,158 @@
+package scheduler
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+// BenchOptions controls a benchmark of the schedulers.
+type BenchOptions struct {
+	Algorithms []Algorithm
+	Sizes      []int
+	Runs       int
+	Seed       int64
+	MaxBurst   int64
+	// TickByTick also times each algorithm with the engine stepping one tick
+	// at a time.
+	TickByTick bool
+}
+
+// BenchResult is the cost of simulating one algorithm over workloads of one
+// size, averaged over the runs.
+type BenchResult struct {
+	Algorithm  string
+	TickByTick bool
+	Processes  int
+	Runs       int
+	PerRun     time.Duration
+	Allocs     uint64
+	Bytes      uint64
+	// Exponent is the empirical growth k in time ~ n^k against the previous
+	// size, or NaN for the first.
+	Exponent float64
+}
+
+// Bench times every algorithm over fresh workloads of each size. Workloads
+// arrive over about as many ticks as they need CPU time, so the ready queue
+// stays busy without growing without bound.
+func Bench(opts BenchOptions) []BenchResult {
+	var results []BenchResult
+	for _, algorithm := range opts.Algorithms {
+		for _, tick := range benchModes(opts.TickByTick) {
+			var prev *BenchResult
+			for _, n := range opts.Sizes {
+				r := benchOne(algorithm, n, tick, opts)
+				r.Exponent = math.NaN()
+				if prev != nil && prev.PerRun > 0 && n != prev.Processes {
+					r.Exponent = math.Log(float64(r.PerRun)/float64(prev.PerRun)) / math.Log(float64(n)/float64(prev.Processes))
+				}
+				results = append(results, r)
+				prev = &results[len(results)-1]
+			}
+		}
+	}
+	return results
+}
+
+func benchModes(tickByTick bool) []bool {
+	if tickByTick {
+		return []bool{false, true}
+	}
+	return []bool{false}
+}
+
+func benchOne(algorithm Algorithm, n int, tick bool, opts BenchOptions) BenchResult {
+	rng := newRand(opts.Seed)
+	gen := GenerateOptions{
+		Count:       n,
+		MaxBurst:    opts.MaxBurst,
+		MaxArrival:  int64(n) * (opts.MaxBurst + 1) / 2,
+		MaxPriority: 5,
+	}
+	// Workloads are generated up front so only simulation is measured.
+	workloads := make([][]Process, opts.Runs)
+	for i := range workloads {
+		workloads[i] = GenerateProcesses(gen, rng)
+	}
+
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+	for _, processes := range workloads {
+		Simulate(processes, algorithm.New(processes, AlgorithmOptions{}), EngineOptions{TickByTick: tick})
+	}
+	elapsed := time.Since(start)
+	runtime.ReadMemStats(&after)
+
+	runs := uint64(opts.Runs)
+	return BenchResult{
+		Algorithm:  algorithm.Name,
+		TickByTick: tick,
+		Processes:  n,
+		Runs:       opts.Runs,
+		PerRun:     elapsed / time.Duration(opts.Runs),
+		Allocs:     (after.Mallocs - before.Mallocs) / runs,
+		Bytes:      (after.TotalAlloc - before.TotalAlloc) / runs,
+	}
+}
+
+func outputBench(w io.Writer, results []BenchResult) {
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Algorithm", "Loop", "Processes", "Runs", "Time/run", "Allocs/run", "Bytes/run", "Scaling"})
+	for _, r := range results {
+		loop := "event"
+		if r.TickByTick {
+			loop = "tick"
+		}
+		scaling := ""
+		if !math.IsNaN(r.Exponent) {
+			scaling = fmt.Sprintf("n^%.2f", r.Exponent)
+		}
+		table.Append([]string{
+			r.Algorithm, loop, fmt.Sprint(r.Processes), fmt.Sprint(r.Runs), r.PerRun.String(),
+			fmt.Sprint(r.Allocs), fmt.Sprint(r.Bytes), scaling,
+		})
+	}
+	table.Render()
+}
+
+func runBench(w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("bench", flag.ContinueOnError)
+	algo := fs.String("algo", "", "comma-separated algorithms to time (default all defaults)")
+	sizes := fs.String("sizes", "1000,10000,100000", "comma-separated workload sizes")
+	runs := fs.Int("runs", 3, "runs per algorithm and size")
+	seed := addSeedFlag(fs, "random seed for the generated workloads")
+	maxBurst := fs.Int64("max-burst", 10, "maximum CPU burst")
+	tick := fs.Bool("tick", false, "also time a tick-by-tick loop for comparison")
+	if err := parseFlags(fs, "bench", args); err != nil {
+		return err
+	}
+	algorithms, err := selectRuns(*algo)
+	if err != nil {
+		return err
+	}
+	opts := BenchOptions{Algorithms: algorithms, Runs: *runs, Seed: pickSeed(os.Stderr, *seed), MaxBurst: *maxBurst, TickByTick: *tick}
+	for _, s := range strings.Split(*sizes, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(s))
+		if err != nil || n < 1 {
+			return fmt.Errorf("%w: bad size %q", ErrInvalidArgs, s)
+		}
+		opts.Sizes = append(opts.Sizes, n)
+	}
+	if opts.Runs < 1 || opts.MaxBurst < 1 {
+		return fmt.Errorf("%w: -runs and -max-burst must be positive", ErrInvalidArgs)
+	}
+
+	outputBench(w, Bench(opts))
+	return nil
+}
@@ -0,0 +1,53 @@
+package scheduler
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestBench(t *testing.T) {
+	t.Parallel()
+	fcfs, _ := LookupAlgorithm("fcfs")
+	results := Bench(BenchOptions{
+		Algorithms: []Algorithm{fcfs},
+		Sizes:      []int{10, 20},
+		Runs:       2,
+		Seed:       1,
+		MaxBurst:   5,
+		TickByTick: true,
+	})
+	type row struct {
+		tick      bool
+		processes int
+		scaled    bool
+	}
+	var got []row
+	for _, r := range results {
+		got = append(got, row{r.TickByTick, r.Processes, !math.IsNaN(r.Exponent)})
+		if r.Runs != 2 || r.Allocs == 0 {
+			t.Errorf("%+v: want 2 runs with allocations counted", r)
+		}
+	}
+	want := []row{{false, 10, false}, {false, 20, true}, {true, 10, false}, {true, 20, true}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Bench() rows = %+v, want %+v", got, want)
+	}
+}
+
+func TestSimulate_tickByTick(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 4, Bursts: []int64{2, 3, 2}},
+		{ProcessID: 2, ArrivalTime: 1, BurstDuration: 5},
+		{ProcessID: 3, ArrivalTime: 9, BurstDuration: 2},
+	}
+	for _, name := range []string{"fcfs", "sjf", "priority", "rr"} {
+		algorithm, _ := LookupAlgorithm(name)
+		event := Simulate(processes, algorithm.New(processes, AlgorithmOptions{}), EngineOptions{})
+		tick := Simulate(processes, algorithm.New(processes, AlgorithmOptions{}), EngineOptions{TickByTick: true})
+		if !reflect.DeepEqual(event.Gantt, tick.Gantt) || !reflect.DeepEqual(event.Schedule, tick.Schedule) {
+			t.Errorf("%s: tick-by-tick schedule %v differs from %v", name, tick.Gantt, event.Gantt)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package scheduler
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+// boost raises by EngineOptions.IOBoost levels the effective priority of t,
+// just back from I/O and queued, as interactive schedulers favour a process
+// that has been waiting on a device over the CPU-bound ones. Like aging, the
+// boost lasts until the process next joins a ready queue, after it has had
+// the CPU.
+func (e *engine) boost(t *Task) {
+	if e.opts.IOBoost <= 0 || !t.queued {
+		return
+	}
+	t.boosted = e.opts.IOBoost
+	t.EffectivePriority = e.effectivePriority(t)
+	e.recordEvent(Event{Time: e.now, Kind: EventBoost, PID: t.ProcessID, CPU: t.cpu, Priority: t.EffectivePriority})
+	e.opts.Log.Log(e.now, "boost", "pid", t.ProcessID, "levels", t.boosted, "priority", t.EffectivePriority)
+	e.queues[t.queue].Fix(t)
+}
+
+// unboost drops the levels t gained on returning from I/O.
+func (e *engine) unboost(t *Task) {
+	if t.boosted == 0 {
+		return
+	}
+	t.boosted = 0
+	t.EffectivePriority = e.effectivePriority(t)
+}
+
+// BoostRow sets one algorithm's run with an I/O boost against the same run
+// without it. The processes that block for I/O should get the CPU back
+// sooner after each burst of I/O, at some cost to the turnaround of those
+// that never block.
+type BoostRow struct {
+	Name string
+	// Wakes is how many times a process came back from I/O.
+	Wakes int
+	// Response and Unboosted are the average time from coming back from
+	// I/O to getting the CPU, with and without the boost.
+	Response  float64
+	Unboosted float64
+	// Turnaround and UnboostedTurnaround are the average turnaround of the
+	// processes that never block for I/O, with and without the boost.
+	Turnaround          float64
+	UnboostedTurnaround float64
+}
+
+// NewBoostRow compares result, run with an I/O boost, with unboosted, the
+// same run without one.
+func NewBoostRow(name string, result, unboosted Result) BoostRow {
+	r := BoostRow{Name: name}
+	r.Response, r.Wakes = ioResponse(result.Events)
+	r.Unboosted, _ = ioResponse(unboosted.Events)
+	r.Turnaround = cpuBoundTurnaround(result)
+	r.UnboostedTurnaround = cpuBoundTurnaround(unboosted)
+	return r
+}
+
+// ioResponse is the average time from a process coming back from I/O to
+// its next dispatch, and how many times one came back.
+func ioResponse(events []Event) (float64, int) {
+	var (
+		blocked = map[int64]bool{}
+		woke    = map[int64]int64{}
+		sum     int64
+		wakes   int
+	)
+	for _, ev := range events {
+		switch ev.Kind {
+		case EventBlock:
+			blocked[ev.PID] = true
+		case EventWake:
+			// A wake also readies a process whose dependencies are done.
+			if blocked[ev.PID] {
+				delete(blocked, ev.PID)
+				woke[ev.PID] = ev.Time
+			}
+		case EventDispatch:
+			if at, ok := woke[ev.PID]; ok {
+				sum += ev.Time - at
+				wakes++
+				delete(woke, ev.PID)
+			}
+		}
+	}
+	if wakes == 0 {
+		return 0, 0
+	}
+	return float64(sum) / float64(wakes), wakes
+}
+
+// cpuBoundTurnaround is the average turnaround of the processes in result
+// that never blocked for I/O.
+func cpuBoundTurnaround(result Result) float64 {
+	blocked := map[int64]bool{}
+	for _, ev := range result.Events {
+		if ev.Kind == EventBlock {
+			blocked[ev.PID] = true
+		}
+	}
+	var (
+		sum int64
+		n   int
+	)
+	for _, row := range result.Schedule {
+		if !blocked[row.ProcessID] {
+			sum += row.Turnaround
+			n++
+		}
+	}
+	if n == 0 {
+		return 0
+	}
+	return float64(sum) / float64(n)
+}
+
+// outputBoost prints what the I/O boost did for the processes coming back
+// from I/O under each algorithm, and what it cost the rest.
+func outputBoost(w io.Writer, levels int64, rows []BoostRow) {
+	outputTitle(w, fmt.Sprintf("I/O boost of %d levels", levels))
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Algorithm", "Wakes", "Response after I/O", "Without boost", "Change", "CPU-bound turnaround", "Without boost"})
+	change := func(with, without float64) string {
+		if without == 0 {
+			return "-"
+		}
+		return fmt.Sprintf("%+.1f%%", (with-without)/without*100)
+	}
+	for _, r := range rows {
+		turnaround, unboosted := "-", "-"
+		if r.Turnaround > 0 {
+			turnaround, unboosted = fmt.Sprintf("%.2f", r.Turnaround), fmt.Sprintf("%.2f", r.UnboostedTurnaround)
+		}
+		table.Append([]string{
+			r.Name, fmt.Sprint(r.Wakes), fmt.Sprintf("%.2f", r.Response), fmt.Sprintf("%.2f", r.Unboosted),
+			change(r.Response, r.Unboosted), turnaround, unboosted,
+		})
+	}
+	table.Render()
+}
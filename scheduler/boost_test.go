@@ -0,0 +1,87 @@
+package scheduler
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSimulate_ioBoost(t *testing.T) {
+	t.Parallel()
+	// P1 runs a tick, waits 2 on I/O and runs another; P2, more important,
+	// takes the CPU meanwhile and keeps it unless P1's boost beats it.
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 2, Priority: 5, Bursts: []int64{1, 2, 1}},
+		NewProcess(2, 1, 8, 3),
+	}
+	tests := []struct {
+		name    string
+		boost   int64
+		exit    int64
+		boosted []int64
+	}{
+		{name: "off", exit: 10},
+		{name: "too weak", boost: 1, exit: 10, boosted: []int64{4}},
+		{name: "3 levels", boost: 3, exit: 4, boosted: []int64{2}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := Simulate(processes, priorityPolicy{}, EngineOptions{IOBoost: tt.boost})
+			for _, row := range got.Schedule {
+				if row.ProcessID == 1 && row.Exit != tt.exit {
+					t.Errorf("P1 exits at %d, want %d", row.Exit, tt.exit)
+				}
+			}
+			var boosted []int64
+			for _, ev := range got.Events {
+				if ev.Kind == EventBoost && ev.PID == 1 {
+					boosted = append(boosted, ev.Priority)
+				}
+			}
+			if !reflect.DeepEqual(boosted, tt.boosted) {
+				t.Errorf("P1 boosted to %v, want %v", boosted, tt.boosted)
+			}
+		})
+	}
+}
+
+func TestNewBoostRow(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 2, Priority: 5, Bursts: []int64{1, 2, 1}},
+		NewProcess(2, 1, 8, 3),
+	}
+	boosted := Simulate(processes, priorityPolicy{}, EngineOptions{IOBoost: 3})
+	unboosted := Simulate(processes, priorityPolicy{}, EngineOptions{})
+	got := NewBoostRow("priority", boosted, unboosted)
+	// P1 wakes at 3 and waits for P2 to finish at 9 without the boost; P2
+	// finishes a tick later with it.
+	want := BoostRow{Name: "priority", Wakes: 1, Response: 0, Unboosted: 6, Turnaround: 9, UnboostedTurnaround: 8}
+	if got != want {
+		t.Errorf("NewBoostRow() = %+v, want %+v", got, want)
+	}
+
+	var buf bytes.Buffer
+	outputBoost(&buf, 3, []BoostRow{got})
+	for _, s := range []string{"I/O boost of 3 levels", "-100.0%"} {
+		if !strings.Contains(buf.String(), s) {
+			t.Errorf("outputBoost() missing %q:\n%s", s, buf.String())
+		}
+	}
+}
+
+func Test_ioResponse_dependencies(t *testing.T) {
+	t.Parallel()
+	// A wake that readies a process after its dependencies is no I/O.
+	events := []Event{
+		{Time: 0, Kind: EventDepWait, PID: 2},
+		{Time: 4, Kind: EventWake, PID: 2},
+		{Time: 6, Kind: EventDispatch, PID: 2},
+	}
+	if mean, wakes := ioResponse(events); mean != 0 || wakes != 0 {
+		t.Errorf("ioResponse() = %v, %d, want 0, 0", mean, wakes)
+	}
+}
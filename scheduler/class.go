@@ -0,0 +1,55 @@
+package scheduler
+
+import (
+	"fmt"
+	"io"
+)
+
+// ClassAverage is how the finished processes of one class fared on
+// average, to compare interactive work with batch work in the same run.
+type ClassAverage struct {
+	Class      ProcessClass `json:"class"`
+	Processes  int          `json:"processes"`
+	Turnaround float64      `json:"turnaround"`
+	Response   float64      `json:"response"`
+}
+
+// ClassAverages averages the turnaround and response time of rows by
+// class, batch first. It is empty unless rows hold more than one class,
+// as the overall averages already cover a run with only one.
+func ClassAverages(rows []ProcessResult) []ClassAverage {
+	var (
+		averages = []ClassAverage{{Class: ClassBatch}, {Class: ClassInteractive}}
+		sums     = make([][2]int64, len(averages))
+	)
+	for _, r := range rows {
+		i := int(r.Class)
+		if i < 0 || i >= len(averages) {
+			continue
+		}
+		averages[i].Processes++
+		sums[i][0] += r.Turnaround
+		sums[i][1] += r.Response
+	}
+	for i := range averages {
+		if averages[i].Processes == 0 {
+			return nil
+		}
+		n := float64(averages[i].Processes)
+		averages[i].Turnaround = float64(sums[i][0]) / n
+		averages[i].Response = float64(sums[i][1]) / n
+	}
+	return averages
+}
+
+// outputClasses prints the averages of each class, one line each.
+func outputClasses(w io.Writer, averages []ClassAverage) {
+	if len(averages) == 0 {
+		return
+	}
+	_, _ = fmt.Fprintln(w, "By class:")
+	for _, a := range averages {
+		_, _ = fmt.Fprintf(w, "  %s: %d finished, average turnaround %.2f, response %.2f\n",
+			a.Class, a.Processes, a.Turnaround, a.Response)
+	}
+}
@@ -0,0 +1,54 @@
+package scheduler
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestClassAverages(t *testing.T) {
+	t.Parallel()
+	// Under FCFS the two short interactive processes wait behind the batch
+	// job that arrived first.
+	processes := []Process{
+		NewProcess(1, 0, 8, 0),
+		NewProcess(2, 1, 2, 0),
+		NewProcess(3, 2, 2, 0),
+		NewProcess(4, 3, 4, 0),
+	}
+	processes[1].Class, processes[2].Class = ClassInteractive, ClassInteractive
+	result := Simulate(processes, fcfsPolicy{}, EngineOptions{})
+
+	want := []ClassAverage{
+		{Class: ClassBatch, Processes: 2, Turnaround: (8 + 13) / 2.0, Response: (0 + 9) / 2.0},
+		{Class: ClassInteractive, Processes: 2, Turnaround: (9 + 10) / 2.0, Response: (7 + 8) / 2.0},
+	}
+	if got := ClassAverages(result.Schedule); !reflect.DeepEqual(got, want) {
+		t.Errorf("ClassAverages() = %+v, want %+v", got, want)
+	}
+	if got := ClassAverages(result.Schedule[:1]); got != nil {
+		t.Errorf("ClassAverages() of one class = %+v, want none", got)
+	}
+
+	var buf bytes.Buffer
+	Render(&buf, result, RenderOptions{})
+	for _, line := range []string{
+		"By class:\n",
+		"  batch: 2 finished, average turnaround 10.50, response 4.50\n",
+		"  interactive: 2 finished, average turnaround 9.50, response 7.50\n",
+	} {
+		if !strings.Contains(buf.String(), line) {
+			t.Errorf("Render() is missing %q:\n%s", line, buf.String())
+		}
+	}
+
+	out, err := json.Marshal(result.Schedule[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(out), `"class":"interactive"`) {
+		t.Errorf("JSON of an interactive row = %s, want its class", out)
+	}
+}
@@ -0,0 +1,449 @@
+package scheduler
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/template"
+	"time"
+)
+
+// cliConfig is the configuration of the default command: the flags as
+// given, then the options parseCLIFlags builds from them.
+type cliConfig struct {
+	// Output
+	mergeGantt   bool
+	view         string
+	quiet        bool
+	verbose      bool
+	summaryOnly  string
+	templatePath string
+	maxRows      int
+	animate      bool
+	animateSpeed float64
+	noProgress   bool
+	reportSpec   *string
+	ganttFlags   ganttFlags
+
+	// Files written alongside the output
+	queueCSV     string
+	series       string
+	seriesWindow int64
+	dbPath       string
+	switchTrace  string
+	mermaid      string
+	dot          string
+	chromeTrace  string
+	svg          string
+	save         string
+	streamGantt  string
+	forkPoint    string
+	forkAt       int64
+
+	// The workload
+	lenient   bool
+	delimiter string
+	tickUnit  time.Duration
+	periods   int
+	inject    injectFlag
+
+	// The algorithms
+	algo        string
+	policyFile  string
+	metricNames string
+	minSharePct float64
+	shareWindow int64
+	seed        *int64
+	algoFlags   algorithmFlags
+
+	// The engine
+	maxTime        int64
+	lockProtocol   string
+	resources      string
+	deadlock       string
+	semaphores     string
+	cpus           int
+	threadMode     string
+	balance        string
+	stealThreshold int
+	steal          string
+	freq           string
+	power          string
+	onMiss         string
+	closedJobs     int
+	think          int64
+	warmup         int64
+	warmupJobs     int
+	dispatchCost   int64
+	aging          int64
+	ioBoost        int64
+	timeout        time.Duration
+	starvation     int64
+	parallel       int
+
+	// Perturbation
+	perturb int
+	jitter  float64
+
+	// Built from the flags by parseCLIFlags
+	runs       []Algorithm
+	metrics    []RegisteredMetric
+	report     ReportSections
+	tmpl       *template.Template
+	algoOpts   AlgorithmOptions
+	engineOpts EngineOptions
+	// freqAuto picks each run's frequency for the lowest energy-delay
+	// product, for -freq min-edp.
+	freqAuto bool
+	comma    rune
+	mode     ThreadMode
+}
+
+// addCLIFlags registers the default command's flags on fs, to be read into
+// the returned config.
+func addCLIFlags(fs *flag.FlagSet) *cliConfig {
+	c := &cliConfig{}
+	fs.BoolVar(&c.mergeGantt, "merge-gantt", false, "merge consecutive Gantt slices of the same process")
+	c.reportSpec = addReportFlag(fs)
+	fs.StringVar(&c.view, "view", "gantt", "draw the schedule as a gantt chart of the CPUs or as lanes, one per process")
+	fs.StringVar(&c.queueCSV, "queue-csv", "", "write ready/blocked queue lengths over time to this CSV file")
+	fs.StringVar(&c.series, "series", "", "write completions and average queue lengths per window of time to this file, as JSON if it ends in .json and CSV otherwise")
+	fs.Int64Var(&c.seriesWindow, "series-window", 1, "ticks in each window of -series")
+	fs.StringVar(&c.dbPath, "db", "", "append each run's workload hash, algorithm, flags and metrics to this SQLite database, for the history subcommand")
+	fs.StringVar(&c.algo, "algo", "", "comma-separated algorithms to run (default all)")
+	fs.StringVar(&c.metricNames, "metrics", "", "comma-separated custom metrics to measure each run by, or all; see the list subcommand")
+	fs.StringVar(&c.switchTrace, "switch-trace", "", "write an ftrace-style context-switch trace to this file")
+	fs.StringVar(&c.mermaid, "mermaid", "", "write each Gantt chart as a Mermaid gantt definition to this file")
+	fs.StringVar(&c.dot, "dot", "", "write each Gantt chart as a Graphviz timeline to this file")
+	fs.StringVar(&c.chromeTrace, "chrome-trace", "", "write each Gantt chart as Chrome trace-event JSON, for chrome://tracing or Perfetto, to this file")
+	fs.StringVar(&c.svg, "svg", "", "write each Gantt chart as an SVG image to this file")
+	fs.StringVar(&c.save, "save", "", "save each algorithm's result to this file, for the load subcommand to render again")
+	fs.Float64Var(&c.minSharePct, "min-share", 0, "guaranteed CPU percentage per process for minshare, audited for every algorithm")
+	fs.Int64Var(&c.shareWindow, "share-window", defaultShareWindow, "sliding window the minimum share applies over")
+	fs.Int64Var(&c.maxTime, "max-time", 0, "stop each simulation at this tick even if processes remain")
+	fs.StringVar(&c.lockProtocol, "lock-protocol", "none", "raise lock holders' priority: none, inherit or ceiling")
+	fs.StringVar(&c.resources, "resources", "", "instances of each resource with more than one, as name=N,...")
+	fs.StringVar(&c.deadlock, "deadlock", "ignore", "deal with deadlocks over resources: ignore, detect or banker to avoid them")
+	fs.StringVar(&c.semaphores, "semaphores", "", "initial value of each semaphore not starting at 0, as name=N,...")
+	fs.IntVar(&c.cpus, "cpus", 1, "number of CPUs; processes with several threads are gang scheduled")
+	fs.StringVar(&c.threadMode, "thread-mode", "gang", "run a process's threads all at once (gang) or schedule each on its own (independent)")
+	fs.StringVar(&c.balance, "balance", "global", "share processes between CPUs with one global queue or percore queues")
+	fs.IntVar(&c.stealThreshold, "steal-threshold", 1, "with percore queues, only steal from a queue holding at least this many processes")
+	fs.StringVar(&c.steal, "steal", "one", "with percore queues, an idle CPU steals one process or half of a queue")
+	fs.StringVar(&c.freq, "freq", "", "model energy at this fraction of the full CPU frequency, or min-edp to pick the lowest energy-delay product")
+	fs.StringVar(&c.power, "power", "", "static,dynamic,idle power of the energy model (default 0.2,1,0.05)")
+	fs.IntVar(&c.periods, "periods", 0, "release this many jobs of each periodic task instead of a hyperperiod's worth")
+	fs.StringVar(&c.onMiss, "on-miss", "continue", "when a process misses its deadline, continue or abort the simulation")
+	fs.StringVar(&c.policyFile, "policy-file", "", "run the scheduler defined in this policy file, alone unless -algo is given")
+	fs.BoolVar(&c.lenient, "lenient", false, "fix invalid processes with a warning instead of rejecting the workload")
+	fs.StringVar(&c.delimiter, "delimiter", "auto", "field separator of the process file: auto, comma, semicolon or tab")
+	fs.DurationVar(&c.tickUnit, "tick-unit", defaultTickUnit, "real time one tick stands for, that process file times with a unit such as 5ms are converted by")
+	fs.BoolVar(&c.verbose, "v", false, "log why each process is dispatched, preempted or taken off the CPU")
+	fs.BoolVar(&c.quiet, "q", false, "print only each algorithm's schedule table")
+	fs.StringVar(&c.summaryOnly, "summary-only", "", "print only one line of metrics per algorithm, in this format: tsv")
+	fs.StringVar(&c.templatePath, "template", "", "print each algorithm's result through this text/template file instead")
+	fs.IntVar(&c.perturb, "perturb", 0, "instead of one schedule, summarise each metric over this many jittered copies of the workload")
+	fs.Float64Var(&c.jitter, "jitter", 0.2, "largest relative change to bursts and arrivals with -perturb")
+	c.seed = addSeedFlag(fs, "random seed for lottery scheduling and -perturb")
+	fs.StringVar(&c.streamGantt, "stream-gantt", "", "write Gantt slices to this CSV file as they are simulated instead of keeping them, for very long schedules")
+	fs.IntVar(&c.maxRows, "max-rows", 0, "print only the averages of schedule tables with more rows than this; 0 prints every row")
+	fs.IntVar(&c.parallel, "parallel", 0, "simulate up to this many algorithms, or -perturb runs, at once; 0 means one per CPU")
+	fs.BoolVar(&c.animate, "animate", false, "replay each schedule in real time before printing it, showing the running process, ready queue and Gantt chart")
+	fs.Float64Var(&c.animateSpeed, "animate-speed", defaultAnimateSpeed, "ticks a second that -animate plays")
+	fs.StringVar(&c.forkPoint, "fork-point", "", "instead of running, write the run's inputs and state at -fork-at to this file, for the fork subcommand to replay and fork")
+	fs.Int64Var(&c.forkAt, "fork-at", 0, "tick of -fork-point")
+	fs.IntVar(&c.closedJobs, "closed-jobs", 0, "run a closed system: each process is a user submitting this many jobs in turn")
+	fs.Int64Var(&c.think, "think", 0, "with -closed-jobs, ticks a user thinks between a job completing and submitting the next")
+	fs.Int64Var(&c.warmup, "warmup", 0, "leave processes arriving before this tick out of averages, percentiles and throughput")
+	fs.Int64Var(&c.dispatchCost, "dispatch-cost", 0, "ticks the scheduler takes to decide, charged on every dispatch")
+	fs.Int64Var(&c.aging, "aging", 0, "raise a waiting process's priority a level every this many ticks, for priority and mlfq")
+	fs.Int64Var(&c.ioBoost, "io-boost", 0, "raise a process's priority this many levels when it comes back from I/O, until it has run, for priority")
+	fs.IntVar(&c.warmupJobs, "warmup-jobs", 0, "leave the first this many processes to complete out of averages, percentiles and throughput")
+	fs.BoolVar(&c.noProgress, "no-progress", false, "don't report progress on stderr while simulating large workloads")
+	fs.DurationVar(&c.timeout, "timeout", 0, "stop each simulation after this much real time, printing the partial schedule; 0 never does")
+	fs.Int64Var(&c.starvation, "starvation", 0, "exit with code 6 if a process waits more than this many ticks; 0 never does")
+	fs.Var(&c.inject, "inject", "add a process mid-run, unknown to the policies in advance: at=T,pid=N,burst=B[,priority=P]; repeatable")
+	c.ganttFlags = addGanttFlags(fs)
+	c.algoFlags = addAlgorithmFlags(fs)
+	return c
+}
+
+// parseCLIFlags parses the default command's flags from args, checks them,
+// and builds the options they give.
+func parseCLIFlags(fs *flag.FlagSet, args []string) (*cliConfig, error) {
+	c := addCLIFlags(fs)
+	if err := parseFlags(fs, "", args); err != nil {
+		return nil, err
+	}
+	if err := c.check(); err != nil {
+		return nil, err
+	}
+	if err := c.build(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// check rejects flags that are out of range or cannot be combined.
+func (c *cliConfig) check() error {
+	if c.verbose && c.quiet {
+		return fmt.Errorf("%w: -v and -q cannot be combined", ErrInvalidArgs)
+	}
+	if c.summaryOnly != "" {
+		if c.summaryOnly != "tsv" {
+			return fmt.Errorf("%w: -summary-only supports tsv, not %q", ErrInvalidArgs, c.summaryOnly)
+		}
+		if c.templatePath != "" || c.animate {
+			return fmt.Errorf("%w: -summary-only cannot be combined with -template or -animate", ErrInvalidArgs)
+		}
+		// A summary leaves out even what -q keeps.
+		c.quiet = true
+	}
+	if c.streamGantt != "" && (c.mermaid != "" || c.dot != "" || c.chromeTrace != "" || c.svg != "" || c.save != "") {
+		return fmt.Errorf("%w: -stream-gantt keeps no chart for -mermaid, -dot, -chrome-trace, -svg or -save", ErrInvalidArgs)
+	}
+	if c.streamGantt != "" && c.animate {
+		return fmt.Errorf("%w: -stream-gantt keeps no chart for -animate", ErrInvalidArgs)
+	}
+	if c.streamGantt != "" && (c.queueCSV != "" || c.series != "" || c.view == "lanes" || c.metricNames != "") {
+		return fmt.Errorf("%w: -stream-gantt keeps no event log for -queue-csv, -series, -view lanes or -metrics", ErrInvalidArgs)
+	}
+	if c.view != "gantt" && c.view != "lanes" {
+		return fmt.Errorf("%w: -view must be gantt or lanes, not %q", ErrInvalidArgs, c.view)
+	}
+	if c.animateSpeed <= 0 {
+		return fmt.Errorf("%w: -animate-speed must be positive", ErrInvalidArgs)
+	}
+	if c.cpus < 1 {
+		return fmt.Errorf("%w: -cpus must be at least 1", ErrInvalidArgs)
+	}
+	if c.closedJobs < 0 || c.think < 0 {
+		return fmt.Errorf("%w: -closed-jobs and -think cannot be negative", ErrInvalidArgs)
+	}
+	if c.seriesWindow < 1 {
+		return fmt.Errorf("%w: -series-window must be at least 1", ErrInvalidArgs)
+	}
+	// One run has no spread to put a confidence interval on.
+	if c.perturb == 1 || c.perturb < 0 {
+		return fmt.Errorf("%w: -perturb must be at least 2", ErrInvalidArgs)
+	}
+	if c.think > 0 && c.closedJobs == 0 {
+		return fmt.Errorf("%w: -think needs -closed-jobs", ErrInvalidArgs)
+	}
+	if c.warmup < 0 || c.warmupJobs < 0 {
+		return fmt.Errorf("%w: -warmup and -warmup-jobs cannot be negative", ErrInvalidArgs)
+	}
+	if c.aging < 0 || c.ioBoost < 0 || c.dispatchCost < 0 {
+		return fmt.Errorf("%w: -aging, -io-boost and -dispatch-cost cannot be negative", ErrInvalidArgs)
+	}
+	if c.starvation < 0 {
+		return fmt.Errorf("%w: -starvation cannot be negative", ErrInvalidArgs)
+	}
+	return nil
+}
+
+// build fills in the options the flags give: the algorithms to run and
+// their options, the engine's, and how to read and report the workload.
+func (c *cliConfig) build() error {
+	var err error
+	if c.runs, err = selectRuns(c.algo); err != nil {
+		return err
+	}
+	if c.metrics, err = selectMetrics(c.metricNames); err != nil {
+		return err
+	}
+	if c.report, err = parseReportSections(*c.reportSpec); err != nil {
+		return err
+	}
+	if c.templatePath != "" {
+		if c.tmpl, err = loadTemplate(c.templatePath); err != nil {
+			return err
+		}
+	}
+	if c.comma, err = parseDelimiter(c.delimiter); err != nil {
+		return err
+	}
+	if c.mode, err = parseThreadMode(c.threadMode); err != nil {
+		return err
+	}
+	if c.algoOpts, err = c.algoFlags.options(); err != nil {
+		return err
+	}
+	c.algoOpts.MinShare = MinShareOptions{Share: c.minSharePct / 100, Window: c.shareWindow}
+	*c.seed = pickSeed(os.Stderr, *c.seed)
+	c.algoOpts.Seed = *c.seed
+	if err := c.buildEngineOptions(); err != nil {
+		return err
+	}
+	if c.policyFile != "" {
+		script, err := LoadScriptPolicy(c.policyFile)
+		if err != nil {
+			return err
+		}
+		if c.algo == "" {
+			c.runs = nil
+		}
+		c.runs = append(c.runs, Algorithm{Name: script.Name, Factory: Factory{
+			Title: "Policy file " + script.Name,
+			New:   func(p []Process, _ AlgorithmOptions) Policy { return script.New(p) },
+		}})
+	}
+	return nil
+}
+
+// buildEngineOptions fills in engineOpts and freqAuto.
+func (c *cliConfig) buildEngineOptions() error {
+	c.engineOpts = EngineOptions{
+		MaxTime:      c.maxTime,
+		CPUs:         c.cpus,
+		Closed:       ClosedOptions{Jobs: c.closedJobs, Think: c.think},
+		Warmup:       Warmup{Time: c.warmup, Jobs: c.warmupJobs},
+		Aging:        c.aging,
+		IOBoost:      c.ioBoost,
+		DispatchCost: c.dispatchCost,
+	}
+	opts, err := &c.engineOpts, error(nil)
+	if c.verbose {
+		opts.Log = NewLogger(os.Stderr)
+	}
+	if opts.Locking, err = parseLockProtocol(c.lockProtocol); err != nil {
+		return err
+	}
+	if opts.Resources, err = parseResources(c.resources); err != nil {
+		return err
+	}
+	if opts.Deadlock, err = parseDeadlockMode(c.deadlock); err != nil {
+		return err
+	}
+	if opts.Semaphores, err = parseSemaphores(c.semaphores); err != nil {
+		return err
+	}
+	if opts.Balance, err = parseBalance(c.balance); err != nil {
+		return err
+	}
+	opts.Steal.Threshold = c.stealThreshold
+	if opts.Steal.Half, err = parseSteal(c.steal); err != nil {
+		return err
+	}
+	if opts.AbortOnMiss, err = parseOnMiss(c.onMiss); err != nil {
+		return err
+	}
+	if opts.Energy.Frequency, c.freqAuto, err = parseFrequency(c.freq); err != nil {
+		return err
+	}
+	if err := parsePower(c.power, &opts.Energy); err != nil {
+		return err
+	}
+	if c.power != "" && opts.Energy.Frequency == 0 {
+		opts.Energy.Frequency = 1
+	}
+	return nil
+}
+
+// cliWorkload is the workload the default command simulates.
+type cliWorkload struct {
+	// processes are what the policies are built from, and workload what is
+	// simulated: processes and the injected ones, so that those take the
+	// policies by surprise.
+	processes, workload []Process
+	// parents are the processes before their threads were split off to be
+	// scheduled independently, and threadsOf their threads' PIDs; it is
+	// nil when the threads were not split.
+	parents   []Process
+	threadsOf map[int64][]int64
+	expansion Expansion
+}
+
+// loadWorkload reads the process file f, checks it, and expands it as the
+// flags say.
+func (c *cliConfig) loadWorkload(f io.Reader) (cliWorkload, error) {
+	processes, err := loadProcessFile(f, ProcessFileOptions{Comma: c.comma, Tick: c.tickUnit})
+	if err != nil {
+		return cliWorkload{}, err
+	}
+	if processes, err = checkProcesses(processes, c.lenient, os.Stderr); err != nil {
+		return cliWorkload{}, err
+	}
+	// Periodic tasks are simulated as the jobs they release.
+	var l cliWorkload
+	if processes, _, l.expansion, err = expandPeriodic(processes, c.periods); err != nil {
+		return cliWorkload{}, err
+	}
+	warnExpansion(os.Stderr, l.expansion)
+	// Threads scheduled independently are simulated as processes of their
+	// own, and gathered back into theirs under each schedule.
+	l.parents = processes
+	if c.mode == ThreadsIndependent {
+		processes, l.threadsOf = splitThreads(processes)
+	}
+	l.processes, l.workload = processes, injectProcesses(processes, c.inject)
+	if len(c.inject) > 0 {
+		if l.workload, err = checkProcesses(l.workload, false, os.Stderr); err != nil {
+			return cliWorkload{}, err
+		}
+	}
+	if err := checkResources(l.workload, c.engineOpts.Resources); err != nil {
+		return cliWorkload{}, err
+	}
+	return l, nil
+}
+
+// writeFiles writes the queue lengths, series, saved results, traces and
+// chart exports the flags ask for, of the runs named names.
+func (c *cliConfig) writeFiles(names, titles []string, results []Result) error {
+	// Queue-length series for plotting
+	if c.queueCSV != "" {
+		err := writeFile(c.queueCSV, func(w io.Writer) error {
+			return writeQueueCSV(w, names, results)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if c.series != "" {
+		if err := writeSeries(c.series, names, results, c.seriesWindow); err != nil {
+			return err
+		}
+	}
+
+	// Results saved for the load subcommand, one file per algorithm
+	if c.save != "" {
+		for i := range results {
+			f := ResultFile{Algorithm: names[i], Title: titles[i], Result: results[i]}
+			err := writeFile(perAlgorithmPath(c.save, names[i], len(results) > 1), func(w io.Writer) error {
+				return writeResultFile(w, f)
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	// Context-switch traces and chart exports, one file per algorithm
+	exports := []struct {
+		path  string
+		write func(w io.Writer, title string, result Result) error
+	}{
+		{c.switchTrace, func(w io.Writer, _ string, result Result) error { return writeSwitchTrace(w, result) }},
+		{c.mermaid, writeMermaid},
+		{c.dot, writeDot},
+		{c.chromeTrace, writeChromeTrace},
+		{c.svg, writeSVG},
+	}
+	for _, export := range exports {
+		if export.path == "" {
+			continue
+		}
+		for i := range results {
+			write, title, result := export.write, titles[i], results[i]
+			err := writeFile(perAlgorithmPath(export.path, names[i], len(results) > 1), func(w io.Writer) error {
+				return write(w, title, result)
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package scheduler
+
+import (
+	"errors"
+	"flag"
+	"testing"
+)
+
+func Test_parseCLIFlags(t *testing.T) {
+	t.Parallel()
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	c, err := parseCLIFlags(fs, []string{"-algo", "rr,fcfs", "-quantum", "3", "-cpus", "2", "-q", "-report", "slowdown", "workload.csv"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(c.runs) != 2 || c.runs[0].Name != "rr" || c.runs[1].Name != "fcfs" {
+		t.Errorf("runs = %v, want rr and fcfs", c.runs)
+	}
+	if c.algoOpts.RR.Quantum != 3 || c.algoOpts.Seed != defaultSeed {
+		t.Errorf("algoOpts = %+v, want quantum 3 and seed %d", c.algoOpts, defaultSeed)
+	}
+	if c.engineOpts.CPUs != 2 || !c.quiet || c.report != ReportSlowdown {
+		t.Errorf("cpus, quiet, report = %d, %v, %v, want 2, true, slowdown", c.engineOpts.CPUs, c.quiet, c.report)
+	}
+	if got := fs.Args(); len(got) != 1 || got[0] != "workload.csv" {
+		t.Errorf("fs.Args() = %v, want the process file", got)
+	}
+}
+
+func Test_parseCLIFlags_invalid(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{name: "verbose and quiet", args: []string{"-v", "-q"}},
+		{name: "unknown view", args: []string{"-view", "pie"}},
+		{name: "one perturbed run", args: []string{"-perturb", "1"}},
+		{name: "think without closed jobs", args: []string{"-think", "3"}},
+		{name: "streamed chart exported", args: []string{"-stream-gantt", "g.csv", "-svg", "g.svg"}},
+		{name: "unknown algorithm", args: []string{"-algo", "fifo"}},
+		{name: "bad lock protocol", args: []string{"-lock-protocol", "steal"}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if _, err := parseCLIFlags(flag.NewFlagSet("", flag.ContinueOnError), tt.args); !errors.Is(err, ErrInvalidArgs) {
+				t.Errorf("parseCLIFlags(%q) error = %v, want %v", tt.args, err, ErrInvalidArgs)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package scheduler
+
+import (
+	"fmt"
+	"io"
+	"sort"
+)
+
+type (
+	// ClosedOptions makes a run a closed system: each process in the
+	// workload is a user who, Think ticks after each of their jobs
+	// completes, submits another like it, until they have submitted Jobs.
+	// Zero Jobs leaves the workload open, arriving as written.
+	ClosedOptions struct {
+		Jobs  int   `json:"jobs,omitempty"`
+		Think int64 `json:"think,omitempty"`
+	}
+	// ClosedStats sums up a closed run. Throughput is the jobs completed a
+	// tick and Response their average turnaround; by the response time law
+	// a steady closed system has Response = Users/Throughput - Think, which
+	// Law gives for comparison.
+	ClosedStats struct {
+		Users      int
+		Think      int64
+		Jobs       int
+		Completed  int
+		Throughput float64
+		Response   float64
+		Law        float64
+	}
+)
+
+// closed reports whether the run resubmits jobs.
+func (o ClosedOptions) closed() bool { return o.Jobs > 0 }
+
+// resubmit queues the next job of t's user, if they have any left, to
+// arrive Think ticks from now. It runs as a copy of the user's first job
+// under the next free PID.
+func (e *engine) resubmit(t *Task) {
+	if !e.opts.Closed.closed() || t.job >= e.opts.Closed.Jobs {
+		return
+	}
+	if e.nextPID == 0 {
+		for _, u := range e.tasks {
+			if u.ProcessID >= e.nextPID {
+				e.nextPID = u.ProcessID + 1
+			}
+		}
+	}
+	job := &Task{
+		Process:           t.Process,
+		bursts:            t.bursts,
+		lockOps:           t.lockOps,
+		claims:            t.claims,
+		semOps:            t.semOps,
+		threads:           t.threads,
+		mask:              t.mask,
+		user:              t.user,
+		job:               t.job + 1,
+		EffectivePriority: t.Priority,
+	}
+	job.ProcessID, job.ArrivalTime = e.nextPID, e.now+e.opts.Closed.Think
+	job.DependsOn, job.Suspend = nil, nil
+	job.Remaining = job.bursts[0]
+	e.nextPID++
+	e.tasks = append(e.tasks, job)
+	i := sort.Search(len(e.arrivals), func(i int) bool { return e.arrivals[i].ArrivalTime > job.ArrivalTime })
+	e.arrivals = append(e.arrivals, nil)
+	copy(e.arrivals[i+1:], e.arrivals[i:])
+	e.arrivals[i] = job
+	e.opts.Log.Log(e.now, "resubmit", "user", t.user, "pid", job.ProcessID, "arrival", job.ArrivalTime)
+}
+
+// closedStats measures a closed run from its completed jobs.
+func (e *engine) closedStats(schedule []ProcessResult, m Metrics) ClosedStats {
+	if !e.opts.Closed.closed() {
+		return ClosedStats{}
+	}
+	s := ClosedStats{Think: e.opts.Closed.Think, Jobs: e.opts.Closed.Jobs, Completed: len(schedule)}
+	for _, t := range e.tasks {
+		if t.job == 1 {
+			s.Users++
+		}
+	}
+	s.Throughput, s.Response = m.Throughput, m.AverageTurnaround
+	if s.Throughput > 0 {
+		s.Law = float64(s.Users)/s.Throughput - float64(s.Think)
+	}
+	return s
+}
+
+// outputClosed prints the throughput and response time of a closed run.
+func outputClosed(w io.Writer, s ClosedStats) {
+	if s.Users == 0 {
+		return
+	}
+	_, _ = fmt.Fprintf(w, "Closed system: %d users, think %d, %d jobs each; %d completed, throughput %.3f, response %.2f (N/X - Z = %.2f)\n",
+		s.Users, s.Think, s.Jobs, s.Completed, s.Throughput, s.Response, s.Law)
+}
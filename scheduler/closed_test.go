@@ -0,0 +1,73 @@
+package scheduler
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestSimulate_closed(t *testing.T) {
+	t.Parallel()
+	processes := []Process{NewProcess(1, 0, 4, 0), NewProcess(2, 0, 2, 0)}
+	tests := []struct {
+		name    string
+		opts    ClosedOptions
+		arrival map[int64]int64
+		users   int
+	}{
+		{
+			name:    "open",
+			arrival: map[int64]int64{1: 0, 2: 0},
+		},
+		{
+			// Under FCFS P1 ends at 4 and P2 at 6; each user's next job is
+			// a copy of their first, arriving a think time later.
+			name:    "closed",
+			opts:    ClosedOptions{Jobs: 2, Think: 3},
+			arrival: map[int64]int64{1: 0, 2: 0, 3: 7, 4: 9},
+			users:   2,
+		},
+		{
+			name:    "no think time",
+			opts:    ClosedOptions{Jobs: 3},
+			arrival: map[int64]int64{1: 0, 2: 0, 3: 4, 4: 6, 5: 10, 6: 12},
+			users:   2,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := Simulate(processes, fcfsPolicy{}, EngineOptions{Closed: tt.opts})
+			arrival := map[int64]int64{}
+			for _, row := range got.Schedule {
+				arrival[row.ProcessID] = row.Arrival
+			}
+			if !reflect.DeepEqual(arrival, tt.arrival) {
+				t.Errorf("arrivals = %v, want %v", arrival, tt.arrival)
+			}
+			if tt.users == 0 {
+				if got.Closed != (ClosedStats{}) {
+					t.Errorf("Closed = %+v for an open run", got.Closed)
+				}
+				return
+			}
+			if got.Closed.Users != tt.users || got.Closed.Completed != len(tt.arrival) {
+				t.Errorf("Closed = %+v, want %d users completing %d jobs", got.Closed, tt.users, len(tt.arrival))
+			}
+		})
+	}
+}
+
+func TestSimulate_closedLaw(t *testing.T) {
+	t.Parallel()
+	// One user alone never waits; the last of its jobs ends at 14.
+	got := Simulate([]Process{NewProcess(1, 0, 2, 0)}, fcfsPolicy{}, EngineOptions{Closed: ClosedOptions{Jobs: 4, Think: 2}})
+	s := got.Closed
+	if s.Completed != 4 || s.Response != 2 {
+		t.Fatalf("Closed = %+v, want 4 jobs of response 2", s)
+	}
+	if math.Abs(s.Throughput-4.0/14) > 1e-9 || math.Abs(s.Law-1.5) > 1e-9 {
+		t.Errorf("Closed = %+v, want throughput 4/14 and N/X - Z = 1.5", s)
+	}
+}
@@ -0,0 +1,65 @@
+package scheduler
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// pidColors are the ANSI foreground colours PIDs are given in turn, so a
+// process keeps the same colour in the Gantt chart and the schedule table.
+var pidColors = []int{31, 32, 33, 34, 35, 36, 91, 92, 93, 94, 95, 96}
+
+// donatedColor is the colour of time run on a donated priority, which no
+// PID is given.
+const donatedColor = 97
+
+func pidColor(pid int64) int {
+	i := pid % int64(len(pidColors))
+	if i < 0 {
+		i += int64(len(pidColors))
+	}
+	return pidColors[i]
+}
+
+// colorTerminal reports whether w is a terminal that should get colour: not
+// a pipe or file, with neither $NO_COLOR set nor a dumb $TERM.
+func colorTerminal(w io.Writer) bool {
+	return ansiTerminal(w) && os.Getenv("NO_COLOR") == ""
+}
+
+// ansiTerminal reports whether w is a terminal that understands ANSI escape
+// codes: not a pipe or file, and without a dumb $TERM.
+func ansiTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok || isPiped(f) {
+		return false
+	}
+	return os.Getenv("TERM") != "dumb"
+}
+
+// paint writes line with each column in its colour, an ANSI foreground code
+// that is drawn as a background behind black text; 0 leaves a column plain.
+func paint(line []byte, colors []int) string {
+	var (
+		b       strings.Builder
+		current int
+	)
+	for i, c := range line {
+		if colors[i] != current {
+			if current != 0 {
+				b.WriteString("\x1b[0m")
+			}
+			if colors[i] != 0 {
+				_, _ = fmt.Fprintf(&b, "\x1b[30;%dm", colors[i]+10)
+			}
+			current = colors[i]
+		}
+		b.WriteByte(c)
+	}
+	if current != 0 {
+		b.WriteString("\x1b[0m")
+	}
+	return b.String()
+}
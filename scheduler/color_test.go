@@ -0,0 +1,125 @@
+package scheduler
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_pidColor(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		pid  int64
+		want int
+	}{
+		{pid: 0, want: 31},
+		{pid: 1, want: 32},
+		{pid: 11, want: 96},
+		// The colours wrap around, negative PIDs too.
+		{pid: 12, want: 31},
+		{pid: -1, want: 96},
+	}
+	for _, tt := range tests {
+		if got := pidColor(tt.pid); got != tt.want {
+			t.Errorf("pidColor(%d) = %d, want %d", tt.pid, got, tt.want)
+		}
+	}
+}
+
+func Test_paint(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		line   string
+		colors []int
+		want   string
+	}{
+		{name: "plain", line: "ab", colors: []int{0, 0}, want: "ab"},
+		{name: "one run", line: "ab.", colors: []int{31, 31, 0}, want: "\x1b[30;41mab\x1b[0m."},
+		{name: "back to back", line: "ab", colors: []int{31, 32}, want: "\x1b[30;41ma\x1b[0m\x1b[30;42mb\x1b[0m"},
+	}
+	for _, tt := range tests {
+		if got := paint([]byte(tt.line), tt.colors); got != tt.want {
+			t.Errorf("%s: paint() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+// Test_colorTerminal sets the environment, so it cannot run in parallel.
+// The null device stands in for a terminal, being a character device too.
+func Test_colorTerminal(t *testing.T) {
+	file, err := os.Create(filepath.Join(t.TempDir(), "out.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = file.Close() }()
+	null, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = null.Close() }()
+
+	tests := []struct {
+		name     string
+		w        io.Writer
+		noColor  string
+		term     string
+		wantANSI bool
+		want     bool
+	}{
+		{name: "buffer", w: &bytes.Buffer{}, term: "xterm"},
+		{name: "redirected to a file", w: file, term: "xterm"},
+		{name: "terminal", w: null, term: "xterm", wantANSI: true, want: true},
+		{name: "NO_COLOR", w: null, noColor: "1", term: "xterm", wantANSI: true},
+		{name: "dumb terminal", w: null, term: "dumb"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("NO_COLOR", tt.noColor)
+			t.Setenv("TERM", tt.term)
+			if got := ansiTerminal(tt.w); got != tt.wantANSI {
+				t.Errorf("ansiTerminal() = %v, want %v", got, tt.wantANSI)
+			}
+			if got := colorTerminal(tt.w); got != tt.want {
+				t.Errorf("colorTerminal() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_outputSchedule_color(t *testing.T) {
+	t.Parallel()
+	rows := []ProcessResult{
+		{ProcessID: 1, Burst: 2, Turnaround: 2, Exit: 2},
+		{ProcessID: 2, Burst: 1, Arrival: 1, Wait: 1, Turnaround: 2, Exit: 3},
+	}
+	tests := []struct {
+		name  string
+		color bool
+		want  []string
+	}{
+		{name: "off"},
+		// Every cell of a row is in its PID's colour, as in the Gantt chart.
+		{name: "on", color: true, want: []string{"\x1b[32m1\x1b[0m", "\x1b[33m2\x1b[0m"}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var w bytes.Buffer
+			outputSchedule(&w, rows, 0.5, 2, 0.67, 1.5, true, tt.color)
+			out := w.String()
+			if !tt.color && strings.Contains(out, "\x1b[") {
+				t.Errorf("outputSchedule() without colour =\n%q\nwant no escape codes", out)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(out, want) {
+					t.Errorf("outputSchedule() =\n%q\nwant it to contain %q", out, want)
+				}
+			}
+		})
+	}
+}
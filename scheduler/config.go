@@ -0,0 +1,192 @@
+package scheduler
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidConfig = errors.New("invalid config")
+
+// configFiles are tried in order when -config is not given; the last is
+// relative to the home directory.
+var configFiles = []string{"scheduler.toml", ".schedrc"}
+
+// Config holds default flag values from a config file, by section. Keys
+// before any section header, in section "", are for the default command;
+// a [step], [bench] or other section is for that subcommand.
+type Config map[string]map[string]string
+
+// parseConfig reads the subset of TOML a config file needs: comments,
+// [section] headers and key = value lines, where a value is a quoted
+// string, a number, a boolean or an array of those. An array becomes the
+// comma-separated list the matching flag expects.
+func parseConfig(r io.Reader) (Config, error) {
+	var (
+		config  = Config{"": {}}
+		section = ""
+		in      = bufio.NewScanner(r)
+		line    = 0
+	)
+	for in.Scan() {
+		line++
+		text := strings.TrimSpace(stripComment(in.Text()))
+		if text == "" {
+			continue
+		}
+		if strings.HasPrefix(text, "[") {
+			if !strings.HasSuffix(text, "]") {
+				return nil, fmt.Errorf("%w: line %d: unterminated section header", ErrInvalidConfig, line)
+			}
+			section = strings.TrimSpace(text[1 : len(text)-1])
+			if config[section] == nil {
+				config[section] = map[string]string{}
+			}
+			continue
+		}
+		key, raw, ok := strings.Cut(text, "=")
+		if !ok {
+			return nil, fmt.Errorf("%w: line %d: want key = value", ErrInvalidConfig, line)
+		}
+		key = strings.TrimSpace(key)
+		value, err := configValue(strings.TrimSpace(raw))
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %s: %v", ErrInvalidConfig, line, key, err)
+		}
+		config[section][key] = value
+	}
+	if err := in.Err(); err != nil {
+		return nil, fmt.Errorf("%w: reading config", err)
+	}
+
+	return config, nil
+}
+
+// stripComment drops a # comment that is not inside a quoted string.
+func stripComment(s string) string {
+	quoted := false
+	for i, r := range s {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case r == '#' && !quoted:
+			return s[:i]
+		}
+	}
+	return s
+}
+
+func configValue(raw string) (string, error) {
+	switch {
+	case raw == "":
+		return "", errors.New("missing value")
+	case strings.HasPrefix(raw, "["):
+		if !strings.HasSuffix(raw, "]") {
+			return "", errors.New("unterminated array")
+		}
+		var items []string
+		for _, item := range strings.Split(raw[1:len(raw)-1], ",") {
+			if item = strings.TrimSpace(item); item == "" {
+				continue
+			}
+			v, err := configValue(item)
+			if err != nil {
+				return "", err
+			}
+			items = append(items, v)
+		}
+		return strings.Join(items, ","), nil
+	case strings.HasPrefix(raw, `"`):
+		return strconv.Unquote(raw)
+	case raw == "true" || raw == "false":
+		return raw, nil
+	}
+	if _, err := strconv.ParseFloat(raw, 64); err != nil {
+		return "", fmt.Errorf("%q is not a string, number, boolean or array", raw)
+	}
+	return raw, nil
+}
+
+// apply sets every flag in fs that the command line left alone to its value
+// in section. A key that names no flag of fs is an error.
+func (c Config) apply(fs *flag.FlagSet, section string) error {
+	given := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) { given[f.Name] = true })
+	for key, value := range c[section] {
+		if fs.Lookup(key) == nil {
+			return fmt.Errorf("%w: [%s] has no option %q", ErrInvalidConfig, sectionName(section), key)
+		}
+		if given[key] {
+			continue
+		}
+		if err := fs.Set(key, value); err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrInvalidConfig, key, err)
+		}
+	}
+	return nil
+}
+
+func sectionName(section string) string {
+	if section == "" {
+		return "top level"
+	}
+	return section
+}
+
+// findConfig returns the config file to use: path if given, otherwise the
+// first of configFiles in the working directory or, for .schedrc, the home
+// directory. It returns "" when there is none.
+func findConfig(path string) string {
+	if path != "" {
+		return path
+	}
+	candidates := append([]string(nil), configFiles...)
+	if home, err := os.UserHomeDir(); err == nil {
+		candidates = append(candidates, filepath.Join(home, ".schedrc"))
+	}
+	for _, p := range candidates {
+		if _, err := os.Stat(p); err == nil {
+			return p
+		}
+	}
+	return ""
+}
+
+// flagError is the error to return for err from parsing flags: ErrHelp as
+// it is, so that -h, having printed the usage, can exit cleanly, and any
+// other error as invalid arguments.
+func flagError(err error) error {
+	if errors.Is(err, flag.ErrHelp) {
+		return err
+	}
+	return fmt.Errorf("%w: %v", ErrInvalidArgs, err)
+}
+
+// parseFlags parses args into fs, adding a -config flag, then fills the
+// flags not given on the command line from the config file's section.
+func parseFlags(fs *flag.FlagSet, section string, args []string) error {
+	configPath := fs.String("config", "", "config file of default options (default scheduler.toml, .schedrc or ~/.schedrc)")
+	if err := fs.Parse(args); err != nil {
+		return flagError(err)
+	}
+	p := findConfig(*configPath)
+	if p == "" {
+		return nil
+	}
+	f, err := os.Open(p)
+	if err != nil {
+		return fmt.Errorf("%v: error opening config %s", err, p)
+	}
+	defer func() { _ = f.Close() }()
+	config, err := parseConfig(f)
+	if err != nil {
+		return fmt.Errorf("%w in %s", err, p)
+	}
+	return config.apply(fs, section)
+}
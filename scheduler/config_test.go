@@ -0,0 +1,104 @@
+package scheduler
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_parseConfig(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		src     string
+		want    Config
+		wantErr error
+	}{
+		{
+			name: "sections and values",
+			src: `# experiment defaults
+algo = ["fcfs", "rr"]  # two of them
+quantum = 4
+merge-gantt = true
+
+[step]
+algo = "rr # not a comment"
+`,
+			want: Config{
+				"":     {"algo": "fcfs,rr", "quantum": "4", "merge-gantt": "true"},
+				"step": {"algo": "rr # not a comment"},
+			},
+		},
+		{name: "missing value", src: "quantum =", wantErr: ErrInvalidConfig},
+		{name: "bare word", src: "algo = fcfs", wantErr: ErrInvalidConfig},
+		{name: "no equals", src: "quantum 4", wantErr: ErrInvalidConfig},
+		{name: "bad header", src: "[step", wantErr: ErrInvalidConfig},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := parseConfig(strings.NewReader(tt.src))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("parseConfig() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseConfig() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfig_apply(t *testing.T) {
+	t.Parallel()
+	config := Config{"": {"algo": "fcfs,rr", "quantum": "4"}}
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	algo := fs.String("algo", "", "")
+	quantum := fs.Int64("quantum", 0, "")
+	if err := fs.Parse([]string{"-quantum", "2"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := config.apply(fs, ""); err != nil {
+		t.Fatal(err)
+	}
+	// The command line wins over the config file.
+	if *algo != "fcfs,rr" || *quantum != 2 {
+		t.Errorf("algo, quantum = %q, %d, want fcfs,rr, 2", *algo, *quantum)
+	}
+
+	config[""]["cpus"] = "4"
+	if err := config.apply(fs, ""); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("apply() with unknown key error = %v, want %v", err, ErrInvalidConfig)
+	}
+}
+
+func Test_parseFlags_errors(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name        string
+		args        []string
+		wantHelp    bool
+		wantInvalid bool
+	}{
+		{name: "help", args: []string{"-h"}, wantHelp: true},
+		{name: "unknown flag", args: []string{"-bogus"}, wantInvalid: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.SetOutput(io.Discard)
+			err := parseFlags(fs, "test", tt.args)
+			if got := errors.Is(err, flag.ErrHelp); got != tt.wantHelp {
+				t.Errorf("parseFlags() = %v, want ErrHelp %v", err, tt.wantHelp)
+			}
+			if got := errors.Is(err, ErrInvalidArgs); got != tt.wantInvalid {
+				t.Errorf("parseFlags() = %v, want ErrInvalidArgs %v", err, tt.wantInvalid)
+			}
+		})
+	}
+}
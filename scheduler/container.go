@@ -0,0 +1,277 @@
+package scheduler
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+func init() {
+	Register("container", Factory{
+		Title:       "Container scheduling",
+		Description: "shares the CPU between containers, the process groups, by weight, each scheduling its own processes with its own algorithm",
+		New:         func(p []Process, o AlgorithmOptions) Policy { return newContainerPolicy(p, o) },
+	})
+}
+
+type (
+	// ContainerSpec is one container of the container algorithm: the
+	// processes whose group is Name, given Weight shares of the CPU and
+	// scheduled among themselves by Algorithm.
+	ContainerSpec struct {
+		Name      string `json:"name"`
+		Weight    int64  `json:"weight"`
+		Algorithm string `json:"algorithm"`
+	}
+	// ContainerOptions configures the container algorithm. Groups with no
+	// spec are containers of weight 1 scheduled first come, first served.
+	ContainerOptions struct {
+		Containers []ContainerSpec `json:"containers,omitempty"`
+		// Slice is the longest a container keeps the CPU before the
+		// others get a turn; zero uses the round-robin quantum.
+		Slice int64 `json:"slice,omitempty"`
+	}
+)
+
+// defaultContainerAlgorithm schedules the processes of a container that
+// names no algorithm.
+const defaultContainerAlgorithm = "fcfs"
+
+// parseContainers reads -containers: comma-separated name=weight[:algorithm]
+// entries.
+func parseContainers(s string) ([]ContainerSpec, error) {
+	if s == "" {
+		return nil, nil
+	}
+	var specs []ContainerSpec
+	for _, entry := range strings.Split(s, ",") {
+		name, rest, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("%w: -containers entry %q is not name=weight[:algorithm]", ErrInvalidArgs, entry)
+		}
+		weight, algorithm, _ := strings.Cut(rest, ":")
+		spec := ContainerSpec{Name: name, Algorithm: algorithm}
+		var err error
+		if spec.Weight, err = strconv.ParseInt(weight, 10, 64); err != nil || spec.Weight <= 0 {
+			return nil, fmt.Errorf("%w: -containers weight %q of %s must be a positive integer", ErrInvalidArgs, weight, name)
+		}
+		if algorithm != "" {
+			if _, ok := LookupAlgorithm(algorithm); !ok || algorithm == "container" {
+				return nil, fmt.Errorf("%w: -containers algorithm %q of %s is not one a container can run", ErrInvalidArgs, algorithm, name)
+			}
+		}
+		specs = append(specs, spec)
+	}
+	return specs, nil
+}
+
+// containerPolicy is two-level scheduling, as a hypervisor schedules
+// virtual machines or the kernel cgroups: the container that has had the
+// least CPU for its weight gets the CPU for a slice, and gives it to the
+// process its own policy ranks first. An inner policy's preemptions and
+// quanta only take effect within its container's slices.
+type containerPolicy struct {
+	slice      int64
+	containers []*container
+	byGroup    map[string]*container
+	byPID      map[int64]*container
+	// started is when each running process's slice began, and slices the
+	// schedule by container.
+	started map[int64]int64
+	slices  []TimeSlice
+}
+
+// container is one container's inner policy and what it has received.
+type container struct {
+	ContainerSpec
+	index     int
+	policy    Policy
+	processes int
+	cpu       int64
+}
+
+func newContainerPolicy(processes []Process, opts AlgorithmOptions) *containerPolicy {
+	p := &containerPolicy{
+		slice:   opts.Containers.Slice,
+		byGroup: map[string]*container{},
+		byPID:   map[int64]*container{},
+		started: map[int64]int64{},
+	}
+	if p.slice <= 0 {
+		p.slice = rrQuantum(processes, opts.RR)
+	}
+	members := map[string][]Process{}
+	for i := range processes {
+		members[processes[i].Group] = append(members[processes[i].Group], processes[i])
+	}
+	for _, spec := range opts.Containers.Containers {
+		p.add(spec, members[spec.Name], opts)
+	}
+	for i := range processes {
+		c, ok := p.byGroup[processes[i].Group]
+		if !ok {
+			c = p.add(ContainerSpec{Name: processes[i].Group}, members[processes[i].Group], opts)
+		}
+		p.byPID[processes[i].ProcessID] = c
+		c.processes++
+	}
+	return p
+}
+
+// add makes a container for spec, whose policy schedules processes.
+func (p *containerPolicy) add(spec ContainerSpec, processes []Process, opts AlgorithmOptions) *container {
+	if spec.Weight <= 0 {
+		spec.Weight = 1
+	}
+	if _, ok := LookupAlgorithm(spec.Algorithm); !ok || spec.Algorithm == "container" {
+		spec.Algorithm = defaultContainerAlgorithm
+	}
+	algorithm, _ := LookupAlgorithm(spec.Algorithm)
+	c := &container{ContainerSpec: spec, index: len(p.containers) + 1, policy: algorithm.New(processes, opts)}
+	p.containers = append(p.containers, c)
+	p.byGroup[spec.Name] = c
+	return c
+}
+
+// of is the container of process pid of group. A process unknown in
+// advance, such as one injected, joins its group's container, or a new one
+// scheduled first come, first served.
+func (p *containerPolicy) of(pid int64, group string) *container {
+	if c, ok := p.byPID[pid]; ok {
+		return c
+	}
+	c, ok := p.byGroup[group]
+	if !ok {
+		c = p.add(ContainerSpec{Name: group}, nil, AlgorithmOptions{})
+	}
+	p.byPID[pid] = c
+	c.processes++
+	return c
+}
+
+// behind reports whether a has had less CPU for its weight than b.
+func (a *container) behind(b *container) bool {
+	if ra, rb := a.cpu*b.Weight, b.cpu*a.Weight; ra != rb {
+		return ra < rb
+	}
+	return a.index < b.index
+}
+
+func (p *containerPolicy) Observe(ev Event) {
+	c, ok := p.byPID[ev.PID]
+	if !ok {
+		return
+	}
+	switch ev.Kind {
+	case EventDispatch:
+		p.started[ev.PID] = ev.Time
+	case EventPreempt, EventExpire, EventBlock, EventLockWait, EventSemWait, EventSuspend, EventComplete:
+		if start, ok := p.started[ev.PID]; ok {
+			c.cpu += ev.Time - start
+			delete(p.started, ev.PID)
+			p.addSlice(TimeSlice{PID: int64(c.index), Start: start, Stop: ev.Time, CPU: ev.CPU})
+		}
+	}
+	if o, ok := c.policy.(Observer); ok {
+		o.Observe(ev)
+	}
+}
+
+// addSlice adds s to the container schedule, joining it to the slice
+// before it on its CPU when the same container ran straight on.
+func (p *containerPolicy) addSlice(s TimeSlice) {
+	if s.Stop <= s.Start {
+		return
+	}
+	for i := len(p.slices) - 1; i >= 0; i-- {
+		if last := &p.slices[i]; last.CPU == s.CPU {
+			if last.PID == s.PID && last.Stop == s.Start {
+				last.Stop = s.Stop
+				return
+			}
+			break
+		}
+	}
+	p.slices = append(p.slices, s)
+}
+
+func (p *containerPolicy) Less(a, b *Task) bool {
+	ca, cb := p.of(a.ProcessID, a.Group), p.of(b.ProcessID, b.Group)
+	if ca != cb {
+		return ca.behind(cb)
+	}
+	return ca.policy.Less(a, b)
+}
+func (p *containerPolicy) Preemptive() bool { return false }
+func (p *containerPolicy) Quantum() int64   { return p.slice }
+
+// TaskQuantum ends a process's turn at the end of its container's slice,
+// or sooner if its container's policy has a shorter quantum.
+func (p *containerPolicy) TaskQuantum(t *Task) int64 {
+	inner := p.of(t.ProcessID, t.Group).policy
+	q := inner.Quantum()
+	if tq, ok := inner.(TaskQuantum); ok {
+		q = tq.TaskQuantum(t)
+	}
+	if q > 0 && q < p.slice {
+		return q
+	}
+	return p.slice
+}
+
+// Report prints each container's share of the CPU against its weight,
+// the schedule by container, and what the inner policies report.
+func (p *containerPolicy) Report(w io.Writer) {
+	if len(p.containers) == 0 {
+		return
+	}
+	var total, weights int64
+	for _, c := range p.containers {
+		total += c.cpu
+		weights += c.Weight
+	}
+	outputTitle(w, "Containers")
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"#", "Container", "Weight", "Algorithm", "Processes", "CPU", "Share", "Entitled"})
+	for _, c := range p.containers {
+		share := "-"
+		if total > 0 {
+			share = fmt.Sprintf("%.1f%%", 100*float64(c.cpu)/float64(total))
+		}
+		table.Append([]string{
+			fmt.Sprint(c.index), groupName(c.Name), fmt.Sprint(c.Weight), c.Algorithm, fmt.Sprint(c.processes),
+			fmt.Sprint(c.cpu), share, fmt.Sprintf("%.1f%%", 100*float64(c.Weight)/float64(weights)),
+		})
+	}
+	table.Render()
+	outputContainerGantt(w, p.slices, GanttOptions{Width: terminalWidth()})
+	for _, c := range p.containers {
+		if r, ok := c.policy.(Reporter); ok {
+			_, _ = fmt.Fprintf(w, "Container %s:\n", groupName(c.Name))
+			r.Report(w)
+		}
+	}
+}
+
+// outputContainerGantt draws the schedule by container, to read against
+// the chart by process, each bar labelled with its container's number.
+func outputContainerGantt(w io.Writer, slices []TimeSlice, opts GanttOptions) {
+	if len(slices) == 0 {
+		return
+	}
+	_, _ = fmt.Fprintln(w, "Container schedule")
+	width := opts.width()
+	origin, end := ganttSpan(slices)
+	col := ganttColumns(origin, end, width, opts)
+	lanes := ganttLanes(slices)
+	for _, lane := range lanes {
+		if len(lanes) > 1 {
+			_, _ = fmt.Fprintf(w, "CPU %d\n", lane[0].CPU)
+		}
+		outputGanttLane(w, lane, origin, end, col, width, opts)
+	}
+	_, _ = fmt.Fprintln(w)
+}
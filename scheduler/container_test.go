@@ -0,0 +1,79 @@
+package scheduler
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestContainer(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 4, Group: "web"},
+		{ProcessID: 2, BurstDuration: 4, Group: "web"},
+		{ProcessID: 3, BurstDuration: 4, Group: "batch"},
+	}
+	opts := AlgorithmOptions{Containers: ContainerOptions{
+		Containers: []ContainerSpec{{Name: "web", Weight: 2, Algorithm: "rr"}, {Name: "batch", Weight: 1}},
+		Slice:      2,
+	}}
+	policy := newContainerPolicy(processes, opts)
+	got := Simulate(processes, policy, EngineOptions{})
+	// web gets two slices to batch's one, and round-robins its two
+	// processes within them.
+	want := []TimeSlice{
+		{PID: 1, Start: 0, Stop: 2}, {PID: 3, Start: 2, Stop: 4}, {PID: 2, Start: 4, Stop: 6},
+		{PID: 1, Start: 6, Stop: 8}, {PID: 3, Start: 8, Stop: 10}, {PID: 2, Start: 10, Stop: 12},
+	}
+	if !reflect.DeepEqual(got.Gantt, want) {
+		t.Errorf("Gantt = %v, want %v", got.Gantt, want)
+	}
+	wantSlices := []TimeSlice{
+		{PID: 1, Start: 0, Stop: 2}, {PID: 2, Start: 2, Stop: 4}, {PID: 1, Start: 4, Stop: 8},
+		{PID: 2, Start: 8, Stop: 10}, {PID: 1, Start: 10, Stop: 12},
+	}
+	if !reflect.DeepEqual(policy.slices, wantSlices) {
+		t.Errorf("container slices = %v, want %v", policy.slices, wantSlices)
+	}
+
+	var w bytes.Buffer
+	policy.Report(&w)
+	for _, want := range []string{
+		"| 1 | web       |      2 | rr        |         2 |   8 | 66.7% | 66.7%    |",
+		"| 2 | batch     |      1 | fcfs      |         1 |   4 | 33.3% | 33.3%    |",
+		"Container schedule\n",
+	} {
+		if !strings.Contains(w.String(), want) {
+			t.Errorf("Report() is missing %q in\n%s", want, w.String())
+		}
+	}
+	w.Reset()
+	outputContainerGantt(&w, policy.slices, GanttOptions{Width: 40})
+	wantChart := `Container schedule
+|  1  |  2  |     1     |  2  |  1  |
+0     2     4           8     10    12
+
+`
+	if w.String() != wantChart {
+		t.Errorf("outputContainerGantt() =\n%s\nwant\n%s", w.String(), wantChart)
+	}
+}
+
+func Test_parseContainers(t *testing.T) {
+	t.Parallel()
+	got, err := parseContainers("web=3:rr, db=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []ContainerSpec{{Name: "web", Weight: 3, Algorithm: "rr"}, {Name: "db", Weight: 1}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseContainers() = %+v, want %+v", got, want)
+	}
+	for _, bad := range []string{"web", "web=0", "web=x", "web=1:nope", "web=1:container"} {
+		if _, err := parseContainers(bad); !errors.Is(err, ErrInvalidArgs) {
+			t.Errorf("parseContainers(%q) error = %v, want %v", bad, err, ErrInvalidArgs)
+		}
+	}
+}
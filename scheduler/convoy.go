@@ -0,0 +1,151 @@
+package scheduler
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// convoyMinWaiters is how many shorter processes must queue behind a
+// long one for it to count as a convoy.
+const convoyMinWaiters = 1
+
+// Convoy is an episode of the convoy effect: Leader ran from Start to Stop
+// while shorter processes, the Waiters, queued behind it, as trucks hold up
+// the cars behind them on a one-lane road. ExtraWait is the waiting they
+// did while it ran, which running them first would have spared them.
+type Convoy struct {
+	Leader    int64
+	Burst     int64
+	Start     int64
+	Stop      int64
+	Waiters   []int64
+	ExtraWait int64
+}
+
+// DetectConvoys finds the convoys in result: each stretch of running in
+// which a process with a long burst, longer than the mean, ran a whole CPU
+// burst without a break while at least convoyMinWaiters processes with
+// shorter bursts were ready and waiting. A preemptive policy has none, as
+// the waiting it makes processes do is its choice of a better one, and nor
+// do the slices a quantum cuts short. FCFS, which never lets a short
+// process past a long one, has the most.
+func DetectConvoys(result Result) []Convoy {
+	if result.Preemptive || len(result.Schedule) == 0 {
+		return nil
+	}
+	bursts := make(map[int64]int64, len(result.Schedule))
+	var total int64
+	for _, row := range result.Schedule {
+		bursts[row.ProcessID] = row.Burst
+		total += row.Burst
+	}
+	mean := float64(total) / float64(len(result.Schedule))
+	pids, changes, _ := laneChanges(result.Events)
+	starts, cuts := burstEdges(result.Events)
+	var convoys []Convoy
+	for _, s := range mergeGantt(result.Gantt) {
+		c := Convoy{Leader: s.PID, Burst: bursts[s.PID], Start: s.Start, Stop: s.Stop}
+		if float64(c.Burst) <= mean || !wholeBurst(starts[s.PID], cuts[s.PID], s.Start, s.Stop) {
+			continue
+		}
+		for _, pid := range pids {
+			if pid == s.PID || bursts[pid] == 0 || bursts[pid] >= c.Burst {
+				continue
+			}
+			if waited := waitingDuring(changes[pid], s.Start, s.Stop); waited > 0 {
+				c.Waiters = append(c.Waiters, pid)
+				c.ExtraWait += waited
+			}
+		}
+		if len(c.Waiters) >= convoyMinWaiters {
+			convoys = append(convoys, c)
+		}
+	}
+	return convoys
+}
+
+// burstEdges lists, by PID, when each process became ready for a new CPU
+// burst, and when it lost the CPU with its burst unfinished.
+func burstEdges(events []Event) (starts, cuts map[int64][]int64) {
+	starts, cuts = map[int64][]int64{}, map[int64][]int64{}
+	for _, ev := range events {
+		switch ev.Kind {
+		case EventArrive, EventWake:
+			starts[ev.PID] = append(starts[ev.PID], ev.Time)
+		case EventPreempt, EventExpire:
+			cuts[ev.PID] = append(cuts[ev.PID], ev.Time)
+		}
+	}
+	return starts, cuts
+}
+
+// wholeBurst reports whether a process that ran from start to stop ran a
+// CPU burst from its beginning without losing the CPU before stop, given
+// the times from burstEdges.
+func wholeBurst(starts, cuts []int64, start, stop int64) bool {
+	var began, cut int64 = -1, -1
+	for _, t := range starts {
+		if t <= start {
+			began = t
+		}
+	}
+	for _, t := range cuts {
+		if t > start && t <= stop {
+			return false
+		}
+		if t <= start {
+			cut = t
+		}
+	}
+	return cut < began
+}
+
+// waitingDuring is how long a process with the given changes of state was
+// waiting in a ready queue between start and stop.
+func waitingDuring(changes []laneChange, start, stop int64) int64 {
+	var waited int64
+	for i, c := range changes {
+		if c.State != laneWaiting {
+			continue
+		}
+		until := stop
+		if i+1 < len(changes) {
+			until = changes[i+1].Time
+		}
+		if from, to := maxInt64(c.Time, start), minInt64(until, stop); to > from {
+			waited += to - from
+		}
+	}
+	return waited
+}
+
+// outputConvoys lists each convoy and how much of all the waiting the
+// convoys account for.
+func outputConvoys(w io.Writer, convoys []Convoy, rows []ProcessResult) {
+	if len(convoys) == 0 {
+		return
+	}
+	_, _ = fmt.Fprintln(w, "Convoys:")
+	var extra, total int64
+	for _, c := range convoys {
+		waiters := make([]string, len(c.Waiters))
+		for i, pid := range c.Waiters {
+			waiters[i] = fmt.Sprintf("P%d", pid)
+		}
+		_, _ = fmt.Fprintf(w, "  t=%d-%d P%d (burst %d) holds up %s: %d ticks of waiting\n",
+			c.Start, c.Stop, c.Leader, c.Burst, strings.Join(waiters, ", "), c.ExtraWait)
+		extra += c.ExtraWait
+	}
+	for _, row := range rows {
+		total += row.Wait
+	}
+	plural, share := "s", ""
+	if len(convoys) == 1 {
+		plural = ""
+	}
+	if total > 0 {
+		share = fmt.Sprintf(", %.0f%% of all waiting", 100*float64(extra)/float64(total))
+	}
+	_, _ = fmt.Fprintf(w, "  %d convoy%s, %d ticks of waiting behind longer processes%s\n", len(convoys), plural, extra, share)
+}
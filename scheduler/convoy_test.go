@@ -0,0 +1,80 @@
+package scheduler
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestDetectConvoys(t *testing.T) {
+	t.Parallel()
+	// P1 arrives first with a long burst and the short ones pile up behind
+	// it; P5 is longer than P1, so it is no waiter of P1's convoy.
+	pileUp := []Process{
+		NewProcess(1, 0, 10, 0),
+		NewProcess(2, 1, 1, 0),
+		NewProcess(3, 2, 2, 0),
+		NewProcess(4, 4, 1, 0),
+		NewProcess(5, 5, 12, 0),
+	}
+	// The example in example_processes.csv: P3 arrives while P2 runs its
+	// burst of 9.
+	classic := []Process{
+		NewProcess(1, 0, 5, 2),
+		NewProcess(2, 3, 9, 1),
+		NewProcess(3, 6, 6, 3),
+	}
+	tests := []struct {
+		name      string
+		processes []Process
+		policy    Policy
+		want      []Convoy
+	}{
+		{
+			name:      "fcfs",
+			processes: pileUp,
+			policy:    fcfsPolicy{},
+			want: []Convoy{
+				{Leader: 1, Burst: 10, Start: 0, Stop: 10, Waiters: []int64{2, 3, 4}, ExtraWait: 9 + 8 + 6},
+			},
+		},
+		// Shortest remaining first lets them all past at once.
+		{name: "sjf", processes: pileUp, policy: sjfPolicy{}},
+		{
+			name:      "fcfs classic",
+			processes: classic,
+			policy:    fcfsPolicy{},
+			want: []Convoy{
+				{Leader: 2, Burst: 9, Start: 5, Stop: 14, Waiters: []int64{3}, ExtraWait: 8},
+			},
+		},
+		// P2 preempting P1 is the policy ranking it first, not a convoy.
+		{name: "priority classic", processes: classic, policy: priorityPolicy{}},
+		// A quantum of 2 never lets P2 run its burst through.
+		{name: "rr classic", processes: classic, policy: rrPolicy{quantum: 2}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			result := Simulate(tt.processes, tt.policy, EngineOptions{})
+			got := DetectConvoys(result)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DetectConvoys() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_outputConvoys(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	convoys := []Convoy{{Leader: 1, Burst: 10, Start: 0, Stop: 10, Waiters: []int64{2, 3}, ExtraWait: 17}}
+	outputConvoys(&buf, convoys, []ProcessResult{{Wait: 0}, {Wait: 9}, {Wait: 11}})
+	want := "Convoys:\n" +
+		"  t=0-10 P1 (burst 10) holds up P2, P3: 17 ticks of waiting\n" +
+		"  1 convoy, 17 ticks of waiting behind longer processes, 85% of all waiting\n"
+	if buf.String() != want {
+		t.Errorf("outputConvoys() =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
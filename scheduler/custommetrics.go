@@ -0,0 +1,173 @@
+package scheduler
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+type (
+	// Metric is a custom measure of a run, computed from its events as
+	// they happened, in order. Report gives its values by name once every
+	// event has been observed.
+	Metric interface {
+		Observe(ev Event)
+		Report() map[string]float64
+	}
+	// MetricFactory describes a custom metric and makes a fresh one for
+	// each run.
+	MetricFactory struct {
+		Description string
+		New         func() Metric
+	}
+	// RegisteredMetric is a MetricFactory and the name it is selected by.
+	RegisteredMetric struct {
+		Name string
+		MetricFactory
+	}
+)
+
+// metricRegistry holds every custom metric in registration order.
+var metricRegistry []RegisteredMetric
+
+// RegisterMetric makes a custom metric available by name to -metrics and
+// the list subcommand. Call it from an init function in the package
+// defining the metric, which may be one importing this one. It panics if
+// the name is taken, holds a "." or ",", which would run into the value
+// names it is printed with or the -metrics list, or the factory cannot
+// build a metric.
+func RegisterMetric(name string, factory MetricFactory) {
+	if name == "" || strings.ContainsAny(name, ".,") {
+		panic(fmt.Sprintf("scheduler: RegisterMetric name %q is empty or holds a \".\" or \",\"", name))
+	}
+	if factory.New == nil {
+		panic("scheduler: RegisterMetric factory for " + name + " is missing New")
+	}
+	if _, dup := LookupMetric(name); dup {
+		panic("scheduler: RegisterMetric called twice for " + name)
+	}
+	metricRegistry = append(metricRegistry, RegisteredMetric{Name: name, MetricFactory: factory})
+}
+
+// LookupMetric finds the metric registered as name.
+func LookupMetric(name string) (RegisteredMetric, bool) {
+	for _, m := range metricRegistry {
+		if m.Name == name {
+			return m, true
+		}
+	}
+	return RegisteredMetric{}, false
+}
+
+// selectMetrics picks the registered metrics named in a comma-separated
+// list, keeping the list's order; "all" selects every one.
+func selectMetrics(spec string) ([]RegisteredMetric, error) {
+	if spec == "" {
+		return nil, nil
+	}
+	if spec == "all" {
+		return metricRegistry, nil
+	}
+	var metrics []RegisteredMetric
+	for _, name := range strings.Split(spec, ",") {
+		m, ok := LookupMetric(strings.TrimSpace(name))
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown metric %q", ErrInvalidArgs, name)
+		}
+		metrics = append(metrics, m)
+	}
+	return metrics, nil
+}
+
+// MeasureMetrics replays events through a fresh instance of each metric
+// and collects their reports by metric name.
+func MeasureMetrics(metrics []RegisteredMetric, events []Event) map[string]map[string]float64 {
+	if len(metrics) == 0 {
+		return nil
+	}
+	reports := make(map[string]map[string]float64, len(metrics))
+	for _, m := range metrics {
+		metric := m.New()
+		for _, ev := range events {
+			metric.Observe(ev)
+		}
+		reports[m.Name] = metric.Report()
+	}
+	return reports
+}
+
+// outputCustomMetrics prints each custom metric's values, named
+// metric.value, in name order.
+func outputCustomMetrics(w io.Writer, reports map[string]map[string]float64) {
+	if len(reports) == 0 {
+		return
+	}
+	var names []string
+	for metric, values := range reports {
+		for value := range values {
+			names = append(names, metric+"."+value)
+		}
+	}
+	sort.Strings(names)
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Metric", "Value"})
+	for _, name := range names {
+		metric, value, _ := strings.Cut(name, ".")
+		table.Append([]string{name, fmt.Sprintf("%.4g", reports[metric][value])})
+	}
+	table.Render()
+}
+
+// outputMetrics lists the registered custom metrics.
+func outputMetrics(w io.Writer, metrics []RegisteredMetric) {
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Metric", "Description"})
+	for _, m := range metrics {
+		table.Append([]string{m.Name, m.Description})
+	}
+	table.Render()
+}
+
+func init() {
+	RegisterMetric("runs", MetricFactory{
+		Description: "how long processes hold the CPU each time they get it: count, mean and max",
+		New:         func() Metric { return &runsMetric{started: map[int64]int64{}} },
+	})
+}
+
+// runsMetric measures the stretches processes run without a break, from a
+// dispatch to whatever takes them off the CPU. Short runs mean many context
+// switches for the work done.
+type runsMetric struct {
+	started map[int64]int64
+	count   int
+	total   int64
+	longest int64
+}
+
+func (m *runsMetric) Observe(ev Event) {
+	switch ev.Kind {
+	case EventDispatch:
+		m.started[ev.PID] = ev.Time
+	case EventPreempt, EventExpire, EventBlock, EventLockWait, EventSemWait, EventSuspend, EventComplete:
+		start, ok := m.started[ev.PID]
+		if !ok {
+			return
+		}
+		delete(m.started, ev.PID)
+		m.count++
+		m.total += ev.Time - start
+		m.longest = maxInt64(m.longest, ev.Time-start)
+	}
+}
+
+func (m *runsMetric) Report() map[string]float64 {
+	report := map[string]float64{"count": float64(m.count), "max": float64(m.longest)}
+	if m.count > 0 {
+		report["mean"] = float64(m.total) / float64(m.count)
+	}
+	return report
+}
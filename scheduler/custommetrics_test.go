@@ -0,0 +1,71 @@
+package scheduler
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// arrivalsMetric counts arrivals, as a metric a user might register.
+type arrivalsMetric struct{ n int }
+
+func (m *arrivalsMetric) Observe(ev Event) {
+	if ev.Kind == EventArrive {
+		m.n++
+	}
+}
+func (m *arrivalsMetric) Report() map[string]float64 {
+	return map[string]float64{"count": float64(m.n)}
+}
+
+func TestRegisterMetric(t *testing.T) {
+	RegisterMetric("test-arrivals", MetricFactory{Description: "counts arrivals", New: func() Metric { return &arrivalsMetric{} }})
+	metrics, err := selectMetrics("runs, test-arrivals")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// FCFS runs P1 for 2 ticks, then P2 for 3.
+	result := Simulate([]Process{NewProcess(1, 0, 2, 0), NewProcess(2, 1, 3, 0)}, fcfsPolicy{}, EngineOptions{})
+	got := MeasureMetrics(metrics, result.Events)
+	want := map[string]map[string]float64{
+		"runs":          {"count": 2, "mean": 2.5, "max": 3},
+		"test-arrivals": {"count": 2},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MeasureMetrics() = %v, want %v", got, want)
+	}
+
+	var buf bytes.Buffer
+	outputCustomMetrics(&buf, got)
+	if out := buf.String(); strings.Index(out, "runs.count") > strings.Index(out, "runs.max") || !strings.Contains(out, "test-arrivals.count") {
+		t.Errorf("outputCustomMetrics() not in name order:\n%s", out)
+	}
+	var list strings.Builder
+	if err := runList(&list, nil); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(list.String(), "counts arrivals") {
+		t.Errorf("list output missing registered metric:\n%s", list.String())
+	}
+
+	if _, err := selectMetrics("runs,nope"); err == nil {
+		t.Error("selectMetrics() of an unknown metric succeeded")
+	}
+	for _, name := range []string{"", "test.dotted", "test,comma"} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("registering %q did not panic", name)
+				}
+			}()
+			RegisterMetric(name, MetricFactory{New: func() Metric { return &arrivalsMetric{} }})
+		}()
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("registering a taken name did not panic")
+		}
+	}()
+	RegisterMetric("runs", MetricFactory{New: func() Metric { return &arrivalsMetric{} }})
+}
@@ -0,0 +1,177 @@
+package scheduler
+
+import (
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+func init() {
+	Register("edf", Factory{
+		Title:       "Earliest deadline first",
+		Description: "preemptively runs the process whose deadline is soonest; those without one run last",
+		New:         func([]Process, AlgorithmOptions) Policy { return edfPolicy{} },
+	})
+}
+
+// edfPolicy orders by absolute deadline, then by when processes became
+// ready.
+type edfPolicy struct{}
+
+func (edfPolicy) Less(a, b *Task) bool {
+	if da, db := a.absoluteDeadline(), b.absoluteDeadline(); da != db {
+		return da < db
+	}
+	return a.Seq < b.Seq
+}
+func (edfPolicy) Preemptive() bool  { return true }
+func (edfPolicy) Quantum() int64    { return 0 }
+func (edfPolicy) StableOrder() bool { return true }
+
+// absoluteDeadline is the tick by which the process must finish, or the
+// largest tick if it has no deadline.
+func (p *Process) absoluteDeadline() int64 {
+	if p.Deadline <= 0 {
+		return math.MaxInt64
+	}
+	return p.ArrivalTime + p.Deadline
+}
+
+type (
+	// DeadlineStats measures how well a run met the deadlines of the
+	// processes that have one.
+	DeadlineStats struct {
+		// Processes counts the processes with a deadline, and Missed those
+		// that finished late or not at all before it passed.
+		Processes int
+		Missed    int
+		MissRatio float64
+		// MaxLateness is the largest lateness, which is negative when every
+		// process finished early; TotalTardiness sums the time processes
+		// finished past their deadlines.
+		MaxLateness    int64
+		TotalTardiness int64
+		Rows           []DeadlineRow
+		// Aborted is set when the run stopped at the first miss, AbortedPID
+		// being the process that missed.
+		Aborted    bool
+		AbortedPID int64
+	}
+	// DeadlineRow is one process's outcome against its deadline.
+	DeadlineRow struct {
+		ProcessID int64
+		Deadline  int64
+		// Finished is unset for a process the run stopped before, whose Exit
+		// is then when the run stopped and its lateness a lower bound.
+		Finished  bool
+		Exit      int64
+		Lateness  int64
+		Tardiness int64
+		Missed    bool
+	}
+)
+
+// nextDeadline is the earliest deadline after now of a process that has
+// not finished.
+func (e *engine) nextDeadline() (int64, bool) {
+	var (
+		next  int64
+		found bool
+	)
+	for _, t := range e.tasks {
+		if d := t.absoluteDeadline(); t.phase < len(t.bursts) && d > e.now && d != math.MaxInt64 && (!found || d < next) {
+			next, found = d, true
+		}
+	}
+	return next, found
+}
+
+// missedDeadline finds a process whose deadline has passed without it
+// finishing.
+func (e *engine) missedDeadline() *Task {
+	for _, t := range e.tasks {
+		if t.phase < len(t.bursts) && t.absoluteDeadline() <= e.now {
+			return t
+		}
+	}
+	return nil
+}
+
+// deadlines measures every process with a deadline against it.
+func (e *engine) deadlines() DeadlineStats {
+	stats := DeadlineStats{MaxLateness: math.MinInt64}
+	if e.aborted != nil {
+		stats.Aborted, stats.AbortedPID = true, e.aborted.ProcessID
+	}
+	for _, t := range e.tasks {
+		if t.Deadline <= 0 {
+			continue
+		}
+		row := DeadlineRow{ProcessID: t.ProcessID, Deadline: t.absoluteDeadline(), Exit: e.now}
+		if t.phase == len(t.bursts) {
+			row.Finished, row.Exit = true, t.finish
+		} else if row.Deadline > e.now {
+			// It may still make it; the run stopped too soon to tell.
+			continue
+		}
+		row.Lateness = row.Exit - row.Deadline
+		if row.Lateness > 0 {
+			row.Tardiness = row.Lateness
+		}
+		if row.Missed = !row.Finished || row.Lateness > 0; row.Missed {
+			stats.Missed++
+		}
+		if row.Lateness > stats.MaxLateness {
+			stats.MaxLateness = row.Lateness
+		}
+		stats.TotalTardiness += row.Tardiness
+		stats.Rows = append(stats.Rows, row)
+	}
+	if stats.Processes = len(stats.Rows); stats.Processes == 0 {
+		return DeadlineStats{}
+	}
+	stats.MissRatio = float64(stats.Missed) / float64(stats.Processes)
+	return stats
+}
+
+// outputDeadlines prints each deadline's outcome and the miss ratio, when
+// any process has a deadline.
+func outputDeadlines(w io.Writer, stats DeadlineStats) {
+	if stats.Processes == 0 {
+		return
+	}
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"ID", "Deadline", "Exit", "Lateness", "Tardiness", "Missed"})
+	for _, r := range stats.Rows {
+		exit, lateness := fmt.Sprint(r.Exit), fmt.Sprint(r.Lateness)
+		if !r.Finished {
+			exit, lateness = "-", fmt.Sprintf(">=%d", r.Lateness)
+		}
+		missed := ""
+		if r.Missed {
+			missed = "yes"
+		}
+		table.Append([]string{fmt.Sprint(r.ProcessID), fmt.Sprint(r.Deadline), exit, lateness, fmt.Sprint(r.Tardiness), missed})
+	}
+	table.Render()
+	_, _ = fmt.Fprintf(w, "Deadlines: %d of %d missed (%.1f%%); max lateness %d, total tardiness %d\n",
+		stats.Missed, stats.Processes, 100*stats.MissRatio, stats.MaxLateness, stats.TotalTardiness)
+	if stats.Aborted {
+		_, _ = fmt.Fprintf(w, "Aborted on the first miss: PID %d\n", stats.AbortedPID)
+	}
+}
+
+// parseOnMiss reads what to do when a deadline is missed: continue or
+// abort.
+func parseOnMiss(s string) (abort bool, err error) {
+	switch s {
+	case "", "continue":
+		return false, nil
+	case "abort":
+		return true, nil
+	default:
+		return false, fmt.Errorf("%w: unknown deadline miss action %q", ErrInvalidArgs, s)
+	}
+}
@@ -0,0 +1,95 @@
+package scheduler
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestSimulate_deadlines(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 4, Deadline: 6},
+		{ProcessID: 2, ArrivalTime: 1, BurstDuration: 3, Deadline: 3},
+		{ProcessID: 3, ArrivalTime: 2, BurstDuration: 2},
+	}
+	tests := []struct {
+		name   string
+		policy Policy
+		opts   EngineOptions
+		want   DeadlineStats
+	}{
+		{
+			name:   "fcfs misses the later, tighter deadline",
+			policy: fcfsPolicy{},
+			want: DeadlineStats{
+				Processes: 2, Missed: 1, MissRatio: 0.5, MaxLateness: 3, TotalTardiness: 3,
+				Rows: []DeadlineRow{
+					{ProcessID: 1, Deadline: 6, Finished: true, Exit: 4, Lateness: -2},
+					{ProcessID: 2, Deadline: 4, Finished: true, Exit: 7, Lateness: 3, Tardiness: 3, Missed: true},
+				},
+			},
+		},
+		{
+			name:   "edf preempts for the tighter deadline",
+			policy: edfPolicy{},
+			want: DeadlineStats{
+				Processes: 2, Missed: 1, MissRatio: 0.5, MaxLateness: 1, TotalTardiness: 1,
+				Rows: []DeadlineRow{
+					{ProcessID: 1, Deadline: 6, Finished: true, Exit: 7, Lateness: 1, Tardiness: 1, Missed: true},
+					{ProcessID: 2, Deadline: 4, Finished: true, Exit: 4},
+				},
+			},
+		},
+		{
+			name:   "abort on the first miss",
+			policy: fcfsPolicy{},
+			opts:   EngineOptions{AbortOnMiss: true},
+			want: DeadlineStats{
+				Processes: 2, Missed: 1, MissRatio: 0.5, MaxLateness: 0,
+				Rows: []DeadlineRow{
+					{ProcessID: 1, Deadline: 6, Finished: true, Exit: 4, Lateness: -2},
+					{ProcessID: 2, Deadline: 4, Exit: 4, Missed: true},
+				},
+				Aborted: true, AbortedPID: 2,
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := Simulate(processes, tt.policy, tt.opts).Deadlines
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Deadlines = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_parseOnMiss(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		in      string
+		want    bool
+		wantErr error
+	}{
+		{in: "", want: false},
+		{in: "continue", want: false},
+		{in: "abort", want: true},
+		{in: "panic", wantErr: ErrInvalidArgs},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.in, func(t *testing.T) {
+			t.Parallel()
+			got, err := parseOnMiss(tt.in)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("parseOnMiss() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseOnMiss() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,264 @@
+package scheduler
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// DeadlockMode is what the engine does about processes that could end up
+// waiting on each other for resources, each holding what another needs.
+type DeadlockMode int
+
+const (
+	// DeadlockIgnore grants whatever is free and lets deadlocks happen; the
+	// run stops once every remaining process is stuck.
+	DeadlockIgnore DeadlockMode = iota
+	// DeadlockDetect also looks for a deadlock whenever a process starts
+	// waiting, and reports the cycle of processes waiting on each other.
+	DeadlockDetect
+	// DeadlockAvoid grants a request only if the Banker's algorithm finds
+	// the system still safe afterwards, so a deadlock never forms: the
+	// process otherwise waits, even with enough instances free.
+	DeadlockAvoid
+)
+
+func (m DeadlockMode) String() string {
+	switch m {
+	case DeadlockDetect:
+		return "detect"
+	case DeadlockAvoid:
+		return "banker"
+	default:
+		return "ignore"
+	}
+}
+
+func parseDeadlockMode(s string) (DeadlockMode, error) {
+	switch s {
+	case "", "ignore":
+		return DeadlockIgnore, nil
+	case "detect":
+		return DeadlockDetect, nil
+	case "banker":
+		return DeadlockAvoid, nil
+	default:
+		return DeadlockIgnore, fmt.Errorf("%w: unknown deadlock mode %q", ErrInvalidArgs, s)
+	}
+}
+
+// parseResources reads -resources: comma-separated name=instances.
+func parseResources(s string) (map[string]int64, error) {
+	if s == "" {
+		return nil, nil
+	}
+	resources := map[string]int64{}
+	for _, f := range strings.Split(s, ",") {
+		name, n, ok := strings.Cut(strings.TrimSpace(f), "=")
+		instances, err := strconv.ParseInt(n, 10, 64)
+		if !ok || name == "" || err != nil || instances < 1 {
+			return nil, fmt.Errorf("%w: resource %q is not name=instances with at least one instance", ErrInvalidArgs, f)
+		}
+		resources[name] = instances
+	}
+	return resources, nil
+}
+
+// checkResources makes sure no process wants more instances of a resource
+// than it has, which would keep it waiting forever.
+func checkResources(processes []Process, resources map[string]int64) error {
+	for _, p := range processes {
+		for _, u := range p.Locks {
+			instances := resources[u.Resource]
+			if instances < 1 {
+				instances = 1
+			}
+			if u.instances() > instances {
+				return fmt.Errorf("%w: PID %d wants %d of %s, which has %d", ErrInvalidLock, p.ProcessID, u.instances(), u.Resource, instances)
+			}
+		}
+	}
+	return nil
+}
+
+// maxClaims is the most instances of each resource uses hold at once.
+func maxClaims(uses []LockUse) map[string]int64 {
+	if len(uses) == 0 {
+		return nil
+	}
+	claims := map[string]int64{}
+	for _, u := range uses {
+		claims[u.Resource] = maxInt64(claims[u.Resource], u.instances())
+	}
+	return claims
+}
+
+// safeAfter is the Banker's algorithm's safety check: whether, were t
+// granted count more of resource, the processes in the system could still
+// all finish in some order, each needing at most the rest of its maximum
+// claims before giving back everything it holds.
+func (e *engine) safeAfter(t *Task, resource string, count int64) bool {
+	held := func(u *Task, name string) int64 {
+		n := e.locks[name].heldBy(u)
+		if u == t && name == resource {
+			n += count
+		}
+		return n
+	}
+	work := make(map[string]int64, len(e.locks))
+	for name, l := range e.locks {
+		work[name] = l.free()
+	}
+	work[resource] -= count
+	var pending []*Task
+	for _, u := range e.tasks {
+		if u.admitted && u.phase < len(u.bursts) && len(u.claims) > 0 {
+			pending = append(pending, u)
+		}
+	}
+	for progress := true; progress; {
+		progress = false
+		rest := pending[:0]
+		for _, u := range pending {
+			finishes := true
+			for name, claim := range u.claims {
+				if claim-held(u, name) > work[name] {
+					finishes = false
+					break
+				}
+			}
+			if !finishes {
+				rest = append(rest, u)
+				continue
+			}
+			for name := range u.claims {
+				work[name] += held(u, name)
+			}
+			progress = true
+		}
+		pending = rest
+	}
+	return len(pending) == 0
+}
+
+// Deadlock is a cycle of processes found waiting on each other at Time:
+// each process in Cycle waits for the resource at the same index in
+// Resources, held by the next process, the last by the first.
+type Deadlock struct {
+	Time      int64    `json:"time"`
+	Cycle     []int64  `json:"cycle"`
+	Resources []string `json:"resources"`
+}
+
+// detectDeadlock records the deadlock t closed by starting to wait, if it
+// did, under DeadlockDetect.
+func (e *engine) detectDeadlock(t *Task) {
+	if e.opts.Deadlock != DeadlockDetect {
+		return
+	}
+	stuck := e.deadlockedTasks()
+	if !stuck[t] {
+		return
+	}
+	cycle := e.waitCycle(t, stuck)
+	if cycle == nil {
+		return
+	}
+	d := Deadlock{Time: e.now}
+	for _, u := range cycle {
+		d.Cycle = append(d.Cycle, u.ProcessID)
+		d.Resources = append(d.Resources, u.waitingFor)
+	}
+	e.deadlocks = append(e.deadlocks, d)
+	e.opts.Log.Log(e.now, "deadlock", "cycle", formatPIDs(d.Cycle))
+}
+
+// deadlockedTasks finds the processes that can never go on, by the
+// detection algorithm for resources with several instances: those waiting
+// for more than would be free even if every other process ran to the end
+// and gave back what it holds. With one instance of each resource they are
+// the processes on, or waiting on, a cycle of the wait-for graph.
+func (e *engine) deadlockedTasks() map[*Task]bool {
+	work := make(map[string]int64, len(e.locks))
+	for name, l := range e.locks {
+		work[name] = l.free()
+	}
+	var waiting []*Task
+	for _, u := range e.tasks {
+		if u.phase == len(u.bursts) {
+			continue
+		}
+		if u.waitingFor != "" {
+			waiting = append(waiting, u)
+			continue
+		}
+		for _, name := range u.held {
+			work[name] += e.locks[name].heldBy(u)
+		}
+	}
+	for progress := true; progress; {
+		progress = false
+		rest := waiting[:0]
+		for _, u := range waiting {
+			if u.lockOps[u.nextOp].count > work[u.waitingFor] {
+				rest = append(rest, u)
+				continue
+			}
+			for _, name := range u.held {
+				work[name] += e.locks[name].heldBy(u)
+			}
+			progress = true
+		}
+		waiting = rest
+	}
+	stuck := make(map[*Task]bool, len(waiting))
+	for _, u := range waiting {
+		stuck[u] = true
+	}
+	return stuck
+}
+
+// waitCycle finds a cycle of the wait-for graph through t, among the stuck
+// processes: t waits for a resource held by the next, and so on back to t.
+func (e *engine) waitCycle(t *Task, stuck map[*Task]bool) []*Task {
+	visited := map[*Task]bool{}
+	var path []*Task
+	var visit func(u *Task) bool
+	visit = func(u *Task) bool {
+		path = append(path, u)
+		visited[u] = true
+		for _, h := range e.locks[u.waitingFor].holders {
+			if h.task == t {
+				return true
+			}
+			if stuck[h.task] && !visited[h.task] && visit(h.task) {
+				return true
+			}
+		}
+		path = path[:len(path)-1]
+		return false
+	}
+	if visit(t) {
+		return path
+	}
+	return nil
+}
+
+// outputDeadlocks lists each deadlock found, with who waits for what.
+func outputDeadlocks(w io.Writer, deadlocks []Deadlock) {
+	if len(deadlocks) == 0 {
+		return
+	}
+	_, _ = fmt.Fprintln(w, "Deadlocks:")
+	for _, d := range deadlocks {
+		cycle := make([]string, 0, len(d.Cycle)+1)
+		waits := make([]string, len(d.Cycle))
+		for i, pid := range d.Cycle {
+			cycle = append(cycle, fmt.Sprintf("P%d", pid))
+			waits[i] = fmt.Sprintf("P%d waits for %s held by P%d", pid, d.Resources[i], d.Cycle[(i+1)%len(d.Cycle)])
+		}
+		cycle = append(cycle, cycle[0])
+		_, _ = fmt.Fprintf(w, "  t=%d %s: %s\n", d.Time, strings.Join(cycle, " -> "), strings.Join(waits, ", "))
+	}
+}
@@ -0,0 +1,136 @@
+package scheduler
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSimulate_deadlockMode(t *testing.T) {
+	t.Parallel()
+	// Each takes its first resource, then waits for the other's.
+	crossed := []Process{
+		{ProcessID: 1, BurstDuration: 4, Locks: []LockUse{{Resource: "A", Acquire: 0, Release: 4}, {Resource: "B", Acquire: 2, Release: 3}}},
+		{ProcessID: 2, BurstDuration: 4, Locks: []LockUse{{Resource: "B", Acquire: 0, Release: 4}, {Resource: "A", Acquire: 1, Release: 2}}},
+	}
+	// Only 3 is left free to run when 1 and 2 deadlock over R, of which
+	// 3 holds the instance they are short of.
+	counted := []Process{
+		{ProcessID: 1, BurstDuration: 6, Locks: []LockUse{{Resource: "R", Count: 2, Acquire: 0, Release: 5}, {Resource: "S", Acquire: 3, Release: 4}}},
+		{ProcessID: 2, ArrivalTime: 1, BurstDuration: 6, Locks: []LockUse{{Resource: "S", Acquire: 0, Release: 5}, {Resource: "R", Count: 2, Acquire: 2, Release: 3}}},
+		{ProcessID: 3, ArrivalTime: 2, BurstDuration: 3, Locks: []LockUse{{Resource: "R", Acquire: 0, Release: 2}}},
+	}
+	resources := map[string]int64{"R": 3}
+	tests := []struct {
+		name      string
+		processes []Process
+		mode      DeadlockMode
+		want      []Deadlock
+		deadlock  bool
+	}{
+		{name: "ignored", processes: crossed, mode: DeadlockIgnore, deadlock: true},
+		{
+			name: "detected", processes: crossed, mode: DeadlockDetect, deadlock: true,
+			want: []Deadlock{{Time: 3, Cycle: []int64{1, 2}, Resources: []string{"B", "A"}}},
+		},
+		{name: "avoided", processes: crossed, mode: DeadlockAvoid},
+		{
+			name: "detected with instances", processes: counted, mode: DeadlockDetect, deadlock: true,
+			want: []Deadlock{{Time: 6, Cycle: []int64{1, 2}, Resources: []string{"S", "R"}}},
+		},
+		{name: "avoided with instances", processes: counted, mode: DeadlockAvoid},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := Simulate(tt.processes, rrPolicy{quantum: 1}, EngineOptions{Resources: resources, Deadlock: tt.mode})
+			if got.Deadlocked != tt.deadlock {
+				t.Errorf("Deadlocked = %v, want %v; Gantt %v", got.Deadlocked, tt.deadlock, got.Gantt)
+			}
+			if !tt.deadlock && len(got.Schedule) != len(tt.processes) {
+				t.Errorf("not every process finished: %+v", got.Incomplete)
+			}
+			if !reflect.DeepEqual(got.Deadlocks, tt.want) {
+				t.Errorf("Deadlocks = %+v, want %+v", got.Deadlocks, tt.want)
+			}
+		})
+	}
+}
+
+func TestSimulate_unsafeWait(t *testing.T) {
+	t.Parallel()
+	// 2 could take S, but then 1, holding A, could not be sure of finishing.
+	got := Simulate([]Process{
+		{ProcessID: 1, BurstDuration: 4, Locks: []LockUse{{Resource: "A", Acquire: 0, Release: 4}, {Resource: "S", Acquire: 2, Release: 3}}},
+		{ProcessID: 2, BurstDuration: 4, Locks: []LockUse{{Resource: "S", Acquire: 0, Release: 4}, {Resource: "A", Acquire: 1, Release: 2}}},
+	}, rrPolicy{quantum: 1}, EngineOptions{Deadlock: DeadlockAvoid})
+	var unsafe []Event
+	for _, ev := range got.Events {
+		if ev.Unsafe {
+			unsafe = append(unsafe, ev)
+		}
+	}
+	want := []Event{{Time: 1, Kind: EventLockWait, PID: 2, Resource: "S", Unsafe: true}}
+	if !reflect.DeepEqual(unsafe, want) {
+		t.Errorf("unsafe waits = %+v, want %+v", unsafe, want)
+	}
+}
+
+func Test_parseLockUses_count(t *testing.T) {
+	t.Parallel()
+	uses, err := parseLockUses("R*2:1-4 S:0-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []LockUse{{Resource: "R", Count: 2, Acquire: 1, Release: 4}, {Resource: "S", Acquire: 0, Release: 1}}
+	if !reflect.DeepEqual(uses, want) {
+		t.Errorf("parseLockUses() = %+v, want %+v", uses, want)
+	}
+	if got := formatLockUses(uses); got != "R*2:1-4 S:0-1" {
+		t.Errorf("formatLockUses() = %q", got)
+	}
+	for _, bad := range []string{"R*0:1-4", "R*x:1-4", "*2:1-4"} {
+		if _, err := parseLockUses(bad); !errors.Is(err, ErrInvalidLock) {
+			t.Errorf("parseLockUses(%q) error = %v, want ErrInvalidLock", bad, err)
+		}
+	}
+}
+
+func Test_parseResources(t *testing.T) {
+	t.Parallel()
+	got, err := parseResources("R=3, S=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]int64{"R": 3, "S": 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("parseResources() = %v, want %v", got, want)
+	}
+	for _, bad := range []string{"R", "R=0", "=2", "R=x"} {
+		if _, err := parseResources(bad); !errors.Is(err, ErrInvalidArgs) {
+			t.Errorf("parseResources(%q) error = %v, want ErrInvalidArgs", bad, err)
+		}
+	}
+}
+
+func Test_checkResources(t *testing.T) {
+	t.Parallel()
+	processes := []Process{{ProcessID: 1, BurstDuration: 4, Locks: []LockUse{{Resource: "R", Count: 2, Acquire: 0, Release: 4}}}}
+	if err := checkResources(processes, map[string]int64{"R": 2}); err != nil {
+		t.Errorf("checkResources() with enough instances = %v", err)
+	}
+	if err := checkResources(processes, nil); !errors.Is(err, ErrInvalidLock) {
+		t.Errorf("checkResources() with one instance = %v, want ErrInvalidLock", err)
+	}
+}
+
+func Test_outputDeadlocks(t *testing.T) {
+	t.Parallel()
+	var w strings.Builder
+	outputDeadlocks(&w, []Deadlock{{Time: 3, Cycle: []int64{1, 2}, Resources: []string{"B", "A"}}})
+	want := "Deadlocks:\n  t=3 P1 -> P2 -> P1: P1 waits for B held by P2, P2 waits for A held by P1\n"
+	if got := w.String(); got != want {
+		t.Errorf("outputDeadlocks() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,138 @@
+package scheduler
+
+import (
+	"fmt"
+	"strings"
+)
+
+// dependencyProblems finds dependencies that can never be met: on a PID
+// not in the workload, on the process itself, or around a cycle. It returns
+// the problems and, for each process by index, the dependencies to keep.
+func dependencyProblems(processes []Process) ([]Problem, [][]int64) {
+	var (
+		problems []Problem
+		keep     = make([][]int64, len(processes))
+		index    = make(map[int64]int, len(processes))
+	)
+	for i := range processes {
+		if _, dup := index[processes[i].ProcessID]; !dup {
+			index[processes[i].ProcessID] = i
+		}
+	}
+	for i := range processes {
+		p := &processes[i]
+		for _, dep := range p.DependsOn {
+			_, known := index[dep]
+			switch {
+			case dep == p.ProcessID:
+				problems = append(problems, Problem{Row: i + 1, PID: p.ProcessID, Message: "depends on itself", Fix: "dependency dropped"})
+			case !known:
+				problems = append(problems, Problem{Row: i + 1, PID: p.ProcessID, Message: fmt.Sprintf("depends on unknown PID %d", dep), Fix: "dependency dropped"})
+			default:
+				keep[i] = append(keep[i], dep)
+			}
+		}
+	}
+
+	// Kahn's algorithm: whatever cannot be ordered waits on a cycle.
+	waiting := make([]int, len(processes))
+	dependents := make(map[int64][]int, len(processes))
+	var ready []int
+	for i := range processes {
+		waiting[i] = len(keep[i])
+		for _, dep := range keep[i] {
+			dependents[dep] = append(dependents[dep], i)
+		}
+		if waiting[i] == 0 {
+			ready = append(ready, i)
+		}
+	}
+	for len(ready) > 0 {
+		i := ready[0]
+		ready = ready[1:]
+		for _, d := range dependents[processes[i].ProcessID] {
+			if waiting[d]--; waiting[d] == 0 {
+				ready = append(ready, d)
+			}
+		}
+	}
+	// A dependency left over closes a cycle if its process, in turn, still
+	// waits for the dependent one. Dropping it breaks the cycle, so later
+	// processes on the same cycle keep theirs.
+	for i := range processes {
+		if waiting[i] == 0 {
+			continue
+		}
+		var cycle, rest []int64
+		for _, dep := range keep[i] {
+			if j := index[dep]; waiting[j] > 0 && waitsFor(processes, keep, index, j, processes[i].ProcessID) {
+				cycle = append(cycle, dep)
+			} else {
+				rest = append(rest, dep)
+			}
+		}
+		if len(cycle) > 0 {
+			problems = append(problems, Problem{
+				Row:     i + 1,
+				PID:     processes[i].ProcessID,
+				Message: fmt.Sprintf("dependency cycle through %s", formatPIDs(cycle)),
+				Fix:     "dependencies on the cycle dropped",
+			})
+		}
+		keep[i] = rest
+	}
+
+	return problems, keep
+}
+
+// waitsFor reports whether processes[from] depends, directly or not, on pid.
+func waitsFor(processes []Process, keep [][]int64, index map[int64]int, from int, pid int64) bool {
+	seen := map[int]bool{from: true}
+	stack := []int{from}
+	for len(stack) > 0 {
+		i := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, dep := range keep[i] {
+			if dep == pid {
+				return true
+			}
+			if j := index[dep]; !seen[j] {
+				seen[j] = true
+				stack = append(stack, j)
+			}
+		}
+	}
+	return false
+}
+
+func formatPIDs(pids []int64) string {
+	fields := make([]string, len(pids))
+	for i, pid := range pids {
+		fields[i] = fmt.Sprint(pid)
+	}
+	return strings.Join(fields, " ")
+}
+
+// initDependencies counts what each task waits for and who waits on it.
+func (e *engine) initDependencies() {
+	e.dependents = map[int64][]*Task{}
+	for _, t := range e.tasks {
+		for _, dep := range t.DependsOn {
+			e.dependents[dep] = append(e.dependents[dep], t)
+			t.depsLeft++
+		}
+	}
+}
+
+// releaseDependents readies the arrived tasks that were only waiting for t.
+func (e *engine) releaseDependents(t *Task) {
+	for _, d := range e.dependents[t.ProcessID] {
+		d.depsLeft--
+		if d.depsLeft == 0 && d.awaitingDeps {
+			d.awaitingDeps = false
+			e.depWaiting--
+			e.record(EventWake, d)
+			e.enqueue(d)
+		}
+	}
+}
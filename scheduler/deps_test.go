@@ -0,0 +1,82 @@
+package scheduler
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSimulate_dependencies(t *testing.T) {
+	t.Parallel()
+	// 2 and 3 arrive first but wait for 1; 3 also waits for 2.
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 1, BurstDuration: 4},
+		{ProcessID: 2, BurstDuration: 1, DependsOn: []int64{1}},
+		{ProcessID: 3, BurstDuration: 2, DependsOn: []int64{1, 2}},
+		{ProcessID: 4, ArrivalTime: 2, BurstDuration: 3},
+	}
+	tests := []struct {
+		name   string
+		policy Policy
+		want   []TimeSlice
+	}{
+		{
+			name:   "fcfs",
+			policy: fcfsPolicy{},
+			want: []TimeSlice{
+				{PID: 1, Start: 1, Stop: 5}, {PID: 4, Start: 5, Stop: 8}, {PID: 2, Start: 8, Stop: 9}, {PID: 3, Start: 9, Stop: 11},
+			},
+		},
+		{
+			name:   "sjf",
+			policy: sjfPolicy{},
+			want: []TimeSlice{
+				{PID: 1, Start: 1, Stop: 5}, {PID: 2, Start: 5, Stop: 6}, {PID: 3, Start: 6, Stop: 8}, {PID: 4, Start: 8, Stop: 11},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := Simulate(processes, tt.policy, EngineOptions{})
+			if !reflect.DeepEqual(got.Gantt, tt.want) {
+				t.Errorf("Gantt = %v, want %v", got.Gantt, tt.want)
+			}
+		})
+	}
+}
+
+func Test_dependencyProblems(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 1, DependsOn: []int64{1, 9}},
+		{ProcessID: 2, BurstDuration: 1, DependsOn: []int64{3}},
+		{ProcessID: 3, BurstDuration: 1, DependsOn: []int64{1, 2}},
+		{ProcessID: 4, BurstDuration: 1, DependsOn: []int64{3}},
+	}
+	problems, keep := dependencyProblems(processes)
+	var got []string
+	for _, p := range problems {
+		got = append(got, p.String())
+	}
+	want := []string{
+		"row 1 (PID 1): depends on itself",
+		"row 1 (PID 1): depends on unknown PID 9",
+		"row 2 (PID 2): dependency cycle through 3",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("problems = %q, want %q", got, want)
+	}
+	if wantKeep := [][]int64{nil, nil, {1, 2}, {3}}; !reflect.DeepEqual(keep, wantKeep) {
+		t.Errorf("keep = %v, want %v", keep, wantKeep)
+	}
+
+	fixed, err := checkProcesses(processes, true, &strings.Builder{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result := Simulate(fixed, fcfsPolicy{}, EngineOptions{}); len(result.Schedule) != len(processes) {
+		t.Errorf("fixed workload finished %d processes, want %d", len(result.Schedule), len(processes))
+	}
+}
@@ -0,0 +1,241 @@
+package scheduler
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"math/bits"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+// histogramWidth is the longest bar a histogram draws.
+const histogramWidth = 40
+
+// HistogramBin counts the values from Low to High, both included.
+type HistogramBin struct {
+	Low   int64
+	High  int64
+	Count int
+}
+
+// WorkloadDescription characterizes a workload before it is scheduled: what
+// its processes ask of the CPU and devices, and how they arrive.
+type WorkloadDescription struct {
+	Processes int
+	// CPUBound processes never block, IOBound ones spend longer blocked on
+	// I/O than running, and Mixed ones block for less than they run.
+	CPUBound int
+	IOBound  int
+	Mixed    int
+	// Demand is the CPU time every process and thread needs in all, and IO
+	// their time blocked on I/O.
+	Demand int64
+	IO     int64
+	// FirstArrival and LastArrival span the arrivals, and Horizon is when
+	// the last process would finish on one CPU with no overlap at all.
+	FirstArrival int64
+	LastArrival  int64
+	Horizon      int64
+	// OfferedLoad is the CPU demand per tick of the arrival span on each
+	// CPU: above 1, work arrives faster than the CPUs can do it and queues
+	// grow for as long as arrivals last. It is 0 when every process arrives
+	// at once.
+	OfferedLoad float64
+	// Bursts holds the CPU bursts and Gaps the times between consecutive
+	// arrivals, in power-of-two bins.
+	Bursts    []HistogramBin
+	Gaps      []HistogramBin
+	BurstMean float64
+	GapMean   float64
+}
+
+// DescribeWorkload characterizes processes as run on cpus CPUs.
+func DescribeWorkload(processes []Process, cpus int) WorkloadDescription {
+	d := WorkloadDescription{Processes: len(processes)}
+	if len(processes) == 0 {
+		return d
+	}
+	if cpus < 1 {
+		cpus = 1
+	}
+	var bursts []int64
+	arrivals := make([]int64, len(processes))
+	for i, p := range processes {
+		arrivals[i] = p.ArrivalTime
+		var cpu, blocked int64
+		switch {
+		case len(p.Bursts) > 0:
+			for j, b := range p.Bursts {
+				if j%2 == 1 {
+					blocked += b
+					continue
+				}
+				cpu += b
+				bursts = append(bursts, b)
+			}
+		case p.threadBursts() != nil:
+			for _, b := range p.threadBursts() {
+				cpu += b
+				bursts = append(bursts, b)
+			}
+		default:
+			cpu = p.BurstDuration
+			bursts = append(bursts, p.BurstDuration)
+		}
+		switch {
+		case blocked == 0:
+			d.CPUBound++
+		case blocked > cpu:
+			d.IOBound++
+		default:
+			d.Mixed++
+		}
+		d.Demand += cpu
+		d.IO += blocked
+	}
+	sort.Slice(arrivals, func(i, j int) bool { return arrivals[i] < arrivals[j] })
+	d.FirstArrival, d.LastArrival = arrivals[0], arrivals[len(arrivals)-1]
+	d.Horizon, _ = workloadHorizon(processes)
+	if span := d.LastArrival - d.FirstArrival; span > 0 {
+		d.OfferedLoad = float64(d.Demand) / float64(span*int64(cpus))
+	}
+	gaps := make([]int64, len(arrivals)-1)
+	for i := range gaps {
+		gaps[i] = arrivals[i+1] - arrivals[i]
+	}
+	d.Bursts, d.BurstMean = histogram(bursts)
+	d.Gaps, d.GapMean = histogram(gaps)
+	return d
+}
+
+// histogram bins values by powers of two, 0, 1, 2-3, 4-7 and so on, so a
+// few long values do not squash the rest into one bin, and returns their
+// mean. Bins between the smallest and largest value are kept even when
+// empty.
+func histogram(values []int64) ([]HistogramBin, float64) {
+	if len(values) == 0 {
+		return nil, 0
+	}
+	var (
+		counts   = map[int]int{}
+		lo, hi   = 64, 0
+		sum      float64
+		binRange = func(k int) (int64, int64) {
+			if k == 0 {
+				return 0, 0
+			}
+			return 1 << (k - 1), 1<<k - 1
+		}
+	)
+	for _, v := range values {
+		k := bits.Len64(uint64(maxInt64(v, 0)))
+		counts[k]++
+		if k < lo {
+			lo = k
+		}
+		if k > hi {
+			hi = k
+		}
+		sum += float64(v)
+	}
+	bins := make([]HistogramBin, 0, hi-lo+1)
+	for k := lo; k <= hi; k++ {
+		low, high := binRange(k)
+		bins = append(bins, HistogramBin{Low: low, High: high, Count: counts[k]})
+	}
+	return bins, sum / float64(len(values))
+}
+
+// outputHistogram draws bins as a bar chart, the longest bar histogramWidth
+// wide.
+func outputHistogram(w io.Writer, title string, bins []HistogramBin, mean float64) {
+	if len(bins) == 0 {
+		return
+	}
+	most := 0
+	for _, b := range bins {
+		if b.Count > most {
+			most = b.Count
+		}
+	}
+	_, _ = fmt.Fprintf(w, "%s (mean %.2f)\n", title, mean)
+	for _, b := range bins {
+		label := fmt.Sprint(b.Low)
+		if b.High > b.Low {
+			label = fmt.Sprintf("%d-%d", b.Low, b.High)
+		}
+		bar := b.Count * histogramWidth / most
+		if bar == 0 && b.Count > 0 {
+			bar = 1
+		}
+		_, _ = fmt.Fprintf(w, "  %12s | %-*s %d\n", label, histogramWidth, strings.Repeat("#", bar), b.Count)
+	}
+}
+
+// outputDescription prints a workload's characterization.
+func outputDescription(w io.Writer, d WorkloadDescription, cpus int) {
+	outputTitle(w, "Workload")
+	if d.Processes == 0 {
+		_, _ = fmt.Fprintln(w, "no processes")
+		return
+	}
+	percent := func(n int) string { return fmt.Sprintf("%d (%.0f%%)", n, 100*float64(n)/float64(d.Processes)) }
+	load := "- (every process arrives at once)"
+	if d.OfferedLoad > 0 {
+		load = fmt.Sprintf("%.3f", d.OfferedLoad)
+		if d.OfferedLoad >= 1 {
+			load += " (overloaded: queues grow while arrivals last)"
+		}
+	}
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Measure", "Value"})
+	table.AppendBulk([][]string{
+		{"Processes", fmt.Sprint(d.Processes)},
+		{"CPU-bound", percent(d.CPUBound)},
+		{"Mixed", percent(d.Mixed)},
+		{"I/O-bound", percent(d.IOBound)},
+		{"Arrivals", fmt.Sprintf("%d to %d", d.FirstArrival, d.LastArrival)},
+		{"CPU demand", fmt.Sprintf("%d (%.0f%% of the horizon)", d.Demand, 100*float64(d.Demand)/float64(maxInt64(d.Horizon, 1)))},
+		{"I/O time", fmt.Sprint(d.IO)},
+		{"Horizon", fmt.Sprint(d.Horizon)},
+		{fmt.Sprintf("Offered load (%d CPU)", cpus), load},
+	})
+	table.Render()
+	outputHistogram(w, "CPU bursts", d.Bursts, d.BurstMean)
+	outputHistogram(w, "Inter-arrival times", d.Gaps, d.GapMean)
+}
+
+// runDescribe is the describe subcommand: it characterizes a workload file
+// before any policy runs on it.
+func runDescribe(w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("describe", flag.ContinueOnError)
+	cpus := fs.Int("cpus", 1, "CPUs to take the offered load over")
+	lenient := fs.Bool("lenient", false, "fix invalid processes with a warning instead of rejecting the workload")
+	if err := parseFlags(fs, "describe", args); err != nil {
+		return err
+	}
+	if fs.NArg() > 1 {
+		return fmt.Errorf("%w: usage: describe [-cpus 1] file", ErrInvalidArgs)
+	}
+	if *cpus < 1 {
+		return fmt.Errorf("%w: -cpus must be at least 1", ErrInvalidArgs)
+	}
+	f, closeFile, err := openProcessingFile(append([]string{"describe"}, fs.Args()...)...)
+	if err != nil {
+		return err
+	}
+	defer closeFile()
+	processes, err := loadProcesses(f)
+	if err != nil {
+		return err
+	}
+	if processes, err = checkProcesses(processes, *lenient, os.Stderr); err != nil {
+		return err
+	}
+	outputDescription(w, DescribeWorkload(processes, *cpus), *cpus)
+	return nil
+}
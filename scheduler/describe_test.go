@@ -0,0 +1,81 @@
+package scheduler
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDescribeWorkload(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		NewProcess(1, 0, 2, 5),
+		{ProcessID: 2, ArrivalTime: 1, BurstDuration: 3, Bursts: []int64{1, 2, 2}},
+		{ProcessID: 3, ArrivalTime: 3, BurstDuration: 2, Bursts: []int64{1, 30, 1}},
+		{ProcessID: 4, ArrivalTime: 11, BurstDuration: 9, ThreadBursts: []int64{9, 4}},
+	}
+	got := DescribeWorkload(processes, 2)
+	want := WorkloadDescription{
+		Processes: 4, CPUBound: 2, IOBound: 1, Mixed: 1,
+		Demand: 20, IO: 32, FirstArrival: 0, LastArrival: 11,
+		// The last arrival at 11, then 2+5+32+9 ticks one after another.
+		Horizon:     59,
+		OfferedLoad: 20.0 / 22,
+		Bursts: []HistogramBin{
+			{Low: 1, High: 1, Count: 3}, {Low: 2, High: 3, Count: 2},
+			{Low: 4, High: 7, Count: 1}, {Low: 8, High: 15, Count: 1},
+		},
+		Gaps:      []HistogramBin{{Low: 1, High: 1, Count: 1}, {Low: 2, High: 3, Count: 1}, {Low: 4, High: 7, Count: 0}, {Low: 8, High: 15, Count: 1}},
+		BurstMean: 20.0 / 7,
+		GapMean:   11.0 / 3,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DescribeWorkload() = %+v, want %+v", got, want)
+	}
+}
+
+func Test_histogram(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		values []int64
+		want   []HistogramBin
+		mean   float64
+	}{
+		{name: "empty"},
+		{name: "zeros", values: []int64{0, 0}, want: []HistogramBin{{Count: 2}}},
+		{
+			name:   "powers of two",
+			values: []int64{0, 4, 7, 8},
+			want: []HistogramBin{
+				{Count: 1}, {Low: 1, High: 1}, {Low: 2, High: 3},
+				{Low: 4, High: 7, Count: 2}, {Low: 8, High: 15, Count: 1},
+			},
+			mean: 4.75,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, mean := histogram(tt.values)
+			if !reflect.DeepEqual(got, tt.want) || mean != tt.mean {
+				t.Errorf("histogram() = %v, %v, want %v, %v", got, mean, tt.want, tt.mean)
+			}
+		})
+	}
+}
+
+func Test_runDescribe(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	if err := runDescribe(&buf, []string{"-cpus", "2", "../example_processes.csv"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{"Offered load (2 CPU)", "1.667 (overloaded", "CPU bursts (mean 6.67)", "  4-7 | ####"} {
+		if !strings.Contains(buf.String(), s) {
+			t.Errorf("describe output missing %q:\n%s", s, buf.String())
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package scheduler
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+)
+
+// processDelimiters are the field separators a process file may use, as
+// exported by spreadsheets and other tools in different locales.
+var processDelimiters = []rune{',', ';', '\t'}
+
+// parseDelimiter reads a -delimiter value: auto (0, detect it), or one of
+// processDelimiters by name or as itself.
+func parseDelimiter(s string) (rune, error) {
+	switch s {
+	case "", "auto":
+		return 0, nil
+	case "comma", ",":
+		return ',', nil
+	case "semicolon", ";":
+		return ';', nil
+	case "tab", "\t", `\t`:
+		return '\t', nil
+	}
+	return 0, fmt.Errorf("%w: -delimiter must be auto, comma, semicolon or tab, not %q", ErrInvalidArgs, s)
+}
+
+// detectDelimiter guesses the delimiter of the process file br reads from
+// its first row, without consuming it: whichever of processDelimiters that
+// row has most of, outside quotes. A comma is assumed if the row is not
+// within the first few kilobytes.
+func detectDelimiter(br *bufio.Reader) rune {
+	head, _ := br.Peek(4096)
+	for _, line := range bytes.Split(head, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 || line[0] == '#' {
+			continue
+		}
+		counts := map[rune]int{}
+		quoted := false
+		for _, c := range string(line) {
+			if c == '"' {
+				quoted = !quoted
+			} else if !quoted {
+				counts[c]++
+			}
+		}
+		best := ','
+		for _, d := range processDelimiters {
+			if counts[d] > counts[best] {
+				best = d
+			}
+		}
+		return best
+	}
+	return ','
+}
@@ -0,0 +1,87 @@
+package scheduler
+
+import (
+	"bufio"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_detectDelimiter(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		in   string
+		want rune
+	}{
+		{name: "comma", in: "1,5,0,2\n", want: ','},
+		{name: "semicolon", in: "1;5;0;2\n", want: ';'},
+		{name: "tab", in: "1\t5\t0\t2\n", want: '\t'},
+		{name: "after comments and blank lines", in: "# pid,burst,arrival\n\n1;5;0\n", want: ';'},
+		{name: "quoted commas", in: "1;5;0;2;batch;\"1,2\"\n", want: ';'},
+		{name: "empty", in: "", want: ','},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := detectDelimiter(bufio.NewReader(strings.NewReader(tt.in))); got != tt.want {
+				t.Errorf("detectDelimiter() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_parseDelimiter(t *testing.T) {
+	t.Parallel()
+	for in, want := range map[string]rune{"auto": 0, ",": ',', "semicolon": ';', "tab": '\t'} {
+		if got, err := parseDelimiter(in); err != nil || got != want {
+			t.Errorf("parseDelimiter(%q) = %q, %v, want %q", in, got, err, want)
+		}
+	}
+	if _, err := parseDelimiter("|"); !errors.Is(err, ErrInvalidArgs) {
+		t.Errorf("parseDelimiter(|) error = %v, want %v", err, ErrInvalidArgs)
+	}
+}
+
+func TestLoadProcesses_dialects(t *testing.T) {
+	t.Parallel()
+	want := []Process{
+		{ProcessID: 1, BurstDuration: 5, ArrivalTime: 0, Priority: 2},
+		{ProcessID: 2, BurstDuration: 3, ArrivalTime: 1, Priority: 1, Bursts: []int64{1, 2, 2}},
+	}
+	tests := []struct {
+		name  string
+		in    string
+		comma rune
+	}{
+		{name: "comma", in: "1,5,0,2\n2,3,1,1,batch,1 2 2\n"},
+		{name: "semicolon with comments", in: "# exported from a spreadsheet\n1;5;0;2\n\n# second\n2;3;1;1;batch;1 2 2\n"},
+		{name: "tab", in: "1\t5\t0\t2\n2\t3\t1\t1\tbatch\t1 2 2\n"},
+		{name: "quoted and spaced", in: "\"1\", \"5\", \"0\", \"2\"\n2, 3, 1, 1, \"batch\", \"1 2 2\"\n"},
+		{name: "given", in: "1;5;0;2\n2;3;1;1;batch;1 2 2\n", comma: ';'},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := loadProcessFile(strings.NewReader(tt.in), ProcessFileOptions{Comma: tt.comma})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("loadProcessFile() = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestLoadProcesses_commentLines(t *testing.T) {
+	t.Parallel()
+	_, err := loadProcesses(strings.NewReader("# header\n1,5,0\n\n2,x,3\n"))
+	var bad *ProcessFileError
+	if !errors.As(err, &bad) || len(bad.Errors) != 1 || bad.Errors[0].Line != 4 {
+		t.Errorf("loadProcesses() error = %v, want one bad value on line 4", err)
+	}
+}
@@ -0,0 +1,244 @@
+package scheduler
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+type (
+	// ResultDiff compares two schedules of the same workload, process by
+	// process and slice by slice.
+	ResultDiff struct {
+		Names [2]string
+		Rows  []DiffRow
+		// Averages are the two runs' average wait and turnaround.
+		Averages [2]Metrics
+		// Diverges is set when the Gantt charts differ, At being the first
+		// tick they do and Running the PIDs the two ran then, zero for idle.
+		Diverges bool
+		At       int64
+		Running  [2]int64
+	}
+	// DiffRow is one process's wait and turnaround in each run; Finished
+	// is unset for a run the process did not finish in.
+	DiffRow struct {
+		ProcessID  int64
+		Finished   [2]bool
+		Wait       [2]int64
+		Turnaround [2]int64
+	}
+)
+
+// DiffResults compares a with b, lining their processes up by PID.
+func DiffResults(names [2]string, a, b Result) ResultDiff {
+	d := ResultDiff{Names: names, Averages: [2]Metrics{a.Metrics, b.Metrics}}
+	index := map[int64]int{}
+	for side, r := range [2]Result{a, b} {
+		for _, row := range r.Schedule {
+			i, ok := index[row.ProcessID]
+			if !ok {
+				i = len(d.Rows)
+				index[row.ProcessID] = i
+				d.Rows = append(d.Rows, DiffRow{ProcessID: row.ProcessID})
+			}
+			d.Rows[i].Finished[side] = true
+			d.Rows[i].Wait[side] = row.Wait
+			d.Rows[i].Turnaround[side] = row.Turnaround
+		}
+	}
+	sort.SliceStable(d.Rows, func(i, j int) bool { return d.Rows[i].ProcessID < d.Rows[j].ProcessID })
+	d.At, d.Diverges = divergence(a.Gantt, b.Gantt)
+	if d.Diverges {
+		d.Running = [2]int64{runningAt(a.Gantt, d.At), runningAt(b.Gantt, d.At)}
+	}
+	return d
+}
+
+// divergence finds the first tick at which the two Gantt charts run
+// different processes, once back-to-back slices are merged.
+func divergence(a, b []TimeSlice) (int64, bool) {
+	a, b = mergeGantt(a), mergeGantt(b)
+	for i := 0; i < len(a) || i < len(b); i++ {
+		switch {
+		case i == len(a):
+			return b[i].Start, true
+		case i == len(b):
+			return a[i].Start, true
+		case a[i] == b[i]:
+			continue
+		case a[i].Start != b[i].Start:
+			return minInt64(a[i].Start, b[i].Start), true
+		case a[i].PID != b[i].PID || a[i].CPU != b[i].CPU:
+			return a[i].Start, true
+		default:
+			return minInt64(a[i].Stop, b[i].Stop), true
+		}
+	}
+	return 0, false
+}
+
+// runningAt is the process running at t, on the lowest CPU if several
+// are, or zero if none is.
+func runningAt(gantt []TimeSlice, t int64) int64 {
+	var (
+		pid int64
+		cpu = -1
+	)
+	for _, s := range gantt {
+		if s.Start <= t && t < s.Stop && (cpu < 0 || s.CPU < cpu) {
+			pid, cpu = s.PID, s.CPU
+		}
+	}
+	return pid
+}
+
+// outputDiff prints the per-process deltas, b minus a, then both Gantt
+// charts on one time axis with the first divergence marked.
+func outputDiff(w io.Writer, d ResultDiff, a, b []TimeSlice, opts GanttOptions) {
+	outputTitle(w, fmt.Sprintf("%s vs %s", d.Names[0], d.Names[1]))
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"ID", "Wait " + d.Names[0], "Wait " + d.Names[1], "Delta", "Turnaround " + d.Names[0], "Turnaround " + d.Names[1], "Delta"})
+	for _, r := range d.Rows {
+		row := []string{fmt.Sprint(r.ProcessID)}
+		row = append(row, diffCells(r.Finished, r.Wait)...)
+		row = append(row, diffCells(r.Finished, r.Turnaround)...)
+		table.Append(row)
+	}
+	avg := d.Averages
+	table.SetFooter([]string{"Average",
+		fmt.Sprintf("%.2f", avg[0].AverageWait), fmt.Sprintf("%.2f", avg[1].AverageWait),
+		fmt.Sprintf("%+.2f", avg[1].AverageWait-avg[0].AverageWait),
+		fmt.Sprintf("%.2f", avg[0].AverageTurnaround), fmt.Sprintf("%.2f", avg[1].AverageTurnaround),
+		fmt.Sprintf("%+.2f", avg[1].AverageTurnaround-avg[0].AverageTurnaround)})
+	table.Render()
+
+	if !d.Diverges {
+		_, _ = fmt.Fprintln(w, "The Gantt charts are the same")
+		return
+	}
+	_, _ = fmt.Fprintf(w, "First divergence at t=%d: %s runs %s, %s runs %s\n",
+		d.At, d.Names[0], describeRunning(d.Running[0]), d.Names[1], describeRunning(d.Running[1]))
+	if len(a) == 0 || len(b) == 0 {
+		return
+	}
+	outputGanttPair(w, d, a, b, opts)
+}
+
+// diffCells formats a measure in both runs and its change, with a dash
+// for a run the process did not finish in.
+func diffCells(finished [2]bool, v [2]int64) []string {
+	cells := []string{"-", "-", "-"}
+	for side := range v {
+		if finished[side] {
+			cells[side] = fmt.Sprint(v[side])
+		}
+	}
+	switch {
+	case !finished[0] || !finished[1]:
+	case v[0] == v[1]:
+		cells[2] = "0"
+	default:
+		cells[2] = fmt.Sprintf("%+d", v[1]-v[0])
+	}
+	return cells
+}
+
+func describeRunning(pid int64) string {
+	if pid == 0 {
+		return "nothing"
+	}
+	return fmt.Sprintf("P%d", pid)
+}
+
+// outputGanttPair draws the two Gantt charts on one time axis, one lane per
+// CPU each, with a caret under the column where they first diverge.
+func outputGanttPair(w io.Writer, d ResultDiff, a, b []TimeSlice, opts GanttOptions) {
+	_, _ = fmt.Fprintln(w, "Gantt schedules")
+	width := opts.width()
+	originA, endA := ganttSpan(a)
+	originB, endB := ganttSpan(b)
+	origin, end := minInt64(originA, originB), endA
+	if endB > end {
+		end = endB
+	}
+	col := ganttColumns(origin, end, width, opts)
+	for side, gantt := range [2][]TimeSlice{a, b} {
+		for _, lane := range ganttLanes(gantt) {
+			label := d.Names[side]
+			if len(ganttLanes(gantt)) > 1 {
+				label = fmt.Sprintf("%s, CPU %d", label, lane[0].CPU)
+			}
+			_, _ = fmt.Fprintln(w, label)
+			outputGanttLane(w, lane, origin, end, col, width, opts)
+		}
+	}
+	// A wrapped chart puts the divergence on a later line; the caret only
+	// fits under the first.
+	if x := col(d.At); x < width {
+		_, _ = fmt.Fprintf(w, "%s^ t=%d\n", strings.Repeat(" ", x), d.At)
+	}
+	_, _ = fmt.Fprintln(w)
+}
+
+// runDiff is the diff subcommand: two result files, or two algorithms run
+// on a workload, compared process by process and slice by slice.
+func runDiff(w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("diff", flag.ContinueOnError)
+	lenient := fs.Bool("lenient", false, "fix invalid processes with a warning instead of rejecting the workload")
+	seed := addSeedFlag(fs, "random seed for stochastic algorithms")
+	ganttFlags := addGanttFlags(fs)
+	algoFlags := addAlgorithmFlags(fs)
+	if err := parseFlags(fs, "diff", args); err != nil {
+		return err
+	}
+	var (
+		names [2]string
+		runs  [2]Result
+	)
+	switch fs.NArg() {
+	case 2:
+		for i := range runs {
+			names[i] = fs.Arg(i)
+			if err := readJSONFile(fs.Arg(i), &runs[i]); err != nil {
+				return err
+			}
+		}
+	case 3:
+		opts, err := algoFlags.options()
+		if err != nil {
+			return err
+		}
+		opts.Seed = pickSeed(os.Stderr, *seed)
+		f, closeFile, err := openProcessingFile("diff", fs.Arg(2))
+		if err != nil {
+			return err
+		}
+		defer closeFile()
+		processes, err := loadProcesses(f)
+		if err != nil {
+			return err
+		}
+		if processes, err = checkProcesses(processes, *lenient, os.Stderr); err != nil {
+			return err
+		}
+		for i := range runs {
+			a, ok := LookupAlgorithm(fs.Arg(i))
+			if !ok {
+				return fmt.Errorf("%w: unknown algorithm %q", ErrInvalidArgs, fs.Arg(i))
+			}
+			names[i] = a.Name
+			runs[i] = Simulate(processes, a.New(processes, opts), EngineOptions{})
+		}
+	default:
+		return fmt.Errorf("%w: usage: diff a.json b.json, or diff algorithm algorithm workload.csv", ErrInvalidArgs)
+	}
+	d := DiffResults(names, runs[0], runs[1])
+	outputDiff(w, d, runs[0].Gantt, runs[1].Gantt, ganttFlags.options(w))
+	return nil
+}
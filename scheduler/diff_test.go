@@ -0,0 +1,88 @@
+package scheduler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_divergence(t *testing.T) {
+	t.Parallel()
+	base := []TimeSlice{{PID: 1, Start: 0, Stop: 4}, {PID: 2, Start: 4, Stop: 6}}
+	tests := []struct {
+		name   string
+		b      []TimeSlice
+		want   int64
+		differ bool
+	}{
+		{name: "same", b: base},
+		{
+			// Back-to-back quanta of one process are the same schedule.
+			name: "split slices",
+			b:    []TimeSlice{{PID: 1, Start: 0, Stop: 2}, {PID: 1, Start: 2, Stop: 4}, {PID: 2, Start: 4, Stop: 6}},
+		},
+		{name: "other process", b: []TimeSlice{{PID: 2, Start: 0, Stop: 2}, {PID: 1, Start: 2, Stop: 6}}, want: 0, differ: true},
+		{name: "shorter slice", b: []TimeSlice{{PID: 1, Start: 0, Stop: 3}, {PID: 2, Start: 3, Stop: 5}, {PID: 1, Start: 5, Stop: 6}}, want: 3, differ: true},
+		{name: "later start", b: []TimeSlice{{PID: 1, Start: 0, Stop: 4}, {PID: 2, Start: 5, Stop: 7}}, want: 4, differ: true},
+		{name: "extra slice", b: append(base[:2:2], TimeSlice{PID: 3, Start: 6, Stop: 7}), want: 6, differ: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, differ := divergence(base, tt.b)
+			if got != tt.want || differ != tt.differ {
+				t.Errorf("divergence() = %d, %v, want %d, %v", got, differ, tt.want, tt.differ)
+			}
+		})
+	}
+}
+
+func TestDiffResults(t *testing.T) {
+	t.Parallel()
+	processes := []Process{NewProcess(1, 0, 5, 2), NewProcess(2, 3, 9, 1), NewProcess(3, 6, 6, 3)}
+	a, b := FCFS(processes), RR(processes)
+	d := DiffResults([2]string{"fcfs", "rr"}, a, b)
+	if !d.Diverges || d.At != 10 || d.Running != [2]int64{2, 3} {
+		t.Errorf("divergence = %v at %d running %v, want at 10 running [2 3]", d.Diverges, d.At, d.Running)
+	}
+	want := DiffRow{ProcessID: 2, Finished: [2]bool{true, true}, Wait: [2]int64{2, 7}, Turnaround: [2]int64{11, 16}}
+	if len(d.Rows) != 3 || !reflect.DeepEqual(d.Rows[1], want) {
+		t.Errorf("Rows = %+v, want P2 %+v", d.Rows, want)
+	}
+}
+
+func Test_runDiff(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	processes := []Process{NewProcess(1, 0, 1, 0), NewProcess(2, 1, 2, 0)}
+	var files []string
+	for _, r := range []Result{FCFS(processes), SJF(processes)} {
+		b, err := json.Marshal(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		p := filepath.Join(dir, fmt.Sprintf("%d.json", len(files)))
+		if err := os.WriteFile(p, b, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, p)
+	}
+	var w bytes.Buffer
+	if err := runDiff(&w, append([]string{"-no-color"}, files...)); err != nil {
+		t.Fatal(err)
+	}
+	// The processes never compete, so every algorithm runs them alike.
+	if !strings.Contains(w.String(), "The Gantt charts are the same") {
+		t.Errorf("runDiff() = %q, want the same charts", w.String())
+	}
+	if err := runDiff(&w, []string{"fcfs"}); !errors.Is(err, ErrInvalidArgs) {
+		t.Errorf("runDiff() with one argument: err = %v, want ErrInvalidArgs", err)
+	}
+}
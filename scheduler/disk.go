@@ -0,0 +1,538 @@
+package scheduler
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"html"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+var ErrInvalidDiskRequest = errors.New("invalid disk request")
+
+type (
+	// DiskRequest asks for a cylinder at Arrival.
+	DiskRequest struct {
+		Cylinder int64
+		Arrival  int64
+	}
+	// DiskOptions describe the disk: it has cylinders 0 to Cylinders-1, and
+	// its arm starts at Head moving towards the last of them, or towards 0
+	// if Down is set. The arm crosses a cylinder a tick.
+	DiskOptions struct {
+		Cylinders int64
+		Head      int64
+		Down      bool
+	}
+	// DiskService is a request served: the Request'th in the list, served
+	// once the arm reached its cylinder at Served, Seek cylinders after
+	// serving the one before.
+	DiskService struct {
+		Request  int
+		Cylinder int64
+		Arrival  int64
+		Served   int64
+		Seek     int64
+		Wait     int64
+	}
+	// DiskStop is a point on the arm's path: where it was at Time, having
+	// served a request there, turned round, or, if Return is set, swept
+	// back from the far end without serving anything on the way.
+	DiskStop struct {
+		Time     int64
+		Cylinder int64
+		Served   bool
+		Return   bool
+	}
+	// DiskResult is one algorithm's run over the requests.
+	DiskResult struct {
+		Algorithm string
+		Services  []DiskService
+		Path      []DiskStop
+		// Movement is the number of cylinders the arm crossed in all.
+		Movement    int64
+		AverageWait float64
+		MaxWait     int64
+	}
+)
+
+// A diskPolicy makes the arm's next move while requests are pending:
+// serving one at the head's cylinder with d.serve, or moving the head.
+type diskPolicy func(d *disk)
+
+// diskAlgorithms are the disk-arm scheduling algorithms, in the order they
+// are compared.
+var diskAlgorithms = []struct {
+	name, title string
+	policy      diskPolicy
+}{
+	{"fcfs", "FCFS", diskFCFS},
+	{"sstf", "SSTF", diskSSTF},
+	{"scan", "SCAN", diskSCAN},
+	{"cscan", "C-SCAN", diskCSCAN},
+	{"look", "LOOK", diskLOOK},
+}
+
+// disk is the arm's state while a policy runs. pending holds the requests
+// arrived and not yet served, in the order they arrived, and last the
+// movement when the one before was served.
+type disk struct {
+	opts     DiskOptions
+	requests []DiskRequest
+	pending  []int
+	head     int64
+	dir      int64
+	now      int64
+	last     int64
+	result   *DiskResult
+}
+
+// SimulateDisk runs policy over requests, which must all be for cylinders
+// on the disk.
+func SimulateDisk(requests []DiskRequest, policy diskPolicy, opts DiskOptions) DiskResult {
+	var result DiskResult
+	d := &disk{opts: opts, requests: requests, head: opts.Head, dir: 1, result: &result}
+	if opts.Down {
+		d.dir = -1
+	}
+	order := make([]int, len(requests))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return requests[order[i]].Arrival < requests[order[j]].Arrival })
+	if len(order) > 0 {
+		d.now = requests[order[0]].Arrival
+	}
+	result.Path = append(result.Path, DiskStop{Time: d.now, Cylinder: d.head})
+	for next := 0; next < len(order) || len(d.pending) > 0; {
+		for ; next < len(order) && requests[order[next]].Arrival <= d.now; next++ {
+			d.pending = append(d.pending, order[next])
+		}
+		if len(d.pending) == 0 {
+			// The arm rests where it is until the next request.
+			d.now = requests[order[next]].Arrival
+			continue
+		}
+		policy(d)
+	}
+	for _, s := range result.Services {
+		result.AverageWait += float64(s.Wait)
+		result.MaxWait = maxInt64(result.MaxWait, s.Wait)
+	}
+	if len(result.Services) > 0 {
+		result.AverageWait /= float64(len(result.Services))
+	}
+	return result
+}
+
+// at is the index in pending of the first request for the head's cylinder,
+// or -1 if there is none.
+func (d *disk) at() int {
+	for i, r := range d.pending {
+		if d.requests[r].Cylinder == d.head {
+			return i
+		}
+	}
+	return -1
+}
+
+// ahead reports whether any pending request lies beyond the head in dir.
+func (d *disk) ahead(dir int64) bool {
+	for _, r := range d.pending {
+		if (d.requests[r].Cylinder-d.head)*dir > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// atEnd reports whether the head is at the last cylinder in its direction.
+func (d *disk) atEnd() bool {
+	return (d.dir > 0 && d.head == d.opts.Cylinders-1) || (d.dir < 0 && d.head == 0)
+}
+
+// serve serves pending[i], which is at the head's cylinder.
+func (d *disk) serve(i int) {
+	r := d.pending[i]
+	d.pending = append(d.pending[:i], d.pending[i+1:]...)
+	req := d.requests[r]
+	d.result.Services = append(d.result.Services, DiskService{
+		Request:  r + 1,
+		Cylinder: req.Cylinder,
+		Arrival:  req.Arrival,
+		Served:   d.now,
+		Seek:     d.result.Movement - d.last,
+		Wait:     d.now - req.Arrival,
+	})
+	d.last = d.result.Movement
+	d.stop(DiskStop{Time: d.now, Cylinder: d.head, Served: true})
+}
+
+// seek moves the head a cylinder in dir, noting on the path where it turned
+// round to do so.
+func (d *disk) seek(dir int64) {
+	if dir != d.dir {
+		d.stop(DiskStop{Time: d.now, Cylinder: d.head})
+		d.dir = dir
+	}
+	d.head += dir
+	d.now++
+	d.result.Movement++
+}
+
+// sweepBack returns the head from the end it reached to the other end,
+// serving nothing on the way, to sweep the same direction again.
+func (d *disk) sweepBack() {
+	d.stop(DiskStop{Time: d.now, Cylinder: d.head})
+	distance := d.opts.Cylinders - 1
+	d.head = d.opts.Cylinders - 1 - d.head
+	d.now += distance
+	d.result.Movement += distance
+	d.stop(DiskStop{Time: d.now, Cylinder: d.head, Return: true})
+}
+
+// stop adds s to the path, unless the arm is already shown there.
+func (d *disk) stop(s DiskStop) {
+	path := d.result.Path
+	if last := path[len(path)-1]; last.Time == s.Time && last.Cylinder == s.Cylinder {
+		path[len(path)-1].Served = last.Served || s.Served
+		return
+	}
+	d.result.Path = append(path, s)
+}
+
+// towards is the direction from the head to cylinder.
+func (d *disk) towards(cylinder int64) int64 {
+	if cylinder < d.head {
+		return -1
+	}
+	return 1
+}
+
+// diskFCFS serves requests in the order they arrived.
+func diskFCFS(d *disk) {
+	r := d.requests[d.pending[0]]
+	if r.Cylinder == d.head {
+		d.serve(0)
+		return
+	}
+	d.seek(d.towards(r.Cylinder))
+}
+
+// diskSSTF serves the request closest to the head next, the first to
+// arrive of those as close.
+func diskSSTF(d *disk) {
+	best := 0
+	distance := func(i int) int64 {
+		c := d.requests[d.pending[i]].Cylinder - d.head
+		if c < 0 {
+			return -c
+		}
+		return c
+	}
+	for i := range d.pending {
+		if distance(i) < distance(best) {
+			best = i
+		}
+	}
+	if distance(best) == 0 {
+		d.serve(best)
+		return
+	}
+	d.seek(d.towards(d.requests[d.pending[best]].Cylinder))
+}
+
+// diskSCAN, the elevator algorithm, sweeps the arm from one end of the disk
+// to the other and back, serving requests as it passes them.
+func diskSCAN(d *disk) {
+	if i := d.at(); i >= 0 {
+		d.serve(i)
+		return
+	}
+	dir := d.dir
+	if d.atEnd() {
+		dir = -dir
+	}
+	d.seek(dir)
+}
+
+// diskCSCAN sweeps only one way, returning the arm to the other end once it
+// reaches the last cylinder, so requests at either end wait alike.
+func diskCSCAN(d *disk) {
+	if i := d.at(); i >= 0 {
+		d.serve(i)
+		return
+	}
+	if d.atEnd() {
+		d.sweepBack()
+		return
+	}
+	d.seek(d.dir)
+}
+
+// diskLOOK is SCAN turning round at the last request in each direction
+// rather than at the end of the disk.
+func diskLOOK(d *disk) {
+	if i := d.at(); i >= 0 {
+		d.serve(i)
+		return
+	}
+	dir := d.dir
+	if !d.ahead(dir) {
+		dir = -dir
+	}
+	d.seek(dir)
+}
+
+// readListFields reads a list separated by spaces, commas or lines, in
+// which a # starts a comment running to the end of its line.
+func readListFields(r io.Reader) ([]string, error) {
+	var fields []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		fields = append(fields, strings.FieldsFunc(line, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' })...)
+	}
+	return fields, scanner.Err()
+}
+
+// parseDiskRequests reads a list of cylinder requests, each a cylinder
+// arriving at 0 or cylinder@arrival.
+func parseDiskRequests(r io.Reader) ([]DiskRequest, error) {
+	fields, err := readListFields(r)
+	if err != nil {
+		return nil, err
+	}
+	requests := make([]DiskRequest, 0, len(fields))
+	for _, f := range fields {
+		cylinder, arrival, timed := strings.Cut(f, "@")
+		var req DiskRequest
+		if req.Cylinder, err = strconv.ParseInt(cylinder, 10, 64); err != nil {
+			return nil, fmt.Errorf("%w: %q is not cylinder or cylinder@arrival", ErrInvalidDiskRequest, f)
+		}
+		if timed {
+			if req.Arrival, err = strconv.ParseInt(arrival, 10, 64); err != nil || req.Arrival < 0 {
+				return nil, fmt.Errorf("%w: %q does not arrive at a time of 0 or more", ErrInvalidDiskRequest, f)
+			}
+		}
+		requests = append(requests, req)
+	}
+	return requests, nil
+}
+
+// checkDisk makes sure the arm starts on the disk and every request is for
+// a cylinder on it.
+func checkDisk(requests []DiskRequest, opts DiskOptions) error {
+	if opts.Cylinders < 1 {
+		return fmt.Errorf("%w: a disk needs at least one cylinder", ErrInvalidArgs)
+	}
+	if opts.Head < 0 || opts.Head >= opts.Cylinders {
+		return fmt.Errorf("%w: the head must start on a cylinder from 0 to %d", ErrInvalidArgs, opts.Cylinders-1)
+	}
+	if len(requests) == 0 {
+		return fmt.Errorf("%w: no requests", ErrInvalidDiskRequest)
+	}
+	for i, r := range requests {
+		if r.Cylinder < 0 || r.Cylinder >= opts.Cylinders {
+			return fmt.Errorf("%w: request %d is for cylinder %d, off a disk of cylinders 0 to %d", ErrInvalidDiskRequest, i+1, r.Cylinder, opts.Cylinders-1)
+		}
+	}
+	return nil
+}
+
+func outputDisk(w io.Writer, result DiskResult) {
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Request", "Cylinder", "Arrival", "Served", "Seek", "Wait"})
+	for _, s := range result.Services {
+		table.Append([]string{
+			fmt.Sprint(s.Request),
+			fmt.Sprint(s.Cylinder),
+			fmt.Sprint(s.Arrival),
+			fmt.Sprint(s.Served),
+			fmt.Sprint(s.Seek),
+			fmt.Sprint(s.Wait),
+		})
+	}
+	table.SetFooter([]string{"", "", "", "Total/Average", fmt.Sprint(result.Movement), fmt.Sprintf("%.2f", result.AverageWait)})
+	table.Render()
+}
+
+// outputDiskChart draws the arm's path down the page, a line per stop on
+// it, with the cylinders across a line width columns wide. Each line marks
+// the stop with * where a request was served and + where the arm turned,
+// and the way it came from the stop before with -, or . for a return sweep.
+func outputDiskChart(w io.Writer, result DiskResult, cylinders int64, width int) {
+	label := func(s DiskStop) string { return fmt.Sprintf("t=%-5d %5d |", s.Time, s.Cylinder) }
+	cols := width - len(label(DiskStop{}))
+	if cols < 10 {
+		cols = 10
+	}
+	col := func(c int64) int {
+		if cylinders <= 1 {
+			return 0
+		}
+		return int(c * int64(cols-1) / (cylinders - 1))
+	}
+	_, _ = fmt.Fprintf(w, "Head movement (cylinders 0-%d):\n", cylinders-1)
+	prev := -1
+	for _, s := range result.Path {
+		line := []byte(strings.Repeat(" ", cols))
+		here := col(s.Cylinder)
+		if prev >= 0 {
+			from, to := prev, here
+			if from > to {
+				from, to = to, from
+			}
+			fill := byte('-')
+			if s.Return {
+				fill = '.'
+			}
+			for i := from; i <= to; i++ {
+				line[i] = fill
+			}
+		}
+		line[here] = '+'
+		if s.Served {
+			line[here] = '*'
+		}
+		prev = here
+		_, _ = fmt.Fprintf(w, "%s%s\n", label(s), strings.TrimRight(string(line), " "))
+	}
+}
+
+func outputDiskComparison(w io.Writer, results []DiskResult) {
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Algorithm", "Head movement", "Average wait", "Max wait"})
+	for _, r := range results {
+		table.Append([]string{r.Algorithm, fmt.Sprint(r.Movement), fmt.Sprintf("%.2f", r.AverageWait), fmt.Sprint(r.MaxWait)})
+	}
+	table.Render()
+}
+
+// diskColors are the colours writeDiskSVG draws each algorithm's path in.
+var diskColors = []string{"steelblue", "seagreen", "darkorange", "crimson", "purple", "saddlebrown"}
+
+// writeDiskSVG draws the algorithms' arm paths on shared axes: cylinders
+// across and time down, a line per algorithm with a dot where it served a
+// request and a dashed line for a return sweep.
+func writeDiskSVG(w io.Writer, results []DiskResult, cylinders int64) error {
+	bw := bufio.NewWriter(w)
+	const left, top, plotWidth, plotHeight = 60, 40, 600, 400
+	var end int64
+	for _, r := range results {
+		end = maxInt64(end, r.Path[len(r.Path)-1].Time)
+	}
+	x := func(c int64) float64 { return left + float64(c)*plotWidth/float64(maxInt64(cylinders-1, 1)) }
+	y := func(t int64) float64 { return top + float64(t)*plotHeight/float64(maxInt64(end, 1)) }
+	legend := top + plotHeight + 30
+	_, _ = fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" font-family="sans-serif" font-size="12">`+"\n",
+		left+plotWidth+40, legend+len(results)*16+10)
+	_, _ = fmt.Fprintf(bw, `  <text x="%d" y="14">0</text><text x="%.1f" y="14" text-anchor="end">cylinder %d</text>`+"\n", left, x(cylinders-1), cylinders-1)
+	_, _ = fmt.Fprintf(bw, `  <line x1="%d" y1="%d" x2="%.1f" y2="%d" stroke="black"/>`+"\n", left, top-10, x(cylinders-1), top-10)
+	_, _ = fmt.Fprintf(bw, `  <text x="4" y="%d">t=0</text><text x="4" y="%.1f">t=%d</text>`+"\n", top+4, y(end)+4, end)
+	for i, r := range results {
+		color := diskColors[i%len(diskColors)]
+		for j := 1; j < len(r.Path); j++ {
+			from, to := r.Path[j-1], r.Path[j]
+			dash := ""
+			if to.Return {
+				dash = ` stroke-dasharray="4 3"`
+			}
+			_, _ = fmt.Fprintf(bw, `  <line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="%s"%s/>`+"\n",
+				x(from.Cylinder), y(from.Time), x(to.Cylinder), y(to.Time), color, dash)
+		}
+		for _, s := range r.Path {
+			if s.Served {
+				_, _ = fmt.Fprintf(bw, `  <circle cx="%.1f" cy="%.1f" r="3" fill="%s"><title>%s: cylinder %d at t=%d</title></circle>`+"\n",
+					x(s.Cylinder), y(s.Time), color, html.EscapeString(r.Algorithm), s.Cylinder, s.Time)
+			}
+		}
+		_, _ = fmt.Fprintf(bw, `  <text x="%d" y="%d" fill="%s">%s: %d cylinders</text>`+"\n", left, legend+i*16, color, html.EscapeString(r.Algorithm), r.Movement)
+	}
+	_, _ = fmt.Fprintln(bw, "</svg>")
+	return bw.Flush()
+}
+
+// selectDiskAlgorithms picks the indexes in diskAlgorithms of those named
+// in a comma-separated list, keeping the list's order. An empty list
+// selects them all.
+func selectDiskAlgorithms(spec string) ([]int, error) {
+	var selected []int
+	if spec == "" {
+		for i := range diskAlgorithms {
+			selected = append(selected, i)
+		}
+		return selected, nil
+	}
+	for _, name := range strings.Split(spec, ",") {
+		i := 0
+		for i < len(diskAlgorithms) && diskAlgorithms[i].name != strings.TrimSpace(name) {
+			i++
+		}
+		if i == len(diskAlgorithms) {
+			return nil, fmt.Errorf("%w: unknown disk algorithm %q", ErrInvalidArgs, name)
+		}
+		selected = append(selected, i)
+	}
+	return selected, nil
+}
+
+// runDisk is the disk subcommand: simulate disk-arm scheduling algorithms
+// over a file of cylinder requests.
+func runDisk(w io.Writer, args []string) error {
+	var opts DiskOptions
+	fs := flag.NewFlagSet("disk", flag.ContinueOnError)
+	algos := fs.String("algo", "", "comma-separated disk algorithms: fcfs, sstf, scan, cscan, look (default all)")
+	fs.Int64Var(&opts.Cylinders, "cylinders", 200, "cylinders on the disk, numbered from 0")
+	fs.Int64Var(&opts.Head, "head", 53, "cylinder the head starts at")
+	fs.BoolVar(&opts.Down, "down", false, "start the arm moving towards cylinder 0")
+	width := fs.Int("width", terminalWidth(), "widest head movement chart line, from $COLUMNS if set")
+	noChart := fs.Bool("no-chart", false, "leave out the head movement charts")
+	svg := fs.String("svg", "", "draw every algorithm's head movement in an SVG image at this file")
+	if err := parseFlags(fs, "disk", args); err != nil {
+		return err
+	}
+	if fs.NArg() > 1 {
+		return fmt.Errorf("%w: usage: disk [flags] file", ErrInvalidArgs)
+	}
+	policies, err := selectDiskAlgorithms(*algos)
+	if err != nil {
+		return err
+	}
+	f, closeFile, err := openProcessingFile(append([]string{"disk"}, fs.Args()...)...)
+	if err != nil {
+		return err
+	}
+	defer closeFile()
+	requests, err := parseDiskRequests(f)
+	if err != nil {
+		return err
+	}
+	if err := checkDisk(requests, opts); err != nil {
+		return err
+	}
+	results := make([]DiskResult, len(policies))
+	for i, p := range policies {
+		results[i] = SimulateDisk(requests, diskAlgorithms[p].policy, opts)
+		results[i].Algorithm = diskAlgorithms[p].title
+		outputTitle(w, results[i].Algorithm+" disk scheduling")
+		outputDisk(w, results[i])
+		if !*noChart {
+			outputDiskChart(w, results[i], opts.Cylinders, *width)
+		}
+	}
+	if len(results) > 1 {
+		outputTitle(w, "Disk scheduling comparison")
+		outputDiskComparison(w, results)
+	}
+	if *svg != "" {
+		return writeFile(*svg, func(w io.Writer) error { return writeDiskSVG(w, results, opts.Cylinders) })
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package scheduler
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// textbookRequests is the classic queue of cylinder requests, with the head
+// at 53 on a disk of 200 cylinders.
+var textbookRequests = []DiskRequest{{Cylinder: 98}, {Cylinder: 183}, {Cylinder: 37}, {Cylinder: 122}, {Cylinder: 14}, {Cylinder: 124}, {Cylinder: 65}, {Cylinder: 67}}
+
+func TestSimulateDisk(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		policy   diskPolicy
+		name     string
+		down     bool
+		movement int64
+		order    []int64
+	}{
+		{name: "fcfs", policy: diskFCFS, movement: 640, order: []int64{98, 183, 37, 122, 14, 124, 65, 67}},
+		{name: "sstf", policy: diskSSTF, movement: 236, order: []int64{65, 67, 37, 14, 98, 122, 124, 183}},
+		{name: "scan", policy: diskSCAN, movement: 331, order: []int64{65, 67, 98, 122, 124, 183, 37, 14}},
+		{name: "scan down", policy: diskSCAN, down: true, movement: 236, order: []int64{37, 14, 65, 67, 98, 122, 124, 183}},
+		{name: "cscan", policy: diskCSCAN, movement: 382, order: []int64{65, 67, 98, 122, 124, 183, 14, 37}},
+		{name: "look", policy: diskLOOK, movement: 299, order: []int64{65, 67, 98, 122, 124, 183, 37, 14}},
+		{name: "look down", policy: diskLOOK, down: true, movement: 208, order: []int64{37, 14, 65, 67, 98, 122, 124, 183}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := SimulateDisk(textbookRequests, tt.policy, DiskOptions{Cylinders: 200, Head: 53, Down: tt.down})
+			if got.Movement != tt.movement {
+				t.Errorf("Movement = %d, want %d", got.Movement, tt.movement)
+			}
+			var order []int64
+			var seek int64
+			for _, s := range got.Services {
+				order = append(order, s.Cylinder)
+				seek += s.Seek
+				// With every request in at 0, a request waits until the arm
+				// has crossed every cylinder on its way there.
+				if s.Wait != s.Served {
+					t.Errorf("request %d waited %d, served at %d", s.Request, s.Wait, s.Served)
+				}
+			}
+			if !reflect.DeepEqual(order, tt.order) {
+				t.Errorf("served %v, want %v", order, tt.order)
+			}
+			if last := got.Services[len(got.Services)-1]; seek != last.Served {
+				t.Errorf("seeks add up to %d, want the %d crossed", seek, last.Served)
+			}
+		})
+	}
+}
+
+func TestSimulateDisk_arrivals(t *testing.T) {
+	t.Parallel()
+	// The arm rests at 20 until the second request arrives.
+	got := SimulateDisk([]DiskRequest{{Cylinder: 20, Arrival: 5}, {Cylinder: 10, Arrival: 100}}, diskSSTF, DiskOptions{Cylinders: 50, Head: 0})
+	want := []DiskService{
+		{Request: 1, Cylinder: 20, Arrival: 5, Served: 25, Seek: 20, Wait: 20},
+		{Request: 2, Cylinder: 10, Arrival: 100, Served: 110, Seek: 10, Wait: 10},
+	}
+	if !reflect.DeepEqual(got.Services, want) {
+		t.Errorf("Services = %+v, want %+v", got.Services, want)
+	}
+	if got.AverageWait != 15 || got.MaxWait != 20 {
+		t.Errorf("AverageWait, MaxWait = %v, %d, want 15, 20", got.AverageWait, got.MaxWait)
+	}
+}
+
+func Test_parseDiskRequests(t *testing.T) {
+	t.Parallel()
+	got, err := parseDiskRequests(strings.NewReader("# queue\n98, 183 37@4\n\n14 # last\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []DiskRequest{{Cylinder: 98}, {Cylinder: 183}, {Cylinder: 37, Arrival: 4}, {Cylinder: 14}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseDiskRequests() = %+v, want %+v", got, want)
+	}
+	for _, bad := range []string{"x", "98@", "98@-1", "@4"} {
+		if _, err := parseDiskRequests(strings.NewReader(bad)); !errors.Is(err, ErrInvalidDiskRequest) {
+			t.Errorf("parseDiskRequests(%q) error = %v, want ErrInvalidDiskRequest", bad, err)
+		}
+	}
+}
+
+func Test_checkDisk(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		requests []DiskRequest
+		opts     DiskOptions
+		want     error
+	}{
+		{name: "valid", requests: textbookRequests, opts: DiskOptions{Cylinders: 200, Head: 53}},
+		{name: "no cylinders", requests: textbookRequests, want: ErrInvalidArgs},
+		{name: "head off the disk", requests: textbookRequests, opts: DiskOptions{Cylinders: 200, Head: 200}, want: ErrInvalidArgs},
+		{name: "no requests", opts: DiskOptions{Cylinders: 200}, want: ErrInvalidDiskRequest},
+		{name: "cylinder off the disk", requests: textbookRequests, opts: DiskOptions{Cylinders: 100}, want: ErrInvalidDiskRequest},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if err := checkDisk(tt.requests, tt.opts); !errors.Is(err, tt.want) {
+				t.Errorf("checkDisk() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func Test_outputDiskChart(t *testing.T) {
+	t.Parallel()
+	result := SimulateDisk([]DiskRequest{{Cylinder: 9}, {Cylinder: 0}}, diskCSCAN, DiskOptions{Cylinders: 10, Head: 5})
+	var w strings.Builder
+	outputDiskChart(&w, result, 10, 24)
+	want := "Head movement (cylinders 0-9):\n" +
+		"t=0         5 |     +\n" +
+		"t=4         9 |     ----*\n" +
+		"t=13        0 |*.........\n"
+	if got := w.String(); got != want {
+		t.Errorf("outputDiskChart() = %q, want %q", got, want)
+	}
+}
+
+func Test_selectDiskAlgorithms(t *testing.T) {
+	t.Parallel()
+	got, err := selectDiskAlgorithms("look, fcfs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{4, 0}; !reflect.DeepEqual(got, want) {
+		t.Errorf("selectDiskAlgorithms() = %v, want %v", got, want)
+	}
+	if all, _ := selectDiskAlgorithms(""); len(all) != len(diskAlgorithms) {
+		t.Errorf("selectDiskAlgorithms(\"\") = %v, want every algorithm", all)
+	}
+	if _, err := selectDiskAlgorithms("elevator"); !errors.Is(err, ErrInvalidArgs) {
+		t.Errorf("selectDiskAlgorithms(\"elevator\") error = %v, want ErrInvalidArgs", err)
+	}
+}
@@ -0,0 +1,8 @@
+// Package scheduler simulates CPU scheduling algorithms over a list of
+// processes and reports on the schedules they produce.
+//
+// Simulate and SimulateContext run processes under a Policy, which
+// LookupAlgorithm builds for any registered algorithm. New algorithms are
+// added with Register and new metrics with RegisterMetric, from this
+// package or one importing it. Main is the command line built on top.
+package scheduler
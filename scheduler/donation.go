@@ -0,0 +1,107 @@
+package scheduler
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Donation is a stretch of time from Start to Stop that PID held a lock at
+// Priority, inherited under LockInherit from a process waiting for it.
+// Chain is the processes the priority passed along, from the one it came
+// from to PID: P3 waiting on P2, itself waiting on P1, gives [3 2 1].
+type Donation struct {
+	PID      int64   `json:"pid"`
+	Priority int64   `json:"priority"`
+	Chain    []int64 `json:"chain"`
+	Start    int64   `json:"start"`
+	Stop     int64   `json:"stop"`
+}
+
+// donate ends t's current donation, if any, and starts one from donor,
+// the waiter t now takes its priority from, unless that is nil.
+func (e *engine) donate(t, donor *Task) {
+	if t.donation > 0 {
+		e.donations[t.donation-1].Stop = e.now
+		t.donation = 0
+	}
+	if donor == nil {
+		return
+	}
+	chain := []int64{donor.ProcessID}
+	if donor.donation > 0 {
+		chain = append([]int64(nil), e.donations[donor.donation-1].Chain...)
+	}
+	e.donations = append(e.donations, Donation{
+		PID:      t.ProcessID,
+		Priority: t.EffectivePriority,
+		Chain:    append(chain, t.ProcessID),
+		Start:    e.now,
+	})
+	t.donation = len(e.donations)
+}
+
+// finishedDonations closes the donations still open when the run ended and
+// drops those that ended as they began.
+func (e *engine) finishedDonations() []Donation {
+	var donations []Donation
+	for _, d := range e.donations {
+		if d.Stop == 0 {
+			d.Stop = e.now
+		}
+		if d.Stop > d.Start {
+			donations = append(donations, d)
+		}
+	}
+	return donations
+}
+
+// markDonations splits the slices of gantt that ran partly on a donated
+// priority and sets Donated on the parts that did.
+func markDonations(gantt []TimeSlice, donations []Donation) []TimeSlice {
+	if len(donations) == 0 {
+		return gantt
+	}
+	byPID := map[int64][]Donation{}
+	for _, d := range donations {
+		byPID[d.PID] = append(byPID[d.PID], d)
+	}
+	marked := make([]TimeSlice, 0, len(gantt))
+	for _, s := range gantt {
+		for _, d := range byPID[s.PID] {
+			if d.Stop <= s.Start || d.Start >= s.Stop {
+				continue
+			}
+			if d.Start > s.Start {
+				before := s
+				before.Stop = d.Start
+				marked = append(marked, before)
+				s.Start = d.Start
+			}
+			donated := s
+			donated.Stop, donated.Donated = minInt64(s.Stop, d.Stop), true
+			marked = append(marked, donated)
+			s.Start = donated.Stop
+		}
+		if s.Stop > s.Start {
+			marked = append(marked, s)
+		}
+	}
+	return marked
+}
+
+// outputDonations lists each priority donation with the chain of waiters it
+// came down.
+func outputDonations(w io.Writer, donations []Donation) {
+	if len(donations) == 0 {
+		return
+	}
+	_, _ = fmt.Fprintln(w, "Priority donations (^ in the Gantt chart):")
+	for _, d := range donations {
+		chain := make([]string, len(d.Chain))
+		for i, pid := range d.Chain {
+			chain[i] = fmt.Sprintf("P%d", pid)
+		}
+		_, _ = fmt.Fprintf(w, "  t=%d-%d P%d runs at priority %d: %s\n", d.Start, d.Stop, d.PID, d.Priority, strings.Join(chain, " -> "))
+	}
+}
@@ -0,0 +1,54 @@
+package scheduler
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSimulate_donationChain(t *testing.T) {
+	t.Parallel()
+	// P1 holds A. P2 takes B, then waits for A; P3 waits for B, so its
+	// priority passes through P2 to P1.
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 4, Priority: 3, Locks: []LockUse{{Resource: "A", Acquire: 0, Release: 3}}},
+		{ProcessID: 2, ArrivalTime: 1, BurstDuration: 3, Priority: 2, Locks: []LockUse{{Resource: "B", Acquire: 0, Release: 2}, {Resource: "A", Acquire: 1, Release: 2}}},
+		{ProcessID: 3, ArrivalTime: 3, BurstDuration: 1, Priority: 1, Locks: []LockUse{{Resource: "B", Acquire: 0, Release: 1}}},
+	}
+	got := Simulate(processes, priorityPolicy{}, EngineOptions{Locking: LockInherit})
+	want := []Donation{
+		{PID: 1, Priority: 2, Chain: []int64{2, 1}, Start: 2, Stop: 3},
+		{PID: 2, Priority: 1, Chain: []int64{3, 2}, Start: 3, Stop: 5},
+		{PID: 1, Priority: 1, Chain: []int64{3, 2, 1}, Start: 3, Stop: 4},
+	}
+	if !reflect.DeepEqual(got.Donations, want) {
+		t.Errorf("Donations = %+v, want %+v", got.Donations, want)
+	}
+	var donated []TimeSlice
+	for _, s := range got.Gantt {
+		if s.Donated {
+			donated = append(donated, s)
+		}
+	}
+	if wantDonated := []TimeSlice{{PID: 1, Start: 2, Stop: 4, Donated: true}, {PID: 2, Start: 4, Stop: 5, Donated: true}}; !reflect.DeepEqual(mergeGantt(donated), wantDonated) {
+		t.Errorf("donated slices = %v, want %v", donated, wantDonated)
+	}
+	var w strings.Builder
+	outputDonations(&w, got.Donations)
+	if line := "  t=3-4 P1 runs at priority 1: P3 -> P2 -> P1\n"; !strings.Contains(w.String(), line) {
+		t.Errorf("outputDonations() =\n%s\nwant a line %q", w.String(), line)
+	}
+}
+
+func Test_markDonations(t *testing.T) {
+	t.Parallel()
+	gantt := []TimeSlice{{PID: 1, Start: 0, Stop: 10}, {PID: 2, Start: 10, Stop: 12}}
+	donations := []Donation{{PID: 1, Start: 2, Stop: 4}, {PID: 1, Start: 6, Stop: 12}}
+	want := []TimeSlice{
+		{PID: 1, Start: 0, Stop: 2}, {PID: 1, Start: 2, Stop: 4, Donated: true}, {PID: 1, Start: 4, Stop: 6},
+		{PID: 1, Start: 6, Stop: 10, Donated: true}, {PID: 2, Start: 10, Stop: 12},
+	}
+	if got := markDonations(gantt, donations); !reflect.DeepEqual(got, want) {
+		t.Errorf("markDonations() = %v, want %v", got, want)
+	}
+}
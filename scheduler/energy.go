@@ -0,0 +1,157 @@
+package scheduler
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// EnergyOptions models the power a CPU draws so schedules can be compared
+// on energy as well as time.
+type EnergyOptions struct {
+	// Frequency is the CPU clock as a fraction of its maximum, in (0, 1]. A
+	// burst of n ticks at full speed takes n/Frequency ticks, rounded up.
+	// Zero leaves energy unmodelled.
+	Frequency float64
+	// A busy CPU draws Static + Dynamic*Frequency³ and an idle one Idle.
+	// All three zero uses defaultPower.
+	Static, Dynamic, Idle float64
+}
+
+var defaultPower = EnergyOptions{Static: 0.2, Dynamic: 1, Idle: 0.05}
+
+// dvfsLevels are the frequencies tried when picking the one with the
+// lowest energy-delay product.
+var dvfsLevels = []float64{0.3, 0.4, 0.5, 0.6, 0.7, 0.8, 0.9, 1}
+
+// EnergyStats is the energy a run used at its frequency.
+type EnergyStats struct {
+	Frequency float64
+	// Busy and Idle are CPU time summed over every CPU.
+	Busy int64
+	Idle int64
+	// Energy is power integrated over the run, in power units times ticks.
+	Energy float64
+	// Makespan is when the run ended, and EDP Energy times Makespan.
+	Makespan int64
+	EDP      float64
+}
+
+// power returns opts with defaultPower filled in if no power was given.
+func (opts EnergyOptions) power() EnergyOptions {
+	if opts.Static == 0 && opts.Dynamic == 0 && opts.Idle == 0 {
+		opts.Static, opts.Dynamic, opts.Idle = defaultPower.Static, defaultPower.Dynamic, defaultPower.Idle
+	}
+	return opts
+}
+
+// slowed is how long n ticks of work at full speed take at frequency f.
+func slowed(n int64, f float64) int64 {
+	// Round away float error before rounding up, so 0.5 at 0.1 is 5.
+	return int64(math.Ceil(math.Round(float64(n)/f*1e9) / 1e9))
+}
+
+// initEnergy stretches every CPU burst and lock point to the frequency.
+func (e *engine) initEnergy() {
+	f := e.opts.Energy.Frequency
+	if f <= 0 || f >= 1 {
+		return
+	}
+	for _, t := range e.tasks {
+		bursts := make([]int64, len(t.bursts))
+		for i, b := range t.bursts {
+			if i%2 == 0 {
+				b = slowed(b, f)
+			}
+			bursts[i] = b
+		}
+		t.bursts = bursts
+		t.Remaining = bursts[0]
+		for i := range t.lockOps {
+			t.lockOps[i].at = slowed(t.lockOps[i].at, f)
+		}
+		for i := range t.semOps {
+			t.semOps[i].At = slowed(t.semOps[i].At, f)
+		}
+	}
+}
+
+func (e *engine) energy() EnergyStats {
+	opts := e.opts.Energy.power()
+	if opts.Frequency <= 0 {
+		return EnergyStats{}
+	}
+	total := e.now * int64(len(e.cores))
+	stats := EnergyStats{
+		Frequency: opts.Frequency,
+		Busy:      total - e.idleTime,
+		Idle:      e.idleTime,
+		Makespan:  e.now,
+	}
+	busyPower := opts.Static + opts.Dynamic*math.Pow(opts.Frequency, 3)
+	stats.Energy = float64(stats.Busy)*busyPower + float64(stats.Idle)*opts.Idle
+	stats.EDP = stats.Energy * float64(stats.Makespan)
+	return stats
+}
+
+// lowestEDPFrequency simulates processes at each of dvfsLevels and returns
+// the frequency with the lowest energy-delay product, the fastest on a tie.
+func lowestEDPFrequency(processes []Process, newPolicy func() Policy, opts EngineOptions) float64 {
+	opts.Log = nil
+	best, bestEDP := 1.0, 0.0
+	for i := len(dvfsLevels) - 1; i >= 0; i-- {
+		opts.Energy.Frequency = dvfsLevels[i]
+		edp := Simulate(processes, newPolicy(), opts).Energy.EDP
+		if i == len(dvfsLevels)-1 || edp < bestEDP {
+			best, bestEDP = dvfsLevels[i], edp
+		}
+	}
+	return best
+}
+
+// parseFrequency reads -freq: a fraction of the maximum clock, or "min-edp"
+// to pick from dvfsLevels, reported as auto.
+func parseFrequency(s string) (f float64, auto bool, err error) {
+	switch s {
+	case "":
+		return 0, false, nil
+	case "min-edp":
+		return 1, true, nil
+	}
+	f, err = strconv.ParseFloat(s, 64)
+	if err != nil || f <= 0 || f > 1 {
+		return 0, false, fmt.Errorf("%w: -freq %q is not a fraction in (0, 1] or min-edp", ErrInvalidArgs, s)
+	}
+	return f, false, nil
+}
+
+// parsePower reads -power: static,dynamic,idle.
+func parsePower(s string, opts *EnergyOptions) error {
+	if s == "" {
+		return nil
+	}
+	fields := strings.Split(s, ",")
+	values := make([]float64, len(fields))
+	for i, f := range fields {
+		v, err := strconv.ParseFloat(strings.TrimSpace(f), 64)
+		if err != nil || v < 0 {
+			return fmt.Errorf("%w: -power %q is not static,dynamic,idle", ErrInvalidArgs, s)
+		}
+		values[i] = v
+	}
+	if len(values) != 3 {
+		return fmt.Errorf("%w: -power %q is not static,dynamic,idle", ErrInvalidArgs, s)
+	}
+	opts.Static, opts.Dynamic, opts.Idle = values[0], values[1], values[2]
+	return nil
+}
+
+func outputEnergy(w io.Writer, stats EnergyStats) {
+	if stats.Frequency == 0 {
+		return
+	}
+	_, _ = fmt.Fprintf(w, "Energy: %.2f at %.0f%% frequency (%d busy, %d idle CPU-ticks); energy-delay product %.2f\n",
+		stats.Energy, 100*stats.Frequency, stats.Busy, stats.Idle, stats.EDP)
+}
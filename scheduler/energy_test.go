@@ -0,0 +1,68 @@
+package scheduler
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestSimulate_energy(t *testing.T) {
+	t.Parallel()
+	processes := []Process{{ProcessID: 1, BurstDuration: 4}, {ProcessID: 2, ArrivalTime: 10, BurstDuration: 2}}
+	got := Simulate(processes, fcfsPolicy{}, EngineOptions{Energy: EnergyOptions{Frequency: 0.5}})
+	// At half speed 1 runs 0-8 and 2 runs 10-14: 12 ticks busy at
+	// 0.2 + 0.5³ and 2 idle at 0.05.
+	want := EnergyStats{Frequency: 0.5, Busy: 12, Idle: 2, Energy: 12*0.325 + 2*0.05, Makespan: 14}
+	want.EDP = want.Energy * 14
+	if math.Abs(got.Energy.Energy-want.Energy) > 1e-9 || math.Abs(got.Energy.EDP-want.EDP) > 1e-9 {
+		t.Errorf("Energy = %+v, want %+v", got.Energy, want)
+	}
+	got.Energy.Energy, got.Energy.EDP = want.Energy, want.EDP
+	if got.Energy != want {
+		t.Errorf("Energy = %+v, want %+v", got.Energy, want)
+	}
+	if exit := got.Schedule[1].Exit; exit != 14 {
+		t.Errorf("P2 exits at %d, want 14", exit)
+	}
+}
+
+func Test_lowestEDPFrequency(t *testing.T) {
+	t.Parallel()
+	// 4 ticks of work take 5 at 0.8, for 5 * 0.712 = 3.56 energy and an
+	// EDP of 17.8, below 19.2 at full speed and 19.5 at 0.7.
+	processes := []Process{{ProcessID: 1, BurstDuration: 4}}
+	got := lowestEDPFrequency(processes, func() Policy { return fcfsPolicy{} }, EngineOptions{})
+	if got != 0.8 {
+		t.Errorf("lowestEDPFrequency() = %v, want 0.8", got)
+	}
+}
+
+func Test_parseFrequency(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		in       string
+		want     float64
+		wantAuto bool
+		wantErr  error
+	}{
+		{in: "", want: 0},
+		{in: "0.6", want: 0.6},
+		{in: "min-edp", want: 1, wantAuto: true},
+		{in: "1.5", wantErr: ErrInvalidArgs},
+		{in: "0", wantErr: ErrInvalidArgs},
+		{in: "fast", wantErr: ErrInvalidArgs},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.in, func(t *testing.T) {
+			t.Parallel()
+			got, auto, err := parseFrequency(tt.in)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("parseFrequency() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want || auto != tt.wantAuto {
+				t.Errorf("parseFrequency() = %v, %v, want %v, %v", got, auto, tt.want, tt.wantAuto)
+			}
+		})
+	}
+}
@@ -0,0 +1,1151 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// Policy decides the order in which the engine dispatches ready processes.
+type Policy interface {
+	// Less reports whether a should be dispatched before b.
+	Less(a, b *Task) bool
+	// Preemptive policies take the CPU back as soon as a process that sorts
+	// before the running one becomes ready.
+	Preemptive() bool
+	// Quantum is the longest a process may run before going back to the
+	// ready queue; zero lets it finish its burst.
+	Quantum() int64
+}
+
+// Observer is implemented by policies that keep their own bookkeeping. The
+// engine hands them every event as it is recorded, before making decisions at
+// that time.
+type Observer interface {
+	Observe(ev Event)
+}
+
+// Reporter is implemented by policies that gather statistics of their own
+// during a run. Report prints them under the schedule.
+type Reporter interface {
+	Report(w io.Writer)
+}
+
+// TaskQuantum is implemented by policies whose quantum depends on the
+// process, such as one per queue level. It is used instead of Quantum.
+type TaskQuantum interface {
+	TaskQuantum(t *Task) int64
+}
+
+// SwitchCoster is implemented by policies that charge for context switches.
+// The CPU spends SwitchCost ticks doing no useful work whenever it is handed
+// to a different process than the one that last ran.
+type SwitchCoster interface {
+	SwitchCost() int64
+}
+
+// RoundQuantum is implemented by policies that choose their quantum from
+// what is ready. The engine calls Adapt as it dispatches t, with the other
+// processes in the ready queues; it reports whether t starts a new round,
+// and the engine logs the quantum chosen for it.
+type RoundQuantum interface {
+	Adapt(t *Task, ready []*Task) (round bool)
+}
+
+// EngineOptions apply to a simulation whatever the policy.
+type EngineOptions struct {
+	// MaxTime stops the simulation at this tick even if processes remain;
+	// zero runs until every process completes.
+	MaxTime int64
+	// TickByTick steps the clock one tick at a time, re-running every
+	// scheduling decision, like a naive tick loop. Results only differ for
+	// policies whose order changes between events; it exists to measure
+	// what the event-driven loop saves.
+	TickByTick bool
+	// Locking is how lock holders have their priority raised.
+	Locking LockProtocol
+	// Resources is how many instances each resource has; one not listed
+	// has a single instance, making it a plain lock.
+	Resources map[string]int64
+	// Deadlock is what the engine does about processes that could end up
+	// waiting on each other for resources.
+	Deadlock DeadlockMode
+	// Semaphores is the initial value of each semaphore; one not listed
+	// starts at zero.
+	Semaphores map[string]int64
+	// Log, if set, is told why each process is dispatched, preempted or
+	// taken off the CPU.
+	Log *Logger `json:"-"`
+	// CPUs is the number of processors; zero means one. A process with
+	// Threads > 1 is gang scheduled: all its threads run at once, on as
+	// many free CPUs, or none of them do.
+	CPUs int
+	// Balance is how ready processes are shared out between the CPUs, and
+	// Steal how CPUs with per-core queues take work from each other.
+	Balance Balance
+	Steal   StealOptions
+	// Energy sets the CPU frequency and the power drawn, to measure the
+	// energy a run uses.
+	Energy EnergyOptions
+	// OnEvent, if set, is handed each event as it is recorded, so a run
+	// can be streamed while it is computed.
+	OnEvent func(Event) `json:"-"`
+	// OnSlice, if set, is handed each Gantt slice as it ends. With
+	// DiscardGantt the slices are not also kept in the Result, so a
+	// schedule too long to hold in memory can be streamed out instead.
+	OnSlice      func(TimeSlice) `json:"-"`
+	DiscardGantt bool
+	// DiscardEvents keeps neither the event log nor the queue samples in
+	// the Result, for the same reason; the queue statistics are kept up as
+	// the run goes, but what is worked out from the log, such as the nice
+	// shares, is left out.
+	DiscardEvents bool
+	// OnProgress, if set, is told how far the run has got after each
+	// event, so a long run can report it is still going.
+	OnProgress func(Progress) `json:"-"`
+	// AbortOnMiss stops the simulation as soon as a process misses its
+	// deadline.
+	AbortOnMiss bool
+	// Closed has users resubmit jobs after thinking, instead of the
+	// workload's arrivals being all there is.
+	Closed ClosedOptions
+	// Warmup leaves the start of the run out of the Result's averages and
+	// percentiles; its schedule still lists every process.
+	Warmup Warmup
+	// DispatchCost is the time the scheduler takes to decide, charged to
+	// the CPU on every dispatch, before any context switch: unlike the
+	// switch cost, it is paid even when the same process carries on.
+	DispatchCost int64
+	// Aging, if positive, raises a process's effective priority by a level
+	// for every Aging ticks it waits in a ready queue, for the policies that
+	// go by priority: priority, and mlfq, whose queues it climbs.
+	Aging int64
+	// IOBoost, if positive, raises a process's effective priority by this
+	// many levels when it comes back from I/O, until it has had the CPU, so
+	// that I/O-bound processes are answered quickly. Only the priority
+	// policy goes by it; mlfq already favours processes that block.
+	IOBoost int64
+}
+
+// Task is the engine's view of a process while it is being simulated.
+type Task struct {
+	Process
+	// Remaining is the time left in the current CPU or I/O burst.
+	Remaining int64
+	// Seq orders tasks by when they last entered the ready queue.
+	Seq int64
+	// EffectivePriority is Priority, raised while the task holds a lock
+	// under a LockProtocol. Priority-based policies order by it.
+	EffectivePriority int64
+
+	bursts   []int64
+	phase    int
+	wait     int64
+	finish   int64
+	admitted bool
+	// queued is set while the task is in the ready queue, where it has been
+	// waiting since readySince on top of wait.
+	queued     bool
+	readySince int64
+	index      int
+
+	cpuDone    int64
+	lockOps    []lockOp
+	nextOp     int
+	held       []string
+	waitingFor string
+	// claims is the most of each resource the task holds at once, its
+	// maximum claim under the Banker's algorithm.
+	claims map[string]int64
+	// semOps are the task's semaphore operations in CPU-time order. While
+	// blocked on one it has been since semSince, on top of semWait.
+	semOps   []SemOp
+	nextSem  int
+	semSince int64
+	semWait  int64
+
+	depsLeft     int
+	awaitingDeps bool
+
+	// threads is how many CPUs the task runs on at once, and mask the CPUs
+	// it may use, or nil for any. While running, cpus are the ones it holds
+	// and sliceStart is when it started doing useful work on them, after
+	// any context switch. cpu is the first CPU it last ran on, if ran, and
+	// queue the ready queue it last joined.
+	threads    int
+	mask       []bool
+	cpus       []int
+	sliceStart int64
+	cpu        int
+	ran        bool
+	queue      int
+
+	// preemptions counts the times the task was taken off the CPU for
+	// another, and migrations the times it came back on a different CPU.
+	preemptions int
+	migrations  int
+	// response is how long after arriving the task was first dispatched.
+	response int64
+	// suspended is set from suspendedAt until the task is resumed, and
+	// parked while it is kept from the ready queue it would otherwise be in.
+	suspended   bool
+	suspendedAt int64
+	parked      bool
+	// user is the PID of the workload process whose job the task is, and
+	// job which of the user's jobs, from 1, in a closed run.
+	user int64
+	job  int
+	// aged is how many levels of priority the task has gained by aging
+	// since it last joined a ready queue.
+	aged int64
+	// boosted is how many levels of priority the task has gained by coming
+	// back from I/O since it last joined a ready queue.
+	boosted int64
+	// donation is one more than the index in the engine's donations of
+	// the one the task holds its priority by, or 0.
+	donation int
+}
+
+// EventKind is what happened to a process at a scheduling event.
+type EventKind int
+
+const (
+	EventArrive EventKind = iota
+	EventDispatch
+	EventPreempt
+	EventExpire
+	EventBlock
+	EventWake
+	EventComplete
+	// EventLockWait takes a process off the CPU to wait for a lock.
+	EventLockWait
+	EventAcquire
+	EventRelease
+	// EventPriority records a change of effective priority.
+	EventPriority
+	// EventDepWait holds an arriving process until the processes it depends
+	// on complete; EventWake readies it.
+	EventDepWait
+	// EventSuspend and EventResume are outside suspends and resumes from
+	// the workload's suspend column.
+	EventSuspend
+	EventResume
+	// EventAge raises a waiting process's priority a level; Priority is its
+	// new effective priority.
+	EventAge
+	// EventBoost raises the priority of a process back from I/O; Priority
+	// is its new effective priority.
+	EventBoost
+	// EventSemWait takes a process off the CPU to wait on a semaphore, and
+	// EventSemDown records a wait taking from one, at once or when
+	// signalled; EventSemUp is a signal.
+	EventSemWait
+	EventSemDown
+	EventSemUp
+)
+
+func (k EventKind) String() string {
+	switch k {
+	case EventArrive:
+		return "arrive"
+	case EventDispatch:
+		return "dispatch"
+	case EventPreempt:
+		return "preempt"
+	case EventExpire:
+		return "expire"
+	case EventBlock:
+		return "block"
+	case EventWake:
+		return "wake"
+	case EventLockWait:
+		return "lock-wait"
+	case EventAcquire:
+		return "acquire"
+	case EventRelease:
+		return "release"
+	case EventPriority:
+		return "priority"
+	case EventDepWait:
+		return "dep-wait"
+	case EventSuspend:
+		return "suspend"
+	case EventResume:
+		return "resume"
+	case EventAge:
+		return "age"
+	case EventBoost:
+		return "boost"
+	case EventSemWait:
+		return "sem-wait"
+	case EventSemDown:
+		return "sem-down"
+	case EventSemUp:
+		return "sem-up"
+	default:
+		return "complete"
+	}
+}
+
+// Event is one entry in the engine's log, in the order it was handled.
+type Event struct {
+	Time int64
+	Kind EventKind
+	PID  int64
+	CPU  int
+	// Resource is the lock of lock events, or the semaphore of semaphore
+	// events, and Count how many of its instances they take, wait for or
+	// give back, when more than one.
+	Resource string
+	Count    int64
+	// Unsafe marks an EventLockWait the Banker's algorithm imposed, with
+	// enough instances free.
+	Unsafe bool
+	// Priority is the new effective priority of EventPriority, EventAge and
+	// EventBoost.
+	Priority int64
+}
+
+// QueueSample is the length of the ready and blocked queues after the events
+// at Time have been handled.
+type QueueSample struct {
+	Time    int64
+	Ready   int
+	Blocked int
+}
+
+type engine struct {
+	policy     Policy
+	opts       EngineOptions
+	now        int64
+	truncated  bool
+	deadlocked bool
+	// cancel is closed when the run's context is done; cancelled records
+	// that the run stopped because it was.
+	cancel    <-chan struct{}
+	cancelled bool
+	// overflowed records that the next event's time overflowed int64.
+	overflowed bool
+	// started is set once the arrivals at time zero are handled, and over
+	// once the run has ended.
+	started bool
+	over    bool
+	// aborted is the process whose missed deadline stopped the run.
+	aborted *Task
+	seq     int64
+
+	tasks    []*Task
+	arrivals []*Task
+	// queues has one ready queue shared by every CPU, or under
+	// BalancePerCore one per CPU.
+	queues  []readyQueue
+	blocked []*Task
+	locks   map[string]*lock
+	// lockNames lists the locks in name order, and deadlocks are the
+	// deadlocks found under DeadlockDetect.
+	lockNames  []string
+	deadlocks  []Deadlock
+	semaphores map[string]*semaphore
+	// donations are the priority donations made under LockInherit, in the
+	// order they began.
+	donations []Donation
+
+	dependents map[int64][]*Task
+	depWaiting int
+
+	// suspends are the suspends and resumes still to come, and suspensions
+	// the ones that have ended.
+	suspends    []suspendEvent
+	suspensions []TimeSlice
+	// nextPID is the PID the next resubmitted job gets, once there is one.
+	nextPID int64
+
+	// cores holds the task on each CPU and lastPID the process that last
+	// ran there. running lists the running tasks in dispatch order.
+	cores   []*Task
+	lastPID []int64
+	running []*Task
+	// idleTime is CPU time with nothing running; gangIdle is the part of it
+	// spent while ready gangs waited for enough CPUs to be free.
+	idleTime   int64
+	gangIdle   int64
+	readyGangs int
+	migrations int
+	steals     int
+
+	// dispatchTime is the CPU time spent on the dispatch decisions counted
+	// by dispatches.
+	dispatchTime int64
+	dispatches   int
+
+	switchTime  int64
+	gantt       []TimeSlice
+	samples     []QueueSample
+	maxReady    int
+	maxBlocked  int
+	events      []Event
+	readyArea   int64
+	blockedArea int64
+	done        int
+	// users is how many processes the workload has, each a user submitting
+	// jobs in a closed run.
+	users int
+	// topPriority is the best priority in the workload, past which aging
+	// does not raise a process.
+	topPriority int64
+}
+
+// Simulate runs processes to completion on opts.CPUs processors, dispatching
+// them in the order given by policy. Processes with Bursts block for their I/O times
+// between CPU bursts.
+func Simulate(processes []Process, policy Policy, opts EngineOptions) Result {
+	result, _ := SimulateContext(context.Background(), processes, policy, opts)
+	return result
+}
+
+// SimulateContext is Simulate, stopping early if ctx is done. A cancelled
+// run ends like one that hit MaxTime, its Result marked Cancelled, and
+// TimedOut too if ctx's deadline passed, and the error is ctx's. A run
+// whose clock would overflow ends the same way, marked Overflowed, with an
+// ErrTimeOverflow error.
+func SimulateContext(ctx context.Context, processes []Process, policy Policy, opts EngineOptions) (Result, error) {
+	e := newEngine(processes, policy, opts)
+	e.cancel = ctx.Done()
+	e.run()
+	if e.cancelled {
+		result := e.result()
+		result.TimedOut = errors.Is(ctx.Err(), context.DeadlineExceeded)
+		return result, ctx.Err()
+	}
+	if e.overflowed {
+		return e.result(), fmt.Errorf("%w at t=%d", ErrTimeOverflow, e.now)
+	}
+	return e.result(), nil
+}
+
+func newEngine(processes []Process, policy Policy, opts EngineOptions) *engine {
+	e := &engine{policy: policy, opts: opts}
+	cpus := opts.CPUs
+	if cpus < 1 {
+		cpus = 1
+	}
+	e.cores = make([]*Task, cpus)
+	e.lastPID = make([]int64, cpus)
+	e.queues = []readyQueue{newReadyQueue(policy)}
+	if opts.Balance == BalancePerCore {
+		for len(e.queues) < cpus {
+			e.queues = append(e.queues, newReadyQueue(policy))
+		}
+	}
+	for i := range processes {
+		t := &Task{Process: ganged(processes[i])}
+		t.bursts = t.Bursts
+		if len(t.bursts) == 0 {
+			t.bursts = []int64{t.BurstDuration}
+		}
+		// A gang wider than the CPUs it may use runs on all of them.
+		t.mask = affinityMask(t.Affinity, cpus)
+		usable := cpus
+		if t.mask != nil {
+			usable = 0
+			for _, ok := range t.mask {
+				if ok {
+					usable++
+				}
+			}
+		}
+		t.threads = 1
+		if t.Threads > 1 {
+			t.threads = usable
+			if t.Threads < int64(usable) {
+				t.threads = int(t.Threads)
+			}
+		}
+		t.Remaining = t.bursts[0]
+		t.EffectivePriority = t.Priority
+		t.user, t.job = t.ProcessID, 1
+		e.tasks = append(e.tasks, t)
+	}
+	e.users = len(e.tasks)
+	for i, t := range e.tasks {
+		if i == 0 || t.Priority < e.topPriority {
+			e.topPriority = t.Priority
+		}
+	}
+	e.initLocks()
+	e.initSemaphores()
+	e.initEnergy()
+	e.initDependencies()
+	e.initSuspensions()
+	e.arrivals = append(e.arrivals, e.tasks...)
+	sort.SliceStable(e.arrivals, func(i, j int) bool {
+		return e.arrivals[i].ArrivalTime < e.arrivals[j].ArrivalTime
+	})
+
+	return e
+}
+
+func (e *engine) run() {
+	for !e.Step() {
+		if e.opts.OnProgress != nil {
+			e.opts.OnProgress(e.progress())
+		}
+	}
+	if e.opts.OnProgress != nil {
+		e.opts.OnProgress(e.progress())
+	}
+}
+
+// begin handles the arrivals at time zero.
+func (e *engine) begin() {
+	e.admit()
+	e.dispatch()
+	e.sample()
+}
+
+// Step handles the next scheduling event, the arrivals at time zero being
+// the first, and reports whether the run is over. The clock jumps straight
+// to the event, however many ticks away it is.
+func (e *engine) Step() bool {
+	over, _ := e.step(-1)
+	return over
+}
+
+// RunUntil handles every event up to and including limit, or to the end if
+// limit is negative, leaving the clock at limit. It reports whether the run
+// is over.
+func (e *engine) RunUntil(limit int64) bool {
+	for {
+		over, reached := e.step(limit)
+		if over || reached {
+			return over
+		}
+	}
+}
+
+// step handles the next event unless it comes after limit, when limit is
+// not negative; then the clock is moved to limit instead and reached is
+// set.
+func (e *engine) step(limit int64) (over, reached bool) {
+	if e.over {
+		return true, false
+	}
+	if !e.started {
+		e.started = true
+		e.begin()
+		return false, false
+	}
+	if e.done == len(e.tasks) {
+		e.over = true
+		return true, false
+	}
+	next, ok := e.nextEvent()
+	if !ok {
+		// Everything left is waiting for a lock or semaphore, or on a
+		// process that is.
+		e.deadlocked, e.over = true, true
+		return true, false
+	}
+	if next < e.now {
+		// Only a time past the largest int64, wrapped around, comes
+		// before now; overheads can push a run there that checkTimeRange
+		// let through.
+		e.overflowed = true
+		e.opts.Log.Log(e.now, "time overflow", "next", next)
+		e.stopAt(e.now)
+		return true, false
+	}
+	if e.opts.TickByTick && next > e.now+1 {
+		next = e.now + 1
+	}
+	if e.opts.MaxTime > 0 && next > e.opts.MaxTime {
+		e.stopAt(e.opts.MaxTime)
+		return true, false
+	}
+	select {
+	case <-e.cancel:
+		e.cancelled = true
+		e.opts.Log.Log(e.now, "cancelled")
+		e.stopAt(e.now)
+		return true, false
+	default:
+	}
+	if limit >= 0 && next > limit {
+		if limit > e.now {
+			e.advance(limit)
+		}
+		return false, true
+	}
+	e.advance(next)
+	e.runLocks()
+	e.runSemaphores()
+	expired := e.stopRunning()
+	e.suspendAndResume()
+	if e.opts.AbortOnMiss {
+		if t := e.missedDeadline(); t != nil {
+			e.aborted = t
+			e.opts.Log.Log(e.now, "abort on deadline miss", "pid", t.ProcessID, "deadline", t.absoluteDeadline())
+			e.stopAt(e.now)
+			return true, false
+		}
+	}
+	e.admit()
+	// A process whose quantum ran out queues behind anything arriving at
+	// the same moment.
+	for _, t := range expired {
+		e.enqueue(t)
+	}
+	e.ageWaiting()
+	e.dispatch()
+	// An expired quantum only cost the process the CPU if something else
+	// got it instead.
+	for _, t := range expired {
+		if t.queued {
+			t.preemptions++
+		}
+	}
+	for e.preempt() {
+		e.dispatch()
+	}
+	e.sample()
+	return false, false
+}
+
+// stopAt ends the simulation at the horizon, cutting the running slice short.
+func (e *engine) stopAt(horizon int64) {
+	if horizon > e.now {
+		e.advance(horizon)
+	}
+	for _, t := range e.running {
+		e.endSlice(t)
+	}
+	e.truncated, e.over = true, true
+	e.sample()
+}
+
+// nextEvent finds the earliest upcoming completion, quantum expiry, arrival
+// or I/O return.
+func (e *engine) nextEvent() (int64, bool) {
+	var (
+		next  int64
+		found bool
+	)
+	consider := func(t int64) {
+		if !found || t < next {
+			next, found = t, true
+		}
+	}
+	for _, t := range e.running {
+		start := e.now
+		if t.sliceStart > start {
+			start = t.sliceStart
+		}
+		consider(start + t.Remaining)
+		if q := e.quantum(t); q > 0 {
+			consider(t.sliceStart + q)
+		}
+		if t.nextOp < len(t.lockOps) {
+			consider(start + t.lockOps[t.nextOp].at - t.cpuDone)
+		}
+		if t.nextSem < len(t.semOps) {
+			consider(start + t.semOps[t.nextSem].At - t.cpuDone)
+		}
+	}
+	if len(e.arrivals) > 0 {
+		consider(e.arrivals[0].ArrivalTime)
+	}
+	if len(e.suspends) > 0 {
+		consider(e.suspends[0].time)
+	}
+	if at, ok := e.nextAging(); ok {
+		consider(at)
+	}
+	for _, t := range e.blocked {
+		consider(e.now + t.Remaining)
+	}
+	if e.opts.AbortOnMiss {
+		if d, ok := e.nextDeadline(); ok {
+			consider(d)
+		}
+	}
+
+	return next, found
+}
+
+func (e *engine) advance(next int64) {
+	dt := next - e.now
+	for _, t := range e.running {
+		// Nothing gets done while the CPU is still switching to the task.
+		ran := next - t.sliceStart
+		if ran > dt {
+			ran = dt
+		}
+		if ran > 0 {
+			t.Remaining -= ran
+			t.cpuDone += ran
+		}
+	}
+	if idle := int64(e.idleCPUs()) * dt; idle > 0 {
+		e.idleTime += idle
+		if e.readyGangs > 0 {
+			e.gangIdle += idle
+		}
+	}
+	for _, t := range e.blocked {
+		t.Remaining -= dt
+	}
+	e.readyArea += int64(e.readyLen()) * dt
+	e.blockedArea += int64(len(e.blocked)+e.lockWaiters()+e.semaphoreWaiters()+e.depWaiting) * dt
+	e.now = next
+}
+
+// stopRunning takes running tasks off the CPU if their burst finished or
+// their quantum expired. Tasks whose quantum expired are returned so the
+// caller can queue them once same-time arrivals are in.
+func (e *engine) stopRunning() []*Task {
+	var expired []*Task
+	running := e.running[:0]
+	for _, t := range e.running {
+		if t.Remaining == 0 {
+			e.endSlice(t)
+			e.freeCPUs(t)
+			t.phase++
+			if t.phase == len(t.bursts) {
+				t.finish = e.now
+				e.done++
+				e.releaseAll(t)
+				e.record(EventComplete, t)
+				e.opts.Log.Log(e.now, "complete", "pid", t.ProcessID)
+				e.releaseDependents(t)
+				e.resubmit(t)
+				continue
+			}
+			t.Remaining = t.bursts[t.phase]
+			e.blocked = append(e.blocked, t)
+			e.record(EventBlock, t)
+			e.opts.Log.Log(e.now, "block for I/O", "pid", t.ProcessID, "until", e.now+t.Remaining)
+			continue
+		}
+		if q := e.quantum(t); q > 0 && e.now-t.sliceStart >= q {
+			e.endSlice(t)
+			e.freeCPUs(t)
+			e.record(EventExpire, t)
+			e.opts.Log.Log(e.now, "quantum expired", "pid", t.ProcessID, "quantum", q, "remaining", t.Remaining)
+			expired = append(expired, t)
+			continue
+		}
+		running = append(running, t)
+	}
+	e.running = running
+
+	return expired
+}
+
+// admit moves arrivals and processes back from I/O into the ready queue.
+func (e *engine) admit() {
+	for len(e.arrivals) > 0 && e.arrivals[0].ArrivalTime <= e.now {
+		t := e.arrivals[0]
+		e.arrivals = e.arrivals[1:]
+		t.admitted = true
+		e.record(EventArrive, t)
+		if t.depsLeft > 0 {
+			t.awaitingDeps = true
+			e.depWaiting++
+			e.record(EventDepWait, t)
+			e.opts.Log.Log(e.now, "wait for dependencies", "pid", t.ProcessID, "after", formatPIDs(t.DependsOn))
+			continue
+		}
+		e.enqueue(t)
+	}
+	blocked := e.blocked[:0]
+	for _, t := range e.blocked {
+		if t.Remaining > 0 {
+			blocked = append(blocked, t)
+			continue
+		}
+		t.phase++
+		t.Remaining = t.bursts[t.phase]
+		e.record(EventWake, t)
+		e.enqueue(t)
+		e.boost(t)
+	}
+	e.blocked = blocked
+}
+
+func (e *engine) enqueue(t *Task) {
+	if t.suspended {
+		t.parked = true
+		return
+	}
+	e.unage(t)
+	e.unboost(t)
+	e.seq++
+	t.Seq = e.seq
+	t.queued = true
+	t.readySince = e.now
+	if t.threads > 1 {
+		e.readyGangs++
+	}
+	t.queue = e.queueFor(t)
+	e.queues[t.queue].Add(t)
+}
+
+// readyLen counts the tasks in every ready queue.
+func (e *engine) readyLen() int {
+	n := 0
+	for _, q := range e.queues {
+		n += q.Len()
+	}
+	return n
+}
+
+// preempt takes the CPUs of running tasks the policy ranks behind the best
+// ready task, lowest ranked first, if that frees enough it may use. It
+// reports whether it took any.
+func (e *engine) preempt() bool {
+	if !e.policy.Preemptive() {
+		return false
+	}
+	if len(e.queues) > 1 {
+		return e.preemptPerCore()
+	}
+	if e.queues[0].Len() == 0 {
+		return false
+	}
+	best := e.queues[0].Best()
+	var behind []*Task
+	for _, t := range e.running {
+		if e.policy.Less(best, t) && best.usable(t.cpus) > 0 {
+			behind = append(behind, t)
+		}
+	}
+	sort.SliceStable(behind, func(i, j int) bool { return e.policy.Less(behind[j], behind[i]) })
+	need, n := best.threads-e.idleFor(best), 0
+	for ; n < len(behind) && need > 0; n++ {
+		need -= best.usable(behind[n].cpus)
+	}
+	if n == 0 || need > 0 {
+		return false
+	}
+	for _, t := range behind[:n] {
+		e.preemptTask(t, best)
+	}
+	return true
+}
+
+func (e *engine) preemptTask(t, by *Task) {
+	e.endSlice(t)
+	t.preemptions++
+	e.record(EventPreempt, t)
+	e.opts.Log.Log(e.now, "preempt", "pid", t.ProcessID, "by", by.ProcessID, "reason", "ranks ahead by policy")
+	e.freeCPUs(t)
+	e.stopped(t)
+	e.enqueue(t)
+}
+
+// dispatch runs ready tasks on the free CPUs in the policy's order. A gang
+// that needs more CPUs than are free stays queued, and the tasks behind it
+// may use them instead.
+func (e *engine) dispatch() {
+	if len(e.queues) > 1 {
+		e.dispatchPerCore()
+		return
+	}
+	q := e.queues[0]
+	if e.idleCPUs() == 0 || q.Len() == 0 {
+		return
+	}
+	candidates := q.Len()
+	var waiting []*Task
+	for q.Len() > 0 && e.idleCPUs() > 0 {
+		t := q.Take()
+		cpus := e.fit(t, -1)
+		if cpus == nil {
+			waiting = append(waiting, t)
+			continue
+		}
+		e.start(t, cpus, candidates)
+	}
+	for _, t := range waiting {
+		q.Add(t)
+	}
+}
+
+// start runs t on cpus.
+func (e *engine) start(t *Task, cpus []int, candidates int) {
+	t.queued = false
+	if t.threads > 1 {
+		e.readyGangs--
+	}
+	t.wait += e.now - t.readySince
+	t.cpus = cpus
+	for _, c := range cpus {
+		e.cores[c] = t
+	}
+	if !t.ran {
+		t.response = e.now - t.ArrivalTime
+	}
+	from, migrated := t.cpu, t.ran && t.cpu != cpus[0]
+	if migrated {
+		e.migrations++
+		t.migrations++
+	}
+	t.ran = true
+	t.cpu = cpus[0]
+	t.sliceStart = e.now + e.opts.DispatchCost
+	e.dispatchTime += e.opts.DispatchCost * int64(len(t.cpus))
+	e.dispatches++
+	if c, ok := e.policy.(SwitchCoster); ok {
+		for _, cpu := range t.cpus {
+			if e.lastPID[cpu] != 0 && e.lastPID[cpu] != t.ProcessID {
+				t.sliceStart += c.SwitchCost()
+				e.switchTime += c.SwitchCost() * int64(len(t.cpus))
+				break
+			}
+		}
+	}
+	for _, cpu := range t.cpus {
+		e.lastPID[cpu] = t.ProcessID
+	}
+	if p, ok := e.policy.(RoundQuantum); ok {
+		var ready []*Task
+		for _, r := range e.tasks {
+			if r.queued {
+				ready = append(ready, r)
+			}
+		}
+		if p.Adapt(t, ready) {
+			e.opts.Log.Log(e.now, "round", "pid", t.ProcessID, "quantum", e.quantum(t), "ready", len(ready)+1)
+		}
+	}
+	e.running = append(e.running, t)
+	e.record(EventDispatch, t)
+	if e.opts.Log != nil {
+		reason := "only ready process"
+		if candidates > 1 {
+			reason = fmt.Sprintf("best of %d ready by policy", candidates)
+		}
+		e.opts.Log.Log(e.now, "dispatch", "pid", t.ProcessID, "reason", reason,
+			"remaining", t.Remaining, "priority", t.EffectivePriority, "waited", t.wait)
+		if migrated {
+			e.opts.Log.Log(e.now, "migrate", "pid", t.ProcessID, "from", from, "to", t.cpu)
+		}
+	}
+}
+
+// idleCPUs counts the CPUs with nothing running.
+func (e *engine) idleCPUs() int {
+	n := 0
+	for _, t := range e.cores {
+		if t == nil {
+			n++
+		}
+	}
+	return n
+}
+
+// freeCPUs takes t off its CPUs; the caller removes it from running.
+func (e *engine) freeCPUs(t *Task) {
+	for _, c := range t.cpus {
+		e.cores[c] = nil
+	}
+	t.cpus = t.cpus[:0]
+}
+
+// stopped removes t from running.
+func (e *engine) stopped(t *Task) {
+	for i, r := range e.running {
+		if r == t {
+			e.running = append(e.running[:i], e.running[i+1:]...)
+			return
+		}
+	}
+}
+
+func (e *engine) quantum(t *Task) int64 {
+	if p, ok := e.policy.(TaskQuantum); ok {
+		return p.TaskQuantum(t)
+	}
+	return e.policy.Quantum()
+}
+
+func (e *engine) record(kind EventKind, t *Task) {
+	e.recordEvent(Event{Time: e.now, Kind: kind, PID: t.ProcessID, CPU: t.cpu})
+}
+
+func (e *engine) recordEvent(ev Event) {
+	if !e.opts.DiscardEvents {
+		e.events = append(e.events, ev)
+	}
+	if e.opts.OnEvent != nil {
+		e.opts.OnEvent(ev)
+	}
+	if o, ok := e.policy.(Observer); ok {
+		o.Observe(ev)
+	}
+}
+
+func (e *engine) endSlice(t *Task) {
+	if e.now > t.sliceStart {
+		for _, cpu := range t.cpus {
+			s := TimeSlice{
+				PID:   t.ProcessID,
+				Start: t.sliceStart,
+				Stop:  e.now,
+				CPU:   cpu,
+			}
+			if e.opts.OnSlice != nil {
+				e.opts.OnSlice(s)
+			}
+			if !e.opts.DiscardGantt {
+				e.gantt = append(e.gantt, s)
+			}
+		}
+	}
+}
+
+func (e *engine) sample() {
+	s := QueueSample{Time: e.now, Ready: e.readyLen(), Blocked: len(e.blocked) + e.lockWaiters() + e.semaphoreWaiters() + e.depWaiting}
+	if n := len(e.samples); n > 0 && e.samples[n-1].Time == s.Time {
+		e.samples[n-1] = s
+		return
+	}
+	// Discarding, only the latest sample is kept, which later events at
+	// the same time may still replace; the one before goes into the maxima.
+	if e.opts.DiscardEvents && len(e.samples) == 1 {
+		e.maxSample(e.samples[0])
+		e.samples[0] = s
+		return
+	}
+	e.samples = append(e.samples, s)
+}
+
+// maxSample keeps the longest queues sampled.
+func (e *engine) maxSample(s QueueSample) {
+	if s.Ready > e.maxReady {
+		e.maxReady = s.Ready
+	}
+	if s.Blocked > e.maxBlocked {
+		e.maxBlocked = s.Blocked
+	}
+}
+
+func (e *engine) result() Result {
+	var (
+		schedule   = make([]ProcessResult, 0, len(e.tasks))
+		incomplete []Incomplete
+	)
+	for _, t := range e.tasks {
+		if t.phase < len(t.bursts) {
+			if t.admitted {
+				incomplete = append(incomplete, Incomplete{ProcessID: t.ProcessID, Remaining: t.remainingCPU()})
+			}
+			continue
+		}
+		schedule = append(schedule, ProcessResult{
+			ProcessID:  t.ProcessID,
+			Priority:   t.Priority,
+			Burst:      t.BurstDuration,
+			Arrival:    t.ArrivalTime,
+			Wait:       t.wait,
+			Turnaround: t.finish - t.ArrivalTime,
+			Exit:       t.finish,
+			Response:   t.response,
+			Class:      t.Class,
+			// Time blocked on semaphores is kept apart from Wait.
+			SemaphoreWait: t.semWait,
+		})
+	}
+
+	for _, s := range e.samples {
+		e.maxSample(s)
+	}
+	queue := QueueStats{MaxReady: e.maxReady, MaxBlocked: e.maxBlocked}
+	samples := e.samples
+	if e.opts.DiscardEvents {
+		samples = nil
+	}
+	if e.now > 0 {
+		queue.AverageReady = float64(e.readyArea) / float64(e.now)
+		queue.AverageBlocked = float64(e.blockedArea) / float64(e.now)
+	}
+
+	// A truncated run is measured over the horizon, not its last completion.
+	var horizon int64
+	if e.truncated {
+		horizon = e.now
+	}
+	// Slices on different CPUs end out of order; list them by start.
+	if len(e.cores) > 1 {
+		sort.SliceStable(e.gantt, func(i, j int) bool {
+			a, b := e.gantt[i], e.gantt[j]
+			if a.Start != b.Start {
+				return a.Start < b.Start
+			}
+			return a.CPU < b.CPU
+		})
+	}
+	donations := e.finishedDonations()
+	measured, start := steadyState(schedule, e.opts.Warmup)
+	metrics := metricsSince(measured, start, horizon)
+	fairness := computeFairness(schedule)
+	shares := e.shares()
+	fairness.ShareDeviation = shareDeviation(shares)
+	return Result{
+		Schedule:     schedule,
+		Gantt:        markDonations(e.gantt, donations),
+		Metrics:      metrics,
+		Percentiles:  NewPercentiles(measured),
+		Slowdown:     NewSlowdownStats(measured),
+		Queue:        queue,
+		Fairness:     fairness,
+		Shares:       shares,
+		Groups:       e.groupUsage(),
+		Cores:        e.coreStats(),
+		Energy:       e.energy(),
+		Deadlines:    e.deadlines(),
+		Preemptions:  e.preemptions(),
+		Preemptive:   e.policy.Preemptive(),
+		Suspensions:  e.openSuspensions(),
+		Donations:    donations,
+		Closed:       e.closedStats(measured, metrics),
+		Warmup:       WarmupStats{Warmup: e.opts.Warmup, Start: start, Excluded: len(schedule) - len(measured)},
+		QueueLength:  samples,
+		Events:       e.events,
+		SwitchTime:   e.switchTime,
+		Dispatches:   e.dispatches,
+		DispatchTime: e.dispatchTime,
+		Truncated:    e.truncated,
+		Cancelled:    e.cancelled,
+		Deadlocked:   e.deadlocked,
+		Deadlocks:    e.deadlocks,
+		Overflowed:   e.overflowed,
+		Incomplete:   incomplete,
+	}
+}
+
+// waited is the time t has spent in the ready queue up to now.
+func (t *Task) waited(now int64) int64 {
+	if t.queued {
+		return t.wait + now - t.readySince
+	}
+	return t.wait
+}
+
+// remainingCPU is the CPU time t still needs, excluding any I/O.
+func (t *Task) remainingCPU() int64 {
+	var cpu int64
+	for i := t.phase; i < len(t.bursts); i++ {
+		switch {
+		case i%2 == 1:
+			continue
+		case i == t.phase:
+			cpu += t.Remaining
+		default:
+			cpu += t.bursts[i]
+		}
+	}
+	return cpu
+}
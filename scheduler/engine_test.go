@@ -0,0 +1,252 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSimulate(t *testing.T) {
+	t.Parallel()
+	type args struct {
+		processes []Process
+		policy    Policy
+	}
+	tests := []struct {
+		name        string
+		args        args
+		wantGantt   []TimeSlice
+		wantWait    []int64
+		wantSamples []QueueSample
+	}{
+		{
+			name: "blocks for I/O between bursts",
+			args: args{
+				processes: []Process{
+					{ProcessID: 1, BurstDuration: 4, Bursts: []int64{2, 3, 2}},
+					{ProcessID: 2, BurstDuration: 4},
+				},
+				policy: fcfsPolicy{},
+			},
+			wantGantt: []TimeSlice{{PID: 1, Start: 0, Stop: 2}, {PID: 2, Start: 2, Stop: 6}, {PID: 1, Start: 6, Stop: 8}},
+			wantWait:  []int64{1, 2},
+			wantSamples: []QueueSample{
+				{Time: 0, Ready: 1},
+				{Time: 2, Blocked: 1},
+				{Time: 5, Ready: 1},
+				{Time: 6},
+				{Time: 8},
+			},
+		},
+		{
+			name: "arrival queues ahead of expired quantum",
+			args: args{
+				processes: []Process{
+					{ProcessID: 1, BurstDuration: 3},
+					{ProcessID: 2, ArrivalTime: 2, BurstDuration: 2},
+				},
+				policy: rrPolicy{quantum: 2},
+			},
+			wantGantt: []TimeSlice{{PID: 1, Start: 0, Stop: 2}, {PID: 2, Start: 2, Stop: 4}, {PID: 1, Start: 4, Stop: 5}},
+			wantWait:  []int64{2, 0},
+			wantSamples: []QueueSample{
+				{Time: 0},
+				{Time: 2, Ready: 1},
+				{Time: 4},
+				{Time: 5},
+			},
+		},
+		{
+			name: "idle until next arrival",
+			args: args{
+				processes: []Process{
+					{ProcessID: 1, BurstDuration: 1},
+					{ProcessID: 2, ArrivalTime: 5, BurstDuration: 1},
+				},
+				policy: sjfPolicy{},
+			},
+			wantGantt:   []TimeSlice{{PID: 1, Start: 0, Stop: 1}, {PID: 2, Start: 5, Stop: 6}},
+			wantWait:    []int64{0, 0},
+			wantSamples: []QueueSample{{Time: 0}, {Time: 1}, {Time: 5}, {Time: 6}},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := Simulate(tt.args.processes, tt.args.policy, EngineOptions{})
+			if !reflect.DeepEqual(got.Gantt, tt.wantGantt) {
+				t.Errorf("Gantt = %v, want %v", got.Gantt, tt.wantGantt)
+			}
+			for i, row := range got.Schedule {
+				if row.Wait != tt.wantWait[i] {
+					t.Errorf("PID %d wait = %d, want %d", row.ProcessID, row.Wait, tt.wantWait[i])
+				}
+			}
+			if !reflect.DeepEqual(got.QueueLength, tt.wantSamples) {
+				t.Errorf("QueueLength = %v, want %v", got.QueueLength, tt.wantSamples)
+			}
+		})
+	}
+}
+
+func TestSimulate_maxTime(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name           string
+		processes      []Process
+		maxTime        int64
+		wantGantt      []TimeSlice
+		wantIncomplete []Incomplete
+		wantTruncated  bool
+	}{
+		{
+			name: "stops mid burst",
+			processes: []Process{
+				{ProcessID: 1, BurstDuration: 5},
+				{ProcessID: 2, ArrivalTime: 1, BurstDuration: 3},
+				{ProcessID: 3, ArrivalTime: 9, BurstDuration: 1},
+			},
+			maxTime:        6,
+			wantGantt:      []TimeSlice{{PID: 1, Start: 0, Stop: 5}, {PID: 2, Start: 5, Stop: 6}},
+			wantIncomplete: []Incomplete{{ProcessID: 2, Remaining: 2}},
+			wantTruncated:  true,
+		},
+		{
+			name: "remaining excludes I/O",
+			processes: []Process{
+				{ProcessID: 1, BurstDuration: 4, Bursts: []int64{2, 3, 2}},
+			},
+			maxTime:        1,
+			wantGantt:      []TimeSlice{{PID: 1, Start: 0, Stop: 1}},
+			wantIncomplete: []Incomplete{{ProcessID: 1, Remaining: 3}},
+			wantTruncated:  true,
+		},
+		{
+			name: "finishes inside the limit",
+			processes: []Process{
+				{ProcessID: 1, BurstDuration: 2},
+			},
+			maxTime:   2,
+			wantGantt: []TimeSlice{{PID: 1, Start: 0, Stop: 2}},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := Simulate(tt.processes, fcfsPolicy{}, EngineOptions{MaxTime: tt.maxTime})
+			if !reflect.DeepEqual(got.Gantt, tt.wantGantt) {
+				t.Errorf("Gantt = %v, want %v", got.Gantt, tt.wantGantt)
+			}
+			if !reflect.DeepEqual(got.Incomplete, tt.wantIncomplete) {
+				t.Errorf("Incomplete = %v, want %v", got.Incomplete, tt.wantIncomplete)
+			}
+			if got.Truncated != tt.wantTruncated {
+				t.Errorf("Truncated = %v, want %v", got.Truncated, tt.wantTruncated)
+			}
+		})
+	}
+}
+
+func TestSimulateContext(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 5},
+		{ProcessID: 2, ArrivalTime: 1, BurstDuration: 3},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// Cancel as the second process arrives; the run stops at the next event.
+	got, err := SimulateContext(ctx, processes, fcfsPolicy{}, EngineOptions{OnEvent: func(ev Event) {
+		if ev.Kind == EventArrive && ev.PID == 2 {
+			cancel()
+		}
+	}})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("SimulateContext() error = %v, want %v", err, context.Canceled)
+	}
+	if !got.Cancelled || !got.Truncated {
+		t.Errorf("Cancelled, Truncated = %v, %v, want true, true", got.Cancelled, got.Truncated)
+	}
+	if want := []TimeSlice{{PID: 1, Start: 0, Stop: 1}}; !reflect.DeepEqual(got.Gantt, want) {
+		t.Errorf("Gantt = %v, want %v", got.Gantt, want)
+	}
+	if want := []Incomplete{{ProcessID: 1, Remaining: 4}, {ProcessID: 2, Remaining: 3}}; !reflect.DeepEqual(got.Incomplete, want) {
+		t.Errorf("Incomplete = %v, want %v", got.Incomplete, want)
+	}
+
+	if _, err := SimulateContext(context.Background(), processes, fcfsPolicy{}, EngineOptions{}); err != nil {
+		t.Errorf("SimulateContext() error = %v, want nil", err)
+	}
+}
+
+func Test_engine_Step(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 1 << 40},
+		{ProcessID: 2, ArrivalTime: 3, BurstDuration: 5, Bursts: []int64{2, 1 << 30, 3}},
+		{ProcessID: 3, ArrivalTime: 1 << 35, BurstDuration: 7},
+	}
+	policy := func() Policy { return newRRPolicy(processes, RROptions{Quantum: 1 << 32}) }
+	e := newEngine(processes, policy(), EngineOptions{})
+	steps := 0
+	for !e.Step() {
+		if steps++; steps > 1000 {
+			t.Fatal("Step() did not finish within 1000 events")
+		}
+	}
+	if !e.Step() {
+		t.Error("Step() after the end = false, want true")
+	}
+	if got, want := e.result(), Simulate(processes, policy(), EngineOptions{}); !reflect.DeepEqual(got, want) {
+		t.Errorf("stepped result = %+v, want %+v", got, want)
+	}
+
+	e = newEngine(processes, policy(), EngineOptions{})
+	if e.RunUntil(1 << 33) {
+		t.Fatal("RunUntil() = true before the end")
+	}
+	if e.now != 1<<33 {
+		t.Errorf("clock after RunUntil() = %d, want %d", e.now, int64(1<<33))
+	}
+	if !e.RunUntil(-1) {
+		t.Error("RunUntil(-1) = false, want true")
+	}
+}
+
+func TestSimulate_switchCost(t *testing.T) {
+	t.Parallel()
+	got := Simulate([]Process{
+		{ProcessID: 1, BurstDuration: 3},
+		{ProcessID: 2, BurstDuration: 2},
+	}, newRRPolicy(nil, RROptions{Quantum: 2, SwitchCost: 1}), EngineOptions{})
+	wantGantt := []TimeSlice{{PID: 1, Start: 0, Stop: 2}, {PID: 2, Start: 3, Stop: 5}, {PID: 1, Start: 6, Stop: 7}}
+	if !reflect.DeepEqual(got.Gantt, wantGantt) {
+		t.Errorf("Gantt = %v, want %v", got.Gantt, wantGantt)
+	}
+	if got.SwitchTime != 2 {
+		t.Errorf("SwitchTime = %d, want 2", got.SwitchTime)
+	}
+	wantWait := []int64{3, 2}
+	for i, row := range got.Schedule {
+		if row.Wait != wantWait[i] {
+			t.Errorf("PID %d wait = %d, want %d", row.ProcessID, row.Wait, wantWait[i])
+		}
+	}
+}
+
+func Test_writeQueueCSV(t *testing.T) {
+	t.Parallel()
+	var w strings.Builder
+	results := []Result{{QueueLength: []QueueSample{{Time: 0, Ready: 1}, {Time: 2, Blocked: 1}}}}
+	if err := writeQueueCSV(&w, []string{"fcfs"}, results); err != nil {
+		t.Fatal(err)
+	}
+	want := "algorithm,time,ready,blocked\nfcfs,0,1,0\nfcfs,2,0,1\n"
+	if got := w.String(); got != want {
+		t.Errorf("writeQueueCSV() = %q, want %q", got, want)
+	}
+}
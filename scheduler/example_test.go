@@ -0,0 +1,153 @@
+package scheduler_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/kasiyo/4600-project1/scheduler"
+)
+
+func ExampleSimulate() {
+	processes := []scheduler.Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 5},
+		{ProcessID: 2, ArrivalTime: 1, BurstDuration: 3},
+		{ProcessID: 3, ArrivalTime: 2, BurstDuration: 1},
+	}
+	rr, _ := scheduler.LookupAlgorithm("rr")
+	policy := rr.New(processes, scheduler.AlgorithmOptions{RR: scheduler.RROptions{Quantum: 2}})
+	result := scheduler.Simulate(processes, policy, scheduler.EngineOptions{})
+	for _, s := range result.Gantt {
+		fmt.Printf("P%d %d-%d\n", s.PID, s.Start, s.Stop)
+	}
+	fmt.Printf("average wait %.2f\n", result.AverageWait)
+	// Output:
+	// P1 0-2
+	// P2 2-4
+	// P3 4-5
+	// P1 5-7
+	// P2 7-8
+	// P1 8-9
+	// average wait 3.33
+}
+
+func ExampleSimulateContext() {
+	processes := []scheduler.Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 4},
+		{ProcessID: 2, ArrivalTime: 0, BurstDuration: 4},
+		{ProcessID: 3, ArrivalTime: 1, BurstDuration: 2},
+	}
+	sjf, _ := scheduler.LookupAlgorithm("sjf")
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	result, err := scheduler.SimulateContext(ctx, processes, sjf.New(processes, scheduler.AlgorithmOptions{}), scheduler.EngineOptions{
+		CPUs:    2,
+		MaxTime: 3,
+	})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println("truncated:", result.Truncated)
+	for _, p := range result.Incomplete {
+		fmt.Printf("P%d has %d left\n", p.ProcessID, p.Remaining)
+	}
+	// Output:
+	// truncated: true
+	// P1 has 1 left
+	// P2 has 3 left
+}
+
+// lifoPolicy runs the newest ready process.
+type lifoPolicy struct{}
+
+func (lifoPolicy) Less(a, b *scheduler.Task) bool { return a.ArrivalTime > b.ArrivalTime }
+func (lifoPolicy) Preemptive() bool               { return false }
+func (lifoPolicy) Quantum() int64                 { return 0 }
+
+func ExampleRegister() {
+	scheduler.Register("lifo", scheduler.Factory{
+		Title:       "Last-in, first-out",
+		Description: "runs the newest ready process",
+		New: func([]scheduler.Process, scheduler.AlgorithmOptions) scheduler.Policy {
+			return lifoPolicy{}
+		},
+	})
+
+	// -algo lifo now selects it too.
+	processes := []scheduler.Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 3},
+		{ProcessID: 2, ArrivalTime: 1, BurstDuration: 3},
+		{ProcessID: 3, ArrivalTime: 2, BurstDuration: 3},
+	}
+	lifo, _ := scheduler.LookupAlgorithm("lifo")
+	result := scheduler.Simulate(processes, lifo.New(processes, scheduler.AlgorithmOptions{}), scheduler.EngineOptions{})
+	for _, s := range result.Gantt {
+		fmt.Printf("P%d %d-%d\n", s.PID, s.Start, s.Stop)
+	}
+	// Output:
+	// P1 0-3
+	// P3 3-6
+	// P2 6-9
+}
+
+// dispatchesMetric counts how often each process is given the CPU.
+type dispatchesMetric struct{ n map[int64]int }
+
+func (m *dispatchesMetric) Observe(ev scheduler.Event) {
+	if ev.Kind == scheduler.EventDispatch {
+		m.n[ev.PID]++
+	}
+}
+
+func (m *dispatchesMetric) Report() map[string]float64 {
+	report := make(map[string]float64, len(m.n))
+	for pid, n := range m.n {
+		report[fmt.Sprint("P", pid)] = float64(n)
+	}
+	return report
+}
+
+func ExampleRegisterMetric() {
+	scheduler.RegisterMetric("dispatches", scheduler.MetricFactory{
+		Description: "counts each process's dispatches",
+		New:         func() scheduler.Metric { return &dispatchesMetric{n: map[int64]int{}} },
+	})
+
+	// -metrics dispatches now selects it too.
+	processes := []scheduler.Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 5},
+		{ProcessID: 2, ArrivalTime: 1, BurstDuration: 2},
+	}
+	rr, _ := scheduler.LookupAlgorithm("rr")
+	result := scheduler.Simulate(processes, rr.New(processes, scheduler.AlgorithmOptions{}), scheduler.EngineOptions{})
+	dispatches, _ := scheduler.LookupMetric("dispatches")
+	report := scheduler.MeasureMetrics([]scheduler.RegisteredMetric{dispatches}, result.Events)
+	fmt.Println(report["dispatches"]["P1"], report["dispatches"]["P2"])
+	// Output:
+	// 3 1
+}
+
+func Example_json() {
+	// Processes and results use the process file's column names in JSON,
+	// as the REST API and saved results do.
+	var processes []scheduler.Process
+	in := `[{"pid": 1, "arrival": 0, "burst": 3}, {"pid": 2, "arrival": 1, "burst": 2, "priority": 1}]`
+	if err := json.Unmarshal([]byte(in), &processes); err != nil {
+		fmt.Println(err)
+		return
+	}
+	fcfs, _ := scheduler.LookupAlgorithm("fcfs")
+	result := scheduler.Simulate(processes, fcfs.New(processes, scheduler.AlgorithmOptions{}), scheduler.EngineOptions{})
+	enc := json.NewEncoder(os.Stdout)
+	for _, p := range result.Schedule {
+		_ = enc.Encode(p)
+	}
+	_ = enc.Encode(result.Gantt)
+	// Output:
+	// {"pid":1,"priority":0,"burst":3,"arrival":0,"wait":0,"turnaround":3,"exit":3,"response":0}
+	// {"pid":2,"priority":1,"burst":2,"arrival":1,"wait":2,"turnaround":4,"exit":5,"response":2}
+	// [{"pid":1,"start":0,"stop":3},{"pid":2,"start":3,"stop":5}]
+}
@@ -0,0 +1,119 @@
+package scheduler
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"strings"
+)
+
+// The exit codes, for grading scripts and CI pipelines to branch on
+// without parsing the output. A failed run exits with the code for why it
+// failed; a run that completed exits with the first outcome any algorithm
+// had, in this order.
+const (
+	exitOK = 0
+	// exitError is anything else going wrong, such as a file that cannot
+	// be opened.
+	exitError = 1
+	// exitUsage is bad flags or arguments, as the flag package exits with.
+	exitUsage = 2
+	// exitParse is a process file that could not be read.
+	exitParse = 3
+	// exitInvalid is a workload rejected as invalid, including one whose
+	// times would overflow.
+	exitInvalid = 4
+	// exitDeadlineMiss is a process missing its deadline.
+	exitDeadlineMiss = 5
+	// exitStarvation is a process starving, with -starvation.
+	exitStarvation = 6
+)
+
+// exitCode is the code to exit with after err.
+func exitCode(err error) int {
+	var (
+		fileErr  *ProcessFileError
+		parseErr *csv.ParseError
+	)
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.As(err, &fileErr), errors.As(err, &parseErr):
+		return exitParse
+	case errors.Is(err, ErrInvalidProcesses), errors.Is(err, ErrTimeOverflow):
+		return exitInvalid
+	case errors.Is(err, ErrInvalidArgs):
+		return exitUsage
+	}
+	return exitError
+}
+
+// fatal logs err and exits with its code.
+func fatal(err error) {
+	log.Print(err)
+	os.Exit(exitCode(err))
+}
+
+// starved lists the processes that waited in result's ready queue for more
+// than limit ticks, or were left incomplete without ever having run though
+// they arrived more than limit ticks before the run stopped. Incomplete
+// processes are only judged when the run kept its Gantt chart.
+func starved(workload []Process, result Result, limit int64) []int64 {
+	var pids []int64
+	for _, r := range result.Schedule {
+		if r.Wait > limit {
+			pids = append(pids, r.ProcessID)
+		}
+	}
+	if len(result.Incomplete) > 0 && len(result.Gantt) > 0 {
+		arrival := map[int64]int64{}
+		for _, p := range workload {
+			arrival[p.ProcessID] = p.ArrivalTime
+		}
+		ran := map[int64]bool{}
+		var stop int64
+		for _, s := range result.Gantt {
+			ran[s.PID] = true
+			stop = maxInt64(stop, s.Stop)
+		}
+		for _, p := range result.Incomplete {
+			if a, ok := arrival[p.ProcessID]; ok && !ran[p.ProcessID] && stop-a > limit {
+				pids = append(pids, p.ProcessID)
+			}
+		}
+	}
+	sort.Slice(pids, func(i, j int) bool { return pids[i] < pids[j] })
+	return pids
+}
+
+// outcomeCode is the code to exit with after the runs of the algorithms
+// named, reporting any starvation, when limit is positive, to warn.
+func outcomeCode(warn io.Writer, workload []Process, names []string, results []Result, limit int64) int {
+	code := exitOK
+	for i, result := range results {
+		if limit > 0 {
+			if pids := starved(workload, result, limit); len(pids) > 0 {
+				ids := make([]string, len(pids))
+				for j, pid := range pids {
+					ids[j] = fmt.Sprint(pid)
+				}
+				label := "PID"
+				if len(ids) > 1 {
+					label = "PIDs"
+				}
+				_, _ = fmt.Fprintf(warn, "warning: %s starved %s %s, waiting over %d ticks\n", names[i], label, strings.Join(ids, ", "), limit)
+				if code == exitOK {
+					code = exitStarvation
+				}
+			}
+		}
+		if result.Deadlines.Missed > 0 || result.Deadlines.Aborted {
+			code = exitDeadlineMiss
+		}
+	}
+	return code
+}
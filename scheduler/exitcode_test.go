@@ -0,0 +1,97 @@
+package scheduler
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_exitCode(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "success", want: exitOK},
+		{name: "bad flag", err: fmt.Errorf("%w: -cpus must be at least 1", ErrInvalidArgs), want: exitUsage},
+		{name: "bad value", err: &ProcessFileError{Errors: []*FieldError{{Line: 2, Column: "burst", Value: "x", Err: errors.New("not a number")}}}, want: exitParse},
+		{name: "bad CSV", err: fmt.Errorf("%w: reading CSV", &csv.ParseError{Line: 3, Err: csv.ErrQuote}), want: exitParse},
+		{name: "invalid workload", err: &ValidationError{Problems: []Problem{{Row: 1, PID: 1, Message: "burst must be positive"}}}, want: exitInvalid},
+		{name: "overflow", err: fmt.Errorf("%w: too long", ErrTimeOverflow), want: exitInvalid},
+		{name: "other", err: errors.New("disk full"), want: exitError},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := exitCode(tt.err); got != tt.want {
+				t.Errorf("exitCode(%v) = %d, want %d", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_starved(t *testing.T) {
+	t.Parallel()
+	// Priority keeps PID 3 waiting behind the stream of better processes.
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 4, Priority: 1},
+		{ProcessID: 2, BurstDuration: 4, ArrivalTime: 2, Priority: 1},
+		{ProcessID: 3, BurstDuration: 2, ArrivalTime: 1, Priority: 9},
+		{ProcessID: 4, BurstDuration: 4, ArrivalTime: 6, Priority: 1},
+	}
+	result := Simulate(processes, priorityPolicy{}, EngineOptions{})
+	if got, want := starved(processes, result, 5), []int64{3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("starved() = %v, want %v", got, want)
+	}
+	if got := starved(processes, result, 20); len(got) > 0 {
+		t.Errorf("starved() with a generous limit = %v, want none", got)
+	}
+
+	// Cut short, PID 3 never ran, though it arrived long before the end.
+	result = Simulate(processes, priorityPolicy{}, EngineOptions{MaxTime: 10})
+	if got, want := starved(processes, result, 5), []int64{3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("starved() of a truncated run = %v, want %v", got, want)
+	}
+}
+
+func Test_outcomeCode(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 4, Deadline: 6},
+		{ProcessID: 2, BurstDuration: 9, ArrivalTime: 1},
+		{ProcessID: 3, BurstDuration: 3, ArrivalTime: 1, Deadline: 10},
+	}
+	fcfs := Simulate(processes, fcfsPolicy{}, EngineOptions{})
+	sjf := Simulate(processes, sjfPolicy{}, EngineOptions{})
+	tests := []struct {
+		name     string
+		results  []Result
+		limit    int64
+		want     int
+		wantWarn string
+	}{
+		{name: "success", results: []Result{sjf}, want: exitOK},
+		{name: "deadline missed", results: []Result{sjf, fcfs}, want: exitDeadlineMiss},
+		{name: "starvation", results: []Result{sjf}, limit: 5, want: exitStarvation, wantWarn: "warning: sjf starved PID 2, waiting over 5 ticks\n"},
+		{name: "deadline misses first", results: []Result{sjf, fcfs}, limit: 5, want: exitDeadlineMiss, wantWarn: "warning: sjf starved PID 2"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var warn strings.Builder
+			names := []string{"sjf", "fcfs"}[:len(tt.results)]
+			if got := outcomeCode(&warn, processes, names, tt.results, tt.limit); got != tt.want {
+				t.Errorf("outcomeCode() = %d, want %d", got, tt.want)
+			}
+			if !strings.HasPrefix(warn.String(), tt.wantWarn) || (tt.wantWarn == "" && warn.Len() > 0) {
+				t.Errorf("warnings = %q, want %q", warn.String(), tt.wantWarn)
+			}
+		})
+	}
+}
@@ -0,0 +1,592 @@
+package scheduler
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+var ErrInvalidManifest = errors.New("invalid experiment manifest")
+
+type (
+	// Manifest is an experiment: every run on every workload, and the
+	// outputs to produce from them, written under Output.
+	Manifest struct {
+		Name      string
+		Output    string
+		Workloads []ManifestWorkload
+		Runs      []ManifestRun
+		Outputs   []string
+	}
+	// ManifestWorkload is a process file, relative to the manifest, named
+	// by Name in output files.
+	ManifestWorkload struct {
+		Name string
+		File string
+	}
+	// ManifestRun is algorithms to run with the same options, which are the
+	// default command's flags without their dash.
+	ManifestRun struct {
+		Name       string
+		Algorithms []string
+		Options    map[string]string
+	}
+	// ExperimentRow is one algorithm's result in one run on one workload.
+	ExperimentRow struct {
+		Workload  string
+		Run       string
+		Algorithm string
+		Result    Result
+	}
+)
+
+// manifestOutputs are the outputs a manifest may ask for: a comparison
+// table printed for each workload, each schedule printed in full, and
+// comparison.csv, result JSON for the load subcommand and SVG Gantt charts
+// written to the output directory.
+var manifestOutputs = []string{"comparison", "text", "csv", "json", "svg"}
+
+// parseManifest reads the subset of YAML a manifest needs: comments,
+// nested mappings and lists by indentation, flow lists like [a, b], and
+// plain or quoted scalars.
+func parseManifest(r io.Reader) (Manifest, error) {
+	lines, err := yamlLines(r)
+	if err != nil {
+		return Manifest{}, err
+	}
+	if len(lines) == 0 {
+		return Manifest{}, fmt.Errorf("%w: empty", ErrInvalidManifest)
+	}
+	doc, next, err := parseYAMLBlock(lines, 0, lines[0].indent)
+	if err != nil {
+		return Manifest{}, err
+	}
+	if next < len(lines) {
+		return Manifest{}, fmt.Errorf("%w: line %d: unexpected indentation", ErrInvalidManifest, lines[next].number)
+	}
+	return decodeManifest(doc)
+}
+
+// yamlLine is a line of YAML with its comment and indentation stripped.
+type yamlLine struct {
+	number int
+	indent int
+	text   string
+}
+
+func yamlLines(r io.Reader) ([]yamlLine, error) {
+	var lines []yamlLine
+	in := bufio.NewScanner(r)
+	for n := 1; in.Scan(); n++ {
+		raw := strings.TrimRight(stripComment(in.Text()), " \t")
+		text := strings.TrimLeft(raw, " ")
+		if text == "" || text == "---" {
+			continue
+		}
+		if strings.HasPrefix(text, "\t") {
+			return nil, fmt.Errorf("%w: line %d: indent with spaces, not tabs", ErrInvalidManifest, n)
+		}
+		lines = append(lines, yamlLine{number: n, indent: len(raw) - len(text), text: text})
+	}
+	if err := in.Err(); err != nil {
+		return nil, fmt.Errorf("%w: reading manifest", err)
+	}
+	return lines, nil
+}
+
+// parseYAMLBlock parses the mapping or list starting at lines[i], whose
+// entries are indented indent spaces, into a map[string]interface{} or
+// []interface{} of them, with strings at the leaves. It returns the index
+// of the first line after the block.
+func parseYAMLBlock(lines []yamlLine, i, indent int) (interface{}, int, error) {
+	if strings.HasPrefix(lines[i].text, "-") {
+		var list []interface{}
+		for i < len(lines) && lines[i].indent == indent && (lines[i].text == "-" || strings.HasPrefix(lines[i].text, "- ")) {
+			item := strings.TrimSpace(strings.TrimPrefix(lines[i].text, "-"))
+			var value interface{}
+			var err error
+			switch {
+			case item == "":
+				if i+1 == len(lines) || lines[i+1].indent <= indent {
+					return nil, 0, fmt.Errorf("%w: line %d: empty list item", ErrInvalidManifest, lines[i].number)
+				}
+				value, i, err = parseYAMLBlock(lines, i+1, lines[i+1].indent)
+			case isYAMLKey(item):
+				// "- key: value" starts a mapping indented past the dash.
+				lines[i].indent, lines[i].text = lines[i].indent+len(lines[i].text)-len(item), item
+				value, i, err = parseYAMLBlock(lines, i, lines[i].indent)
+			default:
+				value, err = yamlScalar(item)
+				i++
+			}
+			if err != nil {
+				return nil, 0, err
+			}
+			list = append(list, value)
+		}
+		return list, i, nil
+	}
+	mapping := map[string]interface{}{}
+	for i < len(lines) && lines[i].indent == indent {
+		line := lines[i]
+		if !isYAMLKey(line.text) {
+			return nil, 0, fmt.Errorf("%w: line %d: want key: value", ErrInvalidManifest, line.number)
+		}
+		key, raw, _ := strings.Cut(line.text, ":")
+		key, raw = strings.TrimSpace(key), strings.TrimSpace(raw)
+		if _, ok := mapping[key]; ok {
+			return nil, 0, fmt.Errorf("%w: line %d: %s given twice", ErrInvalidManifest, line.number, key)
+		}
+		i++
+		if raw != "" {
+			value, err := yamlScalar(raw)
+			if err != nil {
+				return nil, 0, fmt.Errorf("%w: line %d: %s: %v", ErrInvalidManifest, line.number, key, err)
+			}
+			mapping[key] = value
+			continue
+		}
+		// A nested block is indented further, though a list may sit level
+		// with its key.
+		if i == len(lines) || lines[i].indent < indent || (lines[i].indent == indent && !strings.HasPrefix(lines[i].text, "-")) {
+			return nil, 0, fmt.Errorf("%w: line %d: %s has no value", ErrInvalidManifest, line.number, key)
+		}
+		value, next, err := parseYAMLBlock(lines, i, lines[i].indent)
+		if err != nil {
+			return nil, 0, err
+		}
+		mapping[key], i = value, next
+	}
+	if i < len(lines) && lines[i].indent > indent {
+		return nil, 0, fmt.Errorf("%w: line %d: unexpected indentation", ErrInvalidManifest, lines[i].number)
+	}
+	return mapping, i, nil
+}
+
+// isYAMLKey reports whether text starts a key: value pair, the key being
+// unquoted.
+func isYAMLKey(text string) bool {
+	key, rest, ok := strings.Cut(text, ":")
+	return ok && key != "" && !strings.ContainsAny(key, `"'[`) && (rest == "" || rest[0] == ' ')
+}
+
+// yamlScalar reads a scalar, quoted or plain, or a flow list of them.
+func yamlScalar(raw string) (interface{}, error) {
+	switch {
+	case strings.HasPrefix(raw, "["):
+		if !strings.HasSuffix(raw, "]") {
+			return nil, errors.New("unterminated list")
+		}
+		items := []interface{}{}
+		for _, item := range strings.Split(raw[1:len(raw)-1], ",") {
+			if item = strings.TrimSpace(item); item == "" {
+				continue
+			}
+			v, err := yamlScalar(item)
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := v.(string); !ok {
+				return nil, errors.New("nested lists are not supported")
+			}
+			items = append(items, v)
+		}
+		return items, nil
+	case strings.HasPrefix(raw, "{"):
+		return nil, errors.New("flow mappings are not supported; use indentation")
+	case strings.HasPrefix(raw, `"`):
+		return strconv.Unquote(raw)
+	case strings.HasPrefix(raw, "'"):
+		if len(raw) < 2 || !strings.HasSuffix(raw, "'") {
+			return nil, errors.New("unterminated string")
+		}
+		return strings.ReplaceAll(raw[1:len(raw)-1], "''", "'"), nil
+	}
+	return raw, nil
+}
+
+// decodeManifest checks the parsed document against the manifest's shape.
+func decodeManifest(doc interface{}) (Manifest, error) {
+	top, ok := doc.(map[string]interface{})
+	if !ok {
+		return Manifest{}, fmt.Errorf("%w: want a mapping at the top level", ErrInvalidManifest)
+	}
+	m := Manifest{Output: ".", Outputs: []string{"comparison"}}
+	for key, value := range top {
+		var err error
+		switch key {
+		case "name":
+			m.Name, err = manifestString(key, value)
+		case "output":
+			m.Output, err = manifestString(key, value)
+		case "outputs":
+			m.Outputs, err = manifestStrings(key, value)
+		case "workloads":
+			m.Workloads, err = decodeWorkloads(value)
+		case "runs":
+			m.Runs, err = decodeRuns(value)
+		default:
+			err = fmt.Errorf("%w: unknown key %q", ErrInvalidManifest, key)
+		}
+		if err != nil {
+			return Manifest{}, err
+		}
+	}
+	if len(m.Workloads) == 0 {
+		return Manifest{}, fmt.Errorf("%w: no workloads", ErrInvalidManifest)
+	}
+	if len(m.Runs) == 0 {
+		m.Runs = []ManifestRun{{Name: "default"}}
+	}
+	for _, o := range m.Outputs {
+		if !containsString(manifestOutputs, o) {
+			return Manifest{}, fmt.Errorf("%w: unknown output %q; choose from %s", ErrInvalidManifest, o, strings.Join(manifestOutputs, ", "))
+		}
+	}
+	return m, nil
+}
+
+func decodeWorkloads(value interface{}) ([]ManifestWorkload, error) {
+	items, ok := value.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%w: workloads must be a list", ErrInvalidManifest)
+	}
+	var workloads []ManifestWorkload
+	names := map[string]bool{}
+	for i, item := range items {
+		var w ManifestWorkload
+		switch v := item.(type) {
+		case string:
+			w.File = v
+		case map[string]interface{}:
+			for key, value := range v {
+				var err error
+				switch key {
+				case "name":
+					w.Name, err = manifestString(key, value)
+				case "file":
+					w.File, err = manifestString(key, value)
+				default:
+					err = fmt.Errorf("%w: workload %d: unknown key %q", ErrInvalidManifest, i+1, key)
+				}
+				if err != nil {
+					return nil, err
+				}
+			}
+		default:
+			return nil, fmt.Errorf("%w: workload %d must be a file or a mapping", ErrInvalidManifest, i+1)
+		}
+		if w.File == "" {
+			return nil, fmt.Errorf("%w: workload %d has no file", ErrInvalidManifest, i+1)
+		}
+		if w.Name == "" {
+			w.Name = strings.TrimSuffix(filepath.Base(w.File), filepath.Ext(w.File))
+		}
+		if names[w.Name] {
+			return nil, fmt.Errorf("%w: two workloads are named %s", ErrInvalidManifest, w.Name)
+		}
+		names[w.Name] = true
+		workloads = append(workloads, w)
+	}
+	return workloads, nil
+}
+
+func decodeRuns(value interface{}) ([]ManifestRun, error) {
+	items, ok := value.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%w: runs must be a list", ErrInvalidManifest)
+	}
+	var runs []ManifestRun
+	names := map[string]bool{}
+	for i, item := range items {
+		v, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%w: run %d must be a mapping", ErrInvalidManifest, i+1)
+		}
+		run := ManifestRun{Options: map[string]string{}}
+		for key, value := range v {
+			var err error
+			switch key {
+			case "name":
+				run.Name, err = manifestString(key, value)
+			case "algorithms", "algo":
+				run.Algorithms, err = manifestStrings(key, value)
+			default:
+				// Like a config file, a list becomes the comma-separated
+				// value the flag expects.
+				var values []string
+				values, err = manifestStrings(key, value)
+				run.Options[key] = strings.Join(values, ",")
+			}
+			if err != nil {
+				return nil, fmt.Errorf("run %d: %w", i+1, err)
+			}
+		}
+		if run.Name == "" {
+			run.Name = fmt.Sprintf("run%d", i+1)
+		}
+		if names[run.Name] {
+			return nil, fmt.Errorf("%w: two runs are named %s", ErrInvalidManifest, run.Name)
+		}
+		names[run.Name] = true
+		runs = append(runs, run)
+	}
+	return runs, nil
+}
+
+func manifestString(key string, value interface{}) (string, error) {
+	s, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("%w: %s must be a single value", ErrInvalidManifest, key)
+	}
+	return s, nil
+}
+
+// manifestStrings reads a value that may be a single value or a list.
+func manifestStrings(key string, value interface{}) ([]string, error) {
+	switch v := value.(type) {
+	case string:
+		return []string{v}, nil
+	case []interface{}:
+		items := make([]string, len(v))
+		for i, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("%w: %s must be a list of values", ErrInvalidManifest, key)
+			}
+			items[i] = s
+		}
+		return items, nil
+	}
+	return nil, fmt.Errorf("%w: %s must be a value or a list of them", ErrInvalidManifest, key)
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// runOptions builds a run's algorithm and engine options from its options,
+// which take the same names and values as the default command's flags.
+func runOptions(options map[string]string) (AlgorithmOptions, EngineOptions, error) {
+	fs := flag.NewFlagSet("run", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	maxTime := fs.Int64("max-time", 0, "")
+	lockProtocol := fs.String("lock-protocol", "none", "")
+	resources := fs.String("resources", "", "")
+	deadlock := fs.String("deadlock", "ignore", "")
+	semaphores := fs.String("semaphores", "", "")
+	cpus := fs.Int("cpus", 1, "")
+	balance := fs.String("balance", "global", "")
+	stealThreshold := fs.Int("steal-threshold", 1, "")
+	steal := fs.String("steal", "one", "")
+	dispatchCost := fs.Int64("dispatch-cost", 0, "")
+	aging := fs.Int64("aging", 0, "")
+	ioBoost := fs.Int64("io-boost", 0, "")
+	seed := addSeedFlag(fs, "")
+	algoFlags := addAlgorithmFlags(fs)
+	keys := make([]string, 0, len(options))
+	for key := range options {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if fs.Lookup(key) == nil {
+			return AlgorithmOptions{}, EngineOptions{}, fmt.Errorf("%w: unknown option %q", ErrInvalidManifest, key)
+		}
+		if err := fs.Set(key, options[key]); err != nil {
+			return AlgorithmOptions{}, EngineOptions{}, fmt.Errorf("%w: %s: %v", ErrInvalidManifest, key, err)
+		}
+	}
+	algoOpts, err := algoFlags.options()
+	if err != nil {
+		return AlgorithmOptions{}, EngineOptions{}, err
+	}
+	algoOpts.Seed = pickSeed(os.Stderr, *seed)
+	if *cpus < 1 {
+		return AlgorithmOptions{}, EngineOptions{}, fmt.Errorf("%w: cpus must be at least 1", ErrInvalidArgs)
+	}
+	if *aging < 0 || *ioBoost < 0 || *dispatchCost < 0 {
+		return AlgorithmOptions{}, EngineOptions{}, fmt.Errorf("%w: aging, io-boost and dispatch-cost cannot be negative", ErrInvalidArgs)
+	}
+	engineOpts := EngineOptions{MaxTime: *maxTime, CPUs: *cpus, Aging: *aging, IOBoost: *ioBoost, DispatchCost: *dispatchCost}
+	if engineOpts.Locking, err = parseLockProtocol(*lockProtocol); err != nil {
+		return AlgorithmOptions{}, EngineOptions{}, err
+	}
+	if engineOpts.Resources, err = parseResources(*resources); err != nil {
+		return AlgorithmOptions{}, EngineOptions{}, err
+	}
+	if engineOpts.Deadlock, err = parseDeadlockMode(*deadlock); err != nil {
+		return AlgorithmOptions{}, EngineOptions{}, err
+	}
+	if engineOpts.Semaphores, err = parseSemaphores(*semaphores); err != nil {
+		return AlgorithmOptions{}, EngineOptions{}, err
+	}
+	if engineOpts.Balance, err = parseBalance(*balance); err != nil {
+		return AlgorithmOptions{}, EngineOptions{}, err
+	}
+	engineOpts.Steal.Threshold = *stealThreshold
+	if engineOpts.Steal.Half, err = parseSteal(*steal); err != nil {
+		return AlgorithmOptions{}, EngineOptions{}, err
+	}
+	return algoOpts, engineOpts, nil
+}
+
+// RunExperiment runs m, whose workload files are relative to dir, printing
+// to w and writing files under its output directory, also relative to dir.
+func RunExperiment(w io.Writer, m Manifest, dir string) ([]ExperimentRow, error) {
+	wants := map[string]bool{}
+	for _, o := range m.Outputs {
+		wants[o] = true
+	}
+	output := m.Output
+	if !filepath.IsAbs(output) {
+		output = filepath.Join(dir, output)
+	}
+	if wants["csv"] || wants["json"] || wants["svg"] {
+		if err := os.MkdirAll(output, 0o755); err != nil {
+			return nil, fmt.Errorf("%v: error creating %s", err, output)
+		}
+	}
+	if m.Name != "" {
+		outputTitle(w, m.Name)
+	}
+	var rows []ExperimentRow
+	for _, workload := range m.Workloads {
+		processes, err := loadManifestWorkload(dir, workload.File)
+		if err != nil {
+			return nil, fmt.Errorf("workload %s: %w", workload.Name, err)
+		}
+		first := len(rows)
+		for _, run := range m.Runs {
+			algoOpts, engineOpts, err := runOptions(run.Options)
+			if err != nil {
+				return nil, fmt.Errorf("run %s: %w", run.Name, err)
+			}
+			if err := checkResources(processes, engineOpts.Resources); err != nil {
+				return nil, fmt.Errorf("workload %s, run %s: %w", workload.Name, run.Name, err)
+			}
+			algorithms, err := selectRuns(strings.Join(run.Algorithms, ","))
+			if err != nil {
+				return nil, fmt.Errorf("run %s: %w", run.Name, err)
+			}
+			for _, algorithm := range algorithms {
+				result := Simulate(processes, algorithm.New(processes, algoOpts), engineOpts)
+				row := ExperimentRow{Workload: workload.Name, Run: run.Name, Algorithm: algorithm.Name, Result: result}
+				rows = append(rows, row)
+				title := fmt.Sprintf("%s: %s, %s", workload.Name, run.Name, algorithm.Title)
+				if wants["text"] {
+					Render(w, result, RenderOptions{Title: title})
+				}
+				base := filepath.Join(output, fmt.Sprintf("%s.%s.%s", workload.Name, run.Name, algorithm.Name))
+				if wants["json"] {
+					f := ResultFile{Algorithm: algorithm.Name, Title: title, Result: result}
+					if err := writeFile(base+".json", func(w io.Writer) error { return writeResultFile(w, f) }); err != nil {
+						return nil, err
+					}
+				}
+				if wants["svg"] {
+					if err := writeFile(base+".svg", func(w io.Writer) error { return writeSVG(w, title, result) }); err != nil {
+						return nil, err
+					}
+				}
+			}
+		}
+		if wants["comparison"] {
+			outputTitle(w, "Workload "+workload.Name)
+			outputExperiment(w, rows[first:])
+		}
+	}
+	if wants["csv"] {
+		if err := writeFile(filepath.Join(output, "comparison.csv"), func(w io.Writer) error { return writeExperimentCSV(w, rows) }); err != nil {
+			return nil, err
+		}
+	}
+	return rows, nil
+}
+
+// loadManifestWorkload loads and checks a workload file, relative to dir.
+func loadManifestWorkload(dir, file string) ([]Process, error) {
+	if !filepath.IsAbs(file) {
+		file = filepath.Join(dir, file)
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, fmt.Errorf("%v: error opening scheduling file", err)
+	}
+	defer f.Close()
+	processes, err := loadProcesses(f)
+	if err != nil {
+		return nil, err
+	}
+	return checkProcesses(processes, false, io.Discard)
+}
+
+// experimentMetrics are the columns the comparison table and CSV give for
+// each result.
+func experimentMetrics(result Result) []string {
+	return []string{
+		fmt.Sprint(len(result.Schedule)),
+		fmt.Sprintf("%.2f", result.AverageWait),
+		fmt.Sprintf("%.2f", result.AverageTurnaround),
+		fmt.Sprintf("%.4f", result.Throughput),
+		fmt.Sprintf("%.2f", result.Slowdown.Mean),
+	}
+}
+
+func outputExperiment(w io.Writer, rows []ExperimentRow) {
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Run", "Algorithm", "Completed", "Average wait", "Average turnaround", "Throughput", "Normalized turnaround"})
+	for _, r := range rows {
+		table.Append(append([]string{r.Run, r.Algorithm}, experimentMetrics(r.Result)...))
+	}
+	table.Render()
+}
+
+func writeExperimentCSV(w io.Writer, rows []ExperimentRow) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"workload", "run", "algorithm", "completed", "wait", "turnaround", "throughput", "normalized_turnaround"})
+	for _, r := range rows {
+		_ = cw.Write(append([]string{r.Workload, r.Run, r.Algorithm}, experimentMetrics(r.Result)...))
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// runExperiment is the run subcommand: run the experiment an -f manifest
+// describes.
+func runExperiment(w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("run", flag.ContinueOnError)
+	manifest := fs.String("f", "", "experiment manifest (YAML) to run")
+	if err := parseFlags(fs, "run", args); err != nil {
+		return err
+	}
+	if *manifest == "" || fs.NArg() > 0 {
+		return fmt.Errorf("%w: usage: run -f experiment.yaml", ErrInvalidArgs)
+	}
+	f, err := os.Open(*manifest)
+	if err != nil {
+		return fmt.Errorf("%v: error opening manifest", err)
+	}
+	defer f.Close()
+	m, err := parseManifest(f)
+	if err != nil {
+		return fmt.Errorf("%s: %w", *manifest, err)
+	}
+	_, err = RunExperiment(w, m, filepath.Dir(*manifest))
+	return err
+}
@@ -0,0 +1,152 @@
+package scheduler
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_parseManifest(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		src     string
+		want    Manifest
+		wantErr error
+	}{
+		{
+			name: "full",
+			src: `# preemption study
+name: "Preemption # study"
+output: results
+workloads:
+- name: small
+  file: small.csv
+- big.csv
+runs:
+  - name: baseline
+    algorithms: [fcfs, 'sjf']
+  - algorithms:
+      - rr
+    quantum: 3
+    algo-opts: [a, b]
+outputs: [comparison, csv]
+`,
+			want: Manifest{
+				Name:      "Preemption # study",
+				Output:    "results",
+				Workloads: []ManifestWorkload{{Name: "small", File: "small.csv"}, {Name: "big", File: "big.csv"}},
+				Runs: []ManifestRun{
+					{Name: "baseline", Algorithms: []string{"fcfs", "sjf"}, Options: map[string]string{}},
+					{Name: "run2", Algorithms: []string{"rr"}, Options: map[string]string{"quantum": "3", "algo-opts": "a,b"}},
+				},
+				Outputs: []string{"comparison", "csv"},
+			},
+		},
+		{
+			name: "defaults",
+			src:  "workloads: [a.csv]",
+			want: Manifest{
+				Output:    ".",
+				Workloads: []ManifestWorkload{{Name: "a", File: "a.csv"}},
+				Runs:      []ManifestRun{{Name: "default"}},
+				Outputs:   []string{"comparison"},
+			},
+		},
+		{name: "empty", src: "# nothing\n", wantErr: ErrInvalidManifest},
+		{name: "no workloads", src: "name: x", wantErr: ErrInvalidManifest},
+		{name: "unknown key", src: "workloads: [a.csv]\nwork: 1", wantErr: ErrInvalidManifest},
+		{name: "unknown output", src: "workloads: [a.csv]\noutputs: pdf", wantErr: ErrInvalidManifest},
+		{name: "duplicate key", src: "workloads: [a.csv]\nname: a\nname: b", wantErr: ErrInvalidManifest},
+		{name: "duplicate workload", src: "workloads: [a.csv, dir/a.csv]", wantErr: ErrInvalidManifest},
+		{name: "workload without file", src: "workloads:\n  - name: a", wantErr: ErrInvalidManifest},
+		{name: "key without value", src: "workloads:\nname: a", wantErr: ErrInvalidManifest},
+		{name: "bad indentation", src: "workloads: [a.csv]\n  name: a", wantErr: ErrInvalidManifest},
+		{name: "unterminated list", src: "workloads: [a.csv", wantErr: ErrInvalidManifest},
+		{name: "flow mapping", src: "workloads: {a: b}", wantErr: ErrInvalidManifest},
+		{name: "tab indent", src: "workloads:\n\t- a.csv", wantErr: ErrInvalidManifest},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := parseManifest(strings.NewReader(tt.src))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("parseManifest() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseManifest() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_runOptions(t *testing.T) {
+	t.Parallel()
+	algoOpts, engineOpts, err := runOptions(map[string]string{"quantum": "3", "cpus": "2", "seed": "7"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if algoOpts.RR.Quantum != 3 || algoOpts.Seed != 7 || engineOpts.CPUs != 2 {
+		t.Errorf("quantum, seed, cpus = %d, %d, %d, want 3, 7, 2", algoOpts.RR.Quantum, algoOpts.Seed, engineOpts.CPUs)
+	}
+	if _, _, err := runOptions(map[string]string{"quantim": "3"}); !errors.Is(err, ErrInvalidManifest) {
+		t.Errorf("runOptions() with unknown option error = %v, want %v", err, ErrInvalidManifest)
+	}
+	if _, _, err := runOptions(map[string]string{"cpus": "0"}); !errors.Is(err, ErrInvalidArgs) {
+		t.Errorf("runOptions() with no CPUs error = %v, want %v", err, ErrInvalidArgs)
+	}
+}
+
+func Test_runExperiment(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	workload := "1,5,0,2\n2,9,1,1\n3,6,2,3\n"
+	if err := os.WriteFile(filepath.Join(dir, "small.csv"), []byte(workload), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	manifest := filepath.Join(dir, "experiment.yaml")
+	src := `name: Study
+output: out
+workloads: [small.csv]
+runs:
+  - name: base
+    algorithms: [fcfs, sjf]
+  - name: rr2
+    algorithms: rr
+    quantum: 2
+outputs: [comparison, csv, json, svg]
+`
+	if err := os.WriteFile(manifest, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	if err := runExperiment(&out, []string{"-f", manifest}); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"Study", "Workload small", "base", "rr2"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output is missing %q:\n%s", want, out.String())
+		}
+	}
+	for _, name := range []string{"comparison.csv", "small.base.fcfs.json", "small.base.sjf.svg", "small.rr2.rr.json"} {
+		if _, err := os.Stat(filepath.Join(dir, "out", name)); err != nil {
+			t.Errorf("%s was not written: %v", name, err)
+		}
+	}
+	csv, err := os.ReadFile(filepath.Join(dir, "out", "comparison.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Count(string(csv), "\n"); lines != 4 {
+		t.Errorf("comparison.csv has %d lines, want a header and 3 results:\n%s", lines, csv)
+	}
+
+	if err := runExperiment(&out, nil); !errors.Is(err, ErrInvalidArgs) {
+		t.Errorf("runExperiment() without -f error = %v, want %v", err, ErrInvalidArgs)
+	}
+}
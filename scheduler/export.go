@@ -0,0 +1,240 @@
+package scheduler
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"sort"
+	"strings"
+)
+
+// writeMermaid writes result's Gantt chart as a Mermaid gantt definition,
+// one section per process and one task per slice, with ticks as the time
+// unit.
+func writeMermaid(w io.Writer, title string, result Result) error {
+	bw := bufio.NewWriter(w)
+	_, _ = fmt.Fprintln(bw, "gantt")
+	if title != "" {
+		_, _ = fmt.Fprintf(bw, "    title %s\n", strings.ReplaceAll(title, "\n", " "))
+	}
+	_, _ = fmt.Fprintln(bw, "    dateFormat X")
+	_, _ = bw.WriteString("    axisFormat %s\n")
+	var (
+		order  []int64
+		slices = map[int64][]TimeSlice{}
+	)
+	for _, s := range result.Gantt {
+		if _, ok := slices[s.PID]; !ok {
+			order = append(order, s.PID)
+		}
+		slices[s.PID] = append(slices[s.PID], s)
+	}
+	for _, pid := range order {
+		_, _ = fmt.Fprintf(bw, "    section P%d\n", pid)
+		for i, s := range slices[pid] {
+			// A gang has the same slice on each of its CPUs.
+			if i > 0 && s.Start == slices[pid][i-1].Start {
+				continue
+			}
+			_, _ = fmt.Fprintf(bw, "    P%d : %d, %d\n", pid, s.Start, s.Stop)
+		}
+	}
+	return bw.Flush()
+}
+
+// writeChromeTrace writes result's Gantt chart as Chrome trace-event JSON,
+// for chrome://tracing or Perfetto: one thread per CPU, named "CPU n", with
+// one complete event per slice. One tick is exported as a millisecond, as
+// in switch traces.
+func writeChromeTrace(w io.Writer, title string, result Result) error {
+	if title == "" {
+		title = "Schedule"
+	}
+	events := []chromeEvent{{Name: "process_name", Ph: "M", PID: 1, Args: map[string]interface{}{"name": title}}}
+	named := map[int]bool{}
+	for _, s := range result.Gantt {
+		if !named[s.CPU] {
+			named[s.CPU] = true
+			events = append(events, chromeEvent{
+				Name: "thread_name", Ph: "M", PID: 1, TID: int64(s.CPU),
+				Args: map[string]interface{}{"name": fmt.Sprintf("CPU %d", s.CPU)},
+			})
+		}
+		events = append(events, chromeEvent{
+			Name: fmt.Sprintf("P%d", s.PID),
+			Ph:   "X",
+			Ts:   float64(s.Start * traceTickMicros),
+			Dur:  float64((s.Stop - s.Start) * traceTickMicros),
+			PID:  1,
+			TID:  int64(s.CPU),
+			Args: map[string]interface{}{"pid": s.PID},
+		})
+	}
+	enc := json.NewEncoder(w)
+	return enc.Encode(struct {
+		TraceEvents     []chromeEvent `json:"traceEvents"`
+		DisplayTimeUnit string        `json:"displayTimeUnit"`
+	}{events, "ms"})
+}
+
+// dotPointsPerTick is how wide one tick is drawn in Graphviz output; cells
+// narrower than dotMinLabelWidth keep their label only as a tooltip.
+const (
+	dotPointsPerTick = 12
+	dotMinLabelWidth = 36
+)
+
+// dotColors are the Graphviz fill colours PIDs are given in turn.
+var dotColors = []string{"lightblue", "palegreen", "khaki", "lightpink", "plum", "lightsalmon", "paleturquoise", "wheat"}
+
+// writeDot writes result's Gantt chart as a Graphviz graph: a single row
+// table whose cells are as wide as their slices, with idle time greyed. A
+// run on several CPUs gets one such row per CPU, stacked on a shared time
+// axis.
+func writeDot(w io.Writer, title string, result Result) error {
+	bw := bufio.NewWriter(w)
+	_, _ = fmt.Fprintln(bw, "digraph gantt {")
+	if title != "" {
+		_, _ = fmt.Fprintf(bw, "  label=%q;\n  labelloc=t;\n", title)
+	}
+	_, _ = fmt.Fprintln(bw, "  node [shape=plaintext];")
+	cell := func(from, to int64, text, color string) {
+		width := (to - from) * dotPointsPerTick
+		label := fmt.Sprintf("%s %d-%d", text, from, to)
+		shown := ""
+		if width >= dotMinLabelWidth {
+			shown = fmt.Sprintf("%s<BR/>%d-%d", html.EscapeString(text), from, to)
+		}
+		_, _ = fmt.Fprintf(bw, `    <TD WIDTH="%d" FIXEDSIZE="TRUE" BGCOLOR="%s" TITLE="%s">%s</TD>`+"\n",
+			width, color, html.EscapeString(label), shown)
+	}
+	lanes := ganttLanes(result.Gantt)
+	var origin, end int64
+	for i, s := range result.Gantt {
+		if i == 0 || s.Stop > end {
+			end = s.Stop
+		}
+		if i == 0 {
+			origin = s.Start
+		}
+	}
+	for i, lane := range lanes {
+		name := "schedule"
+		if len(lanes) > 1 {
+			name = fmt.Sprintf("cpu%d", lane[0].CPU)
+			if i > 0 {
+				_, _ = fmt.Fprintf(bw, "  cpu%d -> %s [style=invis];\n", lanes[i-1][0].CPU, name)
+			}
+		}
+		_, _ = fmt.Fprintf(bw, "  %s [label=<<TABLE BORDER=\"0\" CELLBORDER=\"1\" CELLSPACING=\"0\"><TR>\n", name)
+		if len(lanes) > 1 {
+			_, _ = fmt.Fprintf(bw, "    <TD>CPU %d</TD>\n", lane[0].CPU)
+			if lane[0].Start > origin {
+				cell(origin, lane[0].Start, "idle", "lightgrey")
+			}
+		}
+		for i, s := range lane {
+			if i > 0 && s.Start > lane[i-1].Stop {
+				cell(lane[i-1].Stop, s.Start, "idle", "lightgrey")
+			}
+			color := dotColors[int(uint64(s.PID)%uint64(len(dotColors)))]
+			cell(s.Start, s.Stop, fmt.Sprintf("P%d", s.PID), color)
+		}
+		if len(lanes) > 1 && lane[len(lane)-1].Stop < end {
+			cell(lane[len(lane)-1].Stop, end, "idle", "lightgrey")
+		}
+		_, _ = fmt.Fprintln(bw, "  </TR></TABLE>>];")
+	}
+	_, _ = fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
+
+// svgPixelsPerTick is how wide one tick is drawn in SVG output, and
+// svgLaneHeight how tall each CPU's lane is.
+const (
+	svgPixelsPerTick = 12
+	svgLaneHeight    = 30
+)
+
+// writeSVG writes result's Gantt chart as an SVG image: one lane of bars
+// per CPU, as wide as their slices and coloured like writeDot's, over a
+// time axis labelled where slices start and stop.
+func writeSVG(w io.Writer, title string, result Result) error {
+	bw := bufio.NewWriter(w)
+	var (
+		lanes       [][]TimeSlice
+		origin, end int64
+	)
+	if len(result.Gantt) > 0 {
+		lanes = ganttLanes(result.Gantt)
+		origin, end = ganttSpan(result.Gantt)
+	}
+	const left, top = 60, 30
+	x := func(t int64) int64 { return left + (t-origin)*svgPixelsPerTick }
+	width, height := x(end)+left, top+int64(len(lanes))*svgLaneHeight+30
+	_, _ = fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" font-family="sans-serif" font-size="12">`+"\n", width, height)
+	if title != "" {
+		_, _ = fmt.Fprintf(bw, `  <text x="%d" y="20" font-weight="bold">%s</text>`+"\n", left, html.EscapeString(title))
+	}
+	ticks := map[int64]bool{}
+	for i, lane := range lanes {
+		y := top + int64(i)*svgLaneHeight
+		_, _ = fmt.Fprintf(bw, `  <text x="4" y="%d">CPU %d</text>`+"\n", y+svgLaneHeight/2+4, lane[0].CPU)
+		for _, s := range lane {
+			color := dotColors[int(uint64(s.PID)%uint64(len(dotColors)))]
+			_, _ = fmt.Fprintf(bw, `  <rect x="%d" y="%d" width="%d" height="%d" fill="%s" stroke="black"><title>P%d %d-%d</title></rect>`+"\n",
+				x(s.Start), y, (s.Stop-s.Start)*svgPixelsPerTick, svgLaneHeight-4, color, s.PID, s.Start, s.Stop)
+			if label := fmt.Sprintf("P%d", s.PID); int64(len(label))*7 < (s.Stop-s.Start)*svgPixelsPerTick {
+				_, _ = fmt.Fprintf(bw, `  <text x="%d" y="%d" text-anchor="middle">%s</text>`+"\n",
+					(x(s.Start)+x(s.Stop))/2, y+svgLaneHeight/2+2, label)
+			}
+			ticks[s.Start], ticks[s.Stop] = true, true
+		}
+	}
+	axis := top + int64(len(lanes))*svgLaneHeight + 12
+	times := make([]int64, 0, len(ticks))
+	for t := range ticks {
+		times = append(times, t)
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i] < times[j] })
+	next := int64(0)
+	for _, t := range times {
+		// Skip labels that would overlap the one before.
+		if x(t) < next {
+			continue
+		}
+		_, _ = fmt.Fprintf(bw, `  <text x="%d" y="%d" text-anchor="middle">%d</text>`+"\n", x(t), axis, t)
+		next = x(t) + int64(len(fmt.Sprint(t)))*7 + 4
+	}
+	_, _ = fmt.Fprintln(bw, "</svg>")
+	return bw.Flush()
+}
+
+// sliceWriter streams Gantt slices as CSV lines of pid,start,stop,cpu, in
+// the order they end, so a schedule is written out without being kept.
+type sliceWriter struct {
+	bw  *bufio.Writer
+	err error
+}
+
+func newSliceWriter(w io.Writer) *sliceWriter {
+	sw := &sliceWriter{bw: bufio.NewWriter(w)}
+	_, sw.err = fmt.Fprintln(sw.bw, "pid,start,stop,cpu")
+	return sw
+}
+
+// write adds s, remembering the first error to report from flush.
+func (sw *sliceWriter) write(s TimeSlice) {
+	if sw.err == nil {
+		_, sw.err = fmt.Fprintf(sw.bw, "%d,%d,%d,%d\n", s.PID, s.Start, s.Stop, s.CPU)
+	}
+}
+
+func (sw *sliceWriter) flush() error {
+	if sw.err != nil {
+		return sw.err
+	}
+	return sw.bw.Flush()
+}
@@ -0,0 +1,137 @@
+package scheduler
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func Test_writeMermaid(t *testing.T) {
+	t.Parallel()
+	result := Result{Gantt: []TimeSlice{{PID: 1, Start: 0, Stop: 2}, {PID: 2, Start: 2, Stop: 3}, {PID: 1, Start: 3, Stop: 5}}}
+	var w bytes.Buffer
+	if err := writeMermaid(&w, "Round-robin", result); err != nil {
+		t.Fatal(err)
+	}
+	want := `gantt
+    title Round-robin
+    dateFormat X
+    axisFormat %s
+    section P1
+    P1 : 0, 2
+    P1 : 3, 5
+    section P2
+    P2 : 2, 3
+`
+	if w.String() != want {
+		t.Errorf("writeMermaid() =\n%s\nwant\n%s", w.String(), want)
+	}
+}
+
+func Test_writeDot(t *testing.T) {
+	t.Parallel()
+	result := Result{Gantt: []TimeSlice{{PID: 1, Start: 0, Stop: 5}, {PID: 2, Start: 7, Stop: 8}}}
+	var w bytes.Buffer
+	if err := writeDot(&w, "FCFS", result); err != nil {
+		t.Fatal(err)
+	}
+	got := w.String()
+	for _, want := range []string{
+		`label="FCFS";`,
+		`<TD WIDTH="60" FIXEDSIZE="TRUE" BGCOLOR="palegreen" TITLE="P1 0-5">P1<BR/>0-5</TD>`,
+		`<TD WIDTH="24" FIXEDSIZE="TRUE" BGCOLOR="lightgrey" TITLE="idle 5-7"></TD>`,
+		`<TD WIDTH="12" FIXEDSIZE="TRUE" BGCOLOR="khaki" TITLE="P2 7-8"></TD>`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("writeDot() is missing %s in\n%s", want, got)
+		}
+	}
+}
+
+func Test_writeChromeTrace(t *testing.T) {
+	t.Parallel()
+	result := Result{Gantt: []TimeSlice{{PID: 1, Start: 0, Stop: 2}, {PID: 2, Start: 1, Stop: 3, CPU: 1}}}
+	var w bytes.Buffer
+	if err := writeChromeTrace(&w, "Round-robin", result); err != nil {
+		t.Fatal(err)
+	}
+	got := w.String()
+	for _, want := range []string{
+		`{"name":"process_name","ph":"M","ts":0,"pid":1,"tid":0,"args":{"name":"Round-robin"}}`,
+		`{"name":"thread_name","ph":"M","ts":0,"pid":1,"tid":1,"args":{"name":"CPU 1"}}`,
+		`{"name":"P1","ph":"X","ts":0,"dur":2000,"pid":1,"tid":0,"args":{"pid":1}}`,
+		`{"name":"P2","ph":"X","ts":1000,"dur":2000,"pid":1,"tid":1,"args":{"pid":2}}`,
+		`"displayTimeUnit":"ms"`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("writeChromeTrace() is missing %s in\n%s", want, got)
+		}
+	}
+}
+
+func Test_writeSVG(t *testing.T) {
+	t.Parallel()
+	result := Result{Gantt: []TimeSlice{{PID: 1, Start: 0, Stop: 5}, {PID: 2, Start: 7, Stop: 8}, {PID: 3, Start: 0, Stop: 2, CPU: 1}}}
+	var w bytes.Buffer
+	if err := writeSVG(&w, "FCFS & co", result); err != nil {
+		t.Fatal(err)
+	}
+	got := w.String()
+	for _, want := range []string{
+		`<svg xmlns="http://www.w3.org/2000/svg" width="216" height="120"`,
+		`<text x="60" y="20" font-weight="bold">FCFS &amp; co</text>`,
+		`<text x="4" y="79">CPU 1</text>`,
+		`<rect x="60" y="30" width="60" height="26" fill="palegreen" stroke="black"><title>P1 0-5</title></rect>`,
+		`<text x="90" y="47" text-anchor="middle">P1</text>`,
+		`<rect x="144" y="30" width="12" height="26" fill="khaki" stroke="black"><title>P2 7-8</title></rect>`,
+		`<rect x="60" y="60" width="24" height="26" fill="lightpink" stroke="black"><title>P3 0-2</title></rect>`,
+		`<text x="156" y="102" text-anchor="middle">8</text>`,
+		"</svg>\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("writeSVG() is missing %s in\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, `>P2</text>`) {
+		t.Errorf("writeSVG() labels a slice too narrow for it:\n%s", got)
+	}
+}
+
+func Test_sliceWriter(t *testing.T) {
+	t.Parallel()
+	processes := []Process{{ProcessID: 1, BurstDuration: 4}, {ProcessID: 2, ArrivalTime: 1, BurstDuration: 3}}
+	var w bytes.Buffer
+	sw := newSliceWriter(&w)
+	result := Simulate(processes, newRRPolicy(processes, RROptions{Quantum: 2}), EngineOptions{OnSlice: sw.write, DiscardGantt: true})
+	if err := sw.flush(); err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Gantt) != 0 {
+		t.Errorf("Gantt = %v, want it discarded", result.Gantt)
+	}
+	want := "pid,start,stop,cpu\n1,0,2,0\n2,2,4,0\n1,4,6,0\n2,6,7,0\n"
+	if w.String() != want {
+		t.Errorf("streamed slices =\n%s\nwant\n%s", w.String(), want)
+	}
+}
+
+func TestSimulate_discardEvents(t *testing.T) {
+	t.Parallel()
+	processes := make([]Process, 2000)
+	for i := range processes {
+		processes[i] = Process{ProcessID: int64(i + 1), BurstDuration: 3, ArrivalTime: int64(i)}
+	}
+	policy := func() Policy { return newRRPolicy(processes, RROptions{Quantum: 2}) }
+	kept := Simulate(processes, policy(), EngineOptions{})
+	streamed := Simulate(processes, policy(), EngineOptions{OnSlice: func(TimeSlice) {}, DiscardGantt: true, DiscardEvents: true})
+	// Nothing the run keeps grows with the schedule.
+	if len(streamed.Events) != 0 || len(streamed.QueueLength) != 0 || len(streamed.Gantt) != 0 {
+		t.Errorf("kept %d events, %d queue samples and %d slices, want none", len(streamed.Events), len(streamed.QueueLength), len(streamed.Gantt))
+	}
+	if streamed.Queue != kept.Queue {
+		t.Errorf("queue stats = %+v, want %+v as when kept", streamed.Queue, kept.Queue)
+	}
+	if streamed.AverageWait != kept.AverageWait || len(streamed.Schedule) != len(kept.Schedule) {
+		t.Errorf("schedule differs when streamed")
+	}
+}
@@ -0,0 +1,203 @@
+package scheduler
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// fairSharePolicy is guaranteed scheduling: with n processes in the system
+// each is owed 1/n of the CPU, and every tick the ready process that has
+// received the smallest fraction of what it is owed runs. Grouped, the CPU is
+// first split equally between the groups with processes in the system, then
+// equally between each group's processes, and the most under-served group
+// goes first.
+type fairSharePolicy struct {
+	grouped  bool
+	group    map[int64]string
+	now      int64
+	order    []int64
+	present  map[int64]bool
+	entitled map[int64]float64
+	received map[int64]int64
+	started  map[int64]int64
+}
+
+func newFairSharePolicy(processes []Process, grouped bool) Policy {
+	p := &fairSharePolicy{
+		grouped:  grouped,
+		group:    map[int64]string{},
+		present:  map[int64]bool{},
+		entitled: map[int64]float64{},
+		received: map[int64]int64{},
+		started:  map[int64]int64{},
+	}
+	if grouped {
+		for i := range processes {
+			p.group[processes[i].ProcessID] = processes[i].Group
+		}
+	}
+	return p
+}
+
+func (p *fairSharePolicy) Observe(ev Event) {
+	// Everyone in the system has been owed their split since the last event.
+	if dt := ev.Time - p.now; dt > 0 && len(p.present) > 0 {
+		members := map[string]int{}
+		for pid := range p.present {
+			members[p.group[pid]]++
+		}
+		for pid := range p.present {
+			p.entitled[pid] += float64(dt) / float64(len(members)) / float64(members[p.group[pid]])
+		}
+	}
+	p.now = ev.Time
+	switch ev.Kind {
+	case EventArrive:
+		p.present[ev.PID] = true
+		p.order = append(p.order, ev.PID)
+	case EventDispatch:
+		p.started[ev.PID] = ev.Time
+	case EventPreempt, EventExpire, EventBlock, EventLockWait, EventSemWait, EventSuspend, EventComplete:
+		if start, ok := p.started[ev.PID]; ok {
+			p.received[ev.PID] += ev.Time - start
+			delete(p.started, ev.PID)
+		}
+		if ev.Kind == EventComplete {
+			delete(p.present, ev.PID)
+		}
+	}
+}
+
+// ratio is the fraction of their entitlement the given processes have
+// received; processes not yet owed anything count as the most under-served.
+func (p *fairSharePolicy) ratio(pids ...int64) float64 {
+	var received, entitled float64
+	for _, pid := range pids {
+		received += float64(p.received[pid])
+		entitled += p.entitled[pid]
+	}
+	if entitled == 0 {
+		return 0
+	}
+	return received / entitled
+}
+
+// groupRatio is ratio over every process of group seen so far.
+func (p *fairSharePolicy) groupRatio(group string) float64 {
+	var pids []int64
+	for _, pid := range p.order {
+		if p.group[pid] == group {
+			pids = append(pids, pid)
+		}
+	}
+	return p.ratio(pids...)
+}
+
+func (p *fairSharePolicy) Less(a, b *Task) bool {
+	if ga, gb := p.group[a.ProcessID], p.group[b.ProcessID]; ga != gb {
+		if ra, rb := p.groupRatio(ga), p.groupRatio(gb); ra != rb {
+			return ra < rb
+		}
+	}
+	if ra, rb := p.ratio(a.ProcessID), p.ratio(b.ProcessID); ra != rb {
+		return ra < rb
+	}
+	return a.Seq < b.Seq
+}
+func (p *fairSharePolicy) Preemptive() bool { return false }
+func (p *fairSharePolicy) Quantum() int64   { return 1 }
+
+// shares is each process's CPU time against its entitlement.
+func (p *fairSharePolicy) shares() []ProcessShare {
+	shares := make([]ProcessShare, len(p.order))
+	for i, pid := range p.order {
+		shares[i] = ProcessShare{PID: pid, Received: p.received[pid], Entitled: p.entitled[pid]}
+	}
+	return shares
+}
+
+// Report prints each process's final CPU time against its entitlement and
+// the mean relative gap between them, then the same for each group.
+func (p *fairSharePolicy) Report(w io.Writer) {
+	shares := p.shares()
+	if len(shares) == 0 {
+		return
+	}
+	_, _ = fmt.Fprintf(w, "Guaranteed share (deviation %.2f):\n", shareDeviation(shares))
+	for _, s := range shares {
+		_, _ = fmt.Fprintf(w, "  P%d: received %d, entitled %.1f\n", s.PID, s.Received, s.Entitled)
+	}
+	if !p.grouped {
+		return
+	}
+	var (
+		groups   []string
+		received = map[string]int64{}
+		entitled = map[string]float64{}
+	)
+	for _, s := range shares {
+		g := p.group[s.PID]
+		if _, ok := entitled[g]; !ok {
+			groups = append(groups, g)
+		}
+		received[g] += s.Received
+		entitled[g] += s.Entitled
+	}
+	for _, g := range groups {
+		_, _ = fmt.Fprintf(w, "  group %s: received %d, entitled %.1f\n", groupName(g), received[g], entitled[g])
+	}
+}
+
+// GroupUsage is the CPU time a group's processes received in a run.
+type GroupUsage struct {
+	Group string
+	CPU   int64
+	// Utilization is CPU as a fraction of the run's length on every CPU.
+	Utilization float64
+}
+
+// groupUsage totals CPU time by group, or returns nil if no process names
+// one.
+func (e *engine) groupUsage() []GroupUsage {
+	var (
+		cpu     = map[string]int64{}
+		grouped bool
+	)
+	for _, t := range e.tasks {
+		cpu[t.Group] += t.cpuDone * int64(t.threads)
+		grouped = grouped || t.Group != ""
+	}
+	if !grouped {
+		return nil
+	}
+	usage := make([]GroupUsage, 0, len(cpu))
+	for g, c := range cpu {
+		u := GroupUsage{Group: g, CPU: c}
+		if e.now > 0 {
+			u.Utilization = float64(c) / float64(e.now*int64(len(e.cores)))
+		}
+		usage = append(usage, u)
+	}
+	sort.Slice(usage, func(i, j int) bool { return usage[i].Group < usage[j].Group })
+	return usage
+}
+
+func groupName(g string) string {
+	if g == "" {
+		return "(none)"
+	}
+	return g
+}
+
+func outputGroups(w io.Writer, groups []GroupUsage) {
+	if len(groups) == 0 {
+		return
+	}
+	parts := make([]string, len(groups))
+	for i, g := range groups {
+		parts[i] = fmt.Sprintf("%s %d (%.0f%%)", groupName(g.Group), g.CPU, 100*g.Utilization)
+	}
+	_, _ = fmt.Fprintf(w, "CPU by group: %s\n", strings.Join(parts, ", "))
+}
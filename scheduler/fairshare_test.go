@@ -0,0 +1,62 @@
+package scheduler
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestFairShare(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 4},
+		{ProcessID: 2, ArrivalTime: 2, BurstDuration: 2},
+	}
+	policy := newFairSharePolicy(processes, false)
+	got := Simulate(processes, policy, EngineOptions{})
+	// 1 runs alone, then the two alternate, each kept near half the CPU
+	// since 2 arrived.
+	want := []TimeSlice{
+		{PID: 1, Start: 0, Stop: 1}, {PID: 1, Start: 1, Stop: 2}, {PID: 2, Start: 2, Stop: 3},
+		{PID: 1, Start: 3, Stop: 4}, {PID: 2, Start: 4, Stop: 5}, {PID: 1, Start: 5, Stop: 6},
+	}
+	if !reflect.DeepEqual(got.Gantt, want) {
+		t.Errorf("Gantt = %v, want %v", got.Gantt, want)
+	}
+
+	var w bytes.Buffer
+	policy.(Reporter).Report(&w)
+	// 1 is owed all of 0-2 and 5-6 and half of 2-5.
+	wantReport := `Guaranteed share (deviation 0.22):
+  P1: received 4, entitled 4.5
+  P2: received 2, entitled 1.5
+`
+	if w.String() != wantReport {
+		t.Errorf("Report() =\n%s\nwant\n%s", w.String(), wantReport)
+	}
+}
+
+func TestFairShare_grouped(t *testing.T) {
+	t.Parallel()
+	// alice has three processes to bob's one, but each group gets half.
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 2, Group: "alice"},
+		{ProcessID: 2, BurstDuration: 2, Group: "alice"},
+		{ProcessID: 3, BurstDuration: 2, Group: "alice"},
+		{ProcessID: 4, BurstDuration: 4, Group: "bob"},
+	}
+	got := Simulate(processes, newFairSharePolicy(processes, true), EngineOptions{})
+	var bobBy4 int64
+	for _, s := range got.Gantt {
+		if s.PID == 4 && s.Stop <= 8 {
+			bobBy4 += s.Stop - s.Start
+		}
+	}
+	if bobBy4 != 4 {
+		t.Errorf("bob ran %d of the first 8 ticks, want 4: %v", bobBy4, got.Gantt)
+	}
+	want := []GroupUsage{{Group: "alice", CPU: 6, Utilization: 0.6}, {Group: "bob", CPU: 4, Utilization: 0.4}}
+	if !reflect.DeepEqual(got.Groups, want) {
+		t.Errorf("Groups = %+v, want %+v", got.Groups, want)
+	}
+}
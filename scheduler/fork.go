@@ -0,0 +1,245 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+)
+
+// ErrInvalidForkPoint is returned when a fork point cannot be taken or its
+// run does not replay to the state it recorded.
+var ErrInvalidForkPoint = errors.New("invalid fork point")
+
+// ForkPoint marks tick Time of a run, for a what-if that hands the rest of
+// it to another algorithm. It holds the run's inputs, not the engine's or
+// the policy's memory: Fork replays the run from the start up to Time, as
+// simulations are deterministic. State is where every process stood at
+// Time, for showing and for Fork to check that the replay got back there.
+type ForkPoint struct {
+	Algorithm string           `json:"algorithm"`
+	Options   AlgorithmOptions `json:"options"`
+	Engine    EngineOptions    `json:"engine"`
+	Processes []Process        `json:"processes"`
+	Time      int64            `json:"time"`
+	State     EngineState      `json:"state"`
+}
+
+// EngineState is the engine's clock, which processes are where, and how far
+// each admitted process has got.
+type EngineState struct {
+	Time    int64   `json:"time"`
+	Running []int64 `json:"running"`
+	// Ready is in the order the processes joined the ready queues.
+	Ready []int64 `json:"ready"`
+	// Blocked processes are waiting for I/O, a lock or another process.
+	Blocked []int64     `json:"blocked"`
+	Done    []int64     `json:"done"`
+	Tasks   []TaskState `json:"tasks"`
+}
+
+// TaskState is an admitted process's progress: the burst it is in, counting
+// CPU and I/O bursts from zero, the time left in it, and its CPU time and
+// time spent waiting so far.
+type TaskState struct {
+	PID       int64 `json:"pid"`
+	Phase     int   `json:"phase"`
+	Remaining int64 `json:"remaining"`
+	CPU       int64 `json:"cpu"`
+	Wait      int64 `json:"wait"`
+}
+
+// state captures where every process is at the current time.
+func (e *engine) state() EngineState {
+	s := EngineState{
+		Time:    e.now,
+		Running: []int64{},
+		Ready:   []int64{},
+		Blocked: []int64{},
+		Done:    []int64{},
+		Tasks:   []TaskState{},
+	}
+	var ready []*Task
+	for _, t := range e.tasks {
+		if !t.admitted {
+			continue
+		}
+		switch {
+		case t.phase == len(t.bursts):
+			s.Done = append(s.Done, t.ProcessID)
+		case t.queued:
+			ready = append(ready, t)
+		case len(t.cpus) > 0:
+			s.Running = append(s.Running, t.ProcessID)
+		default:
+			s.Blocked = append(s.Blocked, t.ProcessID)
+		}
+		s.Tasks = append(s.Tasks, TaskState{
+			PID:       t.ProcessID,
+			Phase:     t.phase,
+			Remaining: t.Remaining,
+			CPU:       t.cpuDone,
+			Wait:      t.waited(e.now),
+		})
+	}
+	sort.Slice(ready, func(i, j int) bool { return ready[i].Seq < ready[j].Seq })
+	for _, t := range ready {
+		s.Ready = append(s.Ready, t.ProcessID)
+	}
+	return s
+}
+
+// setPolicy hands the rest of the run to p. The ready queues are rebuilt in
+// p's order, an Observer is first shown every event so far, and running
+// slices are cut at the current time so any new quantum starts from it.
+func (e *engine) setPolicy(p Policy) {
+	if o, ok := p.(Observer); ok {
+		for _, ev := range e.events {
+			o.Observe(ev)
+		}
+	}
+	e.policy = p
+	for i, q := range e.queues {
+		var tasks []*Task
+		for q.Len() > 0 {
+			tasks = append(tasks, q.Take())
+		}
+		e.queues[i] = newReadyQueue(p)
+		for _, t := range tasks {
+			e.queues[i].Add(t)
+		}
+	}
+	for _, t := range e.running {
+		if t.sliceStart < e.now {
+			e.endSlice(t)
+			t.sliceStart = e.now
+		}
+	}
+	e.dispatch()
+	for e.preempt() {
+		e.dispatch()
+	}
+	e.sample()
+}
+
+// NewForkPoint simulates processes with the named algorithm up to t and
+// marks the state there. It fails if the run is over by then.
+func NewForkPoint(processes []Process, algorithm string, opts AlgorithmOptions, engineOpts EngineOptions, t int64) (ForkPoint, error) {
+	a, ok := LookupAlgorithm(algorithm)
+	if !ok {
+		return ForkPoint{}, fmt.Errorf("%w: unknown algorithm %q", ErrInvalidArgs, algorithm)
+	}
+	e := newEngine(processes, a.New(processes, opts), engineOpts)
+	if e.RunUntil(t) {
+		return ForkPoint{}, fmt.Errorf("%w: the simulation is over before t=%d", ErrInvalidForkPoint, t)
+	}
+	engineOpts.Log, engineOpts.OnEvent = nil, nil
+	return ForkPoint{
+		Algorithm: algorithm,
+		Options:   opts,
+		Engine:    engineOpts,
+		Processes: processes,
+		Time:      t,
+		State:     e.state(),
+	}, nil
+}
+
+// Fork replays fp's run up to its Time and carries it on to the end. If
+// fork is not nil, its policy, built with forkOpts, takes over there;
+// otherwise the original algorithm finishes the run. log, if set, is told
+// about the part of the run after Time.
+func Fork(fp ForkPoint, fork *Algorithm, forkOpts AlgorithmOptions, log *Logger) (Result, error) {
+	a, ok := LookupAlgorithm(fp.Algorithm)
+	if !ok {
+		return Result{}, fmt.Errorf("%w: unknown algorithm %q", ErrInvalidForkPoint, fp.Algorithm)
+	}
+	e := newEngine(fp.Processes, a.New(fp.Processes, fp.Options), fp.Engine)
+	if e.RunUntil(fp.Time) {
+		return Result{}, fmt.Errorf("%w: the simulation is over before t=%d", ErrInvalidForkPoint, fp.Time)
+	}
+	if !reflect.DeepEqual(e.state(), fp.State) {
+		return Result{}, fmt.Errorf("%w: replaying the workload does not reach the saved state", ErrInvalidForkPoint)
+	}
+	e.opts.Log = log
+	if fork != nil {
+		e.setPolicy(fork.New(fp.Processes, forkOpts))
+	}
+	e.RunUntil(-1)
+	return e.result(), nil
+}
+
+func writeForkPoint(w io.Writer, fp ForkPoint) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(fp)
+}
+
+// outputForkPoint summarises where a fork point stands.
+func outputForkPoint(w io.Writer, path string, fp ForkPoint) {
+	s := fp.State
+	fmt.Fprintf(w, "Fork point of %s at t=%d written to %s\n", fp.Algorithm, fp.Time, path)
+	fmt.Fprintf(w, "Running %s, ready %s, blocked %s, done %s\n",
+		formatPIDList(s.Running), formatPIDList(s.Ready), formatPIDList(s.Blocked), formatPIDList(s.Done))
+}
+
+// formatPIDList formats PIDs for a summary line, with a dash for none.
+func formatPIDList(pids []int64) string {
+	if len(pids) == 0 {
+		return "-"
+	}
+	return formatPIDs(pids)
+}
+
+// runFork is the fork subcommand: it finishes a run from its fork point,
+// with another algorithm if -algo is given.
+func runFork(w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("fork", flag.ContinueOnError)
+	algo := fs.String("algo", "", "algorithm to fork the rest of the run to (default the fork point's)")
+	verbose := fs.Bool("v", false, "log why each process is dispatched, preempted or taken off the CPU after the fork point")
+	ganttFlags := addGanttFlags(fs)
+	algoFlags := addAlgorithmFlags(fs)
+	if err := parseFlags(fs, "fork", args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("%w: usage: fork [-algo name] fork.json", ErrInvalidArgs)
+	}
+	var fp ForkPoint
+	if err := readJSONFile(fs.Arg(0), &fp); err != nil {
+		return err
+	}
+	original, ok := LookupAlgorithm(fp.Algorithm)
+	if !ok {
+		return fmt.Errorf("%w: unknown algorithm %q", ErrInvalidForkPoint, fp.Algorithm)
+	}
+	var log *Logger
+	if *verbose {
+		log = NewLogger(os.Stderr)
+	}
+	title := fmt.Sprintf("%s, replayed to t=%d", original.Title, fp.Time)
+	var fork *Algorithm
+	forkOpts := fp.Options
+	if *algo != "" {
+		a, ok := LookupAlgorithm(*algo)
+		if !ok {
+			return fmt.Errorf("%w: unknown algorithm %q", ErrInvalidArgs, *algo)
+		}
+		opts, err := algoFlags.options()
+		if err != nil {
+			return err
+		}
+		opts.MinShare = fp.Options.MinShare
+		fork, forkOpts = &a, opts
+		title = fmt.Sprintf("What-if: %s until t=%d, then %s", original.Title, fp.Time, a.Title)
+	}
+	result, err := Fork(fp, fork, forkOpts, log)
+	if err != nil {
+		return err
+	}
+	Render(w, result, RenderOptions{Title: title, Gantt: ganttFlags.options(w)})
+	return nil
+}
@@ -0,0 +1,107 @@
+package scheduler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestFork(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 5, Priority: 2},
+		{ProcessID: 2, ArrivalTime: 3, BurstDuration: 9, Priority: 1},
+		{ProcessID: 3, ArrivalTime: 6, BurstDuration: 6, Priority: 3, Bursts: []int64{2, 3, 4}},
+	}
+	rr, _ := LookupAlgorithm("rr")
+	tests := []struct {
+		name string
+		at   int64
+		fork *Algorithm
+		// want is the run the forked one should match.
+		want func() Result
+	}{
+		{
+			name: "same algorithm",
+			at:   7,
+			want: func() Result { return Simulate(processes, sjfPolicy{}, EngineOptions{}) },
+		},
+		{
+			name: "fork before anything arrives",
+			at:   0,
+			fork: &rr,
+			want: func() Result { return Simulate(processes, newRRPolicy(processes, RROptions{}), EngineOptions{}) },
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			fp, err := NewForkPoint(processes, "sjf", AlgorithmOptions{}, EngineOptions{}, tt.at)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Go through JSON as the fork subcommand does.
+			var b bytes.Buffer
+			if err := writeForkPoint(&b, fp); err != nil {
+				t.Fatal(err)
+			}
+			var loaded ForkPoint
+			if err := json.Unmarshal(b.Bytes(), &loaded); err != nil {
+				t.Fatal(err)
+			}
+			got, err := Fork(loaded, tt.fork, AlgorithmOptions{}, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := tt.want()
+			if !reflect.DeepEqual(got.Schedule, want.Schedule) {
+				t.Errorf("Schedule = %v, want %v", got.Schedule, want.Schedule)
+			}
+		})
+	}
+}
+
+func TestFork_fork(t *testing.T) {
+	t.Parallel()
+	// FCFS runs 1 to completion. Forking to SJF at 2 hands the CPU to the
+	// shorter 2, which arrived at 1.
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 6},
+		{ProcessID: 2, ArrivalTime: 1, BurstDuration: 2},
+	}
+	fp, err := NewForkPoint(processes, "fcfs", AlgorithmOptions{}, EngineOptions{}, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int64{2}; !reflect.DeepEqual(fp.State.Ready, want) {
+		t.Errorf("Ready = %v, want %v", fp.State.Ready, want)
+	}
+	sjf, _ := LookupAlgorithm("sjf")
+	got, err := Fork(fp, &sjf, AlgorithmOptions{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []TimeSlice{{PID: 1, Start: 0, Stop: 2}, {PID: 2, Start: 2, Stop: 4}, {PID: 1, Start: 4, Stop: 8}}
+	if !reflect.DeepEqual(got.Gantt, want) {
+		t.Errorf("Gantt = %v, want %v", got.Gantt, want)
+	}
+}
+
+func TestFork_errors(t *testing.T) {
+	t.Parallel()
+	processes := []Process{{ProcessID: 1, BurstDuration: 4}}
+	if _, err := NewForkPoint(processes, "fcfs", AlgorithmOptions{}, EngineOptions{}, 4); !errors.Is(err, ErrInvalidForkPoint) {
+		t.Errorf("fork point after the end: error %v, want %v", err, ErrInvalidForkPoint)
+	}
+	fp, err := NewForkPoint(processes, "fcfs", AlgorithmOptions{}, EngineOptions{}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fp.State.Tasks[0].Remaining = 1
+	if _, err := Fork(fp, nil, AlgorithmOptions{}, nil); !errors.Is(err, ErrInvalidForkPoint) {
+		t.Errorf("tampered state: error %v, want %v", err, ErrInvalidForkPoint)
+	}
+}
@@ -0,0 +1,93 @@
+package scheduler
+
+import (
+	"fmt"
+	"io"
+	"sort"
+)
+
+// CoreStats is how busy the CPUs of a multi-CPU run were.
+type CoreStats struct {
+	CPUs int
+	// Idle is the CPU time, summed over CPUs, with nothing running.
+	Idle int64
+	// GangIdle is the part of Idle during which a gang was ready but
+	// needed more CPUs than were free, and nothing else could use them.
+	GangIdle int64
+	// Utilization is the fraction of the run's CPU time that was not idle.
+	Utilization float64
+	// Migrations counts dispatches onto a different CPU from the one the
+	// process last ran on.
+	Migrations int
+	// PerCore is set when each CPU had its own queue; Steals counts the
+	// processes taken from another CPU's queue.
+	PerCore bool
+	Steals  int
+}
+
+// coreStats returns the zero CoreStats for a single CPU, whose idle time the
+// queue statistics already show.
+func (e *engine) coreStats() CoreStats {
+	if len(e.cores) < 2 {
+		return CoreStats{}
+	}
+	stats := CoreStats{
+		CPUs:       len(e.cores),
+		Idle:       e.idleTime,
+		GangIdle:   e.gangIdle,
+		Migrations: e.migrations,
+		PerCore:    len(e.queues) > 1,
+		Steals:     e.steals,
+	}
+	if total := e.now * int64(len(e.cores)); total > 0 {
+		stats.Utilization = 1 - float64(e.idleTime)/float64(total)
+	}
+	return stats
+}
+
+func outputCores(w io.Writer, stats CoreStats) {
+	if stats.CPUs == 0 {
+		return
+	}
+	_, _ = fmt.Fprintf(w, "CPUs: %d, %.0f%% busy, %d migrations", stats.CPUs, 100*stats.Utilization, stats.Migrations)
+	if stats.PerCore {
+		_, _ = fmt.Fprintf(w, ", %d steals", stats.Steals)
+	}
+	_, _ = fmt.Fprintf(w, ", %d CPU-ticks idle", stats.Idle)
+	if stats.GangIdle > 0 {
+		_, _ = fmt.Fprintf(w, ", %d of them while a gang waited for enough free CPUs", stats.GangIdle)
+	}
+	_, _ = fmt.Fprintln(w)
+}
+
+// outputGlobalContrast prints the figures per-core queues are weighed on for
+// the same run with one global queue.
+func outputGlobalContrast(w io.Writer, global Result) {
+	_, _ = fmt.Fprintf(w, "With one global queue: %.0f%% busy, %d migrations, %d CPU-ticks idle, average wait %.2f\n",
+		100*global.Cores.Utilization, global.Cores.Migrations, global.Cores.Idle, global.AverageWait)
+}
+
+// ganttLanes splits gantt by CPU, in CPU order, keeping each lane's slices
+// in order.
+func ganttLanes(gantt []TimeSlice) [][]TimeSlice {
+	index := map[int]int{}
+	var cpus []int
+	for _, s := range gantt {
+		if _, ok := index[s.CPU]; !ok {
+			index[s.CPU] = 0
+			cpus = append(cpus, s.CPU)
+		}
+	}
+	if len(cpus) < 2 {
+		return [][]TimeSlice{gantt}
+	}
+	sort.Ints(cpus)
+	for i, cpu := range cpus {
+		index[cpu] = i
+	}
+	lanes := make([][]TimeSlice, len(cpus))
+	for _, s := range gantt {
+		lanes[index[s.CPU]] = append(lanes[index[s.CPU]], s)
+	}
+	return lanes
+}
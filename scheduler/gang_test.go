@@ -0,0 +1,99 @@
+package scheduler
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSimulate_gang(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		processes []Process
+		cpus      int
+		wantGantt []TimeSlice
+		wantCores CoreStats
+	}{
+		{
+			name: "gang waits for enough free CPUs",
+			processes: []Process{
+				{ProcessID: 1, BurstDuration: 4},
+				{ProcessID: 2, BurstDuration: 2, Threads: 2},
+				{ProcessID: 3, ArrivalTime: 1, BurstDuration: 3},
+			},
+			cpus: 2,
+			// 2 cannot start on CPU 1 alone, so it idles until 3 backfills it.
+			wantGantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 4, CPU: 0},
+				{PID: 3, Start: 1, Stop: 4, CPU: 1},
+				{PID: 2, Start: 4, Stop: 6, CPU: 0},
+				{PID: 2, Start: 4, Stop: 6, CPU: 1},
+			},
+			wantCores: CoreStats{CPUs: 2, Idle: 1, GangIdle: 1, Utilization: 1 - 1.0/12},
+		},
+		{
+			name:      "gang wider than the machine",
+			processes: []Process{{ProcessID: 1, BurstDuration: 3, Threads: 8}},
+			cpus:      2,
+			wantGantt: []TimeSlice{{PID: 1, Start: 0, Stop: 3, CPU: 0}, {PID: 1, Start: 0, Stop: 3, CPU: 1}},
+			wantCores: CoreStats{CPUs: 2, Utilization: 1},
+		},
+		{
+			name:      "one CPU",
+			processes: []Process{{ProcessID: 1, BurstDuration: 3, Threads: 2}},
+			wantGantt: []TimeSlice{{PID: 1, Start: 0, Stop: 3}},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := Simulate(tt.processes, fcfsPolicy{}, EngineOptions{CPUs: tt.cpus})
+			if !reflect.DeepEqual(got.Gantt, tt.wantGantt) {
+				t.Errorf("Gantt = %v, want %v", got.Gantt, tt.wantGantt)
+			}
+			if got.Cores != tt.wantCores {
+				t.Errorf("Cores = %+v, want %+v", got.Cores, tt.wantCores)
+			}
+		})
+	}
+}
+
+func Test_outputGantt_lanes(t *testing.T) {
+	t.Parallel()
+	gantt := []TimeSlice{
+		{PID: 1, Start: 0, Stop: 4, CPU: 0},
+		{PID: 2, Start: 2, Stop: 4, CPU: 1},
+	}
+	var w bytes.Buffer
+	outputGantt(&w, gantt, GanttOptions{Timeline: true, Width: 10})
+	want := `Gantt schedule
+CPU 0
+|   1   |
+0       4
+CPU 1
+|...| 2 |
+0   2   4
+
+`
+	if w.String() != want {
+		t.Errorf("outputGantt() =\n%s\nwant\n%s", w.String(), want)
+	}
+}
+
+func Test_outputCores(t *testing.T) {
+	t.Parallel()
+	var w bytes.Buffer
+	outputCores(&w, CoreStats{CPUs: 2, Idle: 3, GangIdle: 1, Utilization: 0.75})
+	want := "CPUs: 2, 75% busy, 0 migrations, 3 CPU-ticks idle, 1 of them while a gang waited for enough free CPUs\n"
+	if w.String() != want {
+		t.Errorf("outputCores() = %q, want %q", w.String(), want)
+	}
+	w.Reset()
+	outputCores(&w, CoreStats{})
+	if strings.TrimSpace(w.String()) != "" {
+		t.Errorf("outputCores() for one CPU = %q, want nothing", w.String())
+	}
+}
@@ -0,0 +1,319 @@
+package scheduler
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// GanttOptions controls how outputGantt lays a schedule out on the terminal.
+type GanttOptions struct {
+	// Timeline draws the chart to scale, shaped by Width, Scale and
+	// TickEvery. Otherwise every slice gets a cell of the same width.
+	Timeline bool
+	// Width is the longest a chart line may be; zero uses defaultGanttWidth.
+	Width int
+	// Scale is how many ticks each column covers. Zero stretches the chart
+	// to fill Width, or gives each tick one column and wraps when it is too
+	// long for that.
+	Scale int64
+	// TickEvery labels the time axis at multiples of TickEvery, with a ruler
+	// marking each one. Zero labels the start and end of every slice.
+	TickEvery int64
+	// Color fills each bar with its PID's colour.
+	Color bool
+}
+
+const (
+	defaultGanttWidth = 80
+	minGanttWidth     = 10
+)
+
+// terminalWidth is the width the shell reports in $COLUMNS, or
+// defaultGanttWidth.
+func terminalWidth() int {
+	if n, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && n > 0 {
+		return n
+	}
+	return defaultGanttWidth
+}
+
+// outputGantt draws gantt with a cell for each slice, or with opts.Timeline
+// as a timeline whose bar lengths are proportional to the slices'
+// durations. Idle time between slices is dotted, and a bar too short for its
+// PID is filled with '#'.
+func outputGantt(w io.Writer, gantt []TimeSlice, opts GanttOptions) {
+	outputGanttSuspended(w, gantt, nil, opts)
+}
+
+// outputGanttSuspended is outputGantt with a lane under the chart for each
+// suspended process, drawn with ~ over the times it was suspended. Those
+// lanes need a time axis, so suspensions always draw a timeline.
+func outputGanttSuspended(w io.Writer, gantt, suspended []TimeSlice, opts GanttOptions) {
+	_, _ = fmt.Fprintln(w, "Gantt schedule")
+	if len(gantt) == 0 {
+		_, _ = fmt.Fprintln(w)
+		return
+	}
+	if !opts.Timeline && len(suspended) == 0 {
+		lanes := ganttLanes(gantt)
+		for _, lane := range lanes {
+			if len(lanes) > 1 {
+				_, _ = fmt.Fprintf(w, "CPU %d\n", lane[0].CPU)
+			}
+			outputGanttCells(w, lane, opts)
+		}
+		_, _ = fmt.Fprintln(w)
+		return
+	}
+	width := opts.width()
+	// Slices on several CPUs get one chart each, on a shared time axis.
+	origin, end := ganttSpan(gantt)
+	if len(suspended) > 0 {
+		first, last := ganttSpan(suspended)
+		origin, end = minInt64(origin, first), maxInt64(end, last)
+	}
+	col := ganttColumns(origin, end, width, opts)
+
+	lanes := ganttLanes(gantt)
+	for _, lane := range lanes {
+		if len(lanes) > 1 {
+			_, _ = fmt.Fprintf(w, "CPU %d\n", lane[0].CPU)
+		}
+		outputGanttLane(w, lane, origin, end, col, width, opts)
+	}
+	for _, lane := range suspensionLanes(suspended) {
+		_, _ = fmt.Fprintf(w, "Suspended %d\n", lane[0].PID)
+		drawGanttLane(w, lane, origin, end, col, width, opts, '~', ' ')
+	}
+	_, _ = fmt.Fprintln(w)
+}
+
+// ganttCellWidth is how wide outputGanttCells makes each cell.
+const ganttCellWidth = 8
+
+// outputGanttCells draws one CPU's slices as cells of the same width, each
+// slice's start under it and a tab after each start.
+func outputGanttCells(w io.Writer, gantt []TimeSlice, opts GanttOptions) {
+	bar, colors := []byte{'|'}, []int{0}
+	for _, s := range gantt {
+		pid := fmt.Sprint(s.PID)
+		padding := ""
+		if len(pid) < ganttCellWidth {
+			padding = strings.Repeat(" ", (ganttCellWidth-len(pid))/2)
+		}
+		color := 0
+		if opts.Color {
+			color = pidColor(s.PID)
+		}
+		cell := padding + pid + padding
+		bar = append(bar, cell...)
+		for range cell {
+			colors = append(colors, color)
+		}
+		bar, colors = append(bar, '|'), append(colors, 0)
+	}
+	_, _ = fmt.Fprintln(w, paint(bar, colors))
+	for _, s := range gantt {
+		_, _ = fmt.Fprint(w, s.Start, "\t")
+	}
+	_, _ = fmt.Fprintln(w, gantt[len(gantt)-1].Stop)
+}
+
+// suspensionLanes splits suspensions by PID, in order of each PID's first.
+func suspensionLanes(suspended []TimeSlice) [][]TimeSlice {
+	index := map[int64]int{}
+	var lanes [][]TimeSlice
+	for _, s := range suspended {
+		i, ok := index[s.PID]
+		if !ok {
+			i = len(lanes)
+			index[s.PID] = i
+			lanes = append(lanes, nil)
+		}
+		lanes[i] = append(lanes[i], s)
+	}
+	return lanes
+}
+
+// width is the longest a chart line may be.
+func (opts GanttOptions) width() int {
+	switch {
+	case opts.Width <= 0:
+		return defaultGanttWidth
+	case opts.Width < minGanttWidth:
+		return minGanttWidth
+	}
+	return opts.Width
+}
+
+// ganttSpan is when the first of slices, which must not be empty, starts
+// and the last stops.
+func ganttSpan(gantt []TimeSlice) (origin, end int64) {
+	origin, end = gantt[0].Start, gantt[0].Stop
+	for _, s := range gantt {
+		if s.Stop > end {
+			end = s.Stop
+		}
+	}
+	return origin, end
+}
+
+func minInt64(a, b int64) int64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt64(a, b int64) int64 {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// ganttColumns places the times from origin to end on a chart's columns:
+// opts.Scale ticks a column, or stretched to fill width when they would
+// take less.
+func ganttColumns(origin, end int64, width int, opts GanttOptions) func(int64) int {
+	span := end - origin
+	if span <= 0 {
+		span = 1
+	}
+	columnsPerTick, ticksPerColumn := int64(1), int64(1)
+	switch {
+	case opts.Scale > 0:
+		ticksPerColumn = opts.Scale
+	case span < int64(width):
+		columnsPerTick = int64(width-1) / span
+	}
+	return func(t int64) int { return int((t - origin) * columnsPerTick / ticksPerColumn) }
+}
+
+// outputGanttLane draws one CPU's slices from origin to end, placing each
+// time at column col(t).
+func outputGanttLane(w io.Writer, gantt []TimeSlice, origin, end int64, col func(int64) int, width int, opts GanttOptions) {
+	drawGanttLane(w, gantt, origin, end, col, width, opts, ' ', '.')
+}
+
+// drawGanttLane is outputGanttLane with fill behind the slices' labels and
+// idle between them.
+func drawGanttLane(w io.Writer, gantt []TimeSlice, origin, end int64, col func(int64) int, width int, opts GanttOptions, fill, idle byte) {
+	bar := []byte(strings.Repeat(" ", col(end)+1))
+	colors := make([]int, len(bar))
+	draw := func(from, to int64, label string, fill byte, color int) {
+		x0, x1 := col(from), col(to)
+		inner := x1 - x0 - 1
+		if label != "" && len(label) > inner {
+			label, fill = "", '#'
+		}
+		for x := x0 + 1; x < x1; x++ {
+			bar[x], colors[x] = fill, color
+		}
+		copy(bar[x0+1+(inner-len(label))/2:], label)
+		bar[x0], bar[x1] = '|', '|'
+	}
+	var labels []int64
+	if start := gantt[0].Start; start > origin {
+		draw(origin, start, "", idle, 0)
+		labels = append(labels, origin)
+	}
+	for i, s := range gantt {
+		if i > 0 && s.Start > gantt[i-1].Stop {
+			draw(gantt[i-1].Stop, s.Start, "", idle, 0)
+			labels = append(labels, gantt[i-1].Stop)
+		}
+		color, fill := 0, fill
+		if opts.Color {
+			color = pidColor(s.PID)
+		}
+		if s.Donated {
+			fill = '^'
+			if opts.Color {
+				color = donatedColor
+			}
+		}
+		draw(s.Start, s.Stop, fmt.Sprint(s.PID), fill, color)
+		labels = append(labels, s.Start)
+	}
+	if stop := gantt[len(gantt)-1].Stop; stop < end {
+		draw(stop, end, "", idle, 0)
+		labels = append(labels, stop)
+	}
+	labels = append(labels, end)
+	var ruler []byte
+	if opts.TickEvery > 0 {
+		labels = labels[:0]
+		ruler = []byte(strings.Repeat("-", len(bar)))
+		first := (origin + opts.TickEvery - 1) / opts.TickEvery * opts.TickEvery
+		for t := first; t <= end; t += opts.TickEvery {
+			labels = append(labels, t)
+			ruler[col(t)] = '+'
+		}
+	}
+
+	// Long charts wrap, each line with its own labels and starting at the
+	// column the one before it ended on.
+	for lo := 0; ; lo += width - 1 {
+		hi := lo + width
+		if hi > len(bar) {
+			hi = len(bar)
+		}
+		if lo > 0 {
+			_, _ = fmt.Fprintln(w)
+		}
+		_, _ = fmt.Fprintln(w, paint(bar[lo:hi], colors[lo:hi]))
+		if ruler != nil {
+			_, _ = fmt.Fprintf(w, "%s\n", ruler[lo:hi])
+		}
+		var axis []byte
+		for _, t := range labels {
+			x, label := col(t)-lo, fmt.Sprint(t)
+			if x < len(axis) || x < 0 || x >= hi-lo || x+len(label) > width {
+				continue
+			}
+			axis = append(axis, strings.Repeat(" ", x-len(axis))...)
+			axis = append(axis, label...)
+			axis = append(axis, ' ')
+		}
+		_, _ = fmt.Fprintf(w, "%s\n", strings.TrimRight(string(axis), " "))
+		if hi == len(bar) {
+			break
+		}
+	}
+}
+
+// ganttFlags holds the command-line flags that shape the Gantt chart.
+type ganttFlags struct {
+	width     *int
+	scale     *int64
+	tickEvery *int64
+	timeline  *bool
+	noColor   *bool
+}
+
+func addGanttFlags(fs *flag.FlagSet) ganttFlags {
+	return ganttFlags{
+		width:     fs.Int("width", terminalWidth(), "widest Gantt chart line before it wraps, from $COLUMNS if set"),
+		timeline:  fs.Bool("timeline", false, "draw the Gantt chart to scale rather than a cell for each slice; -gantt-scale and -gantt-ticks imply it"),
+		noColor:   fs.Bool("no-color", false, "never colour the output, even on a terminal"),
+		scale:     fs.Int64("gantt-scale", 0, "ticks per Gantt chart column (default fit the width)"),
+		tickEvery: fs.Int64("gantt-ticks", 0, "label the Gantt time axis every N ticks (default at each slice)"),
+	}
+}
+
+// options returns the chart options for output to w, in colour if w is a
+// terminal and -no-color was not given.
+func (f ganttFlags) options(w io.Writer) GanttOptions {
+	return GanttOptions{
+		Timeline:  *f.timeline || *f.scale > 0 || *f.tickEvery > 0,
+		Width:     *f.width,
+		Scale:     *f.scale,
+		TickEvery: *f.tickEvery,
+		Color:     !*f.noColor && colorTerminal(w),
+	}
+}
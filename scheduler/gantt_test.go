@@ -0,0 +1,95 @@
+package scheduler
+
+import (
+	"bytes"
+	"testing"
+)
+
+func Test_outputGantt(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name  string
+		gantt []TimeSlice
+		opts  GanttOptions
+		want  string
+	}{
+		{
+			name:  "cells",
+			gantt: []TimeSlice{{PID: 1, Start: 0, Stop: 2}, {PID: 12, Start: 4, Stop: 7}},
+			want:  "|   1   |   12   |\n0\t4\t7\n",
+		},
+		{
+			name: "cells on two CPUs",
+			gantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 4, CPU: 0},
+				{PID: 2, Start: 2, Stop: 4, CPU: 1},
+			},
+			want: "CPU 0\n|   1   |\n0\t4\nCPU 1\n|   2   |\n2\t4\n",
+		},
+		{
+			name:  "stretched to the width",
+			gantt: []TimeSlice{{PID: 1, Start: 0, Stop: 2}, {PID: 2, Start: 2, Stop: 5}},
+			opts:  GanttOptions{Timeline: true, Width: 16},
+			want:  "|  1  |   2    |\n0     2        5\n",
+		},
+		{
+			name:  "idle gap and narrow bar",
+			gantt: []TimeSlice{{PID: 1, Start: 1, Stop: 3}, {PID: 12, Start: 5, Stop: 7}},
+			opts:  GanttOptions{Timeline: true, Width: 10, Scale: 1},
+			want:  "|1|.|#|\n1 3 5 7\n",
+		},
+		{
+			name:  "wrapped with ticks",
+			gantt: []TimeSlice{{PID: 1, Start: 0, Stop: 12}, {PID: 2, Start: 12, Stop: 20}},
+			opts:  GanttOptions{Timeline: true, Width: 10, Scale: 1, TickEvery: 5},
+			want: "|     1   \n+----+----\n0    5\n\n" +
+				"   |   2  \n-+----+---\n 10   15\n\n" +
+				"  |\n--+\n  20\n",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var w bytes.Buffer
+			outputGantt(&w, tt.gantt, tt.opts)
+			if want := "Gantt schedule\n" + tt.want + "\n"; w.String() != want {
+				t.Errorf("outputGantt() =\n%s\nwant\n%s", w.String(), want)
+			}
+		})
+	}
+}
+
+func Test_outputGantt_color(t *testing.T) {
+	t.Parallel()
+	gantt := []TimeSlice{{PID: 1, Start: 0, Stop: 2}, {PID: 2, Start: 4, Stop: 6}}
+	tests := []struct {
+		name string
+		opts GanttOptions
+		want string
+	}{
+		{
+			name: "cells",
+			opts: GanttOptions{Color: true},
+			want: "|\x1b[30;42m   1   \x1b[0m|\x1b[30;43m   2   \x1b[0m|\n" +
+				"0\t4\t6\n",
+		},
+		{
+			name: "timeline",
+			opts: GanttOptions{Timeline: true, Width: 7, Scale: 1, Color: true},
+			want: "|\x1b[30;42m1\x1b[0m|.|\x1b[30;43m2\x1b[0m|\n" +
+				"0 2 4 6\n",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var w bytes.Buffer
+			outputGantt(&w, gantt, tt.opts)
+			if want := "Gantt schedule\n" + tt.want + "\n"; w.String() != want {
+				t.Errorf("outputGantt() = %q, want %q", w.String(), want)
+			}
+		})
+	}
+}
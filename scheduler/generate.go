@@ -0,0 +1,269 @@
+package scheduler
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"os"
+	"sort"
+)
+
+// ProcessClass distinguishes batch jobs from interactive ones.
+type ProcessClass int
+
+const (
+	ClassBatch ProcessClass = iota
+	ClassInteractive
+)
+
+var ErrInvalidClass = errors.New("invalid process class")
+
+func (c ProcessClass) String() string {
+	switch c {
+	case ClassInteractive:
+		return "interactive"
+	default:
+		return "batch"
+	}
+}
+
+// MarshalText writes the class by name, as in process files.
+func (c ProcessClass) MarshalText() ([]byte, error) { return []byte(c.String()), nil }
+
+// UnmarshalText reads a class name.
+func (c *ProcessClass) UnmarshalText(b []byte) (err error) {
+	*c, err = parseProcessClass(string(b))
+	return err
+}
+
+func parseProcessClass(s string) (ProcessClass, error) {
+	switch s {
+	case "", "batch":
+		return ClassBatch, nil
+	case "interactive":
+		return ClassInteractive, nil
+	default:
+		return ClassBatch, fmt.Errorf("%w: %q", ErrInvalidClass, s)
+	}
+}
+
+// BurstDistribution is how the generator draws CPU bursts.
+type BurstDistribution int
+
+const (
+	// BurstUniform draws bursts uniformly up to MaxBurst, or, with a
+	// ServiceMean, exponentially as BurstExponential does.
+	BurstUniform BurstDistribution = iota
+	BurstExponential
+	// BurstPareto and BurstZipf are heavy-tailed: most bursts are short,
+	// but a few are long enough to hold most of the work, as with real
+	// process lifetimes. Both are capped at MaxBurst.
+	BurstPareto
+	BurstZipf
+)
+
+func parseBurstDistribution(s string) (BurstDistribution, error) {
+	switch s {
+	case "", "uniform":
+		return BurstUniform, nil
+	case "exponential":
+		return BurstExponential, nil
+	case "pareto":
+		return BurstPareto, nil
+	case "zipf":
+		return BurstZipf, nil
+	}
+	return BurstUniform, fmt.Errorf("%w: -burst-dist must be uniform, exponential, pareto or zipf, not %q", ErrInvalidArgs, s)
+}
+
+// defaultTail is the default shape of the heavy-tailed distributions: at
+// 1.5, Pareto bursts have a mean but no variance.
+const defaultTail = 1.5
+
+// GenerateOptions controls the shape of a generated workload.
+type GenerateOptions struct {
+	Count       int
+	MaxBurst    int64
+	MaxArrival  int64
+	MaxPriority int64
+	// Interactive is the probability that a process models an interactive
+	// user rather than a batch job.
+	Interactive float64
+	// SplitProb is the chance, after each tick of CPU, that an interactive
+	// process stops to think (or wait on I/O) before its next burst.
+	SplitProb float64
+	// ThinkMean is the mean of the exponentially distributed think times.
+	ThinkMean float64
+	// ArrivalRate, if positive, draws arrivals from a Poisson process of
+	// that many a tick instead of uniformly up to MaxArrival, and
+	// ServiceMean bursts from an exponential distribution of that mean
+	// instead of uniformly up to MaxBurst.
+	ArrivalRate float64
+	ServiceMean float64
+	// Batch, above one, makes processes arrive in batches of that many at
+	// the same tick.
+	Batch int
+	// Bursts is the distribution of CPU bursts, and Tail the shape of the
+	// heavy-tailed ones: Pareto's alpha, or Zipf's exponent, which must be
+	// above 1. The smaller it is, the heavier the tail.
+	Bursts BurstDistribution
+	Tail   float64
+	// OnPeriod and OffPeriod, if both positive, make arrivals bursty:
+	// processes arrive only during OnPeriod ticks, then none for OffPeriod,
+	// over and over, as the arrivals would otherwise be drawn.
+	OnPeriod  int64
+	OffPeriod int64
+}
+
+// GenerateProcesses builds a random workload sorted by arrival time.
+func GenerateProcesses(opts GenerateOptions, rng *rand.Rand) []Process {
+	processes := make([]Process, opts.Count)
+	var arrivals []int64
+	if opts.ArrivalRate > 0 {
+		arrivals = poissonArrivals(opts.Count, opts.ArrivalRate, rng)
+	}
+	draw := burstDrawer(opts, rng)
+	for i := range processes {
+		processes[i] = Process{
+			ProcessID:     int64(i + 1),
+			ArrivalTime:   rng.Int63n(opts.MaxArrival + 1),
+			BurstDuration: 1 + rng.Int63n(opts.MaxBurst),
+			Priority:      1 + rng.Int63n(opts.MaxPriority),
+		}
+		if arrivals != nil {
+			processes[i].ArrivalTime = arrivals[i]
+		}
+		if draw != nil {
+			processes[i].BurstDuration = draw()
+		}
+		if opts.OnPeriod > 0 && opts.OffPeriod > 0 {
+			processes[i].ArrivalTime = onOffTime(processes[i].ArrivalTime, opts.OnPeriod, opts.OffPeriod)
+		}
+		if rng.Float64() < opts.Interactive {
+			processes[i].Class = ClassInteractive
+			processes[i].Bursts = splitBurst(processes[i].BurstDuration, opts, rng)
+		}
+	}
+	sort.SliceStable(processes, func(i, j int) bool {
+		return processes[i].ArrivalTime < processes[j].ArrivalTime
+	})
+	if opts.Batch > 1 {
+		for i := range processes {
+			processes[i].ArrivalTime = processes[i-i%opts.Batch].ArrivalTime
+		}
+	}
+
+	return processes
+}
+
+// burstDrawer returns what draws bursts from opts' distribution, or nil for
+// the uniform bursts drawn with the rest of each process.
+func burstDrawer(opts GenerateOptions, rng *rand.Rand) func() int64 {
+	tail := opts.Tail
+	if tail <= 0 {
+		tail = defaultTail
+	}
+	switch {
+	case opts.Bursts == BurstPareto:
+		return func() int64 { return paretoBurst(tail, opts.MaxBurst, rng) }
+	case opts.Bursts == BurstZipf:
+		if opts.MaxBurst == 1 {
+			return func() int64 { return 1 }
+		}
+		if tail <= 1 {
+			tail = defaultTail
+		}
+		zipf := rand.NewZipf(rng, tail, 1, uint64(opts.MaxBurst-1))
+		return func() int64 { return 1 + int64(zipf.Uint64()) }
+	case opts.Bursts == BurstExponential || opts.ServiceMean > 0:
+		return func() int64 { return geometricBurst(opts.ServiceMean, rng) }
+	}
+	return nil
+}
+
+// paretoBurst draws a burst of at least a tick from the Pareto distribution
+// of shape alpha, rounded up to whole ticks and capped at limit.
+func paretoBurst(alpha float64, limit int64, rng *rand.Rand) int64 {
+	// 1-Float64 is in (0, 1], keeping the inverse away from infinity.
+	x := math.Pow(1-rng.Float64(), -1/alpha)
+	if x >= float64(limit) {
+		return limit
+	}
+	return int64(math.Ceil(x))
+}
+
+// onOffTime spreads arrival time t, counted over on periods only, across
+// alternating on and off periods: the first on ticks are as they were, the
+// next on come after off ticks of silence, and so on.
+func onOffTime(t, on, off int64) int64 {
+	return t + t/on*off
+}
+
+// splitBurst cuts a burst into CPU runs separated by think times. Returns nil
+// when the burst was never split.
+func splitBurst(burst int64, opts GenerateOptions, rng *rand.Rand) []int64 {
+	var (
+		bursts []int64
+		run    int64
+	)
+	for tick := int64(1); tick <= burst; tick++ {
+		run++
+		if tick < burst && rng.Float64() < opts.SplitProb {
+			think := int64(math.Ceil(rng.ExpFloat64() * opts.ThinkMean))
+			if think < 1 {
+				think = 1
+			}
+			bursts = append(bursts, run, think)
+			run = 0
+		}
+	}
+	if bursts == nil {
+		return nil
+	}
+
+	return append(bursts, run)
+}
+
+func runGenerate(w io.Writer, args []string) error {
+	var opts GenerateOptions
+	fs := flag.NewFlagSet("generate", flag.ContinueOnError)
+	fs.IntVar(&opts.Count, "n", 10, "number of processes")
+	fs.Int64Var(&opts.MaxBurst, "max-burst", 10, "maximum CPU burst")
+	fs.Int64Var(&opts.MaxArrival, "max-arrival", 20, "latest arrival time")
+	fs.Int64Var(&opts.MaxPriority, "max-priority", 5, "largest priority value")
+	fs.Float64Var(&opts.Interactive, "interactive", 0, "fraction of processes that are interactive")
+	fs.Float64Var(&opts.SplitProb, "split-prob", 0.3, "per-tick chance an interactive burst is split by think time")
+	fs.Float64Var(&opts.ThinkMean, "think-mean", 4, "mean think/I-O time between interactive bursts")
+	fs.Float64Var(&opts.ArrivalRate, "arrival-rate", 0, "draw Poisson arrivals at this rate a tick instead of up to -max-arrival")
+	fs.Float64Var(&opts.ServiceMean, "service-mean", 0, "draw exponential bursts of this mean instead of up to -max-burst")
+	fs.IntVar(&opts.Batch, "batch", 1, "processes arriving together in each batch")
+	burstDist := fs.String("burst-dist", "", "CPU bursts: uniform up to -max-burst, exponential of -service-mean, or heavy-tailed pareto or zipf capped at -max-burst (default uniform, or exponential with -service-mean)")
+	fs.Float64Var(&opts.Tail, "tail", defaultTail, "shape of pareto (alpha) and zipf (exponent, above 1) bursts; smaller is heavier")
+	fs.Int64Var(&opts.OnPeriod, "on-period", 0, "with -off-period, ticks processes arrive in before each quiet spell")
+	fs.Int64Var(&opts.OffPeriod, "off-period", 0, "with -on-period, ticks of no arrivals after each on period")
+	seed := addSeedFlag(fs, "random seed")
+	if err := parseFlags(fs, "generate", args); err != nil {
+		return err
+	}
+	if opts.Count < 0 || opts.MaxBurst < 1 || opts.MaxArrival < 0 || opts.MaxPriority < 1 || opts.Batch < 1 ||
+		opts.ArrivalRate < 0 || opts.ServiceMean < 0 {
+		return fmt.Errorf("%w: counts and maximums must be positive", ErrInvalidArgs)
+	}
+	var err error
+	if opts.Bursts, err = parseBurstDistribution(*burstDist); err != nil {
+		return err
+	}
+	if opts.Bursts == BurstExponential && opts.ServiceMean == 0 {
+		return fmt.Errorf("%w: -burst-dist exponential needs -service-mean", ErrInvalidArgs)
+	}
+	if (opts.Bursts == BurstPareto && opts.Tail <= 0) || (opts.Bursts == BurstZipf && opts.Tail <= 1) {
+		return fmt.Errorf("%w: -tail must be positive for pareto and above 1 for zipf, not %g", ErrInvalidArgs, opts.Tail)
+	}
+	if opts.OnPeriod < 0 || opts.OffPeriod < 0 || (opts.OnPeriod > 0) != (opts.OffPeriod > 0) {
+		return fmt.Errorf("%w: -on-period and -off-period must be positive and given together", ErrInvalidArgs)
+	}
+	return writeProcesses(w, GenerateProcesses(opts, newRand(pickSeed(os.Stderr, *seed))))
+}
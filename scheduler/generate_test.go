@@ -0,0 +1,187 @@
+package scheduler
+
+import (
+	"bytes"
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestGenerateProcesses(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name            string
+		opts            GenerateOptions
+		wantInteractive int
+	}{
+		{
+			name: "batch only",
+			opts: GenerateOptions{
+				Count:       50,
+				MaxBurst:    10,
+				MaxArrival:  20,
+				MaxPriority: 5,
+			},
+			wantInteractive: 0,
+		},
+		{
+			name: "interactive only",
+			opts: GenerateOptions{
+				Count:       50,
+				MaxBurst:    10,
+				MaxArrival:  20,
+				MaxPriority: 5,
+				Interactive: 1,
+				SplitProb:   0.5,
+				ThinkMean:   3,
+			},
+			wantInteractive: 50,
+		},
+		{
+			name: "batches",
+			opts: GenerateOptions{
+				Count:       50,
+				MaxBurst:    10,
+				MaxArrival:  20,
+				MaxPriority: 5,
+				Batch:       5,
+			},
+			wantInteractive: 0,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := GenerateProcesses(tt.opts, rand.New(rand.NewSource(1)))
+			if len(got) != tt.opts.Count {
+				t.Fatalf("GenerateProcesses() returned %d processes, want %d", len(got), tt.opts.Count)
+			}
+			interactive := 0
+			for i, p := range got {
+				if i > 0 && p.ArrivalTime < got[i-1].ArrivalTime {
+					t.Errorf("process %d arrives before its predecessor", p.ProcessID)
+				}
+				if tt.opts.Batch > 1 && i%tt.opts.Batch != 0 && p.ArrivalTime != got[i-1].ArrivalTime {
+					t.Errorf("process %d arrives apart from its batch", p.ProcessID)
+				}
+				if p.Class == ClassInteractive {
+					interactive++
+				}
+				if p.Bursts == nil {
+					continue
+				}
+				if len(p.Bursts)%2 != 1 {
+					t.Errorf("process %d has %d bursts, want CPU at both ends", p.ProcessID, len(p.Bursts))
+				}
+				var cpu int64
+				for j := 0; j < len(p.Bursts); j += 2 {
+					cpu += p.Bursts[j]
+				}
+				if cpu != p.BurstDuration {
+					t.Errorf("process %d CPU bursts sum to %d, want %d", p.ProcessID, cpu, p.BurstDuration)
+				}
+			}
+			if interactive != tt.wantInteractive {
+				t.Errorf("got %d interactive processes, want %d", interactive, tt.wantInteractive)
+			}
+			if again := GenerateProcesses(tt.opts, rand.New(rand.NewSource(1))); !reflect.DeepEqual(got, again) {
+				t.Error("same seed produced a different workload")
+			}
+		})
+	}
+}
+
+func TestGenerateProcesses_heavyTail(t *testing.T) {
+	t.Parallel()
+	for name, dist := range map[string]BurstDistribution{"pareto": BurstPareto, "zipf": BurstZipf} {
+		dist := dist
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			opts := GenerateOptions{Count: 2000, MaxBurst: 1000, MaxArrival: 100, MaxPriority: 5, Bursts: dist, Tail: 1.5}
+			got := GenerateProcesses(opts, rand.New(rand.NewSource(1)))
+			bursts := make([]int64, len(got))
+			var total int64
+			for i, p := range got {
+				if p.BurstDuration < 1 || p.BurstDuration > opts.MaxBurst {
+					t.Fatalf("process %d burst %d outside 1..%d", p.ProcessID, p.BurstDuration, opts.MaxBurst)
+				}
+				bursts[i] = p.BurstDuration
+				total += p.BurstDuration
+			}
+			// Heavy-tailed: the median burst is short, and the longest
+			// tenth of the bursts hold well over the fifth of the work
+			// they would if uniform.
+			sort.Slice(bursts, func(i, j int) bool { return bursts[i] > bursts[j] })
+			if median := bursts[len(bursts)/2]; median > 3 {
+				t.Errorf("median burst = %d, want at most 3", median)
+			}
+			var top int64
+			for _, b := range bursts[:len(bursts)/10] {
+				top += b
+			}
+			if top*4 < total {
+				t.Errorf("longest tenth of bursts = %d of %d ticks, want at least a quarter", top, total)
+			}
+		})
+	}
+}
+
+func TestGenerateProcesses_onOff(t *testing.T) {
+	t.Parallel()
+	opts := GenerateOptions{Count: 200, MaxBurst: 5, MaxArrival: 100, MaxPriority: 5, OnPeriod: 10, OffPeriod: 30}
+	for _, p := range GenerateProcesses(opts, rand.New(rand.NewSource(1))) {
+		if p.ArrivalTime%40 >= 10 {
+			t.Errorf("process %d arrives at %d, in an off period", p.ProcessID, p.ArrivalTime)
+		}
+	}
+}
+
+func Test_onOffTime(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct{ t, want int64 }{{0, 0}, {9, 9}, {10, 40}, {25, 85}} {
+		if got := onOffTime(tt.t, 10, 30); got != tt.want {
+			t.Errorf("onOffTime(%d, 10, 30) = %d, want %d", tt.t, got, tt.want)
+		}
+	}
+}
+
+func Test_writeProcesses(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{
+			ProcessID:     1,
+			ArrivalTime:   0,
+			BurstDuration: 5,
+			Priority:      2,
+		},
+		{
+			ProcessID:     2,
+			ArrivalTime:   3,
+			BurstDuration: 4,
+			Priority:      1,
+			Class:         ClassInteractive,
+			Bursts:        []int64{1, 6, 3},
+			Locks:         []LockUse{{Resource: "disk", Acquire: 0, Release: 2}, {Resource: "net", Acquire: 1, Release: 4}},
+			DependsOn:     []int64{1},
+			Nice:          -5,
+			Group:         "alice",
+			Threads:       2,
+			Affinity:      []int{0, 2},
+			Deadline:      12,
+			Period:        20,
+		},
+	}
+	var w bytes.Buffer
+	if err := writeProcesses(&w, processes); err != nil {
+		t.Fatal(err)
+	}
+	got, err := loadProcesses(&w)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, processes) {
+		t.Errorf("round trip = %v, want %v", got, processes)
+	}
+}
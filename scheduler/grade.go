@@ -0,0 +1,287 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+type (
+	// Rubric describes the hidden workloads a submission is graded against.
+	Rubric struct {
+		Workloads []RubricWorkload `json:"workloads"`
+	}
+	// RubricWorkload is one graded workload. Metric targets default to the
+	// reference scheduler named by Algorithm; Targets overrides any of them.
+	RubricWorkload struct {
+		Name      string             `json:"name"`
+		File      string             `json:"file"`
+		Algorithm string             `json:"algorithm"`
+		Points    float64            `json:"points"`
+		Tolerance float64            `json:"tolerance"`
+		Targets   map[string]float64 `json:"targets,omitempty"`
+	}
+	// GradeCheck is the outcome of a single rubric check.
+	GradeCheck struct {
+		Workload string
+		Check    string
+		Passed   bool
+		Detail   string
+		Earned   float64
+		Possible float64
+	}
+	GradeReport struct {
+		Checks   []GradeCheck
+		Score    float64
+		Possible float64
+	}
+)
+
+var ErrInvalidRubric = errors.New("invalid rubric")
+
+// defaultTolerance is how far a submitted metric may stray from its target.
+const defaultTolerance = 0.01
+
+// invariants must hold for any valid single-CPU schedule of a workload.
+var invariants = []struct {
+	name  string
+	check func(processes []Process, result Result) error
+}{
+	{"slices ordered", checkSlicesOrdered},
+	{"respects arrival", checkRespectsArrival},
+	{"complete bursts", checkCompleteBursts},
+}
+
+// metricNames are the targets a rubric may set, in report order.
+var metricNames = []string{"average_wait", "average_turnaround", "throughput"}
+
+func resultMetric(result Result, name string) float64 {
+	switch name {
+	case "average_wait":
+		return result.AverageWait
+	case "average_turnaround":
+		return result.AverageTurnaround
+	default:
+		return result.Throughput
+	}
+}
+
+func checkSlicesOrdered(_ []Process, result Result) error {
+	for i, s := range result.Gantt {
+		if s.Start >= s.Stop {
+			return fmt.Errorf("slice %d for PID %d is empty or reversed", i, s.PID)
+		}
+		if i > 0 && s.Start < result.Gantt[i-1].Stop {
+			return fmt.Errorf("slice %d for PID %d overlaps the previous slice", i, s.PID)
+		}
+	}
+	return nil
+}
+
+func checkRespectsArrival(processes []Process, result Result) error {
+	arrivals := make(map[int64]int64, len(processes))
+	for i := range processes {
+		arrivals[processes[i].ProcessID] = processes[i].ArrivalTime
+	}
+	for _, s := range result.Gantt {
+		arrival, ok := arrivals[s.PID]
+		if !ok {
+			return fmt.Errorf("PID %d is not in the workload", s.PID)
+		}
+		if s.Start < arrival {
+			return fmt.Errorf("PID %d runs at %d before arriving at %d", s.PID, s.Start, arrival)
+		}
+	}
+	return nil
+}
+
+func checkCompleteBursts(processes []Process, result Result) error {
+	ran := make(map[int64]int64, len(processes))
+	for _, s := range result.Gantt {
+		ran[s.PID] += s.Stop - s.Start
+	}
+	for i := range processes {
+		if got := ran[processes[i].ProcessID]; got != processes[i].BurstDuration {
+			return fmt.Errorf("PID %d ran for %d, want %d", processes[i].ProcessID, got, processes[i].BurstDuration)
+		}
+	}
+	return nil
+}
+
+// checkRubricWorkload rejects a workload whose algorithm is not registered,
+// that names neither an algorithm nor targets, or that sets a target other
+// than metricNames, which would be ignored.
+func checkRubricWorkload(wl RubricWorkload) error {
+	if wl.Algorithm != "" {
+		if _, ok := LookupAlgorithm(wl.Algorithm); !ok {
+			return fmt.Errorf("%w: workload %q names unknown algorithm %q", ErrInvalidRubric, wl.Name, wl.Algorithm)
+		}
+	} else if len(wl.Targets) == 0 {
+		return fmt.Errorf("%w: workload %q needs an algorithm or targets", ErrInvalidRubric, wl.Name)
+	}
+	names := make([]string, 0, len(wl.Targets))
+	for name := range wl.Targets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		known := false
+		for _, m := range metricNames {
+			known = known || m == name
+		}
+		if !known {
+			return fmt.Errorf("%w: workload %q has unknown target %q; targets are %s", ErrInvalidRubric, wl.Name, name, strings.Join(metricNames, ", "))
+		}
+	}
+	return nil
+}
+
+// Grade scores a submission, keyed by workload name, against the rubric. Each
+// workload's points are split evenly across its invariant and metric checks.
+func Grade(rubric Rubric, workloads map[string][]Process, submission map[string]Result) (GradeReport, error) {
+	var report GradeReport
+	for _, wl := range rubric.Workloads {
+		if err := checkRubricWorkload(wl); err != nil {
+			return GradeReport{}, err
+		}
+		targets := make(map[string]float64, len(metricNames))
+		if algorithm, ok := LookupAlgorithm(wl.Algorithm); ok {
+			reference := Simulate(workloads[wl.Name], algorithm.New(workloads[wl.Name], AlgorithmOptions{}), EngineOptions{})
+			for _, name := range metricNames {
+				targets[name] = resultMetric(reference, name)
+			}
+		}
+		for name, v := range wl.Targets {
+			targets[name] = v
+		}
+		tolerance := wl.Tolerance
+		if tolerance == 0 {
+			tolerance = defaultTolerance
+		}
+
+		var checks []GradeCheck
+		result, submitted := submission[wl.Name]
+		for _, inv := range invariants {
+			check := GradeCheck{Workload: wl.Name, Check: inv.name, Passed: submitted}
+			if !submitted {
+				check.Detail = "no result submitted"
+			} else if err := inv.check(workloads[wl.Name], result); err != nil {
+				check.Passed, check.Detail = false, err.Error()
+			}
+			checks = append(checks, check)
+		}
+		for _, name := range metricNames {
+			want, ok := targets[name]
+			if !ok {
+				continue
+			}
+			check := GradeCheck{Workload: wl.Name, Check: name}
+			if !submitted {
+				check.Detail = "no result submitted"
+			} else if got := resultMetric(result, name); math.Abs(got-want) <= tolerance {
+				check.Passed = true
+			} else {
+				check.Detail = fmt.Sprintf("got %.2f, want %.2f", got, want)
+			}
+			checks = append(checks, check)
+		}
+
+		share := wl.Points / float64(len(checks))
+		for i := range checks {
+			checks[i].Possible = share
+			if checks[i].Passed {
+				checks[i].Earned = share
+			}
+			report.Score += checks[i].Earned
+		}
+		report.Possible += wl.Points
+		report.Checks = append(report.Checks, checks...)
+	}
+
+	return report, nil
+}
+
+func outputGradeReport(w io.Writer, report GradeReport) {
+	_, _ = fmt.Fprintln(w, "Grade report")
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Workload", "Check", "Result", "Detail", "Points"})
+	for _, c := range report.Checks {
+		status := "pass"
+		if !c.Passed {
+			status = "fail"
+		}
+		table.Append([]string{c.Workload, c.Check, status, c.Detail, fmt.Sprintf("%.2f/%.2f", c.Earned, c.Possible)})
+	}
+	table.SetFooter([]string{"", "", "", "Score", fmt.Sprintf("%.2f/%.2f", report.Score, report.Possible)})
+	table.Render()
+}
+
+func runGrade(w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("grade", flag.ContinueOnError)
+	rubricPath := fs.String("rubric", "", "rubric JSON describing the hidden workloads")
+	if err := parseFlags(fs, "grade", args); err != nil {
+		return err
+	}
+	if *rubricPath == "" || fs.NArg() != 1 {
+		return fmt.Errorf("%w: usage: grade -rubric rubric.json submission.json", ErrInvalidArgs)
+	}
+
+	var rubric Rubric
+	if err := readJSONFile(*rubricPath, &rubric); err != nil {
+		return err
+	}
+	var submission map[string]Result
+	if err := readJSONFile(fs.Arg(0), &submission); err != nil {
+		return err
+	}
+
+	workloads := make(map[string][]Process, len(rubric.Workloads))
+	for _, wl := range rubric.Workloads {
+		// Workload paths are relative to the rubric so the suite can move.
+		p := wl.File
+		if !filepath.IsAbs(p) {
+			p = filepath.Join(filepath.Dir(*rubricPath), p)
+		}
+		f, err := os.Open(p)
+		if err != nil {
+			return fmt.Errorf("%v: error opening workload %q", err, wl.Name)
+		}
+		processes, err := loadProcesses(f)
+		_ = f.Close()
+		if err != nil {
+			return err
+		}
+		if processes, err = checkProcesses(processes, false, nil); err != nil {
+			return fmt.Errorf("%w: workload %q", err, wl.Name)
+		}
+		workloads[wl.Name] = processes
+	}
+
+	report, err := Grade(rubric, workloads, submission)
+	if err != nil {
+		return err
+	}
+	outputGradeReport(w, report)
+
+	return nil
+}
+
+func readJSONFile(p string, v interface{}) error {
+	b, err := os.ReadFile(p)
+	if err != nil {
+		return fmt.Errorf("%v: error reading %s", err, p)
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		return fmt.Errorf("%w: parsing %s", err, p)
+	}
+	return nil
+}
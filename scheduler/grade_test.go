@@ -0,0 +1,117 @@
+package scheduler
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestGrade(t *testing.T) {
+	t.Parallel()
+	workload := []Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 5, Priority: 2},
+		{ProcessID: 2, ArrivalTime: 3, BurstDuration: 9, Priority: 1},
+		{ProcessID: 3, ArrivalTime: 6, BurstDuration: 6, Priority: 3},
+	}
+	early := FCFS(workload)
+	early.Gantt = []TimeSlice{{PID: 2, Start: 0, Stop: 9}, {PID: 1, Start: 9, Stop: 14}, {PID: 3, Start: 14, Stop: 20}}
+
+	type args struct {
+		rubric     Rubric
+		submission map[string]Result
+	}
+	tests := []struct {
+		name      string
+		args      args
+		wantScore float64
+		wantErr   error
+		// wantNamed is text the error must hold, naming what is wrong.
+		wantNamed string
+	}{
+		{
+			name: "matches reference",
+			args: args{
+				rubric:     Rubric{Workloads: []RubricWorkload{{Name: "w", Algorithm: "fcfs", Points: 12}}},
+				submission: map[string]Result{"w": FCFS(workload)},
+			},
+			wantScore: 12,
+		},
+		{
+			name: "runs before arrival",
+			args: args{
+				rubric:     Rubric{Workloads: []RubricWorkload{{Name: "w", Algorithm: "fcfs", Points: 12}}},
+				submission: map[string]Result{"w": early},
+			},
+			wantScore: 10,
+		},
+		{
+			name: "explicit target missed",
+			args: args{
+				rubric: Rubric{Workloads: []RubricWorkload{{
+					Name:    "w",
+					Points:  4,
+					Targets: map[string]float64{"average_wait": 1},
+				}}},
+				submission: map[string]Result{"w": FCFS(workload)},
+			},
+			wantScore: 3,
+		},
+		{
+			name: "missing submission",
+			args: args{
+				rubric: Rubric{Workloads: []RubricWorkload{{Name: "w", Algorithm: "rr", Points: 5}}},
+			},
+			wantScore: 0,
+		},
+		{
+			name: "no algorithm or targets",
+			args: args{
+				rubric: Rubric{Workloads: []RubricWorkload{{Name: "w", Points: 5}}},
+			},
+			wantErr: ErrInvalidRubric,
+		},
+		{
+			name: "misspelled target",
+			args: args{
+				rubric: Rubric{Workloads: []RubricWorkload{{
+					Name:    "w",
+					Points:  10,
+					Targets: map[string]float64{"average_wait": 1, "avg_wait": 1},
+				}}},
+				submission: map[string]Result{"w": FCFS(workload)},
+			},
+			wantErr:   ErrInvalidRubric,
+			wantNamed: `"avg_wait"`,
+		},
+		{
+			name: "unknown algorithm with targets",
+			args: args{
+				rubric: Rubric{Workloads: []RubricWorkload{{
+					Name:      "w",
+					Algorithm: "fifo",
+					Points:    10,
+					Targets:   map[string]float64{"average_wait": 1},
+				}}},
+				submission: map[string]Result{"w": FCFS(workload)},
+			},
+			wantErr:   ErrInvalidRubric,
+			wantNamed: `"fifo"`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := Grade(tt.args.rubric, map[string][]Process{"w": workload}, tt.args.submission)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil && !strings.Contains(err.Error(), tt.wantNamed) {
+				t.Errorf("error = %v, want it to name %s", err, tt.wantNamed)
+			}
+			if got.Score != tt.wantScore {
+				t.Errorf("Grade() score = %v, want %v (%+v)", got.Score, tt.wantScore, got.Checks)
+			}
+		})
+	}
+}
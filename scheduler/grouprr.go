@@ -0,0 +1,139 @@
+package scheduler
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+func init() {
+	Register("grouprr", Factory{
+		Title:       "Group round-robin",
+		Description: "round-robins the CPU between groups, serving each group's processes first come, first served",
+		New:         func(p []Process, o AlgorithmOptions) Policy { return newGroupRRPolicy(p, o.RR) },
+	})
+}
+
+// groupRRPolicy shares the CPU between groups the way cgroups share it
+// between containers: the groups take turns of a quantum each, and a
+// group's turn goes to the process of the group that became ready first,
+// which keeps its place even when its turn runs out. Processes that name
+// no group form a group of their own.
+type groupRRPolicy struct {
+	quantum    int64
+	switchCost int64
+	group      map[int64]string
+	// turn is when each group last had the CPU, and ready when each
+	// process last became ready, both counted in events.
+	events int64
+	turn   map[string]int64
+	ready  map[int64]int64
+	stats  map[string]*groupStats
+	order  []string
+}
+
+// groupStats are a group's totals over a run.
+type groupStats struct {
+	Processes  int
+	Completed  int
+	CPU        int64
+	Turns      int
+	Turnaround int64
+	arrived    map[int64]int64
+	started    map[int64]int64
+}
+
+func newGroupRRPolicy(processes []Process, opts RROptions) *groupRRPolicy {
+	p := &groupRRPolicy{
+		quantum:    rrQuantum(processes, opts),
+		switchCost: opts.SwitchCost,
+		group:      map[int64]string{},
+		turn:       map[string]int64{},
+		ready:      map[int64]int64{},
+		stats:      map[string]*groupStats{},
+	}
+	for i := range processes {
+		p.group[processes[i].ProcessID] = processes[i].Group
+	}
+	return p
+}
+
+func (p *groupRRPolicy) stat(pid int64) *groupStats {
+	g := p.group[pid]
+	s, ok := p.stats[g]
+	if !ok {
+		s = &groupStats{arrived: map[int64]int64{}, started: map[int64]int64{}}
+		p.stats[g] = s
+		p.order = append(p.order, g)
+	}
+	return s
+}
+
+func (p *groupRRPolicy) Observe(ev Event) {
+	p.events++
+	switch ev.Kind {
+	case EventArrive:
+		s := p.stat(ev.PID)
+		s.Processes++
+		s.arrived[ev.PID] = ev.Time
+		p.ready[ev.PID] = p.events
+	case EventWake, EventResume:
+		p.ready[ev.PID] = p.events
+	case EventDispatch:
+		s := p.stat(ev.PID)
+		s.Turns++
+		s.started[ev.PID] = ev.Time
+		p.turn[p.group[ev.PID]] = p.events
+	case EventPreempt, EventExpire, EventBlock, EventLockWait, EventSemWait, EventSuspend, EventComplete:
+		s := p.stat(ev.PID)
+		if start, ok := s.started[ev.PID]; ok {
+			s.CPU += ev.Time - start
+			delete(s.started, ev.PID)
+		}
+		if ev.Kind == EventComplete {
+			s.Completed++
+			s.Turnaround += ev.Time - s.arrived[ev.PID]
+		}
+	}
+}
+
+// Less gives the CPU to the group that has waited longest for its turn,
+// then within a group to the process ready first.
+func (p *groupRRPolicy) Less(a, b *Task) bool {
+	if ga, gb := a.Group, b.Group; ga != gb {
+		if ta, tb := p.turn[ga], p.turn[gb]; ta != tb {
+			return ta < tb
+		}
+	}
+	if ra, rb := p.ready[a.ProcessID], p.ready[b.ProcessID]; ra != rb {
+		return ra < rb
+	}
+	return a.Seq < b.Seq
+}
+func (p *groupRRPolicy) Preemptive() bool  { return false }
+func (p *groupRRPolicy) Quantum() int64    { return p.quantum }
+func (p *groupRRPolicy) SwitchCost() int64 { return p.switchCost }
+
+// Report lists each group's processes, turns on the CPU, CPU time and
+// average turnaround.
+func (p *groupRRPolicy) Report(w io.Writer) {
+	if len(p.order) == 0 {
+		return
+	}
+	groups := append([]string(nil), p.order...)
+	sort.Strings(groups)
+	outputTitle(w, "Groups")
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Group", "Processes", "Completed", "Turns", "CPU", "Average turnaround"})
+	for _, g := range groups {
+		s := p.stats[g]
+		turnaround := "-"
+		if s.Completed > 0 {
+			turnaround = fmt.Sprintf("%.2f", float64(s.Turnaround)/float64(s.Completed))
+		}
+		table.Append([]string{groupName(g), fmt.Sprint(s.Processes), fmt.Sprint(s.Completed), fmt.Sprint(s.Turns), fmt.Sprint(s.CPU), turnaround})
+	}
+	table.Render()
+}
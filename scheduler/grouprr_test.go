@@ -0,0 +1,56 @@
+package scheduler
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGroupRR(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 4, Group: "a"},
+		{ProcessID: 2, BurstDuration: 2, Group: "a"},
+		{ProcessID: 3, ArrivalTime: 1, BurstDuration: 3, Group: "b"},
+	}
+	policy := newGroupRRPolicy(processes, RROptions{Quantum: 2})
+	got := Simulate(processes, policy, EngineOptions{})
+	// The groups alternate, and a keeps giving its turns to 1, ready first,
+	// until it completes.
+	want := []TimeSlice{
+		{PID: 1, Start: 0, Stop: 2}, {PID: 3, Start: 2, Stop: 4}, {PID: 1, Start: 4, Stop: 6},
+		{PID: 3, Start: 6, Stop: 7}, {PID: 2, Start: 7, Stop: 9},
+	}
+	if !reflect.DeepEqual(got.Gantt, want) {
+		t.Errorf("Gantt = %v, want %v", got.Gantt, want)
+	}
+
+	var w bytes.Buffer
+	policy.Report(&w)
+	for _, row := range []string{
+		"| a     |         2 |         2 |     3 |   6 |               7.50 |",
+		"| b     |         1 |         1 |     2 |   3 |               6.00 |",
+	} {
+		if !strings.Contains(w.String(), row) {
+			t.Errorf("Report() is missing %q in\n%s", row, w.String())
+		}
+	}
+}
+
+func TestGroupRR_ungrouped(t *testing.T) {
+	t.Parallel()
+	// Without groups every process is in one group, served first come,
+	// first served.
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 3},
+		{ProcessID: 2, ArrivalTime: 1, BurstDuration: 2},
+	}
+	got := Simulate(processes, newGroupRRPolicy(processes, RROptions{Quantum: 1}), EngineOptions{})
+	want := []TimeSlice{
+		{PID: 1, Start: 0, Stop: 1}, {PID: 1, Start: 1, Stop: 2}, {PID: 1, Start: 2, Stop: 3}, {PID: 2, Start: 3, Stop: 4}, {PID: 2, Start: 4, Stop: 5},
+	}
+	if !reflect.DeepEqual(got.Gantt, want) {
+		t.Errorf("Gantt = %v, want %v", got.Gantt, want)
+	}
+}
@@ -0,0 +1,155 @@
+package scheduler
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative schedulerpb/scheduler.proto
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/kasiyo/4600-project1/scheduler/schedulerpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// grpcServer serves the Scheduler service of schedulerpb/scheduler.proto
+// from the same simulations as the REST API.
+type grpcServer struct {
+	schedulerpb.UnimplementedSchedulerServer
+	// timeout, if positive, bounds each simulation.
+	timeout time.Duration
+}
+
+// newGRPCServer is a gRPC server offering the Scheduler service. A positive
+// timeout bounds each simulation; one is also cancelled when its client
+// goes away.
+func newGRPCServer(timeout time.Duration) *grpc.Server {
+	s := grpc.NewServer()
+	schedulerpb.RegisterSchedulerServer(s, &grpcServer{timeout: timeout})
+	return s
+}
+
+// Simulate streams the events of req's run as the engine records them. A
+// bad request fails before the first one.
+func (s *grpcServer) Simulate(req *schedulerpb.SimulateRequest, stream schedulerpb.Scheduler_SimulateServer) error {
+	sreq, err := simulateRequestFromProto(req)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	ctx, cancel := simulationContext(stream.Context(), s.timeout)
+	defer cancel()
+	var sendErr error
+	_, err = simulateRequest(ctx, sreq, func(ev Event) {
+		if sendErr != nil {
+			return
+		}
+		// A client that cannot be sent to has gone; stop the run.
+		if sendErr = stream.Send(eventProto(ev)); sendErr != nil {
+			cancel()
+		}
+	})
+	switch {
+	case sendErr != nil:
+		return sendErr
+	case err != nil:
+		return status.Error(simulationCode(err), err.Error())
+	}
+	return nil
+}
+
+// Algorithms lists the registered algorithms.
+func (s *grpcServer) Algorithms(context.Context, *schedulerpb.AlgorithmsRequest) (*schedulerpb.AlgorithmsResponse, error) {
+	resp := &schedulerpb.AlgorithmsResponse{}
+	for _, algorithm := range registry {
+		resp.Algorithms = append(resp.Algorithms, &schedulerpb.Algorithm{
+			Name:        algorithm.Name,
+			Title:       algorithm.Title,
+			Description: algorithm.Description,
+			Default:     algorithm.Default,
+		})
+	}
+	return resp, nil
+}
+
+// simulationCode is the gRPC status code for a failed simulation, as
+// simulationStatus is the HTTP status.
+func simulationCode(err error) codes.Code {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return codes.DeadlineExceeded
+	case errors.Is(err, context.Canceled):
+		return codes.Canceled
+	}
+	return codes.InvalidArgument
+}
+
+// simulateRequestFromProto is the REST request equivalent to req.
+func simulateRequestFromProto(req *schedulerpb.SimulateRequest) (SimulateRequest, error) {
+	processes, err := processesFromProto(req.GetProcesses())
+	if err != nil {
+		return SimulateRequest{}, err
+	}
+	inject, err := processesFromProto(req.GetInject())
+	if err != nil {
+		return SimulateRequest{}, err
+	}
+	return SimulateRequest{
+		Algorithm:    req.GetAlgorithm(),
+		Processes:    processes,
+		Inject:       inject,
+		MaxTime:      req.GetMaxTime(),
+		CPUs:         int(req.GetCpus()),
+		Balance:      req.GetBalance(),
+		Periods:      int(req.GetPeriods()),
+		OnMiss:       req.GetOnMiss(),
+		LockProtocol: req.GetLockProtocol(),
+		Options: AlgorithmOptions{
+			RR:   RROptions{Quantum: req.GetQuantum()},
+			Seed: req.GetSeed(),
+		},
+	}, nil
+}
+
+func processesFromProto(pbs []*schedulerpb.Process) ([]Process, error) {
+	var processes []Process
+	for _, pb := range pbs {
+		class, err := parseProcessClass(pb.GetClass())
+		if err != nil {
+			return nil, err
+		}
+		var affinity []int
+		for _, cpu := range pb.GetAffinity() {
+			affinity = append(affinity, int(cpu))
+		}
+		processes = append(processes, Process{
+			ProcessID:     pb.GetPid(),
+			ArrivalTime:   pb.GetArrival(),
+			BurstDuration: pb.GetBurst(),
+			Priority:      pb.GetPriority(),
+			Class:         class,
+			Bursts:        pb.GetBursts(),
+			DependsOn:     pb.GetAfter(),
+			Nice:          pb.GetNice(),
+			Group:         pb.GetGroup(),
+			Threads:       pb.GetThreads(),
+			Affinity:      affinity,
+			Deadline:      pb.GetDeadline(),
+			Period:        pb.GetPeriod(),
+		})
+	}
+	return processes, nil
+}
+
+// eventProto is ev as sent on the stream, with the fields of StreamEvent.
+func eventProto(ev Event) *schedulerpb.Event {
+	return &schedulerpb.Event{
+		Time:     ev.Time,
+		Kind:     ev.Kind.String(),
+		Pid:      ev.PID,
+		Cpu:      int32(ev.CPU),
+		Resource: ev.Resource,
+		Count:    ev.Count,
+		Priority: ev.Priority,
+	}
+}
@@ -0,0 +1,113 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"reflect"
+	"testing"
+
+	"github.com/kasiyo/4600-project1/scheduler/schedulerpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// dialGRPC starts a gRPC server in memory and connects a client to it.
+func dialGRPC(t *testing.T) schedulerpb.SchedulerClient {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	srv := newGRPCServer(0)
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return schedulerpb.NewSchedulerClient(conn)
+}
+
+func Test_grpcServer_Simulate(t *testing.T) {
+	t.Parallel()
+	client := dialGRPC(t)
+	tests := []struct {
+		name     string
+		req      *schedulerpb.SimulateRequest
+		want     []string
+		wantCode codes.Code
+	}{
+		{
+			name: "streams events",
+			req: &schedulerpb.SimulateRequest{Algorithm: "fcfs", Processes: []*schedulerpb.Process{
+				{Pid: 1, Burst: 3},
+				{Pid: 2, Arrival: 1, Burst: 2},
+			}},
+			want: []string{"0 arrive P1", "0 dispatch P1", "1 arrive P2", "3 complete P1", "3 dispatch P2", "5 complete P2"},
+		},
+		{
+			name: "quantum",
+			req: &schedulerpb.SimulateRequest{Algorithm: "rr", Quantum: 2, Processes: []*schedulerpb.Process{
+				{Pid: 1, Burst: 3},
+			}},
+			want: []string{"0 arrive P1", "0 dispatch P1", "2 expire P1", "2 dispatch P1", "3 complete P1"},
+		},
+		{
+			name:     "unknown algorithm",
+			req:      &schedulerpb.SimulateRequest{Algorithm: "stride"},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "invalid class",
+			req:      &schedulerpb.SimulateRequest{Algorithm: "fcfs", Processes: []*schedulerpb.Process{{Pid: 1, Burst: 1, Class: "daemon"}}},
+			wantCode: codes.InvalidArgument,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			stream, err := client.Simulate(context.Background(), tt.req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for {
+				ev, err := stream.Recv()
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				if err != nil {
+					if code := status.Code(err); code != tt.wantCode {
+						t.Fatalf("Recv() error = %v, want code %v", err, tt.wantCode)
+					}
+					return
+				}
+				got = append(got, fmt.Sprintf("%d %s P%d", ev.Time, ev.Kind, ev.Pid))
+			}
+			if tt.wantCode != codes.OK {
+				t.Fatalf("stream ended without error, want code %v", tt.wantCode)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("events = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_grpcServer_Algorithms(t *testing.T) {
+	t.Parallel()
+	resp, err := dialGRPC(t).Algorithms(context.Background(), &schedulerpb.AlgorithmsRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Algorithms) == 0 || resp.Algorithms[0].Name != "fcfs" || !resp.Algorithms[0].Default {
+		t.Errorf("Algorithms() = %v, want fcfs first and run by default", resp.Algorithms)
+	}
+}
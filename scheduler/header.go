@@ -0,0 +1,105 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrUnknownColumn is a process file header naming no column.
+var ErrUnknownColumn = errors.New("unknown column")
+
+// processHeader is a process file's header row, naming the columns its rows
+// give, in any order.
+type processHeader struct {
+	// columns is the index in processColumns of each value of a row.
+	columns []int
+	// positions is where each of processColumns is in a row, or -1.
+	positions []int
+	// width is the length of an arranged row: one past the last column
+	// named.
+	width int
+}
+
+// isProcessHeader reports whether row, the first of a process file, is a
+// header rather than a process: whether it names any of processColumns.
+func isProcessHeader(row []string) bool {
+	for _, name := range row {
+		if processColumn(name) >= 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// processColumn is the index in processColumns of name, ignoring case and
+// surrounding spaces, or -1.
+func processColumn(name string) int {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for i, c := range processColumns {
+		if c == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// parseProcessHeader reads the header row on line. Every name must be one
+// of processColumns, given once, and pid, burst and arrival are required.
+func parseProcessHeader(row []string, line int) (*processHeader, []*FieldError) {
+	h := &processHeader{columns: make([]int, len(row)), positions: make([]int, len(processColumns))}
+	for i := range h.positions {
+		h.positions[i] = -1
+	}
+	var bad []*FieldError
+	for i, name := range row {
+		col := processColumn(name)
+		switch {
+		case col < 0:
+			bad = append(bad, &FieldError{Line: line, Column: "header", Value: name, Err: fmt.Errorf("%w; columns are %s", ErrUnknownColumn, strings.Join(processColumns, ", "))})
+			continue
+		case h.positions[col] >= 0:
+			bad = append(bad, &FieldError{Line: line, Column: "header", Value: name, Err: errors.New("column named twice")})
+			continue
+		}
+		h.columns[i], h.positions[col] = col, i
+		if col >= h.width {
+			h.width = col + 1
+		}
+	}
+	for _, col := range []int{0, 1, 2} {
+		if h.positions[col] < 0 {
+			bad = append(bad, &FieldError{Line: line, Column: "header", Value: processColumns[col], Err: errors.New("missing required column")})
+		}
+	}
+	return h, bad
+}
+
+// position is where column col is in a row: col itself without a header,
+// or -1 when the header does not name it.
+func (h *processHeader) position(col int) int {
+	if h == nil {
+		return col
+	}
+	return h.positions[col]
+}
+
+// arrange puts the values of row, which must have one for each column the
+// header names, in the order of processColumns, leaving the columns it
+// does not name empty.
+func (h *processHeader) arrange(row []string) []string {
+	arranged := make([]string, h.width)
+	for i, col := range h.columns {
+		arranged[col] = row[i]
+	}
+	return arranged
+}
+
+// countError reports row, on line, having too few or too many values for
+// the header.
+func (h *processHeader) countError(row []string, line int) *FieldError {
+	if len(row) < len(h.columns) {
+		return &FieldError{Line: line, Column: processColumns[h.columns[len(row)]], Err: errors.New("missing value")}
+	}
+	return &FieldError{Line: line, Column: fmt.Sprint(len(h.columns) + 1), Value: row[len(h.columns)], Err: errors.New("more values than the header names")}
+}
@@ -0,0 +1,97 @@
+package scheduler
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestLoadProcesses_header(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		in      string
+		want    []Process
+		wantErr []FieldError
+	}{
+		{
+			name: "legacy columns",
+			in:   "pid,burst,arrival,priority\n1,5,0,2\n",
+			want: []Process{{ProcessID: 1, BurstDuration: 5, Priority: 2}},
+		},
+		{
+			name: "named columns in any order",
+			in:   "# exported\nPID; Arrival; Burst; Suspend; Deadline\n1;0;4;2-10;\n2;1;3;;10\n",
+			want: []Process{
+				{ProcessID: 1, BurstDuration: 4, Suspend: []Suspension{{Start: 2, Stop: 10}}},
+				{ProcessID: 2, BurstDuration: 3, ArrivalTime: 1, Deadline: 10},
+			},
+		},
+		{
+			name: "bad value",
+			in:   "arrival,pid,burst\n0,1,x\n",
+			wantErr: []FieldError{
+				{Line: 2, Column: "burst", Value: "x", Err: strconv.ErrSyntax},
+			},
+		},
+		{
+			name: "unknown and repeated names",
+			in:   "pid,burst,arrival,prio,pid\n1,5,0,2,1\n",
+			wantErr: []FieldError{
+				{Line: 1, Column: "header", Value: "prio", Err: ErrUnknownColumn},
+				{Line: 1, Column: "header", Value: "pid"},
+			},
+		},
+		{
+			name: "required column missing",
+			in:   "pid,burst,priority\n1,5,2\n",
+			wantErr: []FieldError{
+				{Line: 1, Column: "header", Value: "arrival"},
+			},
+		},
+		{
+			name: "values missing and extra",
+			in:   "pid,burst,arrival,deadline\n1,5\n2,5,0,9,3\n",
+			wantErr: []FieldError{
+				{Line: 2, Column: "arrival"},
+				{Line: 3, Column: "5", Value: "3"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := loadProcesses(strings.NewReader(tt.in))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("loadProcesses() = %+v, want %+v", got, tt.want)
+			}
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Errorf("error = %v", err)
+				}
+				return
+			}
+			var pfe *ProcessFileError
+			if !errors.As(err, &pfe) {
+				t.Fatalf("error = %v, want a *ProcessFileError", err)
+			}
+			if len(pfe.Errors) != len(tt.wantErr) {
+				t.Fatalf("got %d errors, want %d:\n%v", len(pfe.Errors), len(tt.wantErr), err)
+			}
+			for i, fe := range pfe.Errors {
+				got := *fe
+				if tt.wantErr[i].Err == nil {
+					got.Err = nil
+				} else if errors.Is(fe, tt.wantErr[i].Err) {
+					got.Err = tt.wantErr[i].Err
+				}
+				if got != tt.wantErr[i] {
+					t.Errorf("error %d = %+v, want %+v", i, got, tt.wantErr[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,293 @@
+package scheduler
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+// historyDriver is the database/sql driver of the experiment history,
+// registered by sqlite.go in builds with -tags sqlite.
+const historyDriver = "sqlite3"
+
+// ErrNoSQLite is returned for -db and history by a build without the
+// SQLite driver.
+var ErrNoSQLite = errors.New("built without SQLite support; rebuild with -tags sqlite")
+
+// historySchema creates the table of past runs, one row per algorithm run.
+const historySchema = `CREATE TABLE IF NOT EXISTS runs (
+	id INTEGER PRIMARY KEY,
+	time TEXT NOT NULL,
+	workload TEXT NOT NULL,
+	algorithm TEXT NOT NULL,
+	params TEXT NOT NULL,
+	completed INTEGER NOT NULL,
+	wait REAL NOT NULL,
+	turnaround REAL NOT NULL,
+	response REAL NOT NULL,
+	throughput REAL NOT NULL,
+	makespan INTEGER NOT NULL
+)`
+
+// HistoryRecord is one algorithm's run in the experiment history.
+type HistoryRecord struct {
+	Time time.Time
+	// Workload identifies the processes run, by hash, so runs of the same
+	// workload can be found whatever file it came from.
+	Workload  string
+	Algorithm string
+	// Params are the flags the run was given that shaped its schedule or
+	// metrics, as name=value pairs.
+	Params     string
+	Completed  int
+	Wait       float64
+	Turnaround float64
+	Response   float64
+	Throughput float64
+	Makespan   int64
+}
+
+// NewHistoryRecord records result, of algorithm run at the given time on
+// the workload with that hash.
+func NewHistoryRecord(at time.Time, workload, algorithm, params string, result Result) HistoryRecord {
+	response, makespan := responseAndMakespan(result)
+	return HistoryRecord{
+		Time:       at.UTC(),
+		Workload:   workload,
+		Algorithm:  algorithm,
+		Params:     params,
+		Completed:  len(result.Schedule),
+		Wait:       result.AverageWait,
+		Turnaround: result.AverageTurnaround,
+		Response:   response,
+		Throughput: result.Throughput,
+		Makespan:   makespan,
+	}
+}
+
+// workloadHash is the SHA-256 of processes written as a process file, so
+// the same workload hashes the same however its file was laid out.
+func workloadHash(processes []Process) (string, error) {
+	h := sha256.New()
+	if err := writeProcesses(h, processes); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// engineParams are the flags that shape every algorithm's schedule, or the
+// metrics taken of it.
+var engineParams = []string{
+	"balance", "closed-jobs", "cpus", "deadlock", "dispatch-cost", "freq", "inject",
+	"lock-protocol", "max-time", "on-miss", "periods", "resources", "semaphores",
+	"steal", "steal-threshold", "think", "thread-mode", "warmup", "warmup-jobs",
+}
+
+// algorithmParams are the flags only some algorithms read, with the
+// algorithms that do. The container algorithm reads them all, for the
+// algorithms its containers run.
+var algorithmParams = map[string][]string{
+	"aging":           {"priority", "mlfq"},
+	"io-boost":        {"priority"},
+	"quantum":         {"rr", "grouprr"},
+	"switch-cost":     {"rr", "grouprr"},
+	"quanta":          {"rr", "mlfq"},
+	"mlfq-levels":     {"mlfq"},
+	"mlfq-quanta":     {"mlfq"},
+	"mlfq-boost":      {"mlfq"},
+	"spn-alpha":       {"spn"},
+	"spn-initial":     {"spn"},
+	"min-share":       {"minshare"},
+	"share-window":    {"minshare"},
+	"seed":            {"lottery"},
+	"containers":      {"container"},
+	"container-slice": {"container"},
+}
+
+// scheduleParams lists the flags set on fs that shape algorithm's schedule
+// as sorted name=value pairs. Flags that only change the output, or that
+// algorithm does not read, are left out, so runs differing only in those
+// record the same parameters.
+func scheduleParams(fs *flag.FlagSet, algorithm string) string {
+	var params []string
+	fs.Visit(func(f *flag.Flag) {
+		if readsParam(algorithm, f.Name) {
+			params = append(params, f.Name+"="+f.Value.String())
+		}
+	})
+	return strings.Join(params, " ")
+}
+
+// readsParam reports whether flag name shapes algorithm's schedule.
+func readsParam(algorithm, name string) bool {
+	for _, p := range engineParams {
+		if p == name {
+			return true
+		}
+	}
+	readers, ok := algorithmParams[name]
+	if !ok {
+		return false
+	}
+	if algorithm == "container" {
+		return true
+	}
+	for _, a := range readers {
+		if a == algorithm {
+			return true
+		}
+	}
+	return false
+}
+
+// openHistory opens the experiment history at path, creating it if need be.
+func openHistory(path string) (*sql.DB, error) {
+	found := false
+	for _, d := range sql.Drivers() {
+		found = found || d == historyDriver
+	}
+	if !found {
+		return nil, ErrNoSQLite
+	}
+	db, err := sql.Open(historyDriver, path)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := db.Exec(historySchema); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return db, nil
+}
+
+// appendHistory adds records to the history in one transaction.
+func appendHistory(db *sql.DB, records []HistoryRecord) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	for _, r := range records {
+		_, err := tx.Exec(`INSERT INTO runs (time, workload, algorithm, params, completed, wait, turnaround, response, throughput, makespan)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			r.Time.Format(time.RFC3339), r.Workload, r.Algorithm, r.Params, r.Completed,
+			r.Wait, r.Turnaround, r.Response, r.Throughput, r.Makespan)
+		if err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// HistoryQuery selects past runs: of Algorithm, of workloads whose hash
+// starts with Workload, with Params containing Param, the latest Limit.
+type HistoryQuery struct {
+	Algorithm string
+	Workload  string
+	Param     string
+	Limit     int
+}
+
+// statement is q as SQL with its arguments.
+func (q HistoryQuery) statement() (string, []interface{}) {
+	var (
+		where []string
+		args  []interface{}
+	)
+	if q.Algorithm != "" {
+		where, args = append(where, "algorithm = ?"), append(args, q.Algorithm)
+	}
+	if q.Workload != "" {
+		where, args = append(where, "workload LIKE ?"), append(args, q.Workload+"%")
+	}
+	if q.Param != "" {
+		where, args = append(where, "instr(params, ?) > 0"), append(args, q.Param)
+	}
+	stmt := "SELECT time, workload, algorithm, params, completed, wait, turnaround, response, throughput, makespan FROM runs"
+	if len(where) > 0 {
+		stmt += " WHERE " + strings.Join(where, " AND ")
+	}
+	stmt += " ORDER BY id DESC"
+	if q.Limit > 0 {
+		stmt, args = stmt+" LIMIT ?", append(args, q.Limit)
+	}
+	return stmt, args
+}
+
+func queryHistory(db *sql.DB, q HistoryQuery) ([]HistoryRecord, error) {
+	stmt, args := q.statement()
+	rows, err := db.Query(stmt, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var records []HistoryRecord
+	for rows.Next() {
+		var (
+			r  HistoryRecord
+			at string
+		)
+		if err := rows.Scan(&at, &r.Workload, &r.Algorithm, &r.Params, &r.Completed,
+			&r.Wait, &r.Turnaround, &r.Response, &r.Throughput, &r.Makespan); err != nil {
+			return nil, err
+		}
+		if r.Time, err = time.Parse(time.RFC3339, at); err != nil {
+			return nil, err
+		}
+		records = append(records, r)
+	}
+	return records, rows.Err()
+}
+
+func outputHistory(w io.Writer, records []HistoryRecord) {
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Time", "Workload", "Algorithm", "Parameters", "Completed", "Wait", "Turnaround", "Response", "Throughput", "Makespan"})
+	for _, r := range records {
+		workload := r.Workload
+		if len(workload) > 12 {
+			workload = workload[:12]
+		}
+		table.Append([]string{
+			r.Time.Format(time.RFC3339), workload, r.Algorithm, r.Params, fmt.Sprint(r.Completed),
+			fmt.Sprintf("%.2f", r.Wait), fmt.Sprintf("%.2f", r.Turnaround), fmt.Sprintf("%.2f", r.Response),
+			fmt.Sprintf("%.4f", r.Throughput), fmt.Sprint(r.Makespan),
+		})
+	}
+	table.Render()
+}
+
+// runHistory is the history subcommand: the latest runs recorded with -db.
+func runHistory(w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("history", flag.ContinueOnError)
+	path := fs.String("db", "results.db", "SQLite database the runs were recorded in with -db")
+	var q HistoryQuery
+	fs.StringVar(&q.Algorithm, "algo", "", "only runs of this algorithm")
+	fs.StringVar(&q.Workload, "workload", "", "only runs of workloads whose hash starts with this")
+	fs.StringVar(&q.Param, "param", "", "only runs whose parameters contain this, e.g. quantum=4")
+	fs.IntVar(&q.Limit, "limit", 20, "show at most this many of the latest runs; 0 shows all")
+	if err := parseFlags(fs, "history", args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("%w: usage: history [-db results.db] [-algo name] [-workload hash] [-param name=value]", ErrInvalidArgs)
+	}
+	db, err := openHistory(*path)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	records, err := queryHistory(db, q)
+	if err != nil {
+		return err
+	}
+	outputHistory(w, records)
+	return nil
+}
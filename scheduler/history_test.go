@@ -0,0 +1,108 @@
+package scheduler
+
+import (
+	"database/sql"
+	"errors"
+	"flag"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_workloadHash(t *testing.T) {
+	t.Parallel()
+	a := []Process{{ProcessID: 1, BurstDuration: 5}, {ProcessID: 2, BurstDuration: 3, ArrivalTime: 1}}
+	b := []Process{{ProcessID: 1, BurstDuration: 5}, {ProcessID: 2, BurstDuration: 4, ArrivalTime: 1}}
+	ha, err := workloadHash(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := workloadHash(a); again != ha || len(ha) != 64 {
+		t.Errorf("workloadHash() = %q then %q, want the same 64 hex digits", ha, again)
+	}
+	if hb, _ := workloadHash(b); hb == ha {
+		t.Errorf("different workloads both hash to %q", ha)
+	}
+}
+
+func TestNewHistoryRecord(t *testing.T) {
+	t.Parallel()
+	processes := []Process{{ProcessID: 1, BurstDuration: 5}, {ProcessID: 2, BurstDuration: 3, ArrivalTime: 1}}
+	at := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	got := NewHistoryRecord(at, "abc", "fcfs", "quantum=2", Simulate(processes, fcfsPolicy{}, EngineOptions{}))
+	want := HistoryRecord{
+		Time: at, Workload: "abc", Algorithm: "fcfs", Params: "quantum=2", Completed: 2,
+		Wait: 2, Turnaround: 6, Response: 2, Throughput: 0.25, Makespan: 8,
+	}
+	if got != want {
+		t.Errorf("NewHistoryRecord() = %+v, want %+v", got, want)
+	}
+}
+
+func Test_scheduleParams(t *testing.T) {
+	t.Parallel()
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	fs.Int64("quantum", 0, "")
+	fs.Int64("aging", 0, "")
+	fs.String("db", "", "")
+	fs.Bool("q", false, "")
+	fs.Int("cpus", 1, "")
+	if err := fs.Parse([]string{"-quantum", "4", "-aging", "3", "-db", "r.db", "-q", "-cpus", "2"}); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		algorithm string
+		want      string
+	}{
+		{algorithm: "fcfs", want: "cpus=2"},
+		{algorithm: "rr", want: "cpus=2 quantum=4"},
+		{algorithm: "priority", want: "aging=3 cpus=2"},
+		{algorithm: "container", want: "aging=3 cpus=2 quantum=4"},
+	}
+	for _, tt := range tests {
+		if got := scheduleParams(fs, tt.algorithm); got != tt.want {
+			t.Errorf("scheduleParams(%s) = %q, want %q", tt.algorithm, got, tt.want)
+		}
+	}
+}
+
+func TestHistoryQuery_statement(t *testing.T) {
+	t.Parallel()
+	const columns = "SELECT time, workload, algorithm, params, completed, wait, turnaround, response, throughput, makespan FROM runs"
+	tests := []struct {
+		name     string
+		query    HistoryQuery
+		wantStmt string
+		wantArgs []interface{}
+	}{
+		{name: "everything", wantStmt: columns + " ORDER BY id DESC"},
+		{
+			name:     "filtered",
+			query:    HistoryQuery{Algorithm: "rr", Workload: "ab12", Param: "quantum=4", Limit: 5},
+			wantStmt: columns + " WHERE algorithm = ? AND workload LIKE ? AND instr(params, ?) > 0 ORDER BY id DESC LIMIT ?",
+			wantArgs: []interface{}{"rr", "ab12%", "quantum=4", 5},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			stmt, args := tt.query.statement()
+			if stmt != tt.wantStmt || !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("statement() = %q, %v, want %q, %v", stmt, args, tt.wantStmt, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func Test_openHistory_noDriver(t *testing.T) {
+	t.Parallel()
+	for _, d := range sql.Drivers() {
+		if d == historyDriver {
+			t.Skip("built with the SQLite driver")
+		}
+	}
+	if _, err := openHistory(t.TempDir() + "/results.db"); !errors.Is(err, ErrNoSQLite) {
+		t.Errorf("openHistory() error = %v, want %v", err, ErrNoSQLite)
+	}
+}
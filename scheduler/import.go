@@ -0,0 +1,568 @@
+package scheduler
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidImport is returned when a snapshot or trace cannot be read.
+var ErrInvalidImport = errors.New("invalid import")
+
+// ImportOptions controls how captured system activity becomes a workload.
+type ImportOptions struct {
+	// Tick is the real time one simulated tick stands for.
+	Tick time.Duration
+	// ClockTicks is the kernel's clock ticks per second, USER_HZ, that
+	// /proc times are counted in.
+	ClockTicks int64
+}
+
+// importers read each format the import subcommand accepts.
+var importers = map[string]func(r io.Reader, opts ImportOptions) ([]Process, error){
+	"ps":     importPS,
+	"proc":   importProc,
+	"perf":   importPerfSched,
+	"chrome": importChromeTrace,
+}
+
+// snapshotProcess is a process as a system snapshot sees it: when it
+// started, relative to some fixed point, and the CPU time it has used.
+type snapshotProcess struct {
+	pid, priority, nice int64
+	started, cpu        time.Duration
+}
+
+// snapshotWorkload turns a snapshot into processes. The first to start
+// arrives at zero, and each process's CPU time so far becomes its burst,
+// rounded up to a whole tick.
+func snapshotWorkload(snapshot []snapshotProcess, tick time.Duration) []Process {
+	if len(snapshot) == 0 {
+		return nil
+	}
+	first := snapshot[0].started
+	for _, s := range snapshot {
+		if s.started < first {
+			first = s.started
+		}
+	}
+	processes := make([]Process, len(snapshot))
+	for i, s := range snapshot {
+		burst := int64((s.cpu + tick - 1) / tick)
+		if burst < 1 {
+			burst = 1
+		}
+		processes[i] = Process{
+			ProcessID:     s.pid,
+			ArrivalTime:   int64((s.started - first) / tick),
+			BurstDuration: burst,
+			Priority:      s.priority,
+			Nice:          s.nice,
+		}
+	}
+	sort.SliceStable(processes, func(i, j int) bool { return processes[i].ArrivalTime < processes[j].ArrivalTime })
+	return processes
+}
+
+// importPS reads the output of ps -eo pid,pri,etimes,time, finding the
+// columns by their headers so they may come in any order. NI, if present,
+// gives each process's nice value; other columns are ignored. Elapsed and
+// CPU times may be plain seconds or [[dd-]hh:]mm:ss.
+func importPS(r io.Reader, opts ImportOptions) ([]Process, error) {
+	var (
+		sc       = bufio.NewScanner(r)
+		columns  map[string]int
+		snapshot []snapshotProcess
+		line     int
+	)
+	for sc.Scan() {
+		line++
+		fields := strings.Fields(sc.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if columns == nil {
+			columns = map[string]int{}
+			for i, f := range fields {
+				columns[strings.ToUpper(f)] = i
+			}
+			for _, want := range []string{"PID", "PRI", "ELAPSED", "TIME"} {
+				if _, ok := columns[want]; !ok {
+					return nil, fmt.Errorf("%w: ps header has no %s column", ErrInvalidImport, want)
+				}
+			}
+			continue
+		}
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(fields) {
+				return fields[i]
+			}
+			return ""
+		}
+		var (
+			s   snapshotProcess
+			err error
+		)
+		if s.pid, err = strconv.ParseInt(field("PID"), 10, 64); err != nil {
+			return nil, fmt.Errorf("%w: line %d: bad PID %q", ErrInvalidImport, line, field("PID"))
+		}
+		if s.priority, err = strconv.ParseInt(field("PRI"), 10, 64); err != nil {
+			return nil, fmt.Errorf("%w: line %d: bad PRI %q", ErrInvalidImport, line, field("PRI"))
+		}
+		// Real-time processes show - for their nice value.
+		if ni := field("NI"); ni != "" && ni != "-" {
+			if s.nice, err = strconv.ParseInt(ni, 10, 64); err != nil {
+				return nil, fmt.Errorf("%w: line %d: bad NI %q", ErrInvalidImport, line, ni)
+			}
+		}
+		elapsed, err := parseClockTime(field("ELAPSED"))
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: bad ELAPSED %q", ErrInvalidImport, line, field("ELAPSED"))
+		}
+		if s.cpu, err = parseClockTime(field("TIME")); err != nil {
+			return nil, fmt.Errorf("%w: line %d: bad TIME %q", ErrInvalidImport, line, field("TIME"))
+		}
+		// The longest running process started first.
+		s.started = -elapsed
+		snapshot = append(snapshot, s)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("%w: reading ps output", err)
+	}
+	return snapshotWorkload(snapshot, opts.Tick), nil
+}
+
+// parseClockTime reads a duration as ps prints it: plain seconds, or
+// [[dd-]hh:]mm:ss.
+func parseClockTime(s string) (time.Duration, error) {
+	var days int64
+	if d, rest, ok := strings.Cut(s, "-"); ok {
+		n, err := strconv.ParseInt(d, 10, 64)
+		if err != nil {
+			return 0, err
+		}
+		days, s = n, rest
+	}
+	var seconds int64
+	for _, part := range strings.Split(s, ":") {
+		n, err := strconv.ParseInt(part, 10, 64)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("bad clock time %q", s)
+		}
+		seconds = seconds*60 + n
+	}
+	return time.Duration(days*86400+seconds) * time.Second, nil
+}
+
+// importProc reads /proc/<pid>/stat lines, as collected by
+// cat /proc/[0-9]*/stat. The process's user and system time are its burst,
+// its start time since boot its arrival, and the kernel's priority and nice
+// value carry over.
+func importProc(r io.Reader, opts ImportOptions) ([]Process, error) {
+	var (
+		sc       = bufio.NewScanner(r)
+		snapshot []snapshotProcess
+		line     int
+	)
+	hz := time.Duration(opts.ClockTicks)
+	if hz <= 0 {
+		return nil, fmt.Errorf("%w: clock ticks per second must be positive", ErrInvalidArgs)
+	}
+	for sc.Scan() {
+		line++
+		text := strings.TrimSpace(sc.Text())
+		if text == "" {
+			continue
+		}
+		// The command name is in parentheses and may itself contain spaces
+		// or parentheses, so the fields after it start at the last ).
+		open, end := strings.IndexByte(text, '('), strings.LastIndexByte(text, ')')
+		if open < 0 || end < open {
+			return nil, fmt.Errorf("%w: line %d: no (command) in stat line", ErrInvalidImport, line)
+		}
+		// Fields after the command start at the state, field 3.
+		rest := strings.Fields(text[end+1:])
+		if len(rest) < 20 {
+			return nil, fmt.Errorf("%w: line %d: stat line has %d fields, want at least 22", ErrInvalidImport, line, len(rest)+2)
+		}
+		stat := func(n int) (int64, error) { return strconv.ParseInt(rest[n-3], 10, 64) }
+		var (
+			s   snapshotProcess
+			err error
+		)
+		if s.pid, err = strconv.ParseInt(strings.TrimSpace(text[:open]), 10, 64); err != nil {
+			return nil, fmt.Errorf("%w: line %d: bad PID %q", ErrInvalidImport, line, text[:open])
+		}
+		var ticks [4]int64
+		for i, n := range []int{14, 15, 18, 19} {
+			if ticks[i], err = stat(n); err != nil {
+				return nil, fmt.Errorf("%w: line %d: bad stat field %d", ErrInvalidImport, line, n)
+			}
+		}
+		start, err := stat(22)
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: bad stat field 22", ErrInvalidImport, line)
+		}
+		s.cpu = time.Duration(ticks[0]+ticks[1]) * time.Second / hz
+		s.priority, s.nice = ticks[2], ticks[3]
+		s.started = time.Duration(start) * time.Second / hz
+		snapshot = append(snapshot, s)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("%w: reading /proc scrape", err)
+	}
+	return snapshotWorkload(snapshot, opts.Tick), nil
+}
+
+// traceTask is one task's activity in a captured schedule, built up as the
+// trace is read in time order.
+type traceTask struct {
+	pid      int64
+	priority int64
+	seen     bool
+	arrival  time.Duration
+	// bursts alternates CPU and sleep times, starting with CPU. cpu is the
+	// CPU time since the last sleep, runStart when the task last went on a
+	// CPU, if running, and sleepStart when it last went to sleep, if
+	// sleeping.
+	bursts     []time.Duration
+	cpu        time.Duration
+	running    bool
+	runStart   time.Duration
+	sleeping   bool
+	sleepStart time.Duration
+}
+
+// traceTasks collects tasks by PID in the order they first appear.
+type traceTasks struct {
+	byPID map[int64]*traceTask
+	order []*traceTask
+}
+
+func (ts *traceTasks) get(pid int64) *traceTask {
+	if ts.byPID == nil {
+		ts.byPID = map[int64]*traceTask{}
+	}
+	t, ok := ts.byPID[pid]
+	if !ok {
+		t = &traceTask{pid: pid}
+		ts.byPID[pid] = t
+		ts.order = append(ts.order, t)
+	}
+	return t
+}
+
+func (t *traceTask) arrive(at time.Duration) {
+	if !t.seen {
+		t.seen, t.arrival = true, at
+	}
+}
+
+// wake ends a sleep; the task is then ready, though it may wait to run.
+func (t *traceTask) wake(at time.Duration) {
+	t.arrive(at)
+	if t.sleeping {
+		t.bursts = append(t.bursts, at-t.sleepStart)
+		t.sleeping = false
+	}
+}
+
+func (t *traceTask) run(at time.Duration) {
+	// A task seen running without its wakeup woke as it ran.
+	t.wake(at)
+	t.running, t.runStart = true, at
+}
+
+// stop takes the task off the CPU: still ready if it was preempted, or
+// asleep until it is woken.
+func (t *traceTask) stop(at time.Duration, sleeps bool) {
+	if !t.running {
+		return
+	}
+	t.cpu += at - t.runStart
+	t.running = false
+	if sleeps {
+		t.bursts = append(t.bursts, t.cpu)
+		t.cpu = 0
+		t.sleeping, t.sleepStart = true, at
+	}
+}
+
+// traceWorkload turns traced tasks into processes, each CPU time and sleep
+// rounded up to a whole tick. Tasks still running are cut off at end, the
+// first task to arrive does so at zero, and tasks that never ran are left
+// out.
+func traceWorkload(tasks []*traceTask, end, tick time.Duration) []Process {
+	var (
+		processes []Process
+		first     time.Duration
+		started   bool
+	)
+	for _, t := range tasks {
+		if t.seen && (!started || t.arrival < first) {
+			first, started = t.arrival, true
+		}
+	}
+	ticks := func(d time.Duration) int64 {
+		if n := int64((d + tick - 1) / tick); n > 0 {
+			return n
+		}
+		return 1
+	}
+	for _, t := range tasks {
+		t.stop(end, false)
+		bursts := t.bursts
+		if t.cpu > 0 {
+			bursts = append(bursts, t.cpu)
+		} else if len(bursts)%2 == 0 && len(bursts) > 0 {
+			// The trace ended while it slept or waited after sleeping.
+			bursts = bursts[:len(bursts)-1]
+		}
+		if len(bursts) == 0 {
+			continue
+		}
+		p := Process{ProcessID: t.pid, ArrivalTime: int64((t.arrival - first) / tick), Priority: t.priority}
+		for i, b := range bursts {
+			n := ticks(b)
+			if i%2 == 0 {
+				p.BurstDuration += n
+			}
+			if len(bursts) > 1 {
+				p.Bursts = append(p.Bursts, n)
+			}
+		}
+		processes = append(processes, p)
+	}
+	sort.SliceStable(processes, func(i, j int) bool { return processes[i].ArrivalTime < processes[j].ArrivalTime })
+	return processes
+}
+
+// importPerfSched reads sched_switch and sched_wakeup records, as printed
+// by perf sched script or found in an ftrace text trace such as
+// -switch-trace writes. A task switched out in state R was preempted and
+// stays ready; in X or Z it exited; in any other state it slept until its
+// next wakeup. PID 0, the idle task, is left out.
+func importPerfSched(r io.Reader, opts ImportOptions) ([]Process, error) {
+	var (
+		sc    = bufio.NewScanner(r)
+		tasks traceTasks
+		line  int
+		last  time.Duration
+	)
+	sc.Buffer(nil, 1<<20)
+	for sc.Scan() {
+		line++
+		text := sc.Text()
+		var event string
+		for _, e := range []string{"sched_switch:", "sched_wakeup_new:", "sched_wakeup:"} {
+			if strings.Contains(text, e) {
+				event = e
+				break
+			}
+		}
+		if event == "" {
+			continue
+		}
+		i := strings.Index(text, event)
+		at, err := traceTimestamp(text[:i])
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidImport, line, err)
+		}
+		if at < last {
+			return nil, fmt.Errorf("%w: line %d: records out of time order", ErrInvalidImport, line)
+		}
+		last = at
+		kv := map[string]string{}
+		for _, f := range strings.Fields(text[i+len(event):]) {
+			if k, v, ok := strings.Cut(f, "="); ok {
+				kv[k] = v
+			}
+		}
+		number := func(key string) (int64, error) {
+			n, err := strconv.ParseInt(kv[key], 10, 64)
+			if err != nil {
+				return 0, fmt.Errorf("%w: line %d: bad %s %q", ErrInvalidImport, line, key, kv[key])
+			}
+			return n, nil
+		}
+		if event != "sched_switch:" {
+			pid, err := number("pid")
+			if err != nil {
+				return nil, err
+			}
+			if pid != 0 {
+				tasks.get(pid).wake(at)
+			}
+			continue
+		}
+		prev, err := number("prev_pid")
+		if err != nil {
+			return nil, err
+		}
+		next, err := number("next_pid")
+		if err != nil {
+			return nil, err
+		}
+		if prev != 0 {
+			state := strings.TrimRight(kv["prev_state"], "+")
+			tasks.get(prev).stop(at, state != "R")
+		}
+		if next != 0 {
+			t := tasks.get(next)
+			if prio, err := number("next_prio"); err == nil {
+				t.priority = prio
+			}
+			t.run(at)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("%w: reading sched trace", err)
+	}
+	return traceWorkload(tasks.order, last, opts.Tick), nil
+}
+
+// traceTimestamp reads the seconds.micros: field that ends the text before
+// a trace record's event name.
+func traceTimestamp(prefix string) (time.Duration, error) {
+	fields := strings.Fields(prefix)
+	if len(fields) == 0 {
+		return 0, errors.New("no timestamp")
+	}
+	ts := strings.TrimSuffix(fields[len(fields)-1], ":")
+	seconds, err := strconv.ParseFloat(ts, 64)
+	if err != nil {
+		return 0, fmt.Errorf("bad timestamp %q", ts)
+	}
+	return time.Duration(seconds*1e6+0.5) * time.Microsecond, nil
+}
+
+// chromeEvent is a Chrome trace event; times are in microseconds.
+type chromeEvent struct {
+	Name string                 `json:"name"`
+	Ph   string                 `json:"ph"`
+	Ts   float64                `json:"ts"`
+	Dur  float64                `json:"dur,omitempty"`
+	PID  int64                  `json:"pid"`
+	TID  int64                  `json:"tid"`
+	Args map[string]interface{} `json:"args,omitempty"`
+}
+
+// importChromeTrace reads Chrome trace-event JSON, either an array of
+// events or an object with a traceEvents array. Each thread's complete (X)
+// and begin/end (B, E) events are the times it ran, nested and overlapping
+// ones merged; the gaps between them are taken as sleeps, since the format
+// does not say why a thread stopped. Threads are identified by tid, or pid
+// when tid is zero.
+func importChromeTrace(r io.Reader, opts ImportOptions) ([]Process, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: reading trace", err)
+	}
+	var events []chromeEvent
+	if err := json.Unmarshal(b, &events); err != nil {
+		var doc struct {
+			TraceEvents []chromeEvent `json:"traceEvents"`
+		}
+		if err := json.Unmarshal(b, &doc); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidImport, err)
+		}
+		events = doc.TraceEvents
+	}
+
+	type span struct{ start, end time.Duration }
+	var (
+		spans = map[int64][]span{}
+		open  = map[int64][]time.Duration{}
+		order []int64
+	)
+	micros := func(us float64) time.Duration { return time.Duration(us*1e3 + 0.5) }
+	add := func(id int64, s span) {
+		if _, ok := spans[id]; !ok {
+			order = append(order, id)
+		}
+		spans[id] = append(spans[id], s)
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Ts < events[j].Ts })
+	for _, ev := range events {
+		id := ev.TID
+		if id == 0 {
+			id = ev.PID
+		}
+		switch ev.Ph {
+		case "X":
+			add(id, span{micros(ev.Ts), micros(ev.Ts + ev.Dur)})
+		case "B":
+			open[id] = append(open[id], micros(ev.Ts))
+		case "E":
+			if n := len(open[id]); n > 0 {
+				add(id, span{open[id][n-1], micros(ev.Ts)})
+				open[id] = open[id][:n-1]
+			}
+		}
+	}
+
+	var tasks traceTasks
+	for _, id := range order {
+		s := spans[id]
+		sort.Slice(s, func(i, j int) bool { return s[i].start < s[j].start })
+		t := tasks.get(id)
+		end := s[0].start
+		for i, sp := range s {
+			if i > 0 && sp.start > end {
+				t.stop(end, true)
+			}
+			if !t.running {
+				t.run(sp.start)
+			}
+			if sp.end > end {
+				end = sp.end
+			}
+		}
+		t.stop(end, false)
+	}
+	return traceWorkload(tasks.order, 0, opts.Tick), nil
+}
+
+// runImport is the import subcommand: it converts a system snapshot or a
+// captured schedule into a process file.
+func runImport(w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	format := fs.String("format", "ps", "input format: ps, proc, perf (perf sched script or ftrace) or chrome (trace-event JSON)")
+	var opts ImportOptions
+	fs.DurationVar(&opts.Tick, "tick", time.Second, "real time one simulated tick stands for")
+	fs.Int64Var(&opts.ClockTicks, "clock-ticks", 100, "kernel clock ticks per second that /proc times count")
+	if err := parseFlags(fs, "import", args); err != nil {
+		return err
+	}
+	read, ok := importers[*format]
+	if !ok {
+		return fmt.Errorf("%w: unknown import format %q", ErrInvalidArgs, *format)
+	}
+	if opts.Tick <= 0 {
+		return fmt.Errorf("%w: -tick must be positive", ErrInvalidArgs)
+	}
+	if fs.NArg() > 1 {
+		return fmt.Errorf("%w: usage: import [-format ps|proc|perf|chrome] [file]", ErrInvalidArgs)
+	}
+	f, closeFile, err := openProcessingFile(append([]string{"import"}, fs.Args()...)...)
+	if err != nil {
+		return err
+	}
+	defer closeFile()
+	processes, err := read(f, opts)
+	if err != nil {
+		return err
+	}
+	if processes, err = checkProcesses(processes, true, os.Stderr); err != nil {
+		return err
+	}
+	return writeProcesses(w, processes)
+}
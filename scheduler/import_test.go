@@ -0,0 +1,120 @@
+package scheduler
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_importers(t *testing.T) {
+	t.Parallel()
+	opts := ImportOptions{Tick: time.Second, ClockTicks: 100}
+	tests := []struct {
+		name   string
+		format string
+		in     string
+		// tick overrides the default one-second tick.
+		tick    time.Duration
+		want    []Process
+		wantErr error
+	}{
+		{
+			name:   "ps",
+			format: "ps",
+			in: `    PID PRI  NI ELAPSED     TIME
+      1  19   0     100 00:00:05
+     42  39 -20   01:30 00:01:02
+     77  80   -      40        0
+`,
+			want: []Process{
+				{ProcessID: 1, BurstDuration: 5, Priority: 19},
+				{ProcessID: 42, ArrivalTime: 10, BurstDuration: 62, Priority: 39, Nice: -20},
+				{ProcessID: 77, ArrivalTime: 60, BurstDuration: 1, Priority: 80},
+			},
+		},
+		{
+			name:    "ps without a TIME column",
+			format:  "ps",
+			in:      "PID PRI ELAPSED\n1 19 100\n",
+			wantErr: ErrInvalidImport,
+		},
+		{
+			name:   "proc",
+			format: "proc",
+			in: `7 (my (odd) cmd) S 1 7 7 0 -1 4194560 100 0 0 0 250 50 0 0 20 0 1 0 1000 1000 100
+9 (worker) R 1 9 9 0 -1 4194560 100 0 0 0 100 0 0 0 25 5 1 0 1300 1000 100
+`,
+			want: []Process{
+				{ProcessID: 7, BurstDuration: 3, Priority: 20},
+				{ProcessID: 9, ArrivalTime: 3, BurstDuration: 1, Priority: 25, Nice: 5},
+			},
+		},
+		{
+			name:   "chrome trace",
+			format: "chrome",
+			tick:   time.Millisecond,
+			in: `{"traceEvents": [
+				{"name": "thread_name", "ph": "M", "pid": 1, "tid": 5},
+				{"name": "a", "ph": "X", "ts": 1000, "dur": 2000, "pid": 1, "tid": 5},
+				{"name": "nested", "ph": "X", "ts": 1500, "dur": 1000, "pid": 1, "tid": 5},
+				{"name": "b", "ph": "B", "ts": 6000, "pid": 1, "tid": 5},
+				{"name": "b", "ph": "E", "ts": 7000, "pid": 1, "tid": 5},
+				{"name": "c", "ph": "X", "ts": 2000, "dur": 500, "pid": 2}
+			]}`,
+			want: []Process{
+				{ProcessID: 5, BurstDuration: 3, Bursts: []int64{2, 3, 1}},
+				{ProcessID: 2, ArrivalTime: 1, BurstDuration: 1},
+			},
+		},
+		{
+			name:    "truncated stat line",
+			format:  "proc",
+			in:      "7 (cmd) S 1 7\n",
+			wantErr: ErrInvalidImport,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			opts := opts
+			if tt.tick > 0 {
+				opts.Tick = tt.tick
+			}
+			got, err := importers[tt.format](strings.NewReader(tt.in), opts)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_importPerfSched_roundTrip(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 5, Bursts: []int64{2, 3, 3}},
+		{ProcessID: 2, ArrivalTime: 1, BurstDuration: 4},
+		{ProcessID: 3, ArrivalTime: 2, BurstDuration: 2},
+	}
+	var trace bytes.Buffer
+	if err := writeSwitchTrace(&trace, Simulate(processes, newRRPolicy(processes, RROptions{Quantum: 2}), EngineOptions{})); err != nil {
+		t.Fatal(err)
+	}
+	got, err := importPerfSched(&trace, ImportOptions{Tick: time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The trace keeps every burst but not the priorities.
+	for i := range got {
+		got[i].Priority = 0
+	}
+	if !reflect.DeepEqual(got, processes) {
+		t.Errorf("got %+v, want %+v", got, processes)
+	}
+}
@@ -0,0 +1,89 @@
+package scheduler
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// injectFlag collects the processes given with repeated -inject flags.
+type injectFlag []Process
+
+func (f *injectFlag) String() string {
+	if f == nil {
+		return ""
+	}
+	specs := make([]string, len(*f))
+	for i, p := range *f {
+		specs[i] = fmt.Sprintf("at=%d,pid=%d,burst=%d,priority=%d", p.ArrivalTime, p.ProcessID, p.BurstDuration, p.Priority)
+	}
+	return strings.Join(specs, " ")
+}
+
+func (f *injectFlag) Set(s string) error {
+	p, err := parseInjection(s)
+	if err != nil {
+		return err
+	}
+	*f = append(*f, p)
+	return nil
+}
+
+// parseInjection reads a process to inject, written as comma-separated
+// key=value pairs: at, the tick it arrives, and pid and burst are required;
+// priority defaults to zero.
+func parseInjection(s string) (Process, error) {
+	var (
+		p    Process
+		seen = map[string]bool{}
+	)
+	for _, field := range strings.Split(s, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(field), "=")
+		if !ok {
+			return Process{}, fmt.Errorf("%w: injected process field %q is not key=value", ErrInvalidArgs, field)
+		}
+		n, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		if err != nil {
+			return Process{}, fmt.Errorf("%w: injected process %s %q is not a number", ErrInvalidArgs, key, value)
+		}
+		switch key {
+		case "at":
+			p.ArrivalTime = n
+		case "pid":
+			p.ProcessID = n
+		case "burst":
+			p.BurstDuration = n
+		case "priority":
+			p.Priority = n
+		default:
+			return Process{}, fmt.Errorf("%w: unknown injected process field %q", ErrInvalidArgs, key)
+		}
+		seen[key] = true
+	}
+	for _, key := range []string{"at", "pid", "burst"} {
+		if !seen[key] {
+			return Process{}, fmt.Errorf("%w: injected process %q has no %s", ErrInvalidArgs, s, key)
+		}
+	}
+	return p, nil
+}
+
+// injectProcesses adds injected processes to the end of a workload. Policies
+// are still built from the original workload, so an injected process is a
+// surprise to them: round-robin's default quantum, for instance, ignores it.
+func injectProcesses(processes, injected []Process) []Process {
+	if len(injected) == 0 {
+		return processes
+	}
+	all := make([]Process, 0, len(processes)+len(injected))
+	all = append(all, processes...)
+	return append(all, injected...)
+}
+
+// outputInjections lists the processes injected into the workload.
+func outputInjections(w io.Writer, injected []Process) {
+	for _, p := range injected {
+		fmt.Fprintf(w, "Injecting process %d at t=%d: burst %d, priority %d\n", p.ProcessID, p.ArrivalTime, p.BurstDuration, p.Priority)
+	}
+}
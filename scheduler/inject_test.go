@@ -0,0 +1,49 @@
+package scheduler
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func Test_parseInjection(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		in      string
+		want    Process
+		wantErr error
+	}{
+		{in: "at=50,pid=99,burst=10,priority=1", want: Process{ProcessID: 99, ArrivalTime: 50, BurstDuration: 10, Priority: 1}},
+		{in: "pid=7, burst=3, at=0", want: Process{ProcessID: 7, BurstDuration: 3}},
+		{in: "at=5,pid=7", wantErr: ErrInvalidArgs},
+		{in: "at=5,pid=7,burst=x", wantErr: ErrInvalidArgs},
+		{in: "at=5,pid=7,burst=3,nice=2", wantErr: ErrInvalidArgs},
+		{in: "at=5,pid", wantErr: ErrInvalidArgs},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.in, func(t *testing.T) {
+			t.Parallel()
+			got, err := parseInjection(tt.in)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("parseInjection() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseInjection() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSimulate_injected(t *testing.T) {
+	t.Parallel()
+	processes := []Process{{ProcessID: 1, BurstDuration: 4}, {ProcessID: 2, BurstDuration: 4}}
+	injected := []Process{{ProcessID: 9, ArrivalTime: 2, BurstDuration: 1}}
+	// Round-robin's quantum comes from the workload it was built for, so the
+	// one-tick injected process does not shrink it from 4.
+	got := Simulate(injectProcesses(processes, injected), newRRPolicy(processes, RROptions{}), EngineOptions{})
+	want := []TimeSlice{{PID: 1, Start: 0, Stop: 4}, {PID: 2, Start: 4, Stop: 8}, {PID: 9, Start: 8, Stop: 9}}
+	if !reflect.DeepEqual(got.Gantt, want) {
+		t.Errorf("Gantt = %v, want %v", got.Gantt, want)
+	}
+}
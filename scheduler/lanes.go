@@ -0,0 +1,149 @@
+package scheduler
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// laneState is what a process is doing at a moment of its lane. Higher
+// states win a column that covers several.
+type laneState int
+
+const (
+	laneAbsent laneState = iota
+	laneWaiting
+	laneSuspended
+	laneBlocked
+	laneRunning
+)
+
+// laneMarks draws each state, and laneDone a process's completion.
+var laneMarks = [...]byte{laneAbsent: ' ', laneWaiting: '.', laneSuspended: '~', laneBlocked: '-', laneRunning: '#'}
+
+const laneDone = 'X'
+
+// laneChange is a process entering a state at Time.
+type laneChange struct {
+	Time  int64
+	State laneState
+}
+
+// laneChanges replays events into each process's changes of state, with the
+// PIDs in the order they arrived and the time each completed.
+func laneChanges(events []Event) (pids []int64, changes map[int64][]laneChange, done map[int64]int64) {
+	changes, done = map[int64][]laneChange{}, map[int64]int64{}
+	for _, ev := range events {
+		var state laneState
+		switch ev.Kind {
+		case EventArrive, EventPreempt, EventExpire, EventWake, EventResume:
+			state = laneWaiting
+		case EventDispatch:
+			state = laneRunning
+		case EventBlock, EventLockWait, EventSemWait, EventDepWait:
+			state = laneBlocked
+		case EventSuspend:
+			state = laneSuspended
+		case EventComplete:
+			done[ev.PID] = ev.Time
+		default:
+			continue
+		}
+		if _, ok := changes[ev.PID]; !ok {
+			pids = append(pids, ev.PID)
+		}
+		changes[ev.PID] = append(changes[ev.PID], laneChange{Time: ev.Time, State: state})
+	}
+	return pids, changes, done
+}
+
+// outputLanes draws a timeline with a lane for each process, rather than
+// one for each CPU: '.' while it waits in a ready queue, '#' while it runs,
+// '-' while blocked, '~' while suspended and X where it completes. The chart
+// is scaled to fit opts.Width instead of wrapping, so the lanes line up.
+func outputLanes(w io.Writer, events []Event, opts GanttOptions) {
+	_, _ = fmt.Fprintln(w, "Timeline by process")
+	pids, changes, done := laneChanges(events)
+	if len(pids) == 0 {
+		_, _ = fmt.Fprintln(w)
+		return
+	}
+	origin, end := events[0].Time, events[0].Time
+	for _, ev := range events {
+		origin, end = minInt64(origin, ev.Time), maxInt64(end, ev.Time)
+	}
+	labels := make([]string, len(pids))
+	indent := 0
+	for i, pid := range pids {
+		labels[i] = fmt.Sprintf("P%d", pid)
+		if len(labels[i]) > indent {
+			indent = len(labels[i])
+		}
+	}
+	indent++
+	// The column of end holds completions, so it must fit as well.
+	width := opts.width() - indent
+	if width < minGanttWidth {
+		width = minGanttWidth
+	}
+	if span := end - origin; opts.Scale <= 0 && span >= int64(width) {
+		opts.Scale = (span + int64(width) - 2) / int64(width-1)
+	}
+	col := ganttColumns(origin, end, width, opts)
+
+	for i, pid := range pids {
+		lane := make([]laneState, col(end)+1)
+		cs := changes[pid]
+		for j, c := range cs {
+			until := end
+			if j+1 < len(cs) {
+				until = cs[j+1].Time
+			}
+			for t := c.Time; t < until; t++ {
+				for x := col(t); x == col(t) || x < col(t+1); x++ {
+					if c.State > lane[x] {
+						lane[x] = c.State
+					}
+				}
+			}
+		}
+		bar := make([]byte, len(lane))
+		colors := make([]int, len(lane))
+		for x, s := range lane {
+			bar[x] = laneMarks[s]
+			if s == laneRunning && opts.Color {
+				colors[x] = pidColor(pid)
+			}
+		}
+		if at, ok := done[pid]; ok {
+			bar[col(at)], colors[col(at)] = laneDone, 0
+		}
+		_, _ = fmt.Fprintf(w, "%-*s%s\n", indent, labels[i], strings.TrimRight(paint(bar, colors), " "))
+	}
+
+	axis := []byte(strings.Repeat(" ", indent))
+	// Without -gantt-ticks, label the times of the events that fit.
+	var ticks []int64
+	for _, ev := range events {
+		if n := len(ticks); n == 0 || ticks[n-1] != ev.Time {
+			ticks = append(ticks, ev.Time)
+		}
+	}
+	if opts.TickEvery > 0 {
+		ticks = ticks[:0]
+		for t := (origin + opts.TickEvery - 1) / opts.TickEvery * opts.TickEvery; t <= end; t += opts.TickEvery {
+			ticks = append(ticks, t)
+		}
+	}
+	for _, t := range ticks {
+		x := indent + col(t)
+		if x < len(axis) {
+			continue
+		}
+		axis = append(axis, strings.Repeat(" ", x-len(axis))...)
+		axis = append(axis, fmt.Sprint(t)...)
+		axis = append(axis, ' ')
+	}
+	_, _ = fmt.Fprintln(w, strings.TrimRight(string(axis), " "))
+	_, _ = fmt.Fprintf(w, "%s# running, . waiting, - blocked, ~ suspended, X completed\n\n", strings.Repeat(" ", indent))
+}
@@ -0,0 +1,50 @@
+package scheduler
+
+import (
+	"bytes"
+	"testing"
+)
+
+func Test_outputLanes(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		processes []Process
+		opts      GanttOptions
+		want      string
+	}{
+		{
+			name:      "waiting and running",
+			processes: []Process{{ProcessID: 1, BurstDuration: 3}, {ProcessID: 2, ArrivalTime: 1, BurstDuration: 2}},
+			opts:      GanttOptions{Width: 20, Scale: 1},
+			want:      "P1 ###X\nP2  ..##X\n   0  3 5\n",
+		},
+		{
+			name: "blocked",
+			processes: []Process{
+				{ProcessID: 1, BurstDuration: 2, Bursts: []int64{1, 2, 1}},
+				{ProcessID: 2, BurstDuration: 2},
+			},
+			opts: GanttOptions{Width: 20, Scale: 1},
+			want: "P1 #--#X\nP2 .##X\n   0  3\n",
+		},
+		{
+			name:      "scaled to fit",
+			processes: []Process{{ProcessID: 1, BurstDuration: 30}, {ProcessID: 2, BurstDuration: 30}},
+			opts:      GanttOptions{Width: 13},
+			want:      "P1 ####X\nP2 ....####X\n   0   30  60\n",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var w bytes.Buffer
+			outputLanes(&w, Simulate(tt.processes, fcfsPolicy{}, EngineOptions{}).Events, tt.opts)
+			want := "Timeline by process\n" + tt.want + "   # running, . waiting, - blocked, ~ suspended, X completed\n\n"
+			if w.String() != want {
+				t.Errorf("outputLanes() =\n%s\nwant\n%s", w.String(), want)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package scheduler
+
+import (
+	"fmt"
+	"io"
+	"math"
+)
+
+// littleTolerance is how far apart, relative to the larger, L and λW may be
+// before Little's Law counts as broken.
+const littleTolerance = 0.01
+
+// LittleCheck tests a run's accounting against Little's Law, L = λW, for
+// the ready queue: the time-average number of processes waiting in it, L,
+// measured from the queue-length samples, must equal the rate processes
+// pass through it, λ, times how long each waited on average, W, totalled
+// from the processes' own waits. The two are kept apart in the engine, so
+// a mismatch means one of them is miscounted.
+type LittleCheck struct {
+	// Checked is unset for a run that stopped with processes unfinished,
+	// whose waits are not all known.
+	Checked bool
+	L       float64
+	Lambda  float64
+	W       float64
+	Holds   bool
+}
+
+// CheckLittle checks result against Little's Law over the whole run,
+// warmup included.
+func CheckLittle(result Result) LittleCheck {
+	samples := result.QueueLength
+	if result.Truncated || len(result.Incomplete) > 0 || len(result.Schedule) == 0 || len(samples) == 0 {
+		return LittleCheck{}
+	}
+	var end int64
+	for _, row := range result.Schedule {
+		end = maxInt64(end, row.Exit)
+	}
+	end = maxInt64(end, samples[len(samples)-1].Time)
+	if end <= 0 {
+		return LittleCheck{}
+	}
+	// Each sample's ready length holds until the next sample.
+	var area, wait int64
+	for i, s := range samples {
+		until := end
+		if i+1 < len(samples) {
+			until = samples[i+1].Time
+		}
+		area += int64(s.Ready) * (until - s.Time)
+	}
+	for _, row := range result.Schedule {
+		wait += row.Wait
+	}
+	n := float64(len(result.Schedule))
+	c := LittleCheck{
+		Checked: true,
+		L:       float64(area) / float64(end),
+		Lambda:  n / float64(end),
+		W:       float64(wait) / n,
+	}
+	lw := c.Lambda * c.W
+	c.Holds = math.Abs(c.L-lw) <= littleTolerance*math.Max(c.L, lw)
+	return c
+}
+
+// outputLittle calls out a violation of Little's Law as the likely bug it
+// is. A check that holds prints nothing.
+func outputLittle(w io.Writer, c LittleCheck) {
+	if !c.Checked || c.Holds {
+		return
+	}
+	_, _ = fmt.Fprintf(w, "Little's Law (ready queue): L %.3f, λ %.3f × W %.3f = %.3f, VIOLATED: the engine's queue and wait accounting disagree, probably a bug\n",
+		c.L, c.Lambda, c.W, c.Lambda*c.W)
+}
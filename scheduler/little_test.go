@@ -0,0 +1,76 @@
+package scheduler
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestCheckLittle(t *testing.T) {
+	t.Parallel()
+	f, err := os.Open("../example_processes.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	processes, err := loadProcesses(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	blocking := []Process{
+		{ProcessID: 1, BurstDuration: 4, Priority: 2, Bursts: []int64{1, 3, 3}},
+		{ProcessID: 2, ArrivalTime: 1, BurstDuration: 5, Priority: 1},
+		{ProcessID: 3, ArrivalTime: 2, BurstDuration: 2, Priority: 3, Bursts: []int64{1, 1, 1}},
+	}
+	for _, workload := range [][]Process{processes, blocking} {
+		for _, a := range registry {
+			for _, cpus := range []int{1, 2} {
+				result := Simulate(workload, a.New(workload, AlgorithmOptions{}), EngineOptions{CPUs: cpus, DispatchCost: int64(cpus - 1)})
+				if c := CheckLittle(result); !c.Checked || !c.Holds {
+					t.Errorf("%s on %d CPUs: CheckLittle() = %+v", a.Name, cpus, c)
+				}
+			}
+		}
+	}
+}
+
+func TestCheckLittle_violation(t *testing.T) {
+	t.Parallel()
+	result := Simulate([]Process{NewProcess(1, 0, 2, 0), NewProcess(2, 0, 2, 0)}, fcfsPolicy{}, EngineOptions{})
+	if c := CheckLittle(result); !c.Holds || c.L != 0.5 || c.Lambda != 0.5 || c.W != 1 {
+		t.Errorf("CheckLittle() = %+v, want L 0.5 = 0.5 × 1", c)
+	}
+	// A wait miscounted by a tick breaks the law.
+	result.Schedule[1].Wait++
+	if c := CheckLittle(result); c.Holds {
+		t.Errorf("CheckLittle() with a miscounted wait = %+v, want a violation", c)
+	}
+	result.Truncated = true
+	if c := CheckLittle(result); c.Checked {
+		t.Errorf("CheckLittle() of a truncated run = %+v, want it unchecked", c)
+	}
+}
+
+func Test_outputLittle(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		c    LittleCheck
+		want string
+	}{
+		{name: "unchecked"},
+		{name: "holds", c: LittleCheck{Checked: true, Holds: true, L: 0.5, Lambda: 0.5, W: 1}},
+		{
+			name: "violated",
+			c:    LittleCheck{Checked: true, L: 0.5, Lambda: 0.5, W: 1.5},
+			want: "Little's Law (ready queue): L 0.500, λ 0.500 × W 1.500 = 0.750, VIOLATED: the engine's queue and wait accounting disagree, probably a bug\n",
+		},
+	}
+	for _, tt := range tests {
+		var w strings.Builder
+		outputLittle(&w, tt.c)
+		if w.String() != tt.want {
+			t.Errorf("%s: outputLittle() = %q, want %q", tt.name, w.String(), tt.want)
+		}
+	}
+}
@@ -0,0 +1,463 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// LockUse is a process holding a shared resource from Acquire until Release,
+// both measured in CPU time the process has received.
+type LockUse struct {
+	Resource string `json:"resource"`
+	// Count is how many instances of the resource are held, when it has
+	// several and more than one is wanted.
+	Count   int64 `json:"count,omitempty"`
+	Acquire int64 `json:"acquire"`
+	Release int64 `json:"release"`
+}
+
+// instances is how many instances of its resource u holds.
+func (u LockUse) instances() int64 {
+	if u.Count > 1 {
+		return u.Count
+	}
+	return 1
+}
+
+// LockProtocol decides how holding a lock raises a process's priority, to
+// bound how long a more urgent process waits behind a less urgent one.
+type LockProtocol int
+
+const (
+	// LockNone leaves priorities alone, so priority inversion can occur: a
+	// medium process preempts the low one holding a lock a high one needs.
+	LockNone LockProtocol = iota
+	// LockInherit raises a lock holder to the priority of the most urgent
+	// process waiting for any lock it holds.
+	LockInherit
+	// LockCeiling raises a process, as soon as it takes a lock, to the
+	// priority of the most urgent process that ever uses that lock.
+	LockCeiling
+)
+
+var ErrInvalidLock = errors.New("invalid lock")
+
+func (p LockProtocol) String() string {
+	switch p {
+	case LockInherit:
+		return "inherit"
+	case LockCeiling:
+		return "ceiling"
+	default:
+		return "none"
+	}
+}
+
+func parseLockProtocol(s string) (LockProtocol, error) {
+	switch s {
+	case "", "none":
+		return LockNone, nil
+	case "inherit":
+		return LockInherit, nil
+	case "ceiling":
+		return LockCeiling, nil
+	default:
+		return LockNone, fmt.Errorf("%w: unknown lock protocol %q", ErrInvalidArgs, s)
+	}
+}
+
+// parseLockUses reads the locks column: space-separated resource:from-to,
+// or resource*count:from-to to hold several instances.
+func parseLockUses(s string) ([]LockUse, error) {
+	var uses []LockUse
+	for _, f := range strings.Fields(s) {
+		name, span, ok := strings.Cut(f, ":")
+		from, to, ok2 := strings.Cut(span, "-")
+		if !ok || !ok2 || name == "" {
+			return nil, fmt.Errorf("%w: %q is not resource:from-to", ErrInvalidLock, f)
+		}
+		var count int64
+		name, n, counted := strings.Cut(name, "*")
+		if counted {
+			c, err := strconv.ParseInt(n, 10, 64)
+			if err != nil || c < 1 || name == "" {
+				return nil, fmt.Errorf("%w: %q is not resource*count:from-to with a positive count", ErrInvalidLock, f)
+			}
+			count = c
+		}
+		acquire, err := strconv.ParseInt(from, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q: %v", ErrInvalidLock, f, errors.Unwrap(err))
+		}
+		release, err := strconv.ParseInt(to, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q: %v", ErrInvalidLock, f, errors.Unwrap(err))
+		}
+		uses = append(uses, LockUse{Resource: name, Count: count, Acquire: acquire, Release: release})
+	}
+	return uses, nil
+}
+
+func formatLockUses(uses []LockUse) string {
+	fields := make([]string, len(uses))
+	for i, u := range uses {
+		name := u.Resource
+		if u.Count > 1 {
+			name += "*" + strconv.FormatInt(u.Count, 10)
+		}
+		fields[i] = fmt.Sprintf("%s:%d-%d", name, u.Acquire, u.Release)
+	}
+	return strings.Join(fields, " ")
+}
+
+// lockProblem describes what is wrong with a process's lock uses, if
+// anything.
+func lockProblem(p Process) string {
+	var cpu int64
+	if len(p.Bursts) == 0 {
+		cpu = p.BurstDuration
+	}
+	for i := 0; i < len(p.Bursts); i += 2 {
+		cpu += p.Bursts[i]
+	}
+	for i, u := range p.Locks {
+		if u.Count < 0 {
+			return fmt.Sprintf("lock %s wants %d instances", u.Resource, u.Count)
+		}
+		if u.Acquire < 0 || u.Release <= u.Acquire || u.Release > cpu {
+			return fmt.Sprintf("lock %s held over [%d,%d), outside its %d ticks of CPU", u.Resource, u.Acquire, u.Release, cpu)
+		}
+		for _, v := range p.Locks[:i] {
+			if v.Resource == u.Resource && u.Acquire < v.Release && v.Acquire < u.Release {
+				return fmt.Sprintf("lock %s taken again while already held", u.Resource)
+			}
+		}
+	}
+	return ""
+}
+
+type (
+	// lockOp is a point in a task's CPU time where it takes or gives back
+	// count instances of a lock.
+	lockOp struct {
+		at       int64
+		resource string
+		count    int64
+		acquire  bool
+	}
+	lock struct {
+		// instances is how many the resource has: one for a plain lock.
+		instances int64
+		holders   []lockHold
+		waiters   []*Task
+		// ceiling is the most urgent priority of any task using the lock.
+		ceiling int64
+	}
+	// lockHold is a task holding count instances of a lock.
+	lockHold struct {
+		task  *Task
+		count int64
+	}
+)
+
+// free is how many of l's instances nobody holds.
+func (l *lock) free() int64 {
+	free := l.instances
+	for _, h := range l.holders {
+		free -= h.count
+	}
+	return free
+}
+
+// heldBy is how many of l's instances t holds.
+func (l *lock) heldBy(t *Task) int64 {
+	for _, h := range l.holders {
+		if h.task == t {
+			return h.count
+		}
+	}
+	return 0
+}
+
+func (l *lock) holderPIDs() []int64 {
+	pids := make([]int64, len(l.holders))
+	for i, h := range l.holders {
+		pids[i] = h.task.ProcessID
+	}
+	return pids
+}
+
+func (e *engine) initLocks() {
+	e.locks = map[string]*lock{}
+	for _, t := range e.tasks {
+		for _, u := range t.Locks {
+			l, ok := e.locks[u.Resource]
+			if !ok {
+				l = &lock{instances: 1, ceiling: t.Priority}
+				if n := e.opts.Resources[u.Resource]; n > 1 {
+					l.instances = n
+				}
+				e.locks[u.Resource] = l
+				e.lockNames = append(e.lockNames, u.Resource)
+			}
+			if t.Priority < l.ceiling {
+				l.ceiling = t.Priority
+			}
+			t.lockOps = append(t.lockOps,
+				lockOp{at: u.Acquire, resource: u.Resource, count: u.instances(), acquire: true},
+				lockOp{at: u.Release, resource: u.Resource, count: u.instances()})
+		}
+		// Give locks back before taking new ones at the same moment.
+		sort.SliceStable(t.lockOps, func(i, j int) bool {
+			a, b := t.lockOps[i], t.lockOps[j]
+			if a.at != b.at {
+				return a.at < b.at
+			}
+			return !a.acquire && b.acquire
+		})
+		t.claims = maxClaims(t.Locks)
+	}
+	sort.Strings(e.lockNames)
+}
+
+// runLocks takes and gives back the locks running tasks have reached. A task
+// that finds its lock taken leaves the CPU to wait for it.
+func (e *engine) runLocks() {
+	running := e.running[:0]
+	for _, t := range e.running {
+		if e.runTaskLocks(t) {
+			running = append(running, t)
+		}
+	}
+	e.running = running
+}
+
+// runTaskLocks runs t's lock operations due now and reports whether it is
+// still running afterwards.
+func (e *engine) runTaskLocks(t *Task) bool {
+	for t.nextOp < len(t.lockOps) && t.lockOps[t.nextOp].at == t.cpuDone {
+		op := t.lockOps[t.nextOp]
+		if !op.acquire {
+			t.nextOp++
+			e.release(t, op.resource)
+			continue
+		}
+		l := e.locks[op.resource]
+		if e.grantable(t, op) {
+			t.nextOp++
+			e.acquire(t, op.resource, op.count)
+			continue
+		}
+		e.endSlice(t)
+		e.freeCPUs(t)
+		t.waitingFor = op.resource
+		l.waiters = append(l.waiters, t)
+		// Under DeadlockAvoid a process can be kept waiting with enough free.
+		unsafe := l.free() >= op.count
+		e.recordEvent(Event{Time: e.now, Kind: EventLockWait, PID: t.ProcessID, CPU: t.cpu, Resource: op.resource, Count: countOf(op.count), Unsafe: unsafe})
+		if unsafe {
+			e.opts.Log.Log(e.now, "wait for lock", "pid", t.ProcessID, "resource", op.resource, "reason", "unsafe to grant")
+		} else {
+			e.opts.Log.Log(e.now, "wait for lock", "pid", t.ProcessID, "resource", op.resource, "holder", formatPIDs(l.holderPIDs()))
+		}
+		for _, h := range l.holders {
+			e.updatePriority(h.task)
+		}
+		e.detectDeadlock(t)
+		return false
+	}
+	return true
+}
+
+// grantable reports whether t may take the lock op asks for now: enough
+// instances are free and, under DeadlockAvoid, granting them leaves the
+// system safe.
+func (e *engine) grantable(t *Task, op lockOp) bool {
+	if e.locks[op.resource].free() < op.count {
+		return false
+	}
+	return e.opts.Deadlock != DeadlockAvoid || e.safeAfter(t, op.resource, op.count)
+}
+
+func (e *engine) acquire(t *Task, resource string, count int64) {
+	l := e.locks[resource]
+	l.holders = append(l.holders, lockHold{task: t, count: count})
+	t.held = append(t.held, resource)
+	e.recordEvent(Event{Time: e.now, Kind: EventAcquire, PID: t.ProcessID, Resource: resource, Count: countOf(count)})
+	e.updatePriority(t)
+}
+
+// release gives back what t holds of a lock and hands the instances freed
+// to the waiters that can now have them.
+func (e *engine) release(t *Task, resource string) {
+	l := e.locks[resource]
+	for i, name := range t.held {
+		if name == resource {
+			t.held = append(t.held[:i], t.held[i+1:]...)
+			break
+		}
+	}
+	var count int64
+	for i, h := range l.holders {
+		if h.task == t {
+			count = h.count
+			l.holders = append(l.holders[:i], l.holders[i+1:]...)
+			break
+		}
+	}
+	e.recordEvent(Event{Time: e.now, Kind: EventRelease, PID: t.ProcessID, Resource: resource, Count: countOf(count)})
+	e.updatePriority(t)
+	e.grantWaiters(l)
+}
+
+// grantWaiters hands l to its waiters, each time to the one the policy would
+// dispatch first of those it can be granted to, which becomes ready. Under
+// DeadlockAvoid, giving back any lock can make another wait safe to end, so
+// the waiters for every lock are considered.
+func (e *engine) grantWaiters(l *lock) {
+	locks := []*lock{l}
+	if e.opts.Deadlock == DeadlockAvoid {
+		locks = locks[:0]
+		for _, name := range e.lockNames {
+			locks = append(locks, e.locks[name])
+		}
+	}
+	for {
+		var (
+			best     *Task
+			bestLock *lock
+			bestAt   int
+		)
+		for _, l := range locks {
+			for i, w := range l.waiters {
+				if !e.grantable(w, w.lockOps[w.nextOp]) {
+					continue
+				}
+				if best == nil || e.policy.Less(w, best) {
+					best, bestLock, bestAt = w, l, i
+				}
+			}
+		}
+		if best == nil {
+			return
+		}
+		bestLock.waiters = append(bestLock.waiters[:bestAt], bestLock.waiters[bestAt+1:]...)
+		op := best.lockOps[best.nextOp]
+		best.waitingFor = ""
+		best.nextOp++
+		e.acquire(best, op.resource, op.count)
+		e.record(EventWake, best)
+		e.enqueue(best)
+	}
+}
+
+func (e *engine) releaseAll(t *Task) {
+	for len(t.held) > 0 {
+		e.release(t, t.held[0])
+	}
+}
+
+// countOf is the Count of a lock event for count instances: left out for
+// one.
+func countOf(count int64) int64 {
+	if count > 1 {
+		return count
+	}
+	return 0
+}
+
+// effectivePriority is t's priority less any levels it gained by aging or
+// coming back from I/O, raised further by the locks it holds.
+func (e *engine) effectivePriority(t *Task) int64 {
+	priority, _ := e.inheritedPriority(t)
+	return priority
+}
+
+// inheritedPriority is effectivePriority along with the waiter it was
+// inherited from, if any.
+func (e *engine) inheritedPriority(t *Task) (int64, *Task) {
+	var donor *Task
+	priority := t.Priority - t.aged - t.boosted
+	for _, name := range t.held {
+		l := e.locks[name]
+		switch e.opts.Locking {
+		case LockCeiling:
+			if l.ceiling < priority {
+				priority = l.ceiling
+			}
+		case LockInherit:
+			for _, w := range l.waiters {
+				if w.EffectivePriority < priority {
+					priority, donor = w.EffectivePriority, w
+				}
+			}
+		}
+	}
+	return priority, donor
+}
+
+// updatePriority recomputes t's effective priority from the locks it holds
+// and passes a change on to whoever holds the lock t is waiting for.
+func (e *engine) updatePriority(t *Task) {
+	priority, donor := e.inheritedPriority(t)
+	if priority == t.EffectivePriority {
+		return
+	}
+	t.EffectivePriority = priority
+	e.donate(t, donor)
+	e.recordEvent(Event{Time: e.now, Kind: EventPriority, PID: t.ProcessID, Priority: priority})
+	if t.queued {
+		e.queues[t.queue].Fix(t)
+	}
+	if t.waitingFor == "" {
+		return
+	}
+	for _, h := range e.locks[t.waitingFor].holders {
+		e.updatePriority(h.task)
+	}
+}
+
+func (e *engine) lockWaiters() int {
+	n := 0
+	for _, l := range e.locks {
+		n += len(l.waiters)
+	}
+	return n
+}
+
+// outputLocks lists who took, waited for and gave back each lock, and the
+// priority changes that caused.
+func outputLocks(w io.Writer, events []Event) {
+	header := false
+	for _, ev := range events {
+		var line string
+		resource := ev.Resource
+		if ev.Count > 1 {
+			resource = fmt.Sprintf("%d of %s", ev.Count, ev.Resource)
+		}
+		switch ev.Kind {
+		case EventAcquire:
+			line = fmt.Sprintf("P%d takes %s", ev.PID, resource)
+		case EventLockWait:
+			line = fmt.Sprintf("P%d waits for %s", ev.PID, resource)
+			if ev.Unsafe {
+				line += ", unsafe to grant"
+			}
+		case EventRelease:
+			line = fmt.Sprintf("P%d gives back %s", ev.PID, resource)
+		case EventPriority:
+			line = fmt.Sprintf("P%d now runs at priority %d", ev.PID, ev.Priority)
+		default:
+			continue
+		}
+		if !header {
+			_, _ = fmt.Fprintln(w, "Locks:")
+			header = true
+		}
+		_, _ = fmt.Fprintf(w, "  t=%d %s\n", ev.Time, line)
+	}
+}
@@ -0,0 +1,96 @@
+package scheduler
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSimulate_lockProtocol(t *testing.T) {
+	t.Parallel()
+	// The textbook inversion: low-priority 1 holds R when high-priority 2
+	// needs it, and medium-priority 3 arrives in between.
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 5, Priority: 3, Locks: []LockUse{{Resource: "R", Acquire: 1, Release: 4}}},
+		{ProcessID: 2, ArrivalTime: 2, BurstDuration: 2, Priority: 1, Locks: []LockUse{{Resource: "R", Acquire: 0, Release: 1}}},
+		{ProcessID: 3, ArrivalTime: 3, BurstDuration: 5, Priority: 2},
+	}
+	tests := []struct {
+		name     string
+		protocol LockProtocol
+		want     []TimeSlice
+	}{
+		{
+			name:     "inversion",
+			protocol: LockNone,
+			want: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 2}, {PID: 1, Start: 2, Stop: 3}, {PID: 3, Start: 3, Stop: 8},
+				{PID: 1, Start: 8, Stop: 9}, {PID: 2, Start: 9, Stop: 11}, {PID: 1, Start: 11, Stop: 12},
+			},
+		},
+		{
+			name:     "inheritance",
+			protocol: LockInherit,
+			want: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 2}, {PID: 1, Start: 2, Stop: 4, Donated: true}, {PID: 2, Start: 4, Stop: 6},
+				{PID: 3, Start: 6, Stop: 11}, {PID: 1, Start: 11, Stop: 12},
+			},
+		},
+		{
+			name:     "ceiling",
+			protocol: LockCeiling,
+			want: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 4}, {PID: 2, Start: 4, Stop: 6}, {PID: 3, Start: 6, Stop: 11}, {PID: 1, Start: 11, Stop: 12},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := Simulate(processes, priorityPolicy{}, EngineOptions{Locking: tt.protocol})
+			if !reflect.DeepEqual(got.Gantt, tt.want) {
+				t.Errorf("Gantt = %v, want %v", got.Gantt, tt.want)
+			}
+			if got.Deadlocked || len(got.Schedule) != len(processes) {
+				t.Errorf("not every process finished: %+v", got.Incomplete)
+			}
+		})
+	}
+}
+
+func TestSimulate_deadlock(t *testing.T) {
+	t.Parallel()
+	got := Simulate([]Process{
+		{ProcessID: 1, BurstDuration: 4, Locks: []LockUse{{Resource: "A", Acquire: 0, Release: 4}, {Resource: "B", Acquire: 2, Release: 3}}},
+		{ProcessID: 2, BurstDuration: 4, Locks: []LockUse{{Resource: "B", Acquire: 0, Release: 4}, {Resource: "A", Acquire: 1, Release: 2}}},
+	}, rrPolicy{quantum: 1}, EngineOptions{})
+	if !got.Deadlocked {
+		t.Fatalf("Deadlocked = false, Gantt %v", got.Gantt)
+	}
+	want := []Incomplete{{ProcessID: 1, Remaining: 2}, {ProcessID: 2, Remaining: 3}}
+	if !reflect.DeepEqual(got.Incomplete, want) {
+		t.Errorf("Incomplete = %v, want %v", got.Incomplete, want)
+	}
+}
+
+func Test_outputLocks(t *testing.T) {
+	t.Parallel()
+	var w strings.Builder
+	outputLocks(&w, Simulate([]Process{
+		{ProcessID: 1, BurstDuration: 3, Priority: 2, Locks: []LockUse{{Resource: "R", Acquire: 0, Release: 2}}},
+		{ProcessID: 2, ArrivalTime: 1, BurstDuration: 1, Priority: 1, Locks: []LockUse{{Resource: "R", Acquire: 0, Release: 1}}},
+	}, priorityPolicy{}, EngineOptions{Locking: LockInherit}).Events)
+	want := `Locks:
+  t=0 P1 takes R
+  t=1 P2 waits for R
+  t=1 P1 now runs at priority 1
+  t=2 P1 gives back R
+  t=2 P1 now runs at priority 2
+  t=2 P2 takes R
+  t=3 P2 gives back R
+`
+	if got := w.String(); got != want {
+		t.Errorf("outputLocks() =\n%s\nwant\n%s", got, want)
+	}
+}
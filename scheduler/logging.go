@@ -0,0 +1,41 @@
+package scheduler
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Logger writes the engine's decisions as logfmt lines, such as
+//
+//	t=4 msg=preempt pid=1 by=3
+//
+// so verbose runs can be read or filtered line by line. A nil Logger
+// discards everything.
+type Logger struct {
+	w io.Writer
+}
+
+func NewLogger(w io.Writer) *Logger { return &Logger{w: w} }
+
+// Log writes msg at time now with alternating keys and values.
+func (l *Logger) Log(now int64, msg string, kv ...interface{}) {
+	if l == nil {
+		return
+	}
+	var b strings.Builder
+	_, _ = fmt.Fprintf(&b, "t=%d msg=%s", now, logValue(msg))
+	for i := 0; i+1 < len(kv); i += 2 {
+		_, _ = fmt.Fprintf(&b, " %v=%s", kv[i], logValue(kv[i+1]))
+	}
+	_, _ = fmt.Fprintln(l.w, b.String())
+}
+
+// logValue formats v, quoting it if it has spaces or quotes.
+func logValue(v interface{}) string {
+	s := fmt.Sprint(v)
+	if s == "" || strings.ContainsAny(s, " \"=") {
+		return fmt.Sprintf("%q", s)
+	}
+	return s
+}
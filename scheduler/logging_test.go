@@ -0,0 +1,32 @@
+package scheduler
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSimulate_log(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 4, Priority: 2},
+		{ProcessID: 2, ArrivalTime: 1, BurstDuration: 1, Priority: 1},
+	}
+	var w bytes.Buffer
+	Simulate(processes, priorityPolicy{}, EngineOptions{Log: NewLogger(&w)})
+	want := `t=0 msg=dispatch pid=1 reason="only ready process" remaining=4 priority=2 waited=0
+t=1 msg=preempt pid=1 by=2 reason="ranks ahead by policy"
+t=1 msg=dispatch pid=2 reason="best of 2 ready by policy" remaining=1 priority=1 waited=0
+t=2 msg=complete pid=2
+t=2 msg=dispatch pid=1 reason="only ready process" remaining=3 priority=2 waited=1
+t=5 msg=complete pid=1
+`
+	if w.String() != want {
+		t.Errorf("log =\n%s\nwant\n%s", w.String(), want)
+	}
+}
+
+func TestLogger_nil(t *testing.T) {
+	t.Parallel()
+	var l *Logger
+	l.Log(0, "ignored", "pid", 1)
+}
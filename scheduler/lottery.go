@@ -0,0 +1,53 @@
+package scheduler
+
+import "math/rand"
+
+func init() {
+	Register("lottery", Factory{
+		Title:       "Lottery",
+		Description: "each tick draws a ready process at random, weighted by tickets from its nice value; -seed repeats the draws",
+		New:         func(_ []Process, o AlgorithmOptions) Policy { return newLotteryPolicy(newRand(o.Seed)) },
+	})
+}
+
+// lotteryPolicy holds a lottery for the CPU every tick, each process holding
+// as many tickets as its nice weight. Rather than count out tickets, every
+// ready process draws an exponential time divided by its tickets and the
+// smallest wins, which picks each with probability proportional to its
+// tickets. Draws are made when a process is first compared after an event,
+// so they hold for the whole of one dispatch decision.
+type lotteryPolicy struct {
+	rng   *rand.Rand
+	round int64
+	draws map[int64]lotteryDraw
+}
+
+type lotteryDraw struct {
+	round int64
+	key   float64
+}
+
+func newLotteryPolicy(rng *rand.Rand) *lotteryPolicy {
+	return &lotteryPolicy{rng: rng, draws: map[int64]lotteryDraw{}}
+}
+
+// Observe starts a new round of draws after every event.
+func (p *lotteryPolicy) Observe(Event) { p.round++ }
+
+func (p *lotteryPolicy) draw(t *Task) float64 {
+	d, ok := p.draws[t.ProcessID]
+	if !ok || d.round != p.round {
+		d = lotteryDraw{round: p.round, key: p.rng.ExpFloat64() / niceWeight(t.Nice)}
+		p.draws[t.ProcessID] = d
+	}
+	return d.key
+}
+
+func (p *lotteryPolicy) Less(a, b *Task) bool {
+	if ka, kb := p.draw(a), p.draw(b); ka != kb {
+		return ka < kb
+	}
+	return a.Seq < b.Seq
+}
+func (p *lotteryPolicy) Preemptive() bool { return false }
+func (p *lotteryPolicy) Quantum() int64   { return 1 }
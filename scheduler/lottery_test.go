@@ -0,0 +1,52 @@
+package scheduler
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLottery_seeded(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 3},
+		{ProcessID: 2, BurstDuration: 3},
+		{ProcessID: 3, ArrivalTime: 1, BurstDuration: 2},
+	}
+	lottery, _ := LookupAlgorithm("lottery")
+	run := func(seed int64) []TimeSlice {
+		return mergeGantt(Simulate(processes, lottery.New(processes, AlgorithmOptions{Seed: seed}), EngineOptions{}).Gantt)
+	}
+	got := run(1)
+	want := []TimeSlice{
+		{PID: 1, Start: 0, Stop: 2}, {PID: 2, Start: 2, Stop: 3}, {PID: 1, Start: 3, Stop: 4}, {PID: 3, Start: 4, Stop: 5},
+		{PID: 2, Start: 5, Stop: 6}, {PID: 3, Start: 6, Stop: 7}, {PID: 2, Start: 7, Stop: 8},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Gantt with seed 1 = %v, want %v", got, want)
+	}
+	if again := run(1); !reflect.DeepEqual(again, got) {
+		t.Errorf("Gantt with seed 1 again = %v, want %v", again, got)
+	}
+	if other := run(2); reflect.DeepEqual(other, got) {
+		t.Errorf("Gantt with seed 2 = %v, the same as with seed 1", other)
+	}
+}
+
+func TestLottery_tickets(t *testing.T) {
+	t.Parallel()
+	// Nice -5 holds 1.25^5, about 3.05, times the tickets of nice 0.
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 4000},
+		{ProcessID: 2, BurstDuration: 4000, Nice: -5},
+	}
+	result := Simulate(processes, newLotteryPolicy(newRand(7)), EngineOptions{MaxTime: 4000})
+	var rich int64
+	for _, s := range result.Gantt {
+		if s.PID == 2 {
+			rich += s.Stop - s.Start
+		}
+	}
+	if share, want := float64(rich)/4000, 1.25*1.25*1.25*1.25*1.25/(1+1.25*1.25*1.25*1.25*1.25); share < want-0.03 || share > want+0.03 {
+		t.Errorf("nice -5 share = %.3f, want %.3f", share, want)
+	}
+}
@@ -0,0 +1,1266 @@
+package scheduler
+
+import (
+	"bufio"
+	"bytes"
+	"database/sql"
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+// platformMain, when set, replaces the command line on platforms that have
+// none, such as the browser.
+var platformMain func()
+
+// Main runs the command line in os.Args: a subcommand, or the algorithms
+// its flags select over a process file. It exits the process on errors.
+func Main() {
+	if platformMain != nil {
+		platformMain()
+		return
+	}
+
+	// Subcommands
+	if len(os.Args) > 1 {
+		if run, ok := subcommands[os.Args[1]]; ok {
+			// -h has printed the usage, as the flag package does before
+			// exiting 0 for the top-level flags.
+			if err := run(os.Stdout, os.Args[2:]); err != nil && !errors.Is(err, flag.ErrHelp) {
+				fatal(err)
+			}
+			return
+		}
+	}
+
+	// CLI args
+	fs := flag.NewFlagSet(os.Args[0], flag.ExitOnError)
+	c, err := parseCLIFlags(fs, os.Args[1:])
+	if err != nil {
+		fatal(err)
+	}
+	runs, algoOpts, engineOpts := c.runs, c.algoOpts, c.engineOpts
+	var history *sql.DB
+	if c.dbPath != "" {
+		if history, err = openHistory(c.dbPath); err != nil {
+			fatal(err)
+		}
+		defer history.Close()
+	}
+	f, closeFile, err := openProcessingFile(append(os.Args[:1:1], fs.Args()...)...)
+	if err != nil {
+		fatal(err)
+	}
+	defer closeFile()
+
+	// Load and parse processes
+	loaded, err := c.loadWorkload(f)
+	if err != nil {
+		fatal(err)
+	}
+	processes, workload, expansion := loaded.processes, loaded.workload, loaded.expansion
+
+	if c.forkPoint != "" {
+		if len(runs) != 1 || c.policyFile != "" {
+			fatal(fmt.Errorf("%w: -fork-point needs exactly one algorithm from -algo", ErrInvalidArgs))
+		}
+		run, opts := runs[0], engineOpts
+		if c.freqAuto {
+			opts.Energy.Frequency = lowestEDPFrequency(workload, func() Policy { return run.New(processes, algoOpts) }, opts)
+		}
+		fp, err := NewForkPoint(workload, run.Name, algoOpts, opts, c.forkAt)
+		if err != nil {
+			fatal(err)
+		}
+		if err := writeFile(c.forkPoint, func(w io.Writer) error { return writeForkPoint(w, fp) }); err != nil {
+			fatal(err)
+		}
+		outputForkPoint(os.Stdout, c.forkPoint, fp)
+		return
+	}
+
+	if c.perturb > 0 {
+		opts := PerturbOptions{Runs: c.perturb, Jitter: c.jitter, Seed: *c.seed, Parallel: c.parallel}
+		outputPerturb(os.Stdout, opts, Perturb(workload, runs, algoOpts, engineOpts, opts))
+		return
+	}
+
+	if !c.quiet && c.tmpl == nil {
+		outputExpansion(os.Stdout, expansion)
+		outputInjections(os.Stdout, c.inject)
+	}
+
+	// Run the schedulers side by side, then print them in turn. Each run
+	// logs to its own buffer so -v output stays in order.
+	type outcome struct {
+		policy Policy
+		result Result
+		// global is the run repeated with a global queue, to contrast with
+		// per-core queues, free without the dispatch cost, and unboosted
+		// without the I/O boost.
+		global    Result
+		free      Result
+		unboosted Result
+		log       bytes.Buffer
+	}
+	outcomes := make([]outcome, len(runs))
+	// Long runs c.report how far they have got, so they don't look hung.
+	var progress *progressReporter
+	total := len(workload)
+	if engineOpts.Closed.closed() {
+		total *= engineOpts.Closed.Jobs
+	}
+	if !c.noProgress && total >= progressThreshold {
+		algorithms := make([]string, len(runs))
+		for i, run := range runs {
+			algorithms[i] = run.Name
+		}
+		progress = newProgressReporter(os.Stderr, algorithms, total)
+	}
+	forEachParallel(len(runs), c.parallel, func(i int) {
+		run, o, opts := runs[i], &outcomes[i], engineOpts
+		if c.verbose {
+			opts.Log = NewLogger(&o.log)
+		}
+		opts.Log.Log(0, "simulate", "algorithm", run.Name)
+		if c.freqAuto {
+			opts.Energy.Frequency = lowestEDPFrequency(workload, func() Policy { return run.New(processes, algoOpts) }, opts)
+		}
+		o.policy = run.New(processes, algoOpts)
+		if progress != nil {
+			opts.OnProgress = progress.track(i)
+		}
+		if c.streamGantt == "" {
+			o.result = SimulateTimeout(workload, o.policy, opts, c.timeout)
+		} else {
+			err := writeFile(perAlgorithmPath(c.streamGantt, run.Name, len(runs) > 1), func(w io.Writer) error {
+				sw := newSliceWriter(w)
+				opts.OnSlice, opts.DiscardGantt, opts.DiscardEvents = sw.write, true, true
+				o.result = SimulateTimeout(workload, o.policy, opts, c.timeout)
+				return sw.flush()
+			})
+			if err != nil {
+				fatal(err)
+			}
+		}
+		o.result.Custom = MeasureMetrics(c.metrics, o.result.Events)
+		if !c.quiet && c.tmpl == nil && o.result.Cores.PerCore {
+			globalOpts := opts
+			globalOpts.Balance, globalOpts.Log, globalOpts.OnSlice, globalOpts.OnProgress = BalanceGlobal, nil, nil, nil
+			o.global = SimulateTimeout(workload, run.New(processes, algoOpts), globalOpts, c.timeout)
+		}
+		if !c.quiet && c.tmpl == nil && opts.DispatchCost > 0 {
+			freeOpts := opts
+			freeOpts.DispatchCost, freeOpts.Log, freeOpts.OnSlice, freeOpts.OnProgress = 0, nil, nil, nil
+			o.free = SimulateTimeout(workload, run.New(processes, algoOpts), freeOpts, c.timeout)
+		}
+		if !c.quiet && c.tmpl == nil && opts.IOBoost > 0 {
+			unboostedOpts := opts
+			unboostedOpts.IOBoost, unboostedOpts.Log, unboostedOpts.OnSlice, unboostedOpts.OnProgress = 0, nil, nil, nil
+			o.unboosted = SimulateTimeout(workload, run.New(processes, algoOpts), unboostedOpts, c.timeout)
+		}
+	})
+	if progress != nil {
+		progress.finish()
+	}
+	var (
+		names   []string
+		titles  []string
+		results []Result
+	)
+	for i, run := range runs {
+		policy, result := outcomes[i].policy, outcomes[i].result
+		_, _ = os.Stderr.Write(outcomes[i].log.Bytes())
+		if result.Overflowed {
+			fatal(fmt.Errorf("%s: %w", run.Name, ErrTimeOverflow))
+		}
+		if result.TimedOut {
+			_, _ = fmt.Fprintf(os.Stderr, "warning: %s timed out after %v; its schedule is partial, with %d processes incomplete\n", run.Name, c.timeout, len(result.Incomplete))
+		}
+		ganttFile := ""
+		if c.streamGantt != "" {
+			ganttFile = perAlgorithmPath(c.streamGantt, run.Name, len(runs) > 1)
+		}
+		names = append(names, run.Name)
+		titles = append(titles, run.Title)
+		results = append(results, result)
+		if c.summaryOnly != "" {
+			if err := writeSummaryTSV(os.Stdout, run.Name, result); err != nil {
+				fatal(err)
+			}
+			continue
+		}
+		if c.animate {
+			Animate(os.Stdout, run.Title, result, AnimateOptions{
+				Speed: c.animateSpeed,
+				Clear: ansiTerminal(os.Stdout),
+				Gantt: c.ganttFlags.options(os.Stdout),
+			})
+		}
+		if c.tmpl != nil {
+			if err := renderTemplate(os.Stdout, c.tmpl, run, workload, result); err != nil {
+				fatal(err)
+			}
+			continue
+		}
+		Render(os.Stdout, result, RenderOptions{
+			Title:      run.Title,
+			MergeGantt: c.mergeGantt,
+			Lanes:      c.view == "lanes",
+			Gantt:      c.ganttFlags.options(os.Stdout),
+			Quiet:      c.quiet,
+			GanttFile:  ganttFile,
+			MaxRows:    c.maxRows,
+			Report:     c.report,
+		})
+		if loaded.threadsOf != nil && !c.quiet {
+			outputThreads(os.Stdout, threadedProcesses(result.Schedule, loaded.parents, loaded.threadsOf))
+		}
+		if r, ok := policy.(Reporter); ok && !c.quiet {
+			r.Report(os.Stdout)
+		}
+		// Per-core queues are judged against the global queue they replace.
+		if !c.quiet && result.Cores.PerCore {
+			outputGlobalContrast(os.Stdout, outcomes[i].global)
+		}
+		if !c.quiet && ganttFile == "" && (run.Name == "minshare" || c.minSharePct > 0) {
+			outputShareAudit(os.Stdout, auditMinShare(workload, result, algoOpts.MinShare))
+		}
+	}
+
+	// What the dispatch cost did to each algorithm, side by side
+	if !c.quiet && c.tmpl == nil && engineOpts.DispatchCost > 0 {
+		rows := make([]OverheadRow, len(runs))
+		for i := range runs {
+			rows[i] = NewOverheadRow(names[i], results[i], outcomes[i].free, engineOpts.CPUs)
+		}
+		outputOverhead(os.Stdout, engineOpts.DispatchCost, rows)
+	}
+
+	// What the I/O boost did for interactive processes, side by side
+	if !c.quiet && c.tmpl == nil && engineOpts.IOBoost > 0 {
+		rows := make([]BoostRow, len(runs))
+		for i := range runs {
+			rows[i] = NewBoostRow(names[i], results[i], outcomes[i].unboosted)
+		}
+		outputBoost(os.Stdout, engineOpts.IOBoost, rows)
+	}
+
+	// The experiment history
+	if history != nil {
+		hash, err := workloadHash(workload)
+		if err != nil {
+			fatal(err)
+		}
+		now := time.Now()
+		records := make([]HistoryRecord, len(results))
+		for i := range results {
+			records[i] = NewHistoryRecord(now, hash, names[i], scheduleParams(fs, names[i]), results[i])
+		}
+		if err := appendHistory(history, records); err != nil {
+			fatal(err)
+		}
+	}
+
+	// Files written alongside the output
+	if err := c.writeFiles(names, titles, results); err != nil {
+		fatal(err)
+	}
+
+	// Outcomes scripts can branch on, as the exit code. Exiting skips the
+	// deferred closes, so they are done first.
+	if code := outcomeCode(os.Stderr, workload, names, results, c.starvation); code != exitOK {
+		closeFile()
+		if history != nil {
+			_ = history.Close()
+		}
+		os.Exit(code)
+	}
+}
+
+// subcommands are the alternative modes selected by the first CLI argument.
+var subcommands = map[string]func(w io.Writer, args []string) error{
+	"alloc":     runAlloc,
+	"analyze":   runAnalyze,
+	"bench":     runBench,
+	"describe":  runDescribe,
+	"diff":      runDiff,
+	"disk":      runDisk,
+	"fork":      runFork,
+	"generate":  runGenerate,
+	"grade":     runGrade,
+	"history":   runHistory,
+	"import":    runImport,
+	"list":      runList,
+	"load":      runLoad,
+	"mem":       runMem,
+	"mm1":       runQueueing,
+	"normalize": runNormalize,
+	"optimal":   runOptimal,
+	"repl":      runRepl,
+	"run":       runExperiment,
+	"scenario":  runScenario,
+	"serve":     runServe,
+	"step":      runStep,
+	"sweep":     runSweep,
+	"verify":    runVerify,
+}
+
+// selectRuns picks the registered algorithms named in a comma-separated
+// list, keeping the list's order. An empty list selects the defaults.
+func selectRuns(spec string) ([]Algorithm, error) {
+	if spec == "" {
+		return defaultAlgorithms(), nil
+	}
+	var runs []Algorithm
+	for _, name := range strings.Split(spec, ",") {
+		algorithm, ok := LookupAlgorithm(strings.TrimSpace(name))
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown algorithm %q", ErrInvalidArgs, name)
+		}
+		runs = append(runs, algorithm)
+	}
+
+	return runs, nil
+}
+
+// stdin is read when the scheduling file is "-" or omitted with input piped in.
+var stdin = os.Stdin
+
+func openProcessingFile(args ...string) (*os.File, func(), error) {
+	if (len(args) == 2 && args[1] == "-") || (len(args) == 1 && isPiped(stdin)) {
+		return stdin, func() {}, nil
+	}
+	if len(args) != 2 {
+		return nil, nil, fmt.Errorf("%w: must give a scheduling file to process", ErrInvalidArgs)
+	}
+	// Read in CSV process CSV file
+	f, err := os.Open(args[1])
+	if err != nil {
+		return nil, nil, fmt.Errorf("%v: error opening scheduling file", err)
+	}
+	closeFn := func() {
+		if err := f.Close(); err != nil {
+			log.Fatalf("%v: error closing scheduling file", err)
+		}
+	}
+
+	return f, closeFn, nil
+}
+
+// writeFile creates p and fills it with write.
+func writeFile(p string, write func(w io.Writer) error) error {
+	f, err := os.Create(p)
+	if err != nil {
+		return fmt.Errorf("%v: error creating %s", err, p)
+	}
+	if err := write(f); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("%v: error closing %s", err, p)
+	}
+
+	return nil
+}
+
+// perAlgorithmPath names an output file for one algorithm. When several
+// algorithms share an output flag the algorithm goes before the extension,
+// so trace.txt becomes trace.rr.txt.
+func perAlgorithmPath(p, algorithm string, multiple bool) string {
+	if !multiple {
+		return p
+	}
+	ext := filepath.Ext(p)
+	return strings.TrimSuffix(p, ext) + "." + algorithm + ext
+}
+
+// isPiped reports whether f is a pipe or redirected file rather than a terminal.
+func isPiped(f *os.File) bool {
+	fi, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return fi.Mode()&os.ModeCharDevice == 0
+}
+
+type (
+	// Process is one entry of a workload. Its JSON and CSV names are the
+	// process file's column names, which the REST API uses too; optional
+	// fields are left out of JSON when unset.
+	Process struct {
+		ProcessID     int64 `json:"pid" csv:"pid"`
+		ArrivalTime   int64 `json:"arrival" csv:"arrival"`
+		BurstDuration int64 `json:"burst" csv:"burst"`
+		Priority      int64 `json:"priority" csv:"priority"`
+		// Class tags the kind of job a process models so results can be
+		// separated into batch and interactive work.
+		Class ProcessClass `json:"class,omitempty" csv:"class"`
+		// Bursts alternates CPU and I/O (think) durations, starting and ending
+		// with CPU, for processes whose BurstDuration is split up. The process
+		// is blocked, not waiting, during its I/O times.
+		Bursts []int64 `json:"bursts,omitempty" csv:"bursts"`
+		// Locks are the shared resources the process holds over parts of
+		// its CPU time.
+		Locks []LockUse `json:"locks,omitempty" csv:"locks"`
+		// DependsOn lists the PIDs that must complete before the process
+		// can start.
+		DependsOn []int64 `json:"after,omitempty" csv:"after"`
+		// Nice weights the process's round-robin quantum, from -20 (largest)
+		// to 19 (smallest), as in Unix.
+		Nice int64 `json:"nice,omitempty" csv:"nice"`
+		// Group is the user or group the process runs for; group fair-share
+		// divides the CPU between groups first.
+		Group string `json:"group,omitempty" csv:"group"`
+		// Threads is how many CPUs the process needs at once; zero means
+		// one. Its threads are gang scheduled: they all run or none do.
+		Threads int64 `json:"threads,omitempty" csv:"threads"`
+		// Affinity lists the CPUs the process may run on; empty allows
+		// any.
+		Affinity []int `json:"affinity,omitempty" csv:"affinity"`
+		// Deadline is how long after arriving the process must finish;
+		// zero means it has none.
+		Deadline int64 `json:"deadline,omitempty" csv:"deadline"`
+		// Period makes the process a periodic task, releasing a job of
+		// BurstDuration every Period ticks from ArrivalTime, each due
+		// Deadline (default Period) after its release.
+		Period int64 `json:"period,omitempty" csv:"period"`
+		// Suspend lists times the process is suspended from outside, as
+		// by Ctrl+Z, and later resumed, whatever the policy.
+		Suspend []Suspension `json:"suspend,omitempty" csv:"suspend"`
+		// ThreadBursts gives each thread its own CPU burst, replacing
+		// BurstDuration and Threads.
+		ThreadBursts []int64 `json:"thread_bursts,omitempty" csv:"thread_bursts"`
+		// Semaphores are the waits and signals the process makes on
+		// counting semaphores at points in its CPU time.
+		Semaphores []SemOp `json:"semaphores,omitempty" csv:"semaphores"`
+	}
+	// TimeSlice is one bar of the Gantt chart: PID ran from Start until
+	// Stop.
+	TimeSlice struct {
+		PID   int64 `json:"pid"`
+		Start int64 `json:"start"`
+		Stop  int64 `json:"stop"`
+		// CPU is the processor the slice ran on. A gang has one slice per
+		// CPU it held.
+		CPU int `json:"cpu,omitempty"`
+		// Donated is set when PID ran on a priority inherited from a
+		// process waiting for its lock.
+		Donated bool `json:"donated,omitempty"`
+	}
+	// ProcessResult is how one finished process fared: one line of the
+	// schedule table.
+	ProcessResult struct {
+		ProcessID  int64 `json:"pid" csv:"pid"`
+		Priority   int64 `json:"priority" csv:"priority"`
+		Burst      int64 `json:"burst" csv:"burst"`
+		Arrival    int64 `json:"arrival" csv:"arrival"`
+		Wait       int64 `json:"wait" csv:"wait"`
+		Turnaround int64 `json:"turnaround" csv:"turnaround"`
+		Exit       int64 `json:"exit" csv:"exit"`
+		// Class is the process's class, left out of JSON for batch.
+		Class ProcessClass `json:"class,omitempty" csv:"class"`
+		// Response is how long the process waited to first run.
+		Response int64 `json:"response" csv:"response"`
+		// SemaphoreWait is the time the process spent blocked on
+		// semaphores, which Wait, the time in the ready queue, leaves out.
+		SemaphoreWait int64 `json:"semaphore_wait,omitempty" csv:"semaphore_wait"`
+	}
+	// Result is the outcome of a scheduling run. Schedulers only compute it;
+	// Render is responsible for presenting it.
+	Result struct {
+		Schedule []ProcessResult `json:"schedule"`
+		Gantt    []TimeSlice     `json:"gantt"`
+		Metrics
+		// Percentiles are the tails of the waits, turnarounds and response
+		// times that the averages hide.
+		Percentiles Percentiles `json:"percentiles"`
+		// Slowdown is the mean and worst of turnaround over burst.
+		Slowdown SlowdownStats `json:"slowdown"`
+		Queue    QueueStats    `json:"queue"`
+		Fairness Fairness      `json:"fairness"`
+		// QueueLength samples the queues at every scheduling event, for
+		// plotting how the backlog evolves.
+		QueueLength []QueueSample `json:"queue_length"`
+		Events      []Event       `json:"events"`
+		// Shares compares the CPU time each process received with what its
+		// weight entitled it to.
+		Shares []ProcessShare `json:"shares,omitempty"`
+		// Groups is the CPU time used by each group, when processes name one.
+		Groups []GroupUsage `json:"groups,omitempty"`
+		// Cores summarises how busy the CPUs were, for runs on more than one.
+		Cores CoreStats `json:"cores"`
+		// Energy is what the run used, when an energy model was given.
+		Energy EnergyStats `json:"energy"`
+		// Deadlines measures the run against process deadlines, when any
+		// process has one.
+		Deadlines DeadlineStats `json:"deadlines"`
+		// Preemptions counts how often processes lost the CPU before their
+		// burst was done, and moved between CPUs.
+		Preemptions PreemptionStats `json:"preemptions"`
+		// Preemptive is whether the policy took the CPU back when a process
+		// it ranked higher became ready.
+		Preemptive bool `json:"preemptive"`
+		// Suspensions are when processes were suspended, by PID; the time
+		// is neither running nor waiting.
+		Suspensions []TimeSlice `json:"suspensions,omitempty"`
+		// Donations are the priorities lock holders inherited under
+		// -lock-protocol inherit.
+		Donations []Donation `json:"donations,omitempty"`
+		// Closed measures a closed run's throughput and response time.
+		Closed ClosedStats `json:"closed"`
+		// Warmup is what the averages and percentiles leave out.
+		Warmup WarmupStats `json:"warmup"`
+		// SwitchTime is the CPU time lost to context switches.
+		SwitchTime int64 `json:"switch_time"`
+		// Dispatches counts dispatch decisions, and DispatchTime is the CPU
+		// time they took.
+		Dispatches   int   `json:"dispatches"`
+		DispatchTime int64 `json:"dispatch_time"`
+		// Truncated is set when the run hit its time limit; Incomplete lists
+		// the processes that had arrived but not finished by then.
+		Truncated  bool         `json:"truncated"`
+		Incomplete []Incomplete `json:"incomplete,omitempty"`
+		// Cancelled is set, along with Truncated, when the run was stopped
+		// by its context, and TimedOut when that was its deadline passing.
+		Cancelled bool `json:"cancelled"`
+		TimedOut  bool `json:"timed_out,omitempty"`
+		// Deadlocked is set when the run ended with every remaining process
+		// waiting for a lock; they are listed in Incomplete.
+		Deadlocked bool `json:"deadlocked"`
+		// Deadlocks are the cycles of processes found waiting on each
+		// other, with -deadlock detect.
+		Deadlocks []Deadlock `json:"deadlocks,omitempty"`
+		// Overflowed is set, along with Truncated, when the run stopped
+		// because its next event came after the largest int64 time.
+		Overflowed bool `json:"overflowed,omitempty"`
+		// Custom holds the values of the custom metrics selected with
+		// -metrics, by metric and value name.
+		Custom map[string]map[string]float64 `json:"custom,omitempty"`
+	}
+	// Incomplete is a process left unfinished when a simulation stopped.
+	Incomplete struct {
+		ProcessID int64
+		Remaining int64
+	}
+	// QueueStats summarises queue lengths; averages are weighted by time.
+	QueueStats struct {
+		MaxReady       int
+		AverageReady   float64
+		MaxBlocked     int
+		AverageBlocked float64
+	}
+)
+
+//region Schedulers
+
+// NewProcess returns a process that needs burst ticks of CPU from arrival
+// on, with no I/O, locks or other options.
+func NewProcess(pid, arrival, burst, priority int64) Process {
+	return Process{ProcessID: pid, ArrivalTime: arrival, BurstDuration: burst, Priority: priority}
+}
+
+// FCFSSchedule outputs a schedule of processes in a GANTT chart and a table of timing given:
+// • an output writer
+// • a title for the chart
+// • a slice of processes
+func FCFSSchedule(w io.Writer, title string, processes []Process) {
+	Render(w, FCFS(processes), RenderOptions{Title: title})
+}
+
+// SJFSchedule outputs the shortest-job-first schedule like FCFSSchedule.
+func SJFSchedule(w io.Writer, title string, processes []Process) {
+	Render(w, SJF(processes), RenderOptions{Title: title})
+}
+
+// SJFPrioritySchedule outputs the priority schedule like FCFSSchedule.
+func SJFPrioritySchedule(w io.Writer, title string, processes []Process) {
+	Render(w, SJFPriority(processes), RenderOptions{Title: title})
+}
+
+// RRSchedule outputs the round-robin schedule like FCFSSchedule.
+func RRSchedule(w io.Writer, title string, processes []Process) {
+	Render(w, RR(processes), RenderOptions{Title: title})
+}
+
+// FCFS computes a first-come, first-serve schedule without printing it.
+func FCFS(processes []Process) Result {
+	return Simulate(processes, fcfsPolicy{}, EngineOptions{})
+}
+
+// SJFPriority computes a preemptive priority schedule, lower values first.
+func SJFPriority(processes []Process) Result {
+	return Simulate(processes, priorityPolicy{}, EngineOptions{})
+}
+
+// SJF computes a preemptive shortest-job-first schedule: a new arrival with a
+// shorter burst than what the running process has left takes the CPU.
+func SJF(processes []Process) Result {
+	return Simulate(processes, sjfPolicy{}, EngineOptions{})
+}
+
+// RR computes a round-robin schedule whose quantum is the shortest burst.
+func RR(processes []Process) Result {
+	return Simulate(processes, newRRPolicy(processes, RROptions{}), EngineOptions{})
+}
+
+// RROptions configures the round-robin scheduler.
+type RROptions struct {
+	// Quantum is the longest a process runs before going to the back of the
+	// queue. Zero uses the shortest burst in the workload.
+	Quantum int64
+	// SwitchCost is the CPU time lost every time the CPU changes process.
+	SwitchCost int64
+	// Quanta, if set, gives each priority level its own quantum in place
+	// of Quantum: the first is for the best priority in the workload, the
+	// lowest value, the next for the second best, and so on, the last
+	// standing for every level after it.
+	Quanta []int64
+}
+
+func newRRPolicy(processes []Process, opts RROptions) Policy {
+	return rrPolicy{quantum: rrQuantum(processes, opts), switchCost: opts.SwitchCost, levels: priorityQuanta(processes, opts.Quanta)}
+}
+
+// rrQuantum is opts.Quantum, or if it is not set the shortest burst in
+// processes.
+func rrQuantum(processes []Process, opts RROptions) int64 {
+	quantum := opts.Quantum
+	if quantum <= 0 {
+		for i := range processes {
+			if i == 0 || processes[i].BurstDuration < quantum {
+				quantum = processes[i].BurstDuration
+			}
+		}
+	}
+	return quantum
+}
+
+// priorityQuanta maps each priority in processes to its quantum from
+// quanta, best priority first, or returns nil if there are no quanta.
+func priorityQuanta(processes []Process, quanta []int64) map[int64]int64 {
+	if len(quanta) == 0 {
+		return nil
+	}
+	priorities := make([]int64, 0, len(processes))
+	for i := range processes {
+		priorities = append(priorities, processes[i].Priority)
+	}
+	sort.Slice(priorities, func(i, j int) bool { return priorities[i] < priorities[j] })
+	levels := map[int64]int64{}
+	for _, p := range priorities {
+		if _, ok := levels[p]; ok {
+			continue
+		}
+		i := len(levels)
+		if i >= len(quanta) {
+			i = len(quanta) - 1
+		}
+		levels[p] = quanta[i]
+	}
+	return levels
+}
+
+type (
+	fcfsPolicy     struct{}
+	sjfPolicy      struct{}
+	priorityPolicy struct{}
+	rrPolicy       struct {
+		quantum, switchCost int64
+		// levels is the quantum of each priority, if they differ.
+		levels map[int64]int64
+	}
+)
+
+func (fcfsPolicy) Less(a, b *Task) bool { return a.Seq < b.Seq }
+func (fcfsPolicy) Preemptive() bool     { return false }
+func (fcfsPolicy) Quantum() int64       { return 0 }
+func (fcfsPolicy) StableOrder() bool    { return true }
+
+func (sjfPolicy) Less(a, b *Task) bool {
+	if a.Remaining == b.Remaining {
+		return a.Seq < b.Seq
+	}
+	return a.Remaining < b.Remaining
+}
+func (sjfPolicy) Preemptive() bool  { return true }
+func (sjfPolicy) Quantum() int64    { return 0 }
+func (sjfPolicy) StableOrder() bool { return true }
+
+func (priorityPolicy) Less(a, b *Task) bool {
+	if a.EffectivePriority == b.EffectivePriority {
+		return a.Seq < b.Seq
+	}
+	return a.EffectivePriority < b.EffectivePriority
+}
+func (priorityPolicy) Preemptive() bool  { return true }
+func (priorityPolicy) Quantum() int64    { return 0 }
+func (priorityPolicy) StableOrder() bool { return true }
+
+func (rrPolicy) Less(a, b *Task) bool { return a.Seq < b.Seq }
+func (rrPolicy) Preemptive() bool     { return false }
+func (p rrPolicy) Quantum() int64     { return p.quantum }
+func (p rrPolicy) SwitchCost() int64  { return p.switchCost }
+func (p rrPolicy) TaskQuantum(t *Task) int64 {
+	quantum := p.quantum
+	if q, ok := p.levels[t.Priority]; ok {
+		quantum = q
+	}
+	return weightedQuantum(quantum, t.Nice)
+}
+func (rrPolicy) StableOrder() bool { return true }
+
+//endregion
+
+//region Output helpers
+
+// RenderOptions controls how Render presents a Result.
+type RenderOptions struct {
+	Title string
+	// MergeGantt joins back-to-back slices of the same process into one bar.
+	MergeGantt bool
+	// Gantt shapes the chart; its Color also colours the schedule table's
+	// rows to match.
+	Gantt GanttOptions
+	// Lanes draws a lane for each process in place of the Gantt chart.
+	Lanes bool
+	// Quiet leaves out everything but the title and schedule table.
+	Quiet bool
+	// GanttFile, if set, is where the Gantt slices were streamed instead of
+	// being kept; it is named in place of the chart.
+	GanttFile string
+	// MaxRows leaves out the schedule table's rows, but not its averages,
+	// when more processes than this finished; zero keeps every row.
+	MaxRows int
+	// Report picks the optional sections printed under the schedule table.
+	Report ReportSections
+}
+
+// Render writes result as a titled GANTT chart followed by the schedule table.
+func Render(w io.Writer, result Result, opts RenderOptions) {
+	gantt := result.Gantt
+	if opts.MergeGantt {
+		gantt = mergeGantt(gantt)
+	}
+	outputTitle(w, opts.Title)
+	switch {
+	case opts.Quiet:
+	case opts.GanttFile != "":
+		_, _ = fmt.Fprintf(w, "Gantt schedule streamed to %s\n\n", opts.GanttFile)
+	case opts.Lanes:
+		outputLanes(w, result.Events, opts.Gantt)
+	default:
+		outputGanttSuspended(w, gantt, result.Suspensions, opts.Gantt)
+	}
+	if opts.MaxRows > 0 && len(result.Schedule) > opts.MaxRows {
+		outputScheduleSummary(w, result, opts.Report.Has(ReportSlowdown))
+	} else {
+		outputSchedule(w, result.Schedule, result.AverageWait, result.AverageTurnaround, result.Throughput, result.Slowdown.Mean, opts.Report.Has(ReportSlowdown), opts.Gantt.Color)
+	}
+	outputWarmup(w, result.Warmup)
+	if opts.Quiet {
+		return
+	}
+	if opts.Report.Has(ReportPercentiles) {
+		outputPercentiles(w, result.Percentiles)
+	}
+	if opts.Report.Has(ReportSlowdown) {
+		outputSlowdown(w, result.Slowdown)
+	}
+	outputClasses(w, ClassAverages(result.Schedule))
+	outputClosed(w, result.Closed)
+	outputIncomplete(w, result)
+	outputLocks(w, result.Events)
+	outputDeadlocks(w, result.Deadlocks)
+	outputSemaphores(w, result.Events, result.Schedule)
+	outputDonations(w, result.Donations)
+	outputAging(w, result.Events)
+	if opts.Report.Has(ReportConvoys) {
+		outputConvoys(w, DetectConvoys(result), result.Schedule)
+	}
+	if opts.Report.Has(ReportQueue) {
+		outputQueueStats(w, result.Queue)
+	}
+	outputLittle(w, CheckLittle(result))
+	if opts.Report.Has(ReportFairness) {
+		outputFairness(w, result.Fairness, len(result.Schedule))
+	}
+	outputShares(w, result.Shares, result.Fairness)
+	outputGroups(w, result.Groups)
+	outputCores(w, result.Cores)
+	outputEnergy(w, result.Energy)
+	outputDeadlines(w, result.Deadlines)
+	if opts.Report.Has(ReportPreemptions) {
+		outputPreemptions(w, result.Preemptions)
+	}
+	if result.SwitchTime > 0 {
+		_, _ = fmt.Fprintf(w, "Context switch overhead: %d\n", result.SwitchTime)
+	}
+	if result.DispatchTime > 0 {
+		_, _ = fmt.Fprintf(w, "Dispatch overhead: %d over %d dispatches\n", result.DispatchTime, result.Dispatches)
+	}
+	outputCustomMetrics(w, result.Custom)
+}
+
+func outputIncomplete(w io.Writer, result Result) {
+	switch {
+	case result.Deadlocked:
+		_, _ = fmt.Fprintf(w, "Deadlock; %d incomplete", len(result.Incomplete))
+	case result.Overflowed:
+		_, _ = fmt.Fprintf(w, "Stopped at time overflow; %d incomplete", len(result.Incomplete))
+	case result.TimedOut:
+		_, _ = fmt.Fprintf(w, "Truncated at the timeout; %d incomplete", len(result.Incomplete))
+	case result.Cancelled:
+		_, _ = fmt.Fprintf(w, "Cancelled; %d incomplete", len(result.Incomplete))
+	case result.Deadlines.Aborted:
+		_, _ = fmt.Fprintf(w, "Stopped at a missed deadline; %d incomplete", len(result.Incomplete))
+	case result.Truncated:
+		_, _ = fmt.Fprintf(w, "Stopped at time limit; %d incomplete", len(result.Incomplete))
+	default:
+		return
+	}
+	for i, p := range result.Incomplete {
+		sep := ", "
+		if i == 0 {
+			sep = ": "
+		}
+		_, _ = fmt.Fprintf(w, "%sPID %d (%d remaining)", sep, p.ProcessID, p.Remaining)
+	}
+	_, _ = fmt.Fprintln(w)
+}
+
+func outputTitle(w io.Writer, title string) {
+	_, _ = fmt.Fprintln(w, strings.Repeat("-", len(title)*2))
+	_, _ = fmt.Fprintln(w, strings.Repeat(" ", len(title)/2), title)
+	_, _ = fmt.Fprintln(w, strings.Repeat("-", len(title)*2))
+}
+
+// mergeGantt joins slices where a process keeps the CPU without a break, such
+// as consecutive round-robin quanta with nothing else ready.
+func mergeGantt(gantt []TimeSlice) []TimeSlice {
+	merged := make([]TimeSlice, 0, len(gantt))
+	// last is the index in merged of each CPU's latest slice.
+	last := map[int]int{}
+	for _, s := range gantt {
+		if i, ok := last[s.CPU]; ok && merged[i].PID == s.PID && merged[i].Stop == s.Start && merged[i].Donated == s.Donated {
+			merged[i].Stop = s.Stop
+			continue
+		}
+		last[s.CPU] = len(merged)
+		merged = append(merged, s)
+	}
+
+	return merged
+}
+
+// outputSchedule prints the schedule table, with a Slowdown column and its
+// mean, slowdown, if withSlowdown is set.
+func outputSchedule(w io.Writer, rows []ProcessResult, wait, turnaround, throughput, slowdown float64, withSlowdown, color bool) {
+	_, _ = fmt.Fprintln(w, "Schedule table")
+	table := tablewriter.NewWriter(w)
+	header := []string{"ID", "Priority", "Burst", "Arrival", "Wait", "Turnaround", "Exit"}
+	footer := []string{"", "", "", "",
+		fmt.Sprintf("Average\n%.2f", wait),
+		fmt.Sprintf("Average\n%.2f", turnaround),
+		fmt.Sprintf("Throughput\n%.2f/t", throughput)}
+	if withSlowdown {
+		header, footer = append(header, "Slowdown"), append(footer, fmt.Sprintf("Average\n%.2f", slowdown))
+	}
+	table.SetHeader(header)
+	for i := range rows {
+		row := []string{
+			fmt.Sprint(rows[i].ProcessID),
+			fmt.Sprint(rows[i].Priority),
+			fmt.Sprint(rows[i].Burst),
+			fmt.Sprint(rows[i].Arrival),
+			fmt.Sprint(rows[i].Wait),
+			fmt.Sprint(rows[i].Turnaround),
+			fmt.Sprint(rows[i].Exit),
+		}
+		if withSlowdown {
+			row = append(row, fmt.Sprintf("%.2f", rows[i].Slowdown()))
+		}
+		if !color {
+			table.Append(row)
+			continue
+		}
+		colors := make([]tablewriter.Colors, len(row))
+		for j := range colors {
+			colors[j] = tablewriter.Colors{pidColor(rows[i].ProcessID)}
+		}
+		table.Rich(row, colors)
+	}
+	table.SetFooter(footer)
+	table.Render()
+}
+
+// outputScheduleSummary stands in for a schedule table too long to print,
+// giving the mean slowdown too if withSlowdown is set.
+func outputScheduleSummary(w io.Writer, result Result, withSlowdown bool) {
+	_, _ = fmt.Fprintf(w, "Schedule table: %d processes, rows left out\n", len(result.Schedule))
+	_, _ = fmt.Fprintf(w, "Average wait %.2f, turnaround %.2f", result.AverageWait, result.AverageTurnaround)
+	if withSlowdown {
+		_, _ = fmt.Fprintf(w, ", slowdown %.2f", result.Slowdown.Mean)
+	}
+	_, _ = fmt.Fprintf(w, "; throughput %.2f/t\n", result.Throughput)
+}
+
+func outputQueueStats(w io.Writer, q QueueStats) {
+	_, _ = fmt.Fprintf(w, "Queue length: ready max %d, average %.2f; blocked max %d, average %.2f\n",
+		q.MaxReady, q.AverageReady, q.MaxBlocked, q.AverageBlocked)
+}
+
+// outputFairness prints f, unless none of the processes completed, leaving
+// nothing to measure.
+func outputFairness(w io.Writer, f Fairness, completed int) {
+	if completed == 0 {
+		return
+	}
+	_, _ = fmt.Fprintf(w, "Fairness: Jain's index %.2f; wait std dev %.2f, min %d, max %d\n",
+		f.JainIndex, f.WaitStdDev, f.MinWait, f.MaxWait)
+}
+
+// writeQueueCSV writes the queue-length series of each named result as CSV
+// rows of algorithm, time, ready and blocked lengths.
+func writeQueueCSV(w io.Writer, names []string, results []Result) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"algorithm", "time", "ready", "blocked"})
+	for i := range results {
+		for _, s := range results[i].QueueLength {
+			_ = cw.Write([]string{names[i], fmt.Sprint(s.Time), fmt.Sprint(s.Ready), fmt.Sprint(s.Blocked)})
+		}
+	}
+	cw.Flush()
+
+	return cw.Error()
+}
+
+//endregion
+
+//region Loading processes.
+
+var ErrInvalidArgs = errors.New("invalid args")
+
+var ErrInvalidProcesses = errors.New("invalid process file")
+
+// processColumns names the columns of a process file, in order.
+var processColumns = []string{"pid", "burst", "arrival", "priority", "class", "bursts", "locks", "after", "nice", "group", "threads", "affinity", "deadline", "period", "suspend", "thread_bursts", "semaphores"}
+
+type (
+	// FieldError is one bad value in a process file.
+	FieldError struct {
+		Line   int
+		Column string
+		Value  string
+		Err    error
+	}
+	// ProcessFileError lists every bad value found in a process file.
+	ProcessFileError struct {
+		Errors []*FieldError
+	}
+)
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("line %d, column %s: %q: %v", e.Line, e.Column, e.Value, e.Err)
+}
+
+func (e *FieldError) Unwrap() error { return e.Err }
+
+func (e *ProcessFileError) Error() string {
+	var b strings.Builder
+	_, _ = fmt.Fprintf(&b, "%v: %d bad values", ErrInvalidProcesses, len(e.Errors))
+	for _, fe := range e.Errors {
+		_, _ = fmt.Fprintf(&b, "\n\t%v", fe)
+	}
+	return b.String()
+}
+
+// Is matches ErrInvalidProcesses and the cause of any bad value, such as
+// ErrInvalidClass.
+func (e *ProcessFileError) Is(target error) bool {
+	if target == ErrInvalidProcesses {
+		return true
+	}
+	for _, fe := range e.Errors {
+		if errors.Is(fe, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// ProcessFileOptions controls how a process file is read.
+type ProcessFileOptions struct {
+	// Comma separates fields; zero detects it from the first row.
+	Comma rune
+	// Tick is the real time a tick stands for, that times written with a
+	// unit, such as 5ms, are converted by; zero means defaultTickUnit.
+	Tick time.Duration
+}
+
+// loadProcesses reads a process file, detecting whether its fields are
+// separated by commas, semicolons or tabs.
+func loadProcesses(r io.Reader) ([]Process, error) {
+	return loadProcessFile(r, ProcessFileOptions{})
+}
+
+// loadProcessFile reads a process file as opts says. Blank lines and lines
+// starting with # are skipped, and fields may be quoted. The first row may
+// be a header naming the columns the rows give, in any order. Every bad
+// value is reported, with its line and column, in a *ProcessFileError. A
+// file with no processes at all is rejected too, by name when r is a file.
+func loadProcessFile(r io.Reader, opts ProcessFileOptions) ([]Process, error) {
+	name := "the process file"
+	if f, ok := r.(interface{ Name() string }); ok {
+		name = f.Name()
+	}
+	comma, tick := opts.Comma, opts.Tick
+	if comma == 0 {
+		br := bufio.NewReader(r)
+		comma, r = detectDelimiter(br), br
+	}
+	if tick <= 0 {
+		tick = defaultTickUnit
+	}
+	var (
+		cr        = csv.NewReader(r)
+		processes []Process
+		bad       ProcessFileError
+		header    *processHeader
+		first     = true
+	)
+	cr.Comma, cr.Comment = comma, '#'
+	cr.FieldsPerRecord = -1
+	cr.LazyQuotes, cr.TrimLeadingSpace = true, true
+	for {
+		row, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: reading CSV", err)
+		}
+		line, _ := cr.FieldPos(0)
+		if first && isProcessHeader(row) {
+			first = false
+			var errs []*FieldError
+			if header, errs = parseProcessHeader(row, line); len(errs) > 0 {
+				bad.Errors = errs
+				return nil, &bad
+			}
+			continue
+		}
+		first = false
+		raw := row
+		if header != nil {
+			if len(raw) != len(header.columns) {
+				bad.Errors = append(bad.Errors, header.countError(raw, line))
+				continue
+			}
+			row = header.arrange(raw)
+		}
+		fail := func(col int, err error) {
+			fe := &FieldError{Line: line, Column: processColumns[col], Err: err}
+			if i := header.position(col); i >= 0 && i < len(raw) {
+				fe.Value = raw[i]
+				fe.Line, _ = cr.FieldPos(i)
+			}
+			bad.Errors = append(bad.Errors, fe)
+		}
+		// Optional columns left empty, to reach a later one, are zero.
+		empty := func(col int, s string) bool {
+			return col >= 3 && strings.TrimSpace(s) == ""
+		}
+		integer := func(col int) int64 {
+			if empty(col, row[col]) {
+				return 0
+			}
+			i, err := strconv.ParseInt(strings.TrimSpace(row[col]), 10, 64)
+			if err != nil {
+				fail(col, errors.Unwrap(err))
+			}
+			return i
+		}
+		// Times and durations may be given with a unit.
+		ticks := func(col int, s string, round roundTicks) int64 {
+			if empty(col, s) {
+				return 0
+			}
+			n, err := parseTicks(s, tick, round)
+			if err != nil {
+				fail(col, err)
+			}
+			return n
+		}
+
+		var p Process
+		if len(row) < 3 {
+			fail(len(row), errors.New("missing value"))
+			processes = append(processes, p)
+			continue
+		}
+		p.ProcessID = integer(0)
+		p.BurstDuration = ticks(1, row[1], roundUp)
+		p.ArrivalTime = ticks(2, row[2], roundDown)
+		if len(row) >= 4 {
+			p.Priority = integer(3)
+		}
+		if len(row) >= 5 {
+			if p.Class, err = parseProcessClass(row[4]); err != nil {
+				fail(4, err)
+			}
+		}
+		if len(row) >= 6 {
+			for _, b := range strings.Fields(row[5]) {
+				p.Bursts = append(p.Bursts, ticks(5, b, roundUp))
+			}
+		}
+		if len(row) >= 7 {
+			if p.Locks, err = parseLockUses(row[6]); err != nil {
+				fail(6, err)
+			}
+		}
+		if len(row) >= 8 {
+			for _, pid := range strings.Fields(row[7]) {
+				n, err := strconv.ParseInt(pid, 10, 64)
+				if err != nil {
+					fail(7, errors.Unwrap(err))
+				}
+				p.DependsOn = append(p.DependsOn, n)
+			}
+		}
+		if len(row) >= 9 {
+			p.Nice = integer(8)
+		}
+		if len(row) >= 10 {
+			p.Group = strings.TrimSpace(row[9])
+		}
+		if len(row) >= 11 {
+			p.Threads = integer(10)
+		}
+		if len(row) >= 12 {
+			if p.Affinity, err = parseAffinity(row[11]); err != nil {
+				fail(11, err)
+			}
+		}
+		if len(row) >= 13 {
+			p.Deadline = ticks(12, row[12], roundUp)
+		}
+		if len(row) >= 14 {
+			p.Period = ticks(13, row[13], roundUp)
+		}
+		if len(row) >= 15 {
+			if p.Suspend, err = parseSuspensions(row[14]); err != nil {
+				fail(14, err)
+			}
+		}
+		if len(row) >= 16 {
+			for _, b := range strings.Fields(row[15]) {
+				p.ThreadBursts = append(p.ThreadBursts, ticks(15, b, roundUp))
+			}
+		}
+		if len(row) >= 17 {
+			if p.Semaphores, err = parseSemOps(row[16]); err != nil {
+				fail(16, err)
+			}
+		}
+		processes = append(processes, p)
+	}
+	if len(bad.Errors) > 0 {
+		return nil, &bad
+	}
+	if len(processes) == 0 {
+		return nil, fmt.Errorf("%w: %s has no processes", ErrInvalidProcesses, name)
+	}
+
+	return processes, nil
+}
+
+func writeProcesses(w io.Writer, processes []Process) error {
+	// The locks, after, nice, group, threads, affinity, deadline, period,
+	// suspend, thread bursts and semaphores columns are only written up to
+	// the last one some process needs.
+	columns := 6
+	for i := range processes {
+		switch {
+		case len(processes[i].Semaphores) > 0:
+			columns = 17
+		case len(processes[i].ThreadBursts) > 0 && columns < 16:
+			columns = 16
+		case len(processes[i].Suspend) > 0 && columns < 15:
+			columns = 15
+		case processes[i].Period != 0 && columns < 14:
+			columns = 14
+		case processes[i].Deadline != 0 && columns < 13:
+			columns = 13
+		case len(processes[i].Affinity) > 0 && columns < 12:
+			columns = 12
+		case processes[i].Threads > 1 && columns < 11:
+			columns = 11
+		case processes[i].Group != "" && columns < 10:
+			columns = 10
+		case processes[i].Nice != 0 && columns < 9:
+			columns = 9
+		case len(processes[i].DependsOn) > 0 && columns < 8:
+			columns = 8
+		case len(processes[i].Locks) > 0 && columns < 7:
+			columns = 7
+		}
+	}
+	return writeProcessColumns(w, processes, columns)
+}
+
+// writeProcessColumns writes the first columns columns of the process file
+// rows of processes.
+func writeProcessColumns(w io.Writer, processes []Process, columns int) error {
+	cw := csv.NewWriter(w)
+	for i := range processes {
+		bursts := make([]string, len(processes[i].Bursts))
+		for j, b := range processes[i].Bursts {
+			bursts[j] = fmt.Sprint(b)
+		}
+		threadBursts := make([]string, len(processes[i].ThreadBursts))
+		for j, b := range processes[i].ThreadBursts {
+			threadBursts[j] = fmt.Sprint(b)
+		}
+		row := []string{
+			fmt.Sprint(processes[i].ProcessID),
+			fmt.Sprint(processes[i].BurstDuration),
+			fmt.Sprint(processes[i].ArrivalTime),
+			fmt.Sprint(processes[i].Priority),
+			processes[i].Class.String(),
+			strings.Join(bursts, " "),
+			formatLockUses(processes[i].Locks),
+			formatPIDs(processes[i].DependsOn),
+			fmt.Sprint(processes[i].Nice),
+			processes[i].Group,
+			fmt.Sprint(processes[i].Threads),
+			formatAffinity(processes[i].Affinity),
+			fmt.Sprint(processes[i].Deadline),
+			fmt.Sprint(processes[i].Period),
+			formatSuspensions(processes[i].Suspend),
+			strings.Join(threadBursts, " "),
+			formatSemOps(processes[i].Semaphores),
+		}
+		if err := cw.Write(row[:columns]); err != nil {
+			return fmt.Errorf("%w: writing CSV", err)
+		}
+	}
+	cw.Flush()
+
+	return cw.Error()
+}
+
+//endregion
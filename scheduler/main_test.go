@@ -0,0 +1,563 @@
+package scheduler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestFCFSSchedule(t *testing.T) {
+	t.Parallel()
+	type args struct {
+		processes []Process
+		title     string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantOut string
+	}{
+		{
+			name: "default",
+			args: args{
+				processes: []Process{
+					{
+						ProcessID:     1,
+						ArrivalTime:   0,
+						BurstDuration: 5,
+						Priority:      2,
+					},
+					{
+						ProcessID:     2,
+						ArrivalTime:   3,
+						BurstDuration: 9,
+						Priority:      1,
+					},
+					{
+						ProcessID:     3,
+						ArrivalTime:   6,
+						BurstDuration: 6,
+						Priority:      3,
+					},
+				},
+				title: "First-come, First-serve",
+			},
+			wantOut: loadFixture(t, "fcfs_test.txt"),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var w bytes.Buffer
+			FCFSSchedule(&w, tt.args.title, tt.args.processes)
+			if got := w.String(); got != tt.wantOut {
+				t.Errorf("FCFSSchedule() = %v, want %v", got, tt.wantOut)
+			}
+		})
+	}
+}
+
+func TestFCFS(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 5, Priority: 2},
+		{ProcessID: 2, ArrivalTime: 3, BurstDuration: 9, Priority: 1},
+		{ProcessID: 3, ArrivalTime: 6, BurstDuration: 6, Priority: 3},
+	}
+	want := Result{
+		Schedule: []ProcessResult{
+			{ProcessID: 1, Priority: 2, Burst: 5, Arrival: 0, Wait: 0, Turnaround: 5, Exit: 5, Response: 0},
+			{ProcessID: 2, Priority: 1, Burst: 9, Arrival: 3, Wait: 2, Turnaround: 11, Exit: 14, Response: 2},
+			{ProcessID: 3, Priority: 3, Burst: 6, Arrival: 6, Wait: 8, Turnaround: 14, Exit: 20, Response: 8},
+		},
+		Gantt: []TimeSlice{
+			{PID: 1, Start: 0, Stop: 5},
+			{PID: 2, Start: 5, Stop: 14},
+			{PID: 3, Start: 14, Stop: 20},
+		},
+		Metrics: Metrics{AverageWait: 10.0 / 3, AverageTurnaround: 30.0 / 3, Throughput: 3.0 / 20},
+		Percentiles: Percentiles{
+			Wait:       Tail{P50: 2, P95: 8, P99: 8},
+			Turnaround: Tail{P50: 11, P95: 14, P99: 14},
+			Response:   Tail{P50: 2, P95: 8, P99: 8},
+		},
+		// (1 + 11/9 + 14/6) / 3, rounded as summed in order.
+		Slowdown: SlowdownStats{Mean: 1.5185185185185184, Max: 14.0 / 6, MaxPID: 3},
+		Queue:    QueueStats{MaxReady: 1, AverageReady: 0.5},
+		QueueLength: []QueueSample{
+			{Time: 0},
+			{Time: 3, Ready: 1},
+			{Time: 5},
+			{Time: 6, Ready: 1},
+			{Time: 14},
+			{Time: 20},
+		},
+		Events: []Event{
+			{Time: 0, Kind: EventArrive, PID: 1},
+			{Time: 0, Kind: EventDispatch, PID: 1},
+			{Time: 3, Kind: EventArrive, PID: 2},
+			{Time: 5, Kind: EventComplete, PID: 1},
+			{Time: 5, Kind: EventDispatch, PID: 2},
+			{Time: 6, Kind: EventArrive, PID: 3},
+			{Time: 14, Kind: EventComplete, PID: 2},
+			{Time: 14, Kind: EventDispatch, PID: 3},
+			{Time: 20, Kind: EventComplete, PID: 3},
+		},
+		// Two processes share the CPU from 3 to 5 and 6 to 14.
+		Shares: []ProcessShare{
+			{PID: 1, Received: 5, Entitled: 4},
+			{PID: 2, Received: 9, Entitled: 6},
+			{PID: 3, Received: 6, Entitled: 10},
+		},
+		Dispatches: 3,
+	}
+	want.Fairness = computeFairness(want.Schedule)
+	want.Fairness.ShareDeviation = shareDeviation(want.Shares)
+	if got := FCFS(processes); !reflect.DeepEqual(got, want) {
+		t.Errorf("FCFS() = %+v, want %+v", got, want)
+	}
+}
+
+func TestSJF_leavesInputUntouched(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 4, BurstDuration: 2},
+		{ProcessID: 2, ArrivalTime: 0, BurstDuration: 3},
+	}
+	want := append([]Process(nil), processes...)
+	_ = SJF(processes)
+	if !reflect.DeepEqual(processes, want) {
+		t.Errorf("SJF() reordered its input to %v, want %v", processes, want)
+	}
+}
+
+func TestRR_priorityQuanta(t *testing.T) {
+	t.Parallel()
+	// Priority 1 is best and gets 1 tick; 3 and 7, past the end of the
+	// list, share the last quantum.
+	processes := []Process{NewProcess(1, 0, 2, 3), NewProcess(2, 0, 2, 1), NewProcess(3, 0, 3, 7)}
+	policy := newRRPolicy(processes, RROptions{Quanta: []int64{1, 2}})
+	got := Simulate(processes, policy, EngineOptions{})
+	want := []TimeSlice{
+		{PID: 1, Start: 0, Stop: 2}, {PID: 2, Start: 2, Stop: 3}, {PID: 3, Start: 3, Stop: 5},
+		{PID: 2, Start: 5, Stop: 6}, {PID: 3, Start: 6, Stop: 7},
+	}
+	if !reflect.DeepEqual(got.Gantt, want) {
+		t.Errorf("Gantt = %v, want %v", got.Gantt, want)
+	}
+}
+
+func Test_mergeGantt(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name  string
+		gantt []TimeSlice
+		want  []TimeSlice
+	}{
+		{
+			name:  "consecutive quanta",
+			gantt: []TimeSlice{{PID: 1, Start: 0, Stop: 2}, {PID: 1, Start: 2, Stop: 4}, {PID: 2, Start: 4, Stop: 6}},
+			want:  []TimeSlice{{PID: 1, Start: 0, Stop: 4}, {PID: 2, Start: 4, Stop: 6}},
+		},
+		{
+			name:  "idle gap kept",
+			gantt: []TimeSlice{{PID: 1, Start: 0, Stop: 2}, {PID: 1, Start: 3, Stop: 4}},
+			want:  []TimeSlice{{PID: 1, Start: 0, Stop: 2}, {PID: 1, Start: 3, Stop: 4}},
+		},
+		{
+			name:  "interleaved",
+			gantt: []TimeSlice{{PID: 1, Start: 0, Stop: 2}, {PID: 2, Start: 2, Stop: 4}, {PID: 1, Start: 4, Stop: 6}},
+			want:  []TimeSlice{{PID: 1, Start: 0, Stop: 2}, {PID: 2, Start: 2, Stop: 4}, {PID: 1, Start: 4, Stop: 6}},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := mergeGantt(tt.gantt); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("mergeGantt() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_loadProcesses(t *testing.T) {
+	t.Parallel()
+	type args struct {
+		r io.Reader
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    []Process
+		wantErr error
+	}{
+		{
+			name: "bad CSV",
+			args: args{
+				r: iotest.ErrReader(io.ErrUnexpectedEOF),
+			},
+			wantErr: io.ErrUnexpectedEOF,
+		},
+		{
+			name: "bad class",
+			args: args{
+				r: strings.NewReader("1,5,0,2,daemon\n"),
+			},
+			wantErr: ErrInvalidClass,
+		},
+		{
+			name: "sparse optional columns",
+			args: args{
+				r: strings.NewReader("1,4,0,1,,,,,,,,,,,2-10\n2,3,1,, ,,,,,,,,10,,,,\n"),
+			},
+			want: []Process{
+				{ProcessID: 1, BurstDuration: 4, Priority: 1, Suspend: []Suspension{{Start: 2, Stop: 10}}},
+				{ProcessID: 2, BurstDuration: 3, ArrivalTime: 1, Deadline: 10},
+			},
+		},
+		{
+			name: "no processes",
+			args: args{
+				r: strings.NewReader("# pid,burst,arrival,priority\n\n"),
+			},
+			wantErr: ErrInvalidProcesses,
+		},
+		{
+			name: "empty burst",
+			args: args{
+				r: strings.NewReader("1,,0,2\n"),
+			},
+			wantErr: ErrInvalidProcesses,
+		},
+		{
+			name: "success",
+			args: args{
+				r: strings.NewReader(`1,5,0,2
+2,9,3,1
+3,6,3,3`),
+			},
+			want: []Process{
+				{
+					ProcessID:     1,
+					ArrivalTime:   0,
+					BurstDuration: 5,
+					Priority:      2,
+				},
+				{
+					ProcessID:     2,
+					ArrivalTime:   3,
+					BurstDuration: 9,
+					Priority:      1,
+				},
+				{
+					ProcessID:     3,
+					ArrivalTime:   3,
+					BurstDuration: 6,
+					Priority:      3,
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := loadProcesses(tt.args.r)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("loadProcesses() = %v, want %v", got, tt.want)
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_loadProcesses_emptyFile(t *testing.T) {
+	t.Parallel()
+	p := filepath.Join(t.TempDir(), "empty.csv")
+	if err := os.WriteFile(p, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = f.Close() }()
+	_, err = loadProcesses(f)
+	if !errors.Is(err, ErrInvalidProcesses) || !strings.Contains(err.Error(), p) {
+		t.Errorf("error = %v, want ErrInvalidProcesses naming %s", err, p)
+	}
+	if got := exitCode(err); got != exitInvalid {
+		t.Errorf("exitCode() = %d, want %d", got, exitInvalid)
+	}
+}
+
+func Test_loadProcesses_collectsErrors(t *testing.T) {
+	t.Parallel()
+	_, err := loadProcesses(strings.NewReader("1,5,0\n2,x,3,1\n3,6\n4,2,1,1,batch,1 y 1\n"))
+	var pfe *ProcessFileError
+	if !errors.As(err, &pfe) {
+		t.Fatalf("error = %v, want a *ProcessFileError", err)
+	}
+	want := []FieldError{
+		{Line: 2, Column: "burst", Value: "x", Err: strconv.ErrSyntax},
+		{Line: 3, Column: "arrival"},
+		{Line: 4, Column: "bursts", Value: "1 y 1", Err: strconv.ErrSyntax},
+	}
+	if len(pfe.Errors) != len(want) {
+		t.Fatalf("got %d errors, want %d:\n%v", len(pfe.Errors), len(want), err)
+	}
+	for i, fe := range pfe.Errors {
+		got := *fe
+		if want[i].Err == nil {
+			got.Err = nil
+		}
+		if got != want[i] {
+			t.Errorf("error %d = %+v, want %+v", i, got, want[i])
+		}
+	}
+	if !errors.Is(err, ErrInvalidProcesses) {
+		t.Errorf("errors.Is(%v, ErrInvalidProcesses) = false", err)
+	}
+}
+
+func loadFixture(t *testing.T, p ...string) string {
+	b, err := os.ReadFile(path.Join(p...))
+	if err != nil {
+		t.Fail()
+	}
+
+	return string(b)
+}
+
+func Test_openProcessingFile1(t *testing.T) {
+	tmpFile, tErr := os.CreateTemp(t.TempDir(), "")
+	if tErr != nil {
+		t.Fatal(tErr)
+	}
+	terminal, tErr := os.Open(os.DevNull)
+	if tErr != nil {
+		t.Fatal(tErr)
+	}
+	t.Cleanup(func() { _ = terminal.Close() })
+	pipe, pipeW, tErr := os.Pipe()
+	if tErr != nil {
+		t.Fatal(tErr)
+	}
+	t.Cleanup(func() {
+		_ = pipe.Close()
+		_ = pipeW.Close()
+	})
+
+	type args struct {
+		args  []string
+		stdin *os.File
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    *os.File
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				args:  []string{"binary_name", tmpFile.Name()},
+				stdin: terminal,
+			},
+			want: tmpFile,
+		},
+		{
+			name: "not enough args",
+			args: args{
+				args:  []string{"binary_name"},
+				stdin: terminal,
+			},
+			wantErr: true,
+		},
+		{
+			name: "bad file",
+			args: args{
+				args:  []string{"binary_name", "bad_file_name"},
+				stdin: terminal,
+			},
+			wantErr: true,
+		},
+		{
+			name: "dash reads stdin",
+			args: args{
+				args:  []string{"binary_name", "-"},
+				stdin: terminal,
+			},
+			want: terminal,
+		},
+		{
+			name: "piped stdin without file",
+			args: args{
+				args:  []string{"binary_name"},
+				stdin: pipe,
+			},
+			want: pipe,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stdin = tt.args.stdin
+			t.Cleanup(func() { stdin = os.Stdin })
+			got, closeFn, err := openProcessingFile(tt.args.args...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("openProcessingFile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			if got == nil {
+				t.Fatal("file is unexpectedly nil")
+			}
+			if closeFn == nil {
+				t.Fatal("closeFn is unexpectedly nil")
+			}
+			t.Cleanup(closeFn)
+
+			f1, err := got.Stat()
+			if err != nil {
+				t.Fatalf("Could not stat file: %v", got)
+			}
+			f2, err := tt.want.Stat()
+			if err != nil {
+				t.Fatalf("Could not stat file: %v", tt.want)
+			}
+
+			if !os.SameFile(f1, f2) {
+				t.Fatal("files are not the same")
+			}
+		})
+	}
+}
+
+func Test_selectRuns(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		spec    string
+		want    []string
+		wantErr error
+	}{
+		{name: "all", spec: "", want: []string{"fcfs", "sjf", "priority", "rr"}},
+		{name: "subset keeps order", spec: "rr,fcfs", want: []string{"rr", "fcfs"}},
+		{name: "unknown", spec: "stride", wantErr: ErrInvalidArgs},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			runs, err := selectRuns(tt.spec)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			var got []string
+			for _, run := range runs {
+				got = append(got, run.Name)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("selectRuns() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProcess_json(t *testing.T) {
+	t.Parallel()
+	p := NewProcess(3, 2, 9, 1)
+	p.Class, p.Bursts, p.Locks, p.DependsOn = ClassInteractive, []int64{4, 2, 5}, []LockUse{{Resource: "db", Acquire: 1, Release: 3}}, []int64{1}
+	b, err := json.Marshal(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"pid":3,"arrival":2,"burst":9,"priority":1,"class":"interactive","bursts":[4,2,5],"locks":[{"resource":"db","acquire":1,"release":3}],"after":[1]}`
+	if string(b) != want {
+		t.Errorf("json.Marshal() = %s, want %s", b, want)
+	}
+	var got Process
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, p) {
+		t.Errorf("round trip = %+v, want %+v", got, p)
+	}
+	if err := json.Unmarshal([]byte(`{"pid":1,"class":"daemon"}`), &got); !errors.Is(err, ErrInvalidClass) {
+		t.Errorf("json.Unmarshal() error = %v, want %v", err, ErrInvalidClass)
+	}
+}
+
+// Process's csv tags are exactly a process file's columns.
+func Test_processColumns(t *testing.T) {
+	t.Parallel()
+	tags := map[string]bool{}
+	typ := reflect.TypeOf(Process{})
+	for i := 0; i < typ.NumField(); i++ {
+		if tag := typ.Field(i).Tag.Get("csv"); tag != "" {
+			tags[tag] = true
+		}
+	}
+	if len(tags) != len(processColumns) {
+		t.Errorf("Process has %d csv tags, want %d", len(tags), len(processColumns))
+	}
+	for _, c := range processColumns {
+		if !tags[c] {
+			t.Errorf("no Process field is tagged csv:%q", c)
+		}
+	}
+}
+
+func TestRender_maxRows(t *testing.T) {
+	t.Parallel()
+	result := FCFS([]Process{{ProcessID: 1, BurstDuration: 2}, {ProcessID: 2, BurstDuration: 2}})
+	var w bytes.Buffer
+	Render(&w, result, RenderOptions{Title: "FCFS", GanttFile: "gantt.csv", MaxRows: 1})
+	got := w.String()
+	for _, want := range []string{"Gantt schedule streamed to gantt.csv", "Schedule table: 2 processes, rows left out", "Average wait 1.00, turnaround 3.00"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Render() =\n%s\nwant it to contain %q", got, want)
+		}
+	}
+	if strings.Contains(got, "TURNAROUND") {
+		t.Errorf("Render() =\n%s\nwant no schedule table rows", got)
+	}
+}
+
+func TestRender_noneCompleted(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		processes []Process
+		opts      EngineOptions
+	}{
+		{name: "empty workload"},
+		{name: "stopped first", processes: []Process{NewProcess(1, 0, 10, 0)}, opts: EngineOptions{MaxTime: 5}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var w bytes.Buffer
+			Render(&w, Simulate(tt.processes, fcfsPolicy{}, tt.opts), RenderOptions{})
+			if got := w.String(); strings.Contains(got, "Fairness") {
+				t.Errorf("Render() =\n%s\nwant no fairness line", got)
+			}
+		})
+	}
+}
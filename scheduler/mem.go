@@ -0,0 +1,368 @@
+package scheduler
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+var ErrInvalidReference = errors.New("invalid page reference")
+
+type (
+	// PageStep is one reference of a page replacement run: the page, the
+	// frames' contents after it, -1 for an empty frame, and whether it
+	// faulted, evicting Victim from its frame if one was full.
+	PageStep struct {
+		Page   int64
+		Frames []int64
+		Fault  bool
+		Victim int64
+	}
+	// PageResult is one algorithm's run over a reference string with a
+	// number of frames.
+	PageResult struct {
+		Algorithm string
+		Frames    int
+		Steps     []PageStep
+		Faults    int
+		// Residency has a slice for each stay of a page in a frame, with the
+		// page as PID and the frame as CPU, timed by reference: a page
+		// loaded at the i'th reference and evicted at the j'th spans i to j.
+		Residency []TimeSlice
+	}
+)
+
+// FaultRate is the share of references that faulted.
+func (r PageResult) FaultRate() float64 {
+	if len(r.Steps) == 0 {
+		return 0
+	}
+	return float64(r.Faults) / float64(len(r.Steps))
+}
+
+// pager is the frames' state while a policy runs: the page in each, -1 if
+// empty, the reference it was loaded at and last used at, and the clock's
+// reference bits and hand.
+type pager struct {
+	refs   []int64
+	pages  []int64
+	loaded []int
+	used   []int
+	ref    []bool
+	hand   int
+}
+
+// A pagePolicy picks the frame whose page to evict for the i'th reference,
+// every frame being full.
+type pagePolicy func(p *pager, i int) int
+
+// pageAlgorithms are the page replacement algorithms, in the order they are
+// compared.
+var pageAlgorithms = []struct {
+	name, title string
+	policy      pagePolicy
+}{
+	{"fifo", "FIFO", pageFIFO},
+	{"lru", "LRU", pageLRU},
+	{"opt", "OPT", pageOPT},
+	{"clock", "Clock", pageClock},
+}
+
+// SimulatePaging runs policy over refs with frames page frames, all empty
+// at the start.
+func SimulatePaging(refs []int64, frames int, policy pagePolicy) PageResult {
+	p := &pager{refs: refs, pages: make([]int64, frames), loaded: make([]int, frames), used: make([]int, frames), ref: make([]bool, frames)}
+	for f := range p.pages {
+		p.pages[f] = -1
+	}
+	result := PageResult{Frames: frames}
+	since := make([]int64, frames)
+	for i, page := range refs {
+		step := PageStep{Page: page, Victim: -1}
+		f := p.frameOf(page)
+		if f < 0 {
+			step.Fault = true
+			result.Faults++
+			if f = p.frameOf(-1); f < 0 {
+				f = policy(p, i)
+				step.Victim = p.pages[f]
+				result.Residency = append(result.Residency, TimeSlice{PID: p.pages[f], Start: since[f], Stop: int64(i), CPU: f})
+			}
+			p.pages[f], p.loaded[f], since[f] = page, i, int64(i)
+		}
+		p.used[f], p.ref[f] = i, true
+		step.Frames = append([]int64(nil), p.pages...)
+		result.Steps = append(result.Steps, step)
+	}
+	for f, page := range p.pages {
+		if page >= 0 {
+			result.Residency = append(result.Residency, TimeSlice{PID: page, Start: since[f], Stop: int64(len(refs)), CPU: f})
+		}
+	}
+	return result
+}
+
+// frameOf is the frame holding page, or -1 if none does.
+func (p *pager) frameOf(page int64) int {
+	for f, q := range p.pages {
+		if q == page {
+			return f
+		}
+	}
+	return -1
+}
+
+// pageFIFO evicts the page loaded longest ago.
+func pageFIFO(p *pager, _ int) int {
+	victim := 0
+	for f := range p.pages {
+		if p.loaded[f] < p.loaded[victim] {
+			victim = f
+		}
+	}
+	return victim
+}
+
+// pageLRU evicts the page used longest ago.
+func pageLRU(p *pager, _ int) int {
+	victim := 0
+	for f := range p.pages {
+		if p.used[f] < p.used[victim] {
+			victim = f
+		}
+	}
+	return victim
+}
+
+// pageOPT, Belady's optimal algorithm, evicts the page that will go unused
+// the longest, looking ahead in the reference string: the first frame
+// whose page is never used again, if any.
+func pageOPT(p *pager, i int) int {
+	victim, farthest := 0, -1
+	for f, page := range p.pages {
+		next := len(p.refs)
+		for j := i + 1; j < len(p.refs); j++ {
+			if p.refs[j] == page {
+				next = j
+				break
+			}
+		}
+		if next > farthest {
+			victim, farthest = f, next
+		}
+	}
+	return victim
+}
+
+// pageClock, second chance, sweeps a hand round the frames, clearing each
+// reference bit it finds set, and evicts the first page whose bit is clear.
+func pageClock(p *pager, _ int) int {
+	for p.ref[p.hand] {
+		p.ref[p.hand] = false
+		p.hand = (p.hand + 1) % len(p.pages)
+	}
+	victim := p.hand
+	p.hand = (p.hand + 1) % len(p.pages)
+	return victim
+}
+
+// parseReferences reads a reference string: a list of page numbers.
+func parseReferences(r io.Reader) ([]int64, error) {
+	fields, err := readListFields(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("%w: no references", ErrInvalidReference)
+	}
+	refs := make([]int64, len(fields))
+	for i, f := range fields {
+		if refs[i], err = strconv.ParseInt(f, 10, 64); err != nil || refs[i] < 0 {
+			return nil, fmt.Errorf("%w: %q is not a page number of 0 or more", ErrInvalidReference, f)
+		}
+	}
+	return refs, nil
+}
+
+// parseFrameCounts reads -frames: comma-separated numbers of frames.
+func parseFrameCounts(s string) ([]int, error) {
+	var counts []int
+	for _, f := range strings.Split(s, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(f))
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("%w: %q is not a number of frames of 1 or more", ErrInvalidArgs, f)
+		}
+		counts = append(counts, n)
+	}
+	return counts, nil
+}
+
+// pageTableColumns is how many references each frame-state table shows, a
+// longer string being split over several.
+const pageTableColumns = 20
+
+// outputPaging lays out the frames' contents after each reference, a
+// column per reference and a row per frame, with F under each fault.
+func outputPaging(w io.Writer, result PageResult) {
+	for lo := 0; lo < len(result.Steps); lo += pageTableColumns {
+		steps := result.Steps[lo:]
+		if len(steps) > pageTableColumns {
+			steps = steps[:pageTableColumns]
+		}
+		table := tablewriter.NewWriter(w)
+		header := []string{"Reference"}
+		for _, s := range steps {
+			header = append(header, fmt.Sprint(s.Page))
+		}
+		table.SetHeader(header)
+		for f := 0; f < result.Frames; f++ {
+			row := []string{fmt.Sprintf("Frame %d", f)}
+			for _, s := range steps {
+				cell := ""
+				if s.Frames[f] >= 0 {
+					cell = fmt.Sprint(s.Frames[f])
+				}
+				row = append(row, cell)
+			}
+			table.Append(row)
+		}
+		faults := []string{"Fault"}
+		for _, s := range steps {
+			cell := ""
+			if s.Fault {
+				cell = "F"
+			}
+			faults = append(faults, cell)
+		}
+		table.Append(faults)
+		table.Render()
+	}
+	_, _ = fmt.Fprintf(w, "Page faults: %d of %d references (%.1f%%)\n", result.Faults, len(result.Steps), result.FaultRate()*100)
+}
+
+// outputResidency draws which page each frame held over the references as
+// a Gantt chart, a lane per frame with the pages as its bars.
+func outputResidency(w io.Writer, result PageResult, opts GanttOptions) {
+	_, _ = fmt.Fprintln(w, "Frame residency")
+	if len(result.Residency) == 0 {
+		_, _ = fmt.Fprintln(w)
+		return
+	}
+	width := opts.width()
+	end := int64(len(result.Steps))
+	col := ganttColumns(0, end, width, opts)
+	for _, lane := range ganttLanes(result.Residency) {
+		_, _ = fmt.Fprintf(w, "Frame %d\n", lane[0].CPU)
+		outputGanttLane(w, lane, 0, end, col, width, opts)
+	}
+	_, _ = fmt.Fprintln(w)
+}
+
+// outputPagingComparison tabulates each algorithm's faults with each number
+// of frames, and notes any algorithm faulting more with more frames:
+// Belady's anomaly, which FIFO can show but LRU and OPT never do.
+func outputPagingComparison(w io.Writer, results [][]PageResult) {
+	table := tablewriter.NewWriter(w)
+	header := []string{"Algorithm"}
+	for _, r := range results[0] {
+		header = append(header, fmt.Sprintf("%d frames", r.Frames))
+	}
+	table.SetHeader(header)
+	for _, runs := range results {
+		row := []string{runs[0].Algorithm}
+		for _, r := range runs {
+			row = append(row, fmt.Sprintf("%d (%.1f%%)", r.Faults, r.FaultRate()*100))
+		}
+		table.Append(row)
+	}
+	table.Render()
+	for _, runs := range results {
+		for i := 1; i < len(runs); i++ {
+			for j := 0; j < i; j++ {
+				if a, b := runs[j], runs[i]; b.Frames > a.Frames && b.Faults > a.Faults {
+					_, _ = fmt.Fprintf(w, "Belady's anomaly: %s faults %d times with %d frames but %d with %d\n", a.Algorithm, b.Faults, b.Frames, a.Faults, a.Frames)
+				}
+			}
+		}
+	}
+}
+
+// selectPageAlgorithms picks the indexes in pageAlgorithms of those named
+// in a comma-separated list, keeping the list's order. An empty list
+// selects them all.
+func selectPageAlgorithms(spec string) ([]int, error) {
+	var selected []int
+	if spec == "" {
+		for i := range pageAlgorithms {
+			selected = append(selected, i)
+		}
+		return selected, nil
+	}
+	for _, name := range strings.Split(spec, ",") {
+		i := 0
+		for i < len(pageAlgorithms) && pageAlgorithms[i].name != strings.TrimSpace(name) {
+			i++
+		}
+		if i == len(pageAlgorithms) {
+			return nil, fmt.Errorf("%w: unknown page replacement algorithm %q", ErrInvalidArgs, name)
+		}
+		selected = append(selected, i)
+	}
+	return selected, nil
+}
+
+// runMem is the mem subcommand: simulate page replacement algorithms over a
+// file holding a reference string.
+func runMem(w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("mem", flag.ContinueOnError)
+	algos := fs.String("algo", "", "comma-separated page replacement algorithms: fifo, lru, opt, clock (default all)")
+	frames := fs.String("frames", "3", "comma-separated numbers of page frames to run with")
+	noChart := fs.Bool("no-chart", false, "leave out the frame residency charts")
+	gantt := addGanttFlags(fs)
+	if err := parseFlags(fs, "mem", args); err != nil {
+		return err
+	}
+	if fs.NArg() > 1 {
+		return fmt.Errorf("%w: usage: mem [flags] file", ErrInvalidArgs)
+	}
+	policies, err := selectPageAlgorithms(*algos)
+	if err != nil {
+		return err
+	}
+	counts, err := parseFrameCounts(*frames)
+	if err != nil {
+		return err
+	}
+	f, closeFile, err := openProcessingFile(append([]string{"mem"}, fs.Args()...)...)
+	if err != nil {
+		return err
+	}
+	defer closeFile()
+	refs, err := parseReferences(f)
+	if err != nil {
+		return err
+	}
+	results := make([][]PageResult, len(policies))
+	for i, p := range policies {
+		for _, n := range counts {
+			r := SimulatePaging(refs, n, pageAlgorithms[p].policy)
+			r.Algorithm = pageAlgorithms[p].title
+			results[i] = append(results[i], r)
+			outputTitle(w, fmt.Sprintf("%s page replacement, %d frames", r.Algorithm, n))
+			outputPaging(w, r)
+			if !*noChart {
+				outputResidency(w, r, gantt.options(w))
+			}
+		}
+	}
+	if len(policies)*len(counts) > 1 {
+		outputTitle(w, "Page replacement comparison")
+		outputPagingComparison(w, results)
+	}
+	return nil
+}
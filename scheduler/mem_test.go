@@ -0,0 +1,139 @@
+package scheduler
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSimulatePaging(t *testing.T) {
+	t.Parallel()
+	textbook := []int64{7, 0, 1, 2, 0, 3, 0, 4, 2, 3, 0, 3, 2, 1, 2, 0, 1, 7, 0, 1}
+	belady := []int64{1, 2, 3, 4, 1, 2, 5, 1, 2, 3, 4, 5}
+	tests := []struct {
+		name   string
+		policy pagePolicy
+		refs   []int64
+		frames int
+		faults int
+	}{
+		{name: "fifo", policy: pageFIFO, refs: textbook, frames: 3, faults: 15},
+		{name: "lru", policy: pageLRU, refs: textbook, frames: 3, faults: 12},
+		{name: "opt", policy: pageOPT, refs: textbook, frames: 3, faults: 9},
+		{name: "clock", policy: pageClock, refs: textbook, frames: 3, faults: 14},
+		{name: "fifo belady 3", policy: pageFIFO, refs: belady, frames: 3, faults: 9},
+		{name: "fifo belady 4", policy: pageFIFO, refs: belady, frames: 4, faults: 10},
+		{name: "lru belady 4", policy: pageLRU, refs: belady, frames: 4, faults: 8},
+		{name: "opt belady 4", policy: pageOPT, refs: belady, frames: 4, faults: 6},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := SimulatePaging(tt.refs, tt.frames, tt.policy)
+			if got.Faults != tt.faults {
+				t.Errorf("Faults = %d, want %d", got.Faults, tt.faults)
+			}
+			// Every reference is to a page in a frame afterwards, each
+			// frame's residency covering it.
+			for i, s := range got.Steps {
+				resident := false
+				for _, r := range got.Residency {
+					resident = resident || (r.PID == s.Page && r.Start <= int64(i) && int64(i) < r.Stop && s.Frames[r.CPU] == s.Page)
+				}
+				if !resident {
+					t.Errorf("reference %d to page %d not resident: %+v", i, s.Page, got.Residency)
+				}
+			}
+		})
+	}
+}
+
+func TestSimulatePaging_steps(t *testing.T) {
+	t.Parallel()
+	got := SimulatePaging([]int64{1, 2, 1, 3}, 2, pageLRU)
+	want := []PageStep{
+		{Page: 1, Frames: []int64{1, -1}, Fault: true, Victim: -1},
+		{Page: 2, Frames: []int64{1, 2}, Fault: true, Victim: -1},
+		{Page: 1, Frames: []int64{1, 2}, Victim: -1},
+		{Page: 3, Frames: []int64{1, 3}, Fault: true, Victim: 2},
+	}
+	if !reflect.DeepEqual(got.Steps, want) {
+		t.Errorf("Steps = %+v, want %+v", got.Steps, want)
+	}
+	residency := []TimeSlice{{PID: 2, Start: 1, Stop: 3, CPU: 1}, {PID: 1, Start: 0, Stop: 4}, {PID: 3, Start: 3, Stop: 4, CPU: 1}}
+	if !reflect.DeepEqual(got.Residency, residency) {
+		t.Errorf("Residency = %+v, want %+v", got.Residency, residency)
+	}
+}
+
+func Test_parseReferences(t *testing.T) {
+	t.Parallel()
+	got, err := parseReferences(strings.NewReader("7 0,1\n# again\n7\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int64{7, 0, 1, 7}; !reflect.DeepEqual(got, want) {
+		t.Errorf("parseReferences() = %v, want %v", got, want)
+	}
+	for _, bad := range []string{"", "# none", "x", "-1"} {
+		if _, err := parseReferences(strings.NewReader(bad)); !errors.Is(err, ErrInvalidReference) {
+			t.Errorf("parseReferences(%q) error = %v, want ErrInvalidReference", bad, err)
+		}
+	}
+}
+
+func Test_parseFrameCounts(t *testing.T) {
+	t.Parallel()
+	got, err := parseFrameCounts("3, 4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{3, 4}; !reflect.DeepEqual(got, want) {
+		t.Errorf("parseFrameCounts() = %v, want %v", got, want)
+	}
+	for _, bad := range []string{"", "0", "x", "3,"} {
+		if _, err := parseFrameCounts(bad); !errors.Is(err, ErrInvalidArgs) {
+			t.Errorf("parseFrameCounts(%q) error = %v, want ErrInvalidArgs", bad, err)
+		}
+	}
+}
+
+func Test_outputPagingComparison(t *testing.T) {
+	t.Parallel()
+	refs := []int64{1, 2, 3, 4, 1, 2, 5, 1, 2, 3, 4, 5}
+	var results [][]PageResult
+	for _, a := range []int{0, 2} {
+		var runs []PageResult
+		for _, n := range []int{3, 4} {
+			r := SimulatePaging(refs, n, pageAlgorithms[a].policy)
+			r.Algorithm = pageAlgorithms[a].title
+			runs = append(runs, r)
+		}
+		results = append(results, runs)
+	}
+	var w strings.Builder
+	outputPagingComparison(&w, results)
+	got := w.String()
+	if !strings.Contains(got, "Belady's anomaly: FIFO faults 10 times with 4 frames but 9 with 3\n") {
+		t.Errorf("outputPagingComparison() = %q, want FIFO's anomaly noted", got)
+	}
+	if strings.Contains(got, "anomaly: OPT") {
+		t.Errorf("outputPagingComparison() = %q, noting an anomaly for OPT", got)
+	}
+}
+
+func Test_selectPageAlgorithms(t *testing.T) {
+	t.Parallel()
+	got, err := selectPageAlgorithms("clock,fifo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{3, 0}; !reflect.DeepEqual(got, want) {
+		t.Errorf("selectPageAlgorithms() = %v, want %v", got, want)
+	}
+	if _, err := selectPageAlgorithms("mru"); !errors.Is(err, ErrInvalidArgs) {
+		t.Errorf("selectPageAlgorithms(\"mru\") error = %v, want ErrInvalidArgs", err)
+	}
+}
@@ -0,0 +1,155 @@
+package scheduler
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+)
+
+// Metrics are the averages printed under the schedule table.
+type Metrics struct {
+	AverageWait       float64 `json:"average_wait"`
+	AverageTurnaround float64 `json:"average_turnaround"`
+	// Throughput is finished processes per tick, up to the last exit or,
+	// for a run cut short, the end of the run.
+	Throughput float64 `json:"throughput"`
+}
+
+// NewMetrics averages the schedule rows of the processes that finished.
+// Throughput is measured up to horizon, or the last exit if horizon is
+// zero.
+func NewMetrics(rows []ProcessResult, horizon int64) Metrics {
+	return metricsSince(rows, 0, horizon)
+}
+
+// metricsSince is NewMetrics with throughput measured from start.
+func metricsSince(rows []ProcessResult, start, horizon int64) Metrics {
+	if len(rows) == 0 {
+		return Metrics{}
+	}
+	var wait, turnaround, end int64
+	for _, r := range rows {
+		wait += r.Wait
+		turnaround += r.Turnaround
+		if r.Exit > end {
+			end = r.Exit
+		}
+	}
+	if horizon > 0 {
+		end = horizon
+	}
+	n := float64(len(rows))
+	m := Metrics{
+		AverageWait:       float64(wait) / n,
+		AverageTurnaround: float64(turnaround) / n,
+	}
+	if end > start {
+		m.Throughput = n / float64(end-start)
+	}
+	return m
+}
+
+type (
+	// Percentiles are the 50th, 95th and 99th percentiles of the finished
+	// processes' waits, turnarounds and response times.
+	Percentiles struct {
+		Wait       Tail `json:"wait"`
+		Turnaround Tail `json:"turnaround"`
+		Response   Tail `json:"response"`
+	}
+	// Tail is the median and the tail of one measure, by nearest rank: the
+	// smallest value at least that share of processes did not exceed.
+	Tail struct {
+		P50 int64 `json:"p50"`
+		P95 int64 `json:"p95"`
+		P99 int64 `json:"p99"`
+	}
+)
+
+// NewPercentiles takes the percentiles of the schedule rows.
+func NewPercentiles(rows []ProcessResult) Percentiles {
+	if len(rows) == 0 {
+		return Percentiles{}
+	}
+	wait := make([]int64, len(rows))
+	turnaround := make([]int64, len(rows))
+	response := make([]int64, len(rows))
+	for i, r := range rows {
+		wait[i], turnaround[i], response[i] = r.Wait, r.Turnaround, r.Response
+	}
+	return Percentiles{Wait: newTail(wait), Turnaround: newTail(turnaround), Response: newTail(response)}
+}
+
+// newTail sorts values, which must not be empty, and picks out the
+// percentiles.
+func newTail(values []int64) Tail {
+	sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+	rank := func(p int) int64 {
+		// The nearest rank is ceil(p/100 * n), counted from one.
+		return values[(p*len(values)+99)/100-1]
+	}
+	return Tail{P50: rank(50), P95: rank(95), P99: rank(99)}
+}
+
+// outputPercentiles prints the tails under the schedule table.
+func outputPercentiles(w io.Writer, p Percentiles) {
+	_, _ = fmt.Fprintf(w, "Percentiles p50/p95/p99: wait %d/%d/%d, turnaround %d/%d/%d, response %d/%d/%d\n",
+		p.Wait.P50, p.Wait.P95, p.Wait.P99,
+		p.Turnaround.P50, p.Turnaround.P95, p.Turnaround.P99,
+		p.Response.P50, p.Response.P95, p.Response.P99)
+}
+
+// Fairness summarises how evenly a schedule treated its processes, which
+// averages alone hide.
+type Fairness struct {
+	// JainIndex is Jain's fairness index over each process's CPU share, the
+	// fraction of its time in the system spent running. 1 is perfectly fair;
+	// 1/n means one process got everything.
+	JainIndex float64
+	// WaitStdDev is the population standard deviation of wait times.
+	WaitStdDev float64
+	MinWait    int64
+	MaxWait    int64
+	// ShareDeviation is the mean relative gap between the CPU time each
+	// process received and its weighted entitlement; 0 is exact.
+	ShareDeviation float64
+}
+
+func computeFairness(rows []ProcessResult) Fairness {
+	if len(rows) == 0 {
+		return Fairness{}
+	}
+	var (
+		f                               = Fairness{MinWait: rows[0].Wait, MaxWait: rows[0].Wait}
+		shareSum, shareSquares, waitSum float64
+	)
+	for _, r := range rows {
+		share := 1.0
+		if r.Turnaround > 0 {
+			share = float64(r.Burst) / float64(r.Turnaround)
+		}
+		shareSum += share
+		shareSquares += share * share
+		waitSum += float64(r.Wait)
+		if r.Wait < f.MinWait {
+			f.MinWait = r.Wait
+		}
+		if r.Wait > f.MaxWait {
+			f.MaxWait = r.Wait
+		}
+	}
+	n := float64(len(rows))
+	if shareSquares > 0 {
+		f.JainIndex = shareSum * shareSum / (n * shareSquares)
+	}
+	mean := waitSum / n
+	var variance float64
+	for _, r := range rows {
+		d := float64(r.Wait) - mean
+		variance += d * d
+	}
+	f.WaitStdDev = math.Sqrt(variance / n)
+
+	return f
+}
@@ -0,0 +1,127 @@
+package scheduler
+
+import (
+	"math"
+	"testing"
+)
+
+func Test_computeFairness(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		rows []ProcessResult
+		want Fairness
+	}{
+		{
+			name: "empty",
+			want: Fairness{},
+		},
+		{
+			name: "equal shares",
+			rows: []ProcessResult{
+				{Burst: 2, Wait: 2, Turnaround: 4},
+				{Burst: 3, Wait: 3, Turnaround: 6},
+			},
+			want: Fairness{JainIndex: 1, WaitStdDev: 0.5, MinWait: 2, MaxWait: 3},
+		},
+		{
+			name: "one process starved",
+			rows: []ProcessResult{
+				{Burst: 4, Wait: 0, Turnaround: 4},
+				{Burst: 1, Wait: 99, Turnaround: 100},
+			},
+			want: Fairness{JainIndex: 1.01 * 1.01 / (2 * 1.0001), WaitStdDev: 49.5, MinWait: 0, MaxWait: 99},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := computeFairness(tt.rows)
+			if math.Abs(got.JainIndex-tt.want.JainIndex) > 1e-9 || math.Abs(got.WaitStdDev-tt.want.WaitStdDev) > 1e-9 ||
+				got.MinWait != tt.want.MinWait || got.MaxWait != tt.want.MaxWait {
+				t.Errorf("computeFairness() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewMetrics(t *testing.T) {
+	t.Parallel()
+	rows := []ProcessResult{
+		{ProcessID: 1, Wait: 0, Turnaround: 4, Exit: 4},
+		{ProcessID: 2, Wait: 3, Turnaround: 5, Exit: 8},
+	}
+	tests := []struct {
+		name    string
+		rows    []ProcessResult
+		horizon int64
+		want    Metrics
+	}{
+		{name: "empty", want: Metrics{}},
+		{name: "last exit", rows: rows, want: Metrics{AverageWait: 1.5, AverageTurnaround: 4.5, Throughput: 0.25}},
+		{name: "horizon", rows: rows, horizon: 10, want: Metrics{AverageWait: 1.5, AverageTurnaround: 4.5, Throughput: 0.2}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := NewMetrics(tt.rows, tt.horizon); got != tt.want {
+				t.Errorf("NewMetrics() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewPercentiles(t *testing.T) {
+	t.Parallel()
+	// Waits 1 to 100, in reverse; turnarounds are waits plus 10.
+	var rows []ProcessResult
+	for i := int64(100); i >= 1; i-- {
+		rows = append(rows, ProcessResult{ProcessID: i, Wait: i, Turnaround: i + 10})
+	}
+	tests := []struct {
+		name string
+		rows []ProcessResult
+		want Percentiles
+	}{
+		{name: "empty"},
+		{
+			name: "one",
+			rows: []ProcessResult{{Wait: 3, Turnaround: 7, Response: 1}},
+			want: Percentiles{Wait: Tail{3, 3, 3}, Turnaround: Tail{7, 7, 7}, Response: Tail{1, 1, 1}},
+		},
+		{
+			name: "hundred",
+			rows: rows,
+			want: Percentiles{Wait: Tail{50, 95, 99}, Turnaround: Tail{60, 105, 109}},
+		},
+		{
+			name: "tail of three",
+			rows: []ProcessResult{{Wait: 8}, {Wait: 0}, {Wait: 2}},
+			want: Percentiles{Wait: Tail{2, 8, 8}},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := NewPercentiles(tt.rows); got != tt.want {
+				t.Errorf("NewPercentiles() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSimulate_response(t *testing.T) {
+	t.Parallel()
+	processes := []Process{NewProcess(1, 0, 4, 0), NewProcess(2, 1, 4, 0)}
+	got := Simulate(processes, newRRPolicy(nil, RROptions{Quantum: 2}), EngineOptions{})
+	// P2 first runs at 2, a tick after arriving, but only finishes at 8.
+	want := map[int64]int64{1: 0, 2: 1}
+	for _, r := range got.Schedule {
+		if r.Response != want[r.ProcessID] {
+			t.Errorf("PID %d response = %d, want %d", r.ProcessID, r.Response, want[r.ProcessID])
+		}
+	}
+}
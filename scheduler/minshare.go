@@ -0,0 +1,221 @@
+package scheduler
+
+import (
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+// MinShareOptions configures the guaranteed-minimum-share scheduler.
+type MinShareOptions struct {
+	// Share is the fraction of the CPU each process is guaranteed while it is
+	// runnable. Zero guarantees every process an equal 1/n share.
+	Share float64
+	// Window is the length of the sliding window the guarantee applies over.
+	Window int64
+	// Quantum is how often the scheduler re-checks who is furthest behind.
+	Quantum int64
+}
+
+const defaultShareWindow = 10
+
+func (o MinShareOptions) withDefaults(processes []Process) MinShareOptions {
+	if o.Share <= 0 && len(processes) > 0 {
+		o.Share = 1 / float64(len(processes))
+	}
+	if o.Window <= 0 {
+		o.Window = defaultShareWindow
+	}
+	if o.Quantum <= 0 {
+		o.Quantum = 1
+	}
+	return o
+}
+
+// MinShare computes a schedule that, every quantum, runs the ready process
+// furthest below its guaranteed share of the CPU over the last Window ticks.
+func MinShare(processes []Process, opts MinShareOptions) Result {
+	return Simulate(processes, newMinSharePolicy(processes, opts), EngineOptions{})
+}
+
+func newMinSharePolicy(processes []Process, opts MinShareOptions) Policy {
+	return &minSharePolicy{
+		opts:    opts.withDefaults(processes),
+		arrived: map[int64]int64{},
+		started: map[int64]int64{},
+	}
+}
+
+type minSharePolicy struct {
+	opts    MinShareOptions
+	now     int64
+	arrived map[int64]int64
+	started map[int64]int64
+	used    []TimeSlice
+}
+
+func (p *minSharePolicy) Observe(ev Event) {
+	p.now = ev.Time
+	switch ev.Kind {
+	case EventArrive:
+		p.arrived[ev.PID] = ev.Time
+	case EventDispatch:
+		p.started[ev.PID] = ev.Time
+	case EventPreempt, EventExpire, EventBlock, EventLockWait, EventSemWait, EventSuspend, EventComplete:
+		if start := p.started[ev.PID]; ev.Time > start {
+			p.used = append(p.used, TimeSlice{PID: ev.PID, Start: start, Stop: ev.Time})
+		}
+		delete(p.started, ev.PID)
+	}
+	// Forget CPU use that has slid out of every window.
+	keep := p.used[:0]
+	for _, s := range p.used {
+		if s.Stop > p.now-p.opts.Window {
+			keep = append(keep, s)
+		}
+	}
+	p.used = keep
+}
+
+// deficit is how far t is below its guaranteed CPU time in the window
+// ending now.
+func (p *minSharePolicy) deficit(t *Task) float64 {
+	from := p.now - p.opts.Window
+	if arrived := p.arrived[t.ProcessID]; arrived > from {
+		from = arrived
+	}
+	var received int64
+	for _, s := range p.used {
+		if s.PID == t.ProcessID {
+			received += overlap(s.Start, s.Stop, from, p.now)
+		}
+	}
+	return p.opts.Share*float64(p.now-from) - float64(received)
+}
+
+func (p *minSharePolicy) Less(a, b *Task) bool {
+	if da, db := p.deficit(a), p.deficit(b); da != db {
+		return da > db
+	}
+	return a.Seq < b.Seq
+}
+func (p *minSharePolicy) Preemptive() bool { return false }
+func (p *minSharePolicy) Quantum() int64   { return p.opts.Quantum }
+
+// overlap is the length of the intersection of [aStart, aStop) and
+// [bStart, bStop).
+func overlap(aStart, aStop, bStart, bStop int64) int64 {
+	if bStart > aStart {
+		aStart = bStart
+	}
+	if bStop < aStop {
+		aStop = bStop
+	}
+	if aStop < aStart {
+		return 0
+	}
+	return aStop - aStart
+}
+
+type (
+	// ShareViolation is a window in which a process got less CPU than it was
+	// guaranteed for the time it was runnable.
+	ShareViolation struct {
+		PID      int64
+		Start    int64
+		Stop     int64
+		Received int64
+		Required float64
+	}
+	// ShareAudit checks a schedule against a minimum-share guarantee.
+	ShareAudit struct {
+		Share      float64
+		Window     int64
+		Violations []ShareViolation
+		// Shortfall is the total CPU time each process was short, in the
+		// order processes were given.
+		Shortfall []ProcessShortfall
+	}
+	ProcessShortfall struct {
+		PID        int64
+		Violations int
+		Shortfall  float64
+	}
+)
+
+// auditMinShare checks every process's consecutive windows, starting at its
+// arrival, for CPU received against opts.Share of the time it was runnable.
+// Time blocked on I/O, a lock or a dependency is not owed CPU.
+func auditMinShare(processes []Process, result Result, opts MinShareOptions) ShareAudit {
+	opts = opts.withDefaults(processes)
+	audit := ShareAudit{Share: opts.Share, Window: opts.Window}
+
+	exits := make(map[int64]int64, len(result.Schedule))
+	for _, row := range result.Schedule {
+		exits[row.ProcessID] = row.Exit
+	}
+	blocked := map[int64][]TimeSlice{}
+	blockedAt := map[int64]int64{}
+	for _, ev := range result.Events {
+		switch ev.Kind {
+		case EventBlock, EventLockWait, EventSemWait, EventDepWait, EventSuspend:
+			blockedAt[ev.PID] = ev.Time
+		case EventWake, EventResume:
+			blocked[ev.PID] = append(blocked[ev.PID], TimeSlice{PID: ev.PID, Start: blockedAt[ev.PID], Stop: ev.Time})
+		}
+	}
+
+	for i := range processes {
+		pid := processes[i].ProcessID
+		total := ProcessShortfall{PID: pid}
+		for start := processes[i].ArrivalTime; start+opts.Window <= exits[pid]; start += opts.Window {
+			stop := start + opts.Window
+			runnable := opts.Window
+			for _, b := range blocked[pid] {
+				runnable -= overlap(b.Start, b.Stop, start, stop)
+			}
+			var received int64
+			for _, s := range result.Gantt {
+				if s.PID == pid {
+					received += overlap(s.Start, s.Stop, start, stop)
+				}
+			}
+			// CPU is handed out in whole ticks, so a guarantee of 3.33 ticks
+			// is met by 3.
+			required := opts.Share * float64(runnable)
+			if float64(received) < math.Floor(required+1e-9) {
+				audit.Violations = append(audit.Violations, ShareViolation{
+					PID:      pid,
+					Start:    start,
+					Stop:     stop,
+					Received: received,
+					Required: required,
+				})
+				total.Violations++
+				total.Shortfall += required - float64(received)
+			}
+		}
+		audit.Shortfall = append(audit.Shortfall, total)
+	}
+
+	return audit
+}
+
+func outputShareAudit(w io.Writer, audit ShareAudit) {
+	_, _ = fmt.Fprintf(w, "Minimum share %.0f%% per %d-tick window\n", audit.Share*100, audit.Window)
+	for _, v := range audit.Violations {
+		_, _ = fmt.Fprintf(w, "Violation: PID %d in [%d,%d) received %d of %.2f\n",
+			v.PID, v.Start, v.Stop, v.Received, v.Required)
+	}
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"ID", "Violations", "Shortfall"})
+	var total float64
+	for _, s := range audit.Shortfall {
+		table.Append([]string{fmt.Sprint(s.PID), fmt.Sprint(s.Violations), fmt.Sprintf("%.2f", s.Shortfall)})
+		total += s.Shortfall
+	}
+	table.SetFooter([]string{"", "Total", fmt.Sprintf("%.2f", total)})
+	table.Render()
+}
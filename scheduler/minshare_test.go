@@ -0,0 +1,60 @@
+package scheduler
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_auditMinShare(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 10},
+		{ProcessID: 2, BurstDuration: 10},
+	}
+	opts := MinShareOptions{Share: 0.4, Window: 5}
+	tests := []struct {
+		name           string
+		result         Result
+		wantViolations []ShareViolation
+		wantShortfall  []ProcessShortfall
+	}{
+		{
+			name:   "fcfs starves the second process",
+			result: FCFS(processes),
+			wantViolations: []ShareViolation{
+				{PID: 2, Start: 0, Stop: 5, Received: 0, Required: 2},
+				{PID: 2, Start: 5, Stop: 10, Received: 0, Required: 2},
+			},
+			wantShortfall: []ProcessShortfall{{PID: 1}, {PID: 2, Violations: 2, Shortfall: 4}},
+		},
+		{
+			name:          "minshare keeps the guarantee",
+			result:        MinShare(processes, opts),
+			wantShortfall: []ProcessShortfall{{PID: 1}, {PID: 2}},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := auditMinShare(processes, tt.result, opts)
+			if !reflect.DeepEqual(got.Violations, tt.wantViolations) {
+				t.Errorf("Violations = %v, want %v", got.Violations, tt.wantViolations)
+			}
+			if !reflect.DeepEqual(got.Shortfall, tt.wantShortfall) {
+				t.Errorf("Shortfall = %v, want %v", got.Shortfall, tt.wantShortfall)
+			}
+		})
+	}
+}
+
+func Test_auditMinShare_blockedTimeNotOwed(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 2, Bursts: []int64{1, 8, 1}},
+	}
+	audit := auditMinShare(processes, FCFS(processes), MinShareOptions{Share: 0.5, Window: 5})
+	if len(audit.Violations) != 0 {
+		t.Errorf("Violations = %v, want none while blocked", audit.Violations)
+	}
+}
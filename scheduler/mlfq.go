@@ -0,0 +1,85 @@
+package scheduler
+
+// MLFQOptions configures the multilevel feedback queue scheduler.
+type MLFQOptions struct {
+	// Levels is the number of queues; zero uses len(Quanta), or 3.
+	Levels int
+	// Quanta is the quantum at each level, top first. Missing levels double
+	// the quantum of the one above, starting from 2.
+	Quanta []int64
+	// BoostInterval moves every process back to the top queue at the first
+	// scheduling event after each multiple of it; zero never boosts.
+	BoostInterval int64
+}
+
+const defaultMLFQLevels = 3
+
+func (o MLFQOptions) withDefaults() MLFQOptions {
+	if o.Levels <= 0 {
+		o.Levels = len(o.Quanta)
+	}
+	if o.Levels <= 0 {
+		o.Levels = defaultMLFQLevels
+	}
+	quanta := make([]int64, o.Levels)
+	for i := range quanta {
+		switch {
+		case i < len(o.Quanta) && o.Quanta[i] > 0:
+			quanta[i] = o.Quanta[i]
+		case i == 0:
+			quanta[i] = 2
+		default:
+			quanta[i] = 2 * quanta[i-1]
+		}
+	}
+	o.Quanta = quanta
+	return o
+}
+
+// MLFQ computes a multilevel feedback queue schedule. Processes start in the
+// top queue and drop a level each time they use up their quantum; a process
+// in a higher queue preempts any below it.
+func MLFQ(processes []Process, opts MLFQOptions) Result {
+	return Simulate(processes, newMLFQPolicy(opts), EngineOptions{})
+}
+
+func newMLFQPolicy(opts MLFQOptions) Policy {
+	return &mlfqPolicy{opts: opts.withDefaults(), level: map[int64]int{}}
+}
+
+type mlfqPolicy struct {
+	opts      MLFQOptions
+	level     map[int64]int
+	nextBoost int64
+}
+
+func (p *mlfqPolicy) Observe(ev Event) {
+	if b := p.opts.BoostInterval; b > 0 && ev.Time >= p.nextBoost {
+		if p.nextBoost > 0 {
+			p.level = map[int64]int{}
+		}
+		p.nextBoost = (ev.Time/b + 1) * b
+	}
+	if ev.Kind == EventExpire && p.level[ev.PID] < p.opts.Levels-1 {
+		p.level[ev.PID]++
+	}
+	// Aging moves a process that has waited long up a queue for good.
+	if ev.Kind == EventAge && p.level[ev.PID] > 0 {
+		p.level[ev.PID]--
+	}
+}
+
+// AgeHeadroom is how many queues t can climb by aging.
+func (p *mlfqPolicy) AgeHeadroom(t *Task) int64 { return int64(p.level[t.ProcessID]) }
+
+func (p *mlfqPolicy) Less(a, b *Task) bool {
+	if la, lb := p.level[a.ProcessID], p.level[b.ProcessID]; la != lb {
+		return la < lb
+	}
+	return a.Seq < b.Seq
+}
+func (p *mlfqPolicy) Preemptive() bool { return true }
+func (p *mlfqPolicy) Quantum() int64   { return p.opts.Quanta[0] }
+func (p *mlfqPolicy) TaskQuantum(t *Task) int64 {
+	return p.opts.Quanta[p.level[t.ProcessID]]
+}
@@ -0,0 +1,79 @@
+package scheduler
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMLFQ(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		processes []Process
+		opts      MLFQOptions
+		want      []TimeSlice
+	}{
+		{
+			name: "demotes on expired quantum",
+			processes: []Process{
+				{ProcessID: 1, BurstDuration: 10},
+				{ProcessID: 2, ArrivalTime: 1, BurstDuration: 3},
+			},
+			opts: MLFQOptions{Quanta: []int64{2, 4, 8}},
+			want: []TimeSlice{{PID: 1, Start: 0, Stop: 2}, {PID: 2, Start: 2, Stop: 4}, {PID: 1, Start: 4, Stop: 8}, {PID: 2, Start: 8, Stop: 9}, {PID: 1, Start: 9, Stop: 13}},
+		},
+		{
+			name: "higher queue preempts",
+			processes: []Process{
+				{ProcessID: 1, BurstDuration: 6},
+				{ProcessID: 2, ArrivalTime: 3, BurstDuration: 1},
+			},
+			opts: MLFQOptions{Levels: 2, Quanta: []int64{1}},
+			want: []TimeSlice{{PID: 1, Start: 0, Stop: 1}, {PID: 1, Start: 1, Stop: 3}, {PID: 2, Start: 3, Stop: 4}, {PID: 1, Start: 4, Stop: 6}, {PID: 1, Start: 6, Stop: 7}},
+		},
+		{
+			name: "boost returns to the top queue",
+			processes: []Process{
+				{ProcessID: 1, BurstDuration: 20},
+				{ProcessID: 2, ArrivalTime: 1, BurstDuration: 3},
+			},
+			opts: MLFQOptions{Quanta: []int64{2, 4, 8}, BoostInterval: 10},
+			want: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 2}, {PID: 2, Start: 2, Stop: 4}, {PID: 1, Start: 4, Stop: 8}, {PID: 2, Start: 8, Stop: 9},
+				{PID: 1, Start: 9, Stop: 17}, {PID: 1, Start: 17, Stop: 21}, {PID: 1, Start: 21, Stop: 23},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := MLFQ(tt.processes, tt.opts).Gantt; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Gantt = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMLFQOptions_withDefaults(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		opts MLFQOptions
+		want MLFQOptions
+	}{
+		{name: "zero", want: MLFQOptions{Levels: 3, Quanta: []int64{2, 4, 8}}},
+		{name: "levels from quanta", opts: MLFQOptions{Quanta: []int64{1, 3}}, want: MLFQOptions{Levels: 2, Quanta: []int64{1, 3}}},
+		{name: "extends quanta", opts: MLFQOptions{Levels: 4, Quanta: []int64{3}}, want: MLFQOptions{Levels: 4, Quanta: []int64{3, 6, 12, 24}}},
+		{name: "truncates quanta", opts: MLFQOptions{Levels: 1, Quanta: []int64{5, 10}}, want: MLFQOptions{Levels: 1, Quanta: []int64{5}}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := tt.opts.withDefaults(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("withDefaults() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package scheduler
+
+import (
+	"fmt"
+	"io"
+	"math"
+)
+
+// Nice values run from minNice, the heaviest weight, to maxNice.
+const (
+	minNice = -20
+	maxNice = 19
+)
+
+// niceWeight is the relative weight of a process at nice: each step down in
+// nice gives about 25% more CPU, as in Linux, with nice 0 weighing 1.
+func niceWeight(nice int64) float64 {
+	return math.Pow(1.25, float64(-nice))
+}
+
+// weightedQuantum scales quantum by the weight of nice, keeping at least one
+// tick.
+func weightedQuantum(quantum, nice int64) int64 {
+	if nice == 0 {
+		return quantum
+	}
+	q := int64(math.Round(float64(quantum) * niceWeight(nice)))
+	if q < 1 {
+		q = 1
+	}
+	return q
+}
+
+// ProcessShare compares the CPU time a process received with its entitlement:
+// while runnable, a process is owed the fraction of the CPU its weight makes
+// up of the weight of every runnable process.
+type ProcessShare struct {
+	PID      int64
+	Nice     int64
+	Received int64
+	Entitled float64
+}
+
+// shares replays the event log, accruing each runnable task's entitlement at
+// the rate weight over total runnable weight.
+func (e *engine) shares() []ProcessShare {
+	var (
+		byPID    = make(map[int64]*Task, len(e.tasks))
+		since    = map[int64]float64{}
+		entitled = map[int64]float64{}
+		weight   float64
+		perUnit  float64 // CPU time owed so far per unit of weight
+		last     int64
+	)
+	for _, t := range e.tasks {
+		byPID[t.ProcessID] = t
+	}
+	accrue := func(now int64) {
+		if weight > 0 {
+			perUnit += float64(now-last) / weight
+		}
+		last = now
+	}
+	stop := func(pid int64) {
+		if start, ok := since[pid]; ok {
+			w := niceWeight(byPID[pid].Nice)
+			entitled[pid] += w * (perUnit - start)
+			weight -= w
+			delete(since, pid)
+		}
+	}
+	start := func(pid int64) {
+		if _, ok := since[pid]; !ok {
+			since[pid] = perUnit
+			weight += niceWeight(byPID[pid].Nice)
+		}
+	}
+	// suspended holds whether each suspended process is runnable once it
+	// is resumed.
+	suspended := map[int64]bool{}
+	for _, ev := range e.events {
+		accrue(ev.Time)
+		runnable, isSuspended := suspended[ev.PID]
+		switch ev.Kind {
+		case EventArrive, EventWake:
+			if isSuspended {
+				suspended[ev.PID] = true
+				continue
+			}
+			start(ev.PID)
+		case EventSuspend:
+			_, runnable = since[ev.PID]
+			suspended[ev.PID] = runnable
+			stop(ev.PID)
+		case EventResume:
+			delete(suspended, ev.PID)
+			if runnable {
+				start(ev.PID)
+			}
+		case EventBlock, EventLockWait, EventSemWait, EventDepWait, EventComplete:
+			if isSuspended {
+				suspended[ev.PID] = false
+			}
+			stop(ev.PID)
+		}
+	}
+	accrue(e.now)
+	for pid := range since {
+		stop(pid)
+	}
+
+	var shares []ProcessShare
+	for _, t := range e.tasks {
+		if t.admitted {
+			shares = append(shares, ProcessShare{PID: t.ProcessID, Nice: t.Nice, Received: t.cpuDone, Entitled: entitled[t.ProcessID]})
+		}
+	}
+	return shares
+}
+
+// shareDeviation is the mean of |received - entitled| / entitled over the
+// processes owed any CPU.
+func shareDeviation(shares []ProcessShare) float64 {
+	var sum float64
+	n := 0
+	for _, s := range shares {
+		if s.Entitled > 0 {
+			sum += math.Abs(float64(s.Received)-s.Entitled) / s.Entitled
+			n++
+		}
+	}
+	if n == 0 {
+		return 0
+	}
+	return sum / float64(n)
+}
+
+// outputShares lists each process's CPU time against its entitlement, when
+// nice values make the entitlements unequal.
+func outputShares(w io.Writer, shares []ProcessShare, f Fairness) {
+	weighted := false
+	for _, s := range shares {
+		weighted = weighted || s.Nice != 0
+	}
+	if !weighted {
+		return
+	}
+	_, _ = fmt.Fprintf(w, "CPU share (deviation %.2f):\n", f.ShareDeviation)
+	for _, s := range shares {
+		_, _ = fmt.Fprintf(w, "  P%d nice %d: received %d, entitled %.1f\n", s.PID, s.Nice, s.Received, s.Entitled)
+	}
+}
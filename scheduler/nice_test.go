@@ -0,0 +1,60 @@
+package scheduler
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func Test_weightedQuantum(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		quantum, nice, want int64
+	}{
+		{quantum: 4, nice: 0, want: 4},
+		{quantum: 4, nice: -5, want: 12},
+		{quantum: 4, nice: 5, want: 1},
+		{quantum: 1, nice: 19, want: 1},
+	}
+	for _, tt := range tests {
+		if got := weightedQuantum(tt.quantum, tt.nice); got != tt.want {
+			t.Errorf("weightedQuantum(%d, %d) = %d, want %d", tt.quantum, tt.nice, got, tt.want)
+		}
+	}
+}
+
+func TestSimulate_weightedRR(t *testing.T) {
+	t.Parallel()
+	// Nice -3 weighs about twice nice 0, so 1 gets a quantum of 4 to 2's 2.
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 8, Nice: -3},
+		{ProcessID: 2, BurstDuration: 8},
+	}
+	got := Simulate(processes, rrPolicy{quantum: 2}, EngineOptions{})
+	want := []TimeSlice{
+		{PID: 1, Start: 0, Stop: 4}, {PID: 2, Start: 4, Stop: 6},
+		{PID: 1, Start: 6, Stop: 10}, {PID: 2, Start: 10, Stop: 12},
+		{PID: 2, Start: 12, Stop: 14}, {PID: 2, Start: 14, Stop: 16},
+	}
+	if !reflect.DeepEqual(got.Gantt, want) {
+		t.Errorf("Gantt = %v, want %v", got.Gantt, want)
+	}
+
+	// Both are runnable until 1 finishes at 10, then 2 is alone.
+	w := niceWeight(-3)
+	wantShares := []ProcessShare{
+		{PID: 1, Nice: -3, Received: 8, Entitled: 10 * w / (w + 1)},
+		{PID: 2, Received: 8, Entitled: 10/(w+1) + 6},
+	}
+	if len(got.Shares) != len(wantShares) {
+		t.Fatalf("Shares = %+v, want %+v", got.Shares, wantShares)
+	}
+	for i, s := range got.Shares {
+		if s.PID != wantShares[i].PID || s.Received != wantShares[i].Received || math.Abs(s.Entitled-wantShares[i].Entitled) > 1e-9 {
+			t.Errorf("Shares[%d] = %+v, want %+v", i, s, wantShares[i])
+		}
+	}
+	if got.Fairness.ShareDeviation <= 0 {
+		t.Errorf("ShareDeviation = %v, want > 0", got.Fairness.ShareDeviation)
+	}
+}
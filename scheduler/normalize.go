@@ -0,0 +1,175 @@
+package scheduler
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+)
+
+// NormalizeOptions controls how a workload is put in canonical form.
+type NormalizeOptions struct {
+	// Scale multiplies every time and duration, rounding to the nearest
+	// tick, as when converting a trace in milliseconds to coarser ticks;
+	// zero or one leaves them alone.
+	Scale float64
+	// Strip drops everything but each process's PID, burst, arrival and
+	// priority.
+	Strip bool
+	// Anonymize renames groups g1, g2, ..., lock resources r1, r2, ... and
+	// semaphores s1, s2, ... in order of first use.
+	Anonymize bool
+}
+
+// NormalizeProcesses returns processes in canonical form, for sharing and
+// grading: sorted by arrival, numbered from PID 1 in that order, with
+// dependencies following the new PIDs, and shifted so the first arrives at
+// zero. Scaling can leave a valid workload invalid, suspensions rounded
+// into each other for instance; that is an error.
+func NormalizeProcesses(processes []Process, opts NormalizeOptions) ([]Process, error) {
+	normal := make([]Process, len(processes))
+	copy(normal, processes)
+	sort.SliceStable(normal, func(i, j int) bool { return normal[i].ArrivalTime < normal[j].ArrivalTime })
+	var first int64
+	if len(normal) > 0 {
+		first = normal[0].ArrivalTime
+	}
+	pids := make(map[int64]int64, len(normal))
+	for i := range normal {
+		pids[normal[i].ProcessID] = int64(i + 1)
+	}
+	var (
+		names = map[string]string{}
+		used  = map[string]int{}
+	)
+	rename := func(prefix, name string) string {
+		if name == "" {
+			return ""
+		}
+		key := prefix + ":" + name
+		if _, ok := names[key]; !ok {
+			used[prefix]++
+			names[key] = fmt.Sprintf("%s%d", prefix, used[prefix])
+		}
+		return names[key]
+	}
+	for i := range normal {
+		p := &normal[i]
+		p.ProcessID = pids[p.ProcessID]
+		p.ArrivalTime -= first
+		if opts.Strip {
+			*p = Process{ProcessID: p.ProcessID, ArrivalTime: p.ArrivalTime, BurstDuration: p.BurstDuration, Priority: p.Priority}
+			continue
+		}
+		p.DependsOn = append([]int64(nil), p.DependsOn...)
+		for j, pid := range p.DependsOn {
+			p.DependsOn[j] = pids[pid]
+		}
+		p.Suspend = append([]Suspension(nil), p.Suspend...)
+		for j := range p.Suspend {
+			p.Suspend[j].Start = maxInt64(p.Suspend[j].Start-first, 0)
+			p.Suspend[j].Stop -= first
+		}
+		p.Locks = append([]LockUse(nil), p.Locks...)
+		p.Semaphores = append([]SemOp(nil), p.Semaphores...)
+		if opts.Anonymize {
+			p.Group = rename("g", p.Group)
+			for j := range p.Locks {
+				p.Locks[j].Resource = rename("r", p.Locks[j].Resource)
+			}
+			for j := range p.Semaphores {
+				p.Semaphores[j].Semaphore = rename("s", p.Semaphores[j].Semaphore)
+			}
+		}
+	}
+	if opts.Scale > 0 && opts.Scale != 1 {
+		for i := range normal {
+			scaleProcess(&normal[i], opts.Scale)
+		}
+		if problems := validateProcesses(normal); len(problems) > 0 {
+			return nil, fmt.Errorf("scaling by %g: %w", opts.Scale, &ValidationError{Problems: problems})
+		}
+	}
+	return normal, nil
+}
+
+// scaleProcess multiplies p's times by scale. Bursts and other durations
+// keep at least a tick, and locks and semaphore operations stay within the
+// scaled CPU time.
+func scaleProcess(p *Process, scale float64) {
+	at := func(t int64) int64 { return int64(math.Round(float64(t) * scale)) }
+	duration := func(t int64) int64 { return maxInt64(at(t), 1) }
+	p.ArrivalTime = at(p.ArrivalTime)
+	p.BurstDuration = duration(p.BurstDuration)
+	if len(p.Bursts) > 0 {
+		p.Bursts = append([]int64(nil), p.Bursts...)
+		p.BurstDuration = 0
+		for i := range p.Bursts {
+			p.Bursts[i] = duration(p.Bursts[i])
+			if i%2 == 0 {
+				p.BurstDuration += p.Bursts[i]
+			}
+		}
+	}
+	for i := range p.Locks {
+		u := &p.Locks[i]
+		u.Release = minInt64(at(u.Release), p.BurstDuration)
+		u.Acquire = minInt64(at(u.Acquire), u.Release-1)
+	}
+	for i := range p.Semaphores {
+		o := &p.Semaphores[i]
+		o.At = minInt64(at(o.At), p.BurstDuration)
+	}
+	if p.Deadline > 0 {
+		p.Deadline = duration(p.Deadline)
+	}
+	if p.Period > 0 {
+		p.Period = duration(p.Period)
+	}
+	for i := range p.Suspend {
+		s := &p.Suspend[i]
+		s.Start = at(s.Start)
+		s.Stop = maxInt64(at(s.Stop), s.Start+1)
+	}
+}
+
+// runNormalize is the normalize subcommand: it rewrites a workload file in
+// canonical form.
+func runNormalize(w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("normalize", flag.ContinueOnError)
+	var opts NormalizeOptions
+	fs.Float64Var(&opts.Scale, "scale", 1, "multiply every time and duration by this factor, rounding to whole ticks")
+	fs.BoolVar(&opts.Strip, "strip", false, "keep only the pid, burst, arrival and priority columns")
+	fs.BoolVar(&opts.Anonymize, "anonymize", false, "rename groups, lock resources and semaphores g1, r1, s1, ... in order of first use")
+	lenient := fs.Bool("lenient", false, "fix invalid processes with a warning instead of rejecting the workload")
+	if err := parseFlags(fs, "normalize", args); err != nil {
+		return err
+	}
+	if fs.NArg() > 1 {
+		return fmt.Errorf("%w: usage: normalize [-scale 1] [-strip] [-anonymize] file", ErrInvalidArgs)
+	}
+	if opts.Scale <= 0 {
+		return fmt.Errorf("%w: -scale must be positive, not %g", ErrInvalidArgs, opts.Scale)
+	}
+	f, closeFile, err := openProcessingFile(append([]string{"normalize"}, fs.Args()...)...)
+	if err != nil {
+		return err
+	}
+	defer closeFile()
+	processes, err := loadProcesses(f)
+	if err != nil {
+		return err
+	}
+	if processes, err = checkProcesses(processes, *lenient, os.Stderr); err != nil {
+		return err
+	}
+	if processes, err = NormalizeProcesses(processes, opts); err != nil {
+		return err
+	}
+	if opts.Strip {
+		return writeProcessColumns(w, processes, 4)
+	}
+	return writeProcesses(w, processes)
+}
@@ -0,0 +1,121 @@
+package scheduler
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeProcesses(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 40, ArrivalTime: 14, BurstDuration: 6, Priority: 2, Group: "alice", DependsOn: []int64{7}},
+		{ProcessID: 7, ArrivalTime: 10, BurstDuration: 4, Priority: 1, Group: "bob", Locks: []LockUse{{Resource: "disk", Acquire: 1, Release: 3}}},
+		{ProcessID: 12, ArrivalTime: 10, BurstDuration: 5, Bursts: []int64{2, 3, 3}, Group: "alice", Suspend: []Suspension{{Start: 12, Stop: 16}}},
+	}
+	tests := []struct {
+		name string
+		opts NormalizeOptions
+		want []Process
+	}{
+		{
+			name: "renumbered and shifted",
+			want: []Process{
+				{ProcessID: 1, ArrivalTime: 0, BurstDuration: 4, Priority: 1, Group: "bob", Locks: []LockUse{{Resource: "disk", Acquire: 1, Release: 3}}},
+				{ProcessID: 2, ArrivalTime: 0, BurstDuration: 5, Bursts: []int64{2, 3, 3}, Group: "alice", Suspend: []Suspension{{Start: 2, Stop: 6}}},
+				{ProcessID: 3, ArrivalTime: 4, BurstDuration: 6, Priority: 2, Group: "alice", DependsOn: []int64{1}},
+			},
+		},
+		{
+			name: "anonymized",
+			opts: NormalizeOptions{Anonymize: true},
+			want: []Process{
+				{ProcessID: 1, ArrivalTime: 0, BurstDuration: 4, Priority: 1, Group: "g1", Locks: []LockUse{{Resource: "r1", Acquire: 1, Release: 3}}},
+				{ProcessID: 2, ArrivalTime: 0, BurstDuration: 5, Bursts: []int64{2, 3, 3}, Group: "g2", Suspend: []Suspension{{Start: 2, Stop: 6}}},
+				{ProcessID: 3, ArrivalTime: 4, BurstDuration: 6, Priority: 2, Group: "g2", DependsOn: []int64{1}},
+			},
+		},
+		{
+			name: "stripped",
+			opts: NormalizeOptions{Strip: true},
+			want: []Process{
+				{ProcessID: 1, ArrivalTime: 0, BurstDuration: 4, Priority: 1},
+				{ProcessID: 2, ArrivalTime: 0, BurstDuration: 5},
+				{ProcessID: 3, ArrivalTime: 4, BurstDuration: 6, Priority: 2},
+			},
+		},
+		{
+			name: "scaled",
+			opts: NormalizeOptions{Scale: 0.5, Strip: true},
+			want: []Process{
+				{ProcessID: 1, ArrivalTime: 0, BurstDuration: 2, Priority: 1},
+				{ProcessID: 2, ArrivalTime: 0, BurstDuration: 3},
+				{ProcessID: 3, ArrivalTime: 2, BurstDuration: 3, Priority: 2},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := NormalizeProcesses(processes, tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NormalizeProcesses() =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+	if processes[0].ProcessID != 40 || processes[0].DependsOn[0] != 7 {
+		t.Errorf("NormalizeProcesses() changed its input: %+v", processes[0])
+	}
+}
+
+func Test_scaleProcess(t *testing.T) {
+	t.Parallel()
+	p := Process{
+		ArrivalTime: 10, BurstDuration: 7, Bursts: []int64{3, 1, 4}, Deadline: 20,
+		Locks:   []LockUse{{Resource: "a", Acquire: 5, Release: 7}},
+		Suspend: []Suspension{{Start: 4, Stop: 5}},
+	}
+	scaleProcess(&p, 0.3)
+	want := Process{
+		ArrivalTime: 3, BurstDuration: 2, Bursts: []int64{1, 1, 1}, Deadline: 6,
+		Locks:   []LockUse{{Resource: "a", Acquire: 1, Release: 2}},
+		Suspend: []Suspension{{Start: 1, Stop: 2}},
+	}
+	if !reflect.DeepEqual(p, want) {
+		t.Errorf("scaleProcess() =\n%+v\nwant\n%+v", p, want)
+	}
+}
+
+func TestRunNormalize(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "workload.csv")
+	if err := os.WriteFile(path, []byte("9,5,3,2,batch,,,,0,admin\n4,2,8,1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var w bytes.Buffer
+	if err := runNormalize(&w, []string{path}); err != nil {
+		t.Fatal(err)
+	}
+	if want := "1,5,0,2,batch,,,,0,admin\n2,2,5,1,batch,,,,0,\n"; w.String() != want {
+		t.Errorf("normalize =\n%s\nwant\n%s", w.String(), want)
+	}
+	w.Reset()
+	if err := runNormalize(&w, []string{"-strip", path}); err != nil {
+		t.Fatal(err)
+	}
+	if want := "1,5,0,2\n2,2,5,1\n"; w.String() != want {
+		t.Errorf("normalize -strip =\n%s\nwant\n%s", w.String(), want)
+	}
+	err := runNormalize(&w, []string{"-scale", "0", path})
+	if !errors.Is(err, ErrInvalidArgs) || !strings.Contains(err.Error(), "-scale") {
+		t.Errorf("normalize -scale 0 error = %v, want %v", err, ErrInvalidArgs)
+	}
+}
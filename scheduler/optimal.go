@@ -0,0 +1,232 @@
+package scheduler
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+const (
+	// defaultOptimalProcesses is how many processes optimal searches by
+	// default, and maxOptimalProcesses the most it ever will: the search
+	// visits every subset of the workload.
+	defaultOptimalProcesses = 12
+	maxOptimalProcesses     = 20
+)
+
+type (
+	// OptimalComparison sets the heuristics' average waits against the
+	// optimal non-preemptive schedule's.
+	OptimalComparison struct {
+		// Order is the optimal order of PIDs; Optimal is its schedule.
+		Order   []int64
+		Optimal Result
+		Rows    []OptimalRow
+	}
+	// OptimalRow is one algorithm's average wait and how far it is above
+	// the optimum; preemptive algorithms can come in below it.
+	OptimalRow struct {
+		Algorithm   string
+		AverageWait float64
+		Gap         float64
+	}
+	// optimalLabel is one way of scheduling a subset of processes first:
+	// when the last of them ends and their total wait. last and parent
+	// lead back through the subsets to the order.
+	optimalLabel struct {
+		end, wait    int64
+		last, parent int
+	}
+)
+
+// OptimalSchedule finds the non-preemptive single-CPU order of processes
+// with the least total wait, by dynamic programming over subsets: for each
+// subset it keeps every way of scheduling it first that is not beaten on
+// both end time and wait. The CPU may idle for a process that has not
+// arrived, so no heuristic that never idles can do better. Processes must
+// be single CPU bursts without locks or dependencies, and at most maxN.
+func OptimalSchedule(processes []Process, maxN int) ([]int64, Result, error) {
+	n := len(processes)
+	if maxN > maxOptimalProcesses {
+		maxN = maxOptimalProcesses
+	}
+	if n > maxN {
+		return nil, Result{}, fmt.Errorf("%w: %d processes is more than the %d an exhaustive search allows", ErrInvalidProcesses, n, maxN)
+	}
+	for i := range processes {
+		p := &processes[i]
+		if len(p.Bursts) > 1 || len(p.Locks) > 0 || len(p.Semaphores) > 0 || len(p.DependsOn) > 0 || p.Threads > 1 || p.periodic() {
+			return nil, Result{}, fmt.Errorf("%w: PID %d: the optimal search handles only single CPU bursts", ErrInvalidProcesses, p.ProcessID)
+		}
+	}
+
+	fronts := make([][]optimalLabel, 1<<n)
+	fronts[0] = []optimalLabel{{last: -1, parent: -1}}
+	for mask := 0; mask < 1<<n; mask++ {
+		fronts[mask] = paretoFront(fronts[mask])
+		for li, l := range fronts[mask] {
+			for j := 0; j < n; j++ {
+				if mask&(1<<j) != 0 {
+					continue
+				}
+				p := &processes[j]
+				start := l.end
+				if p.ArrivalTime > start {
+					start = p.ArrivalTime
+				}
+				next := mask | 1<<j
+				fronts[next] = append(fronts[next], optimalLabel{
+					end: start + p.BurstDuration, wait: l.wait + start - p.ArrivalTime, last: j, parent: li,
+				})
+			}
+		}
+	}
+
+	full := 1<<n - 1
+	best := 0
+	for i, l := range fronts[full] {
+		if l.wait < fronts[full][best].wait {
+			best = i
+		}
+	}
+	order := make([]int, n)
+	for mask, li, k := full, best, n-1; k >= 0; k-- {
+		l := fronts[mask][li]
+		order[k] = l.last
+		mask, li = mask&^(1<<l.last), l.parent
+	}
+	pids, result := scheduleInOrder(processes, order)
+	return pids, result, nil
+}
+
+// paretoFront keeps the labels that no other label beats on both end time
+// and wait, in order of end time.
+func paretoFront(labels []optimalLabel) []optimalLabel {
+	sort.SliceStable(labels, func(i, j int) bool {
+		if labels[i].end != labels[j].end {
+			return labels[i].end < labels[j].end
+		}
+		return labels[i].wait < labels[j].wait
+	})
+	front := labels[:0]
+	for _, l := range labels {
+		if len(front) == 0 || l.wait < front[len(front)-1].wait {
+			front = append(front, l)
+		}
+	}
+	return front
+}
+
+// scheduleInOrder runs processes in the given order of indexes, each as
+// soon as it has arrived and the one before it has finished.
+func scheduleInOrder(processes []Process, order []int) ([]int64, Result) {
+	var (
+		now    int64
+		pids   = make([]int64, len(order))
+		result Result
+	)
+	for k, i := range order {
+		p := &processes[i]
+		start := now
+		if p.ArrivalTime > start {
+			start = p.ArrivalTime
+		}
+		now = start + p.BurstDuration
+		pids[k] = p.ProcessID
+		result.Gantt = append(result.Gantt, TimeSlice{PID: p.ProcessID, Start: start, Stop: now})
+		result.Schedule = append(result.Schedule, ProcessResult{
+			ProcessID:  p.ProcessID,
+			Priority:   p.Priority,
+			Burst:      p.BurstDuration,
+			Arrival:    p.ArrivalTime,
+			Wait:       start - p.ArrivalTime,
+			Turnaround: now - p.ArrivalTime,
+			Exit:       now,
+			Response:   start - p.ArrivalTime,
+			Class:      p.Class,
+		})
+	}
+	result.Metrics = NewMetrics(result.Schedule, 0)
+	result.Percentiles = NewPercentiles(result.Schedule)
+	result.Slowdown = NewSlowdownStats(result.Schedule)
+	return pids, result
+}
+
+// CompareOptimal simulates each algorithm and measures its average wait
+// against the optimal non-preemptive schedule's.
+func CompareOptimal(processes []Process, algorithms []Algorithm, opts AlgorithmOptions, maxN int) (OptimalComparison, error) {
+	order, optimal, err := OptimalSchedule(processes, maxN)
+	if err != nil {
+		return OptimalComparison{}, err
+	}
+	c := OptimalComparison{Order: order, Optimal: optimal}
+	for _, a := range algorithms {
+		wait := Simulate(processes, a.New(processes, opts), EngineOptions{}).AverageWait
+		c.Rows = append(c.Rows, OptimalRow{Algorithm: a.Name, AverageWait: wait, Gap: wait - optimal.AverageWait})
+	}
+	return c, nil
+}
+
+func outputOptimal(w io.Writer, c OptimalComparison, gantt GanttOptions) {
+	outputTitle(w, "Optimal non-preemptive schedule")
+	outputGantt(w, c.Optimal.Gantt, gantt)
+	outputSchedule(w, c.Optimal.Schedule, c.Optimal.AverageWait, c.Optimal.AverageTurnaround, c.Optimal.Throughput, c.Optimal.Slowdown.Mean, false, gantt.Color)
+	_, _ = fmt.Fprintf(w, "Order: %s\n", formatPIDList(c.Order))
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Algorithm", "Average wait", "Gap", "Gap %"})
+	for _, r := range c.Rows {
+		pct := "-"
+		if c.Optimal.AverageWait > 0 {
+			pct = fmt.Sprintf("%+.1f%%", 100*r.Gap/c.Optimal.AverageWait)
+		}
+		table.Append([]string{r.Algorithm, fmt.Sprintf("%.2f", r.AverageWait), fmt.Sprintf("%+.2f", r.Gap), pct})
+	}
+	table.Render()
+}
+
+// runOptimal is the optimal subcommand: the best non-preemptive schedule
+// of a small workload, and how far each algorithm is from it.
+func runOptimal(w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("optimal", flag.ContinueOnError)
+	algo := fs.String("algo", "", "comma-separated algorithms to compare (default all the defaults)")
+	maxN := fs.Int("max-n", defaultOptimalProcesses, fmt.Sprintf("refuse workloads of more processes than this, up to %d", maxOptimalProcesses))
+	lenient := fs.Bool("lenient", false, "fix invalid processes with a warning instead of rejecting the workload")
+	ganttFlags := addGanttFlags(fs)
+	algoFlags := addAlgorithmFlags(fs)
+	if err := parseFlags(fs, "optimal", args); err != nil {
+		return err
+	}
+	if fs.NArg() > 1 {
+		return fmt.Errorf("%w: usage: optimal [-max-n N] file", ErrInvalidArgs)
+	}
+	runs, err := selectRuns(*algo)
+	if err != nil {
+		return err
+	}
+	opts, err := algoFlags.options()
+	if err != nil {
+		return err
+	}
+	f, closeFile, err := openProcessingFile(append([]string{"optimal"}, fs.Args()...)...)
+	if err != nil {
+		return err
+	}
+	defer closeFile()
+	processes, err := loadProcesses(f)
+	if err != nil {
+		return err
+	}
+	if processes, err = checkProcesses(processes, *lenient, os.Stderr); err != nil {
+		return err
+	}
+	c, err := CompareOptimal(processes, runs, opts, *maxN)
+	if err != nil {
+		return err
+	}
+	outputOptimal(w, c, ganttFlags.options(w))
+	return nil
+}
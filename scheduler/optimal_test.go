@@ -0,0 +1,100 @@
+package scheduler
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestOptimalSchedule(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		processes []Process
+		want      []int64
+		wait      float64
+	}{
+		{
+			name:      "shortest first",
+			processes: []Process{NewProcess(1, 0, 5, 0), NewProcess(2, 0, 1, 0), NewProcess(3, 0, 3, 0)},
+			want:      []int64{2, 3, 1},
+			wait:      5.0 / 3,
+		},
+		{
+			// Idling a tick for the short job beats starting the long one.
+			name:      "idles for a short arrival",
+			processes: []Process{NewProcess(1, 0, 10, 0), NewProcess(2, 1, 1, 0)},
+			want:      []int64{2, 1},
+			wait:      1,
+		},
+		{
+			name:      "gap before a late arrival",
+			processes: []Process{NewProcess(1, 0, 2, 0), NewProcess(2, 5, 2, 0)},
+			want:      []int64{1, 2},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			order, result, err := OptimalSchedule(tt.processes, defaultOptimalProcesses)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(order, tt.want) {
+				t.Errorf("order = %v, want %v", order, tt.want)
+			}
+			if math.Abs(result.AverageWait-tt.wait) > 1e-9 {
+				t.Errorf("AverageWait = %v, want %v", result.AverageWait, tt.wait)
+			}
+			if len(result.Gantt) != len(tt.processes) {
+				t.Errorf("Gantt = %v, want one slice per process", result.Gantt)
+			}
+		})
+	}
+}
+
+func TestOptimalSchedule_rejects(t *testing.T) {
+	t.Parallel()
+	multi := NewProcess(1, 0, 2, 0)
+	multi.Bursts = []int64{1, 1, 1}
+	tests := []struct {
+		name      string
+		processes []Process
+		maxN      int
+	}{
+		{name: "too many", processes: []Process{NewProcess(1, 0, 1, 0), NewProcess(2, 0, 1, 0)}, maxN: 1},
+		{name: "bursts", processes: []Process{multi}, maxN: defaultOptimalProcesses},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if _, _, err := OptimalSchedule(tt.processes, tt.maxN); !errors.Is(err, ErrInvalidProcesses) {
+				t.Errorf("err = %v, want ErrInvalidProcesses", err)
+			}
+		})
+	}
+}
+
+func TestCompareOptimal(t *testing.T) {
+	t.Parallel()
+	processes := []Process{NewProcess(1, 0, 10, 0), NewProcess(2, 1, 1, 0), NewProcess(3, 1, 2, 0)}
+	algorithms, err := selectRuns("fcfs,sjf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := CompareOptimal(processes, algorithms, AlgorithmOptions{}, defaultOptimalProcesses)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range c.Rows {
+		if r.Algorithm == "fcfs" && r.Gap <= 0 {
+			t.Errorf("fcfs gap = %v, want above the optimum", r.Gap)
+		}
+		if math.Abs(r.AverageWait-c.Optimal.AverageWait-r.Gap) > 1e-9 {
+			t.Errorf("%s gap = %v, want %v", r.Algorithm, r.Gap, r.AverageWait-c.Optimal.AverageWait)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrTimeOverflow is returned when a workload's times could grow past what
+// an int64 holds, which would otherwise wrap around into nonsense.
+var ErrTimeOverflow = errors.New("time overflows int64")
+
+// addTicks returns a + b, and false if the sum overflows.
+func addTicks(a, b int64) (int64, bool) {
+	if (b > 0 && a > math.MaxInt64-b) || (b < 0 && a < math.MinInt64-b) {
+		return 0, false
+	}
+	return a + b, true
+}
+
+// workloadHorizon bounds when the last of processes can finish on one CPU
+// without overheads: after the last arrival or resume, every CPU burst,
+// I/O wait and suspension in turn. It reports false if even that
+// overflows.
+func workloadHorizon(processes []Process) (int64, bool) {
+	var latest, total int64
+	ok := true
+	add := func(d int64) {
+		if ok && d > 0 {
+			total, ok = addTicks(total, d)
+		}
+	}
+	for _, p := range processes {
+		latest = maxInt64(latest, p.ArrivalTime)
+		if len(p.Bursts) == 0 {
+			add(p.BurstDuration)
+		}
+		for _, b := range p.Bursts {
+			add(b)
+		}
+		for _, s := range p.Suspend {
+			latest = maxInt64(latest, s.Stop)
+			add(s.Stop - s.Start)
+		}
+	}
+	if !ok {
+		return 0, false
+	}
+	return addTicks(latest, total)
+}
+
+// checkTimeRange rejects a workload whose times could overflow: its
+// horizon, or the sums of its processes' waits and turnarounds, each up to
+// the horizon, that the averages are taken from.
+func checkTimeRange(processes []Process) error {
+	horizon, ok := workloadHorizon(processes)
+	if !ok {
+		return fmt.Errorf("%w: the workload's bursts and arrivals add up to more than %d ticks", ErrTimeOverflow, int64(math.MaxInt64))
+	}
+	if n := int64(len(processes)); n > 0 && horizon > math.MaxInt64/n {
+		return fmt.Errorf("%w: %d processes that may take up to %d ticks could total more than %d ticks of waiting", ErrTimeOverflow, n, horizon, int64(math.MaxInt64))
+	}
+	return nil
+}
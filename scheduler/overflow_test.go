@@ -0,0 +1,92 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+)
+
+func Test_addTicks(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		a, b int64
+		want int64
+		ok   bool
+	}{
+		{a: 2, b: 3, want: 5, ok: true},
+		{a: math.MaxInt64 - 1, b: 1, want: math.MaxInt64, ok: true},
+		{a: math.MaxInt64, b: 1},
+		{a: math.MinInt64, b: -1},
+		{a: -4, b: 3, want: -1, ok: true},
+	}
+	for _, tt := range tests {
+		if got, ok := addTicks(tt.a, tt.b); got != tt.want || ok != tt.ok {
+			t.Errorf("addTicks(%d, %d) = %d, %t, want %d, %t", tt.a, tt.b, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func Test_checkTimeRange(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		processes []Process
+		overflow  bool
+	}{
+		{
+			name:      "small",
+			processes: []Process{{ProcessID: 1, BurstDuration: 5}, {ProcessID: 2, ArrivalTime: 3, BurstDuration: 2}},
+		},
+		{
+			name:      "one huge burst",
+			processes: []Process{{ProcessID: 1, BurstDuration: math.MaxInt64 / 2}},
+		},
+		{
+			name:      "bursts add up past int64",
+			processes: []Process{{ProcessID: 1, BurstDuration: math.MaxInt64 / 2}, {ProcessID: 2, BurstDuration: math.MaxInt64/2 + 2}},
+			overflow:  true,
+		},
+		{
+			name:      "late arrival",
+			processes: []Process{{ProcessID: 1, ArrivalTime: math.MaxInt64 - 1, BurstDuration: 2}},
+			overflow:  true,
+		},
+		{
+			name:      "I/O and suspensions count",
+			processes: []Process{{ProcessID: 1, BurstDuration: 2, Bursts: []int64{1, math.MaxInt64 - 10, 1}, Suspend: []Suspension{{Start: 0, Stop: 20}}}},
+			overflow:  true,
+		},
+		{
+			name:      "waits could add up past int64",
+			processes: []Process{{ProcessID: 1, BurstDuration: math.MaxInt64 / 3}, {ProcessID: 2, BurstDuration: 1}, {ProcessID: 3, BurstDuration: 1}},
+			overflow:  true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if err := checkTimeRange(tt.processes); errors.Is(err, ErrTimeOverflow) != tt.overflow {
+				t.Errorf("checkTimeRange() error = %v, want overflow %t", err, tt.overflow)
+			}
+		})
+	}
+}
+
+func TestSimulate_overflow(t *testing.T) {
+	t.Parallel()
+	// The workload fits, but the dispatch cost carries the run past the
+	// largest time.
+	processes := []Process{{ProcessID: 1, ArrivalTime: math.MaxInt64 - 10, BurstDuration: 5}}
+	if err := checkTimeRange(processes); err != nil {
+		t.Fatal(err)
+	}
+	result, err := SimulateContext(context.Background(), processes, fcfsPolicy{}, EngineOptions{DispatchCost: 20})
+	if !errors.Is(err, ErrTimeOverflow) {
+		t.Errorf("SimulateContext() error = %v, want %v", err, ErrTimeOverflow)
+	}
+	if !result.Overflowed || len(result.Incomplete) != 1 {
+		t.Errorf("SimulateContext() = overflowed %t, incomplete %v, want the process left incomplete", result.Overflowed, result.Incomplete)
+	}
+}
@@ -0,0 +1,68 @@
+package scheduler
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+// OverheadRow sets one algorithm's run with a dispatch cost against the
+// same run without it. A policy that dispatches often, such as round-robin
+// with a short quantum, pays the cost many times, and every payment delays
+// everything queued behind it, so turnaround grows by more than the
+// overhead alone.
+type OverheadRow struct {
+	Name       string
+	Dispatches int
+	// Overhead is the CPU time spent deciding what to run, and Share its
+	// fraction of the CPU time up to the last exit.
+	Overhead int64
+	Share    float64
+	// Turnaround and Free are the average turnaround with and without the
+	// dispatch cost.
+	Turnaround float64
+	Free       float64
+}
+
+// NewOverheadRow compares result, run with a dispatch cost, with free, the
+// same run without one.
+func NewOverheadRow(name string, result, free Result, cpus int) OverheadRow {
+	r := OverheadRow{
+		Name:       name,
+		Dispatches: result.Dispatches,
+		Overhead:   result.DispatchTime,
+		Turnaround: result.AverageTurnaround,
+		Free:       free.AverageTurnaround,
+	}
+	var end int64
+	for _, row := range result.Schedule {
+		end = maxInt64(end, row.Exit)
+	}
+	if cpus < 1 {
+		cpus = 1
+	}
+	if end > 0 {
+		r.Share = float64(r.Overhead) / float64(end*int64(cpus))
+	}
+	return r
+}
+
+// outputOverhead prints the dispatch overhead of each algorithm and what it
+// cost their turnaround.
+func outputOverhead(w io.Writer, cost int64, rows []OverheadRow) {
+	outputTitle(w, fmt.Sprintf("Dispatch overhead of %d a dispatch", cost))
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Algorithm", "Dispatches", "Overhead", "Share of CPU", "Turnaround", "Without overhead", "Increase"})
+	for _, r := range rows {
+		increase := "-"
+		if r.Free > 0 {
+			increase = fmt.Sprintf("%+.1f%%", (r.Turnaround-r.Free)/r.Free*100)
+		}
+		table.Append([]string{
+			r.Name, fmt.Sprint(r.Dispatches), fmt.Sprint(r.Overhead), fmt.Sprintf("%.1f%%", r.Share*100),
+			fmt.Sprintf("%.2f", r.Turnaround), fmt.Sprintf("%.2f", r.Free), increase,
+		})
+	}
+	table.Render()
+}
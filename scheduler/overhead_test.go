@@ -0,0 +1,36 @@
+package scheduler
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestSimulate_dispatchCost(t *testing.T) {
+	t.Parallel()
+	processes := []Process{NewProcess(1, 0, 2, 0), NewProcess(2, 0, 2, 0)}
+	// Every quantum starts with a tick of deciding.
+	got := Simulate(processes, rrPolicy{quantum: 1}, EngineOptions{DispatchCost: 1})
+	want := []TimeSlice{
+		{PID: 1, Start: 1, Stop: 2}, {PID: 2, Start: 3, Stop: 4},
+		{PID: 1, Start: 5, Stop: 6}, {PID: 2, Start: 7, Stop: 8},
+	}
+	if !reflect.DeepEqual(got.Gantt, want) {
+		t.Errorf("Gantt = %v, want %v", got.Gantt, want)
+	}
+	if got.Dispatches != 4 || got.DispatchTime != 4 {
+		t.Errorf("Dispatches = %d, DispatchTime = %d, want 4 and 4", got.Dispatches, got.DispatchTime)
+	}
+
+	// Unlike a context switch, it is paid when the same process carries on.
+	alone := Simulate(processes[:1], rrPolicy{quantum: 1}, EngineOptions{DispatchCost: 1})
+	if want := []TimeSlice{{PID: 1, Start: 1, Stop: 2}, {PID: 1, Start: 3, Stop: 4}}; !reflect.DeepEqual(alone.Gantt, want) {
+		t.Errorf("Gantt alone = %v, want %v", alone.Gantt, want)
+	}
+
+	free := Simulate(processes, rrPolicy{quantum: 1}, EngineOptions{})
+	row := NewOverheadRow("rr", got, free, 1)
+	if row.Overhead != 4 || math.Abs(row.Share-0.5) > 1e-9 || row.Turnaround != 7 || row.Free != 3.5 {
+		t.Errorf("NewOverheadRow() = %+v", row)
+	}
+}
@@ -0,0 +1,43 @@
+package scheduler
+
+import (
+	"runtime"
+	"sync"
+)
+
+// forEachParallel calls f for every index below n, on up to workers
+// goroutines at once, and returns when all calls have. workers below one
+// means one per CPU. Callers store results by index, so their order does
+// not depend on which call finishes first.
+func forEachParallel(n, workers int, f func(i int)) {
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > n {
+		workers = n
+	}
+	if workers <= 1 {
+		for i := 0; i < n; i++ {
+			f(i)
+		}
+		return
+	}
+	var (
+		wg      sync.WaitGroup
+		indexes = make(chan int)
+	)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				f(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+}
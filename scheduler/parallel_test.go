@@ -0,0 +1,51 @@
+package scheduler
+
+import (
+	"sync"
+	"testing"
+)
+
+func Test_forEachParallel(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		n       int
+		workers int
+	}{
+		{name: "sequential", n: 5, workers: 1},
+		{name: "more work than workers", n: 20, workers: 3},
+		{name: "more workers than work", n: 2, workers: 8},
+		{name: "one per CPU", n: 10},
+		{name: "nothing to do", workers: 4},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var (
+				mu           sync.Mutex
+				calls        = make([]int, tt.n)
+				active, peak int
+			)
+			forEachParallel(tt.n, tt.workers, func(i int) {
+				mu.Lock()
+				calls[i]++
+				if active++; active > peak {
+					peak = active
+				}
+				mu.Unlock()
+				mu.Lock()
+				active--
+				mu.Unlock()
+			})
+			for i, c := range calls {
+				if c != 1 {
+					t.Errorf("index %d called %d times, want 1", i, c)
+				}
+			}
+			if tt.workers > 0 && peak > tt.workers {
+				t.Errorf("%d calls ran at once, want at most %d", peak, tt.workers)
+			}
+		})
+	}
+}
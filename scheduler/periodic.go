@@ -0,0 +1,171 @@
+package scheduler
+
+import (
+	"fmt"
+	"io"
+	"math"
+)
+
+const (
+	// practicalJobs is how many jobs a periodic workload can release before
+	// it is worth warning that the hyperperiod is impractically large.
+	practicalJobs = 10000
+	// maxJobs is the most jobs a workload is allowed to expand into.
+	maxJobs = 1 << 22
+)
+
+// Expansion describes how a workload's periodic tasks were turned into
+// jobs.
+type Expansion struct {
+	// Hyperperiod is the least common multiple of the periods, or zero if
+	// it overflowed. Horizon is when the last job may be released: the
+	// hyperperiod after the latest first release, unless Periods capped
+	// each task at that many jobs.
+	Hyperperiod int64
+	Horizon     int64
+	Periods     int
+	Tasks       int
+	Jobs        int
+	// FirstJob is the PID of the first job; jobs are numbered on from it.
+	FirstJob int64
+}
+
+// periodic reports whether the process is a periodic task.
+func (p *Process) periodic() bool { return p.Period > 0 }
+
+// relativeDeadline is how long each of a periodic task's jobs has to
+// finish: its Deadline, or its Period if it has none.
+func (p *Process) relativeDeadline() int64 {
+	if p.Deadline > 0 {
+		return p.Deadline
+	}
+	return p.Period
+}
+
+func gcd(a, b int64) int64 {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+// hyperperiod is the least common multiple of the periodic tasks' periods,
+// after which their releases repeat, or false if that overflows.
+func hyperperiod(processes []Process) (int64, bool) {
+	h := int64(1)
+	for i := range processes {
+		if !processes[i].periodic() {
+			continue
+		}
+		t := processes[i].Period
+		m := h / gcd(h, t)
+		if m > math.MaxInt64/t {
+			return 0, false
+		}
+		h = m * t
+	}
+	return h, true
+}
+
+// expandPeriodic turns each periodic task into the jobs it releases, from
+// its ArrivalTime every Period, and passes other processes through. Tasks
+// release jobs over one hyperperiod after the latest first release, or, if
+// periods is positive, periods jobs each. Jobs get PIDs above every PID in
+// processes and their task's relative deadline; jobOf maps each job's PID
+// to its task's. It fails if there would be too many jobs to simulate.
+func expandPeriodic(processes []Process, periods int) (jobs []Process, jobOf map[int64]int64, x Expansion, err error) {
+	var latest int64
+	for i := range processes {
+		if processes[i].ProcessID > x.FirstJob {
+			x.FirstJob = processes[i].ProcessID
+		}
+		if processes[i].periodic() {
+			x.Tasks++
+			if processes[i].ArrivalTime > latest {
+				latest = processes[i].ArrivalTime
+			}
+		}
+	}
+	x.FirstJob++
+	if x.Tasks == 0 {
+		return processes, nil, Expansion{}, nil
+	}
+	x.Periods = periods
+	h, ok := hyperperiod(processes)
+	if ok {
+		x.Hyperperiod = h
+	}
+	if periods <= 0 {
+		if !ok || h > math.MaxInt64-latest {
+			return nil, nil, x, fmt.Errorf("%w: the hyperperiod of the periodic tasks overflows; cap it with -periods", ErrInvalidProcesses)
+		}
+		x.Horizon = latest + h
+	}
+	// Count before expanding, so a huge hyperperiod fails instead of
+	// running out of memory.
+	for i := range processes {
+		if p := &processes[i]; p.periodic() {
+			x.Jobs += p.jobCount(x)
+			if x.Jobs > maxJobs {
+				return nil, nil, x, fmt.Errorf("%w: the periodic tasks release over %d jobs; cap them with -periods", ErrInvalidProcesses, maxJobs)
+			}
+		}
+	}
+
+	jobOf = make(map[int64]int64, x.Jobs)
+	next := x.FirstJob
+	for i := range processes {
+		p := processes[i]
+		if !p.periodic() {
+			jobs = append(jobs, p)
+			continue
+		}
+		n := p.jobCount(x)
+		for k := 0; k < n; k++ {
+			job := p
+			job.ProcessID, job.ArrivalTime, job.Period = next, p.ArrivalTime+int64(k)*p.Period, 0
+			job.Deadline = p.relativeDeadline()
+			// Dependencies and suspensions name tasks, not jobs.
+			job.DependsOn, job.Suspend = nil, nil
+			jobs = append(jobs, job)
+			jobOf[next] = p.ProcessID
+			next++
+		}
+		if periods > 0 {
+			if end := p.ArrivalTime + int64(n)*p.Period; end > x.Horizon {
+				x.Horizon = end
+			}
+		}
+	}
+	return jobs, jobOf, x, nil
+}
+
+// jobCount is how many jobs the periodic task releases under x.
+func (p *Process) jobCount(x Expansion) int {
+	if x.Periods > 0 {
+		return x.Periods
+	}
+	return int((x.Horizon - p.ArrivalTime + p.Period - 1) / p.Period)
+}
+
+// outputExpansion says how periodic tasks were expanded into jobs.
+func outputExpansion(w io.Writer, x Expansion) {
+	if x.Tasks == 0 {
+		return
+	}
+	last := x.FirstJob + int64(x.Jobs) - 1
+	if x.Periods > 0 {
+		_, _ = fmt.Fprintf(w, "Periodic tasks: %d, %d jobs each up to t=%d, PIDs %d-%d\n", x.Tasks, x.Periods, x.Horizon, x.FirstJob, last)
+		return
+	}
+	_, _ = fmt.Fprintf(w, "Periodic tasks: %d over hyperperiod %d, %d jobs up to t=%d, PIDs %d-%d\n",
+		x.Tasks, x.Hyperperiod, x.Jobs, x.Horizon, x.FirstJob, last)
+}
+
+// warnExpansion warns when a hyperperiod makes for an impractically long
+// simulation.
+func warnExpansion(w io.Writer, x Expansion) {
+	if x.Periods <= 0 && x.Jobs > practicalJobs {
+		_, _ = fmt.Fprintf(w, "warning: hyperperiod %d releases %d jobs; -periods N caps each task at N jobs\n", x.Hyperperiod, x.Jobs)
+	}
+}
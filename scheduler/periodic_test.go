@@ -0,0 +1,84 @@
+package scheduler
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func Test_expandPeriodic(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 1, Period: 4},
+		{ProcessID: 2, BurstDuration: 2, ArrivalTime: 1, Period: 6, Deadline: 5},
+		{ProcessID: 3, BurstDuration: 3, ArrivalTime: 2},
+	}
+	tests := []struct {
+		name      string
+		periods   int
+		wantJobs  []Process
+		wantOf    map[int64]int64
+		wantShape Expansion
+	}{
+		{
+			name:    "one hyperperiod after the last first release",
+			periods: 0,
+			wantJobs: []Process{
+				{ProcessID: 4, BurstDuration: 1, Deadline: 4},
+				{ProcessID: 5, BurstDuration: 1, ArrivalTime: 4, Deadline: 4},
+				{ProcessID: 6, BurstDuration: 1, ArrivalTime: 8, Deadline: 4},
+				{ProcessID: 7, BurstDuration: 1, ArrivalTime: 12, Deadline: 4},
+				{ProcessID: 8, BurstDuration: 2, ArrivalTime: 1, Deadline: 5},
+				{ProcessID: 9, BurstDuration: 2, ArrivalTime: 7, Deadline: 5},
+				{ProcessID: 3, BurstDuration: 3, ArrivalTime: 2},
+			},
+			wantOf:    map[int64]int64{4: 1, 5: 1, 6: 1, 7: 1, 8: 2, 9: 2},
+			wantShape: Expansion{Hyperperiod: 12, Horizon: 13, Tasks: 2, Jobs: 6, FirstJob: 4},
+		},
+		{
+			name:    "capped periods",
+			periods: 1,
+			wantJobs: []Process{
+				{ProcessID: 4, BurstDuration: 1, Deadline: 4},
+				{ProcessID: 5, BurstDuration: 2, ArrivalTime: 1, Deadline: 5},
+				{ProcessID: 3, BurstDuration: 3, ArrivalTime: 2},
+			},
+			wantOf:    map[int64]int64{4: 1, 5: 2},
+			wantShape: Expansion{Hyperperiod: 12, Horizon: 7, Periods: 1, Tasks: 2, Jobs: 2, FirstJob: 4},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			jobs, jobOf, x, err := expandPeriodic(processes, tt.periods)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(jobs, tt.wantJobs) {
+				t.Errorf("jobs = %+v, want %+v", jobs, tt.wantJobs)
+			}
+			if !reflect.DeepEqual(jobOf, tt.wantOf) {
+				t.Errorf("jobOf = %v, want %v", jobOf, tt.wantOf)
+			}
+			if x != tt.wantShape {
+				t.Errorf("expansion = %+v, want %+v", x, tt.wantShape)
+			}
+		})
+	}
+}
+
+func Test_expandPeriodic_tooLarge(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 1, Period: 1},
+		{ProcessID: 2, BurstDuration: 1, Period: math.MaxInt64 / 2},
+	}
+	if _, _, _, err := expandPeriodic(processes, 0); !errors.Is(err, ErrInvalidProcesses) {
+		t.Errorf("error = %v, want %v", err, ErrInvalidProcesses)
+	}
+	if _, _, x, err := expandPeriodic(processes, 3); err != nil || x.Jobs != 6 {
+		t.Errorf("capped: %d jobs, error %v; want 6 jobs", x.Jobs, err)
+	}
+}
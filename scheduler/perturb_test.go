@@ -0,0 +1,131 @@
+package scheduler
+
+import (
+	"bytes"
+	"math"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_confidenceInterval(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name             string
+		samples          []float64
+		wantMean, wantCI float64
+	}{
+		{name: "empty", wantCI: math.NaN()},
+		{name: "one sample", samples: []float64{3}, wantMean: 3, wantCI: math.NaN()},
+		{name: "two samples", samples: []float64{1, 3}, wantMean: 2, wantCI: 12.706},
+	}
+	for _, tt := range tests {
+		mean, half := confidenceInterval(tt.samples)
+		if math.IsNaN(tt.wantCI) {
+			if mean != tt.wantMean || !math.IsNaN(half) {
+				t.Errorf("%s: confidenceInterval() = %v, %v, want %v, NaN", tt.name, mean, half, tt.wantMean)
+			}
+			continue
+		}
+		if mean != tt.wantMean || math.Abs(half-tt.wantCI) > 1e-9 {
+			t.Errorf("%s: confidenceInterval() = %v, %v, want %v, %v", tt.name, mean, half, tt.wantMean, tt.wantCI)
+		}
+	}
+}
+
+func Test_perturbProcesses(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 10},
+		{ProcessID: 2, ArrivalTime: 1, BurstDuration: 4, Bursts: []int64{1, 5, 3}},
+		{ProcessID: 3, BurstDuration: 4, Locks: []LockUse{{Resource: "R", Acquire: 0, Release: 4}}},
+	}
+	got := perturbProcesses(processes, 0.5, rand.New(rand.NewSource(1)))
+	for i, p := range got {
+		if len(validateProcesses([]Process{p})) > 0 {
+			t.Errorf("perturbed process %+v is invalid", p)
+		}
+		if d := float64(p.BurstDuration - processes[i].BurstDuration); math.Abs(d) > 0.5*float64(processes[i].BurstDuration)+1 {
+			t.Errorf("PID %d burst %d is more than 50%% from %d", p.ProcessID, p.BurstDuration, processes[i].BurstDuration)
+		}
+	}
+	if got[2].BurstDuration != 4 {
+		t.Errorf("burst of a process using locks changed to %d", got[2].BurstDuration)
+	}
+	if processes[1].Bursts[0] != 1 {
+		t.Error("perturbProcesses modified its input")
+	}
+}
+
+func TestPerturb_noJitter(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 5},
+		{ProcessID: 2, ArrivalTime: 1, BurstDuration: 2},
+	}
+	fcfs, _ := LookupAlgorithm("fcfs")
+	got := Perturb(processes, []Algorithm{fcfs}, AlgorithmOptions{}, EngineOptions{}, PerturbOptions{Runs: 3, Seed: 1})
+	result := FCFS(processes)
+	var want []MetricSummary
+	for _, name := range metricNames {
+		v := resultMetric(result, name)
+		want = append(want, MetricSummary{Algorithm: "fcfs", Metric: name, Mean: v, Low: v, High: v})
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Perturb() = %+v, want %+v", got, want)
+	}
+}
+
+func TestPerturb_parallel(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 5},
+		{ProcessID: 2, ArrivalTime: 1, BurstDuration: 2},
+		{ProcessID: 3, ArrivalTime: 2, BurstDuration: 7, Priority: 1},
+	}
+	var algorithms []Algorithm
+	for _, name := range []string{"fcfs", "sjf", "rr"} {
+		a, _ := LookupAlgorithm(name)
+		algorithms = append(algorithms, a)
+	}
+	opts := PerturbOptions{Runs: 10, Jitter: 0.3, Seed: 4, Parallel: 1}
+	want := Perturb(processes, algorithms, AlgorithmOptions{}, EngineOptions{}, opts)
+	opts.Parallel = 4
+	if got := Perturb(processes, algorithms, AlgorithmOptions{}, EngineOptions{}, opts); !reflect.DeepEqual(got, want) {
+		t.Errorf("Perturb() in parallel = %+v, want %+v", got, want)
+	}
+}
+
+func Test_outputPerturb(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 5},
+		{ProcessID: 2, ArrivalTime: 1, BurstDuration: 2},
+	}
+	fcfs, _ := LookupAlgorithm("fcfs")
+	tests := []struct {
+		name   string
+		runs   int
+		wantNA bool
+	}{
+		{name: "one run", runs: 1, wantNA: true},
+		{name: "three runs", runs: 3},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			opts := PerturbOptions{Runs: tt.runs, Jitter: 0.2, Seed: 1}
+			var buf bytes.Buffer
+			outputPerturb(&buf, opts, Perturb(processes, []Algorithm{fcfs}, AlgorithmOptions{}, EngineOptions{}, opts))
+			out := buf.String()
+			if got := strings.Contains(out, "n/a"); got != tt.wantNA {
+				t.Errorf("outputPerturb() =\n%s\nwant n/a %v", out, tt.wantNA)
+			}
+			if strings.Contains(out, "NaN") {
+				t.Errorf("outputPerturb() =\n%s\nwant no NaN", out)
+			}
+		})
+	}
+}
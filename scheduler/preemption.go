@@ -0,0 +1,71 @@
+package scheduler
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+type (
+	// PreemptionStats counts the involuntary context switches behind a
+	// run's response times: a process taken off the CPU by a better one, or
+	// at the end of its quantum when another process then ran.
+	PreemptionStats struct {
+		Total int
+		// Migrations counts dispatches onto a different CPU from the one
+		// the process last ran on; only runs on more than one CPU have any.
+		Migrations int
+		// Rows lists the processes preempted or migrated at least once.
+		Rows []ProcessPreemptions
+	}
+	// ProcessPreemptions is how often one process was preempted and
+	// migrated.
+	ProcessPreemptions struct {
+		ProcessID   int64
+		Preemptions int
+		Migrations  int
+	}
+)
+
+// preemptions counts each process's preemptions and migrations.
+func (e *engine) preemptions() PreemptionStats {
+	var stats PreemptionStats
+	for _, t := range e.tasks {
+		if t.preemptions == 0 && t.migrations == 0 {
+			continue
+		}
+		stats.Total += t.preemptions
+		stats.Migrations += t.migrations
+		stats.Rows = append(stats.Rows, ProcessPreemptions{ProcessID: t.ProcessID, Preemptions: t.preemptions, Migrations: t.migrations})
+	}
+	return stats
+}
+
+// outputPreemptions lists the preempted processes, and the migrated ones
+// too when there were migrations.
+func outputPreemptions(w io.Writer, stats PreemptionStats) {
+	if len(stats.Rows) == 0 {
+		return
+	}
+	counts := make([]string, 0, len(stats.Rows))
+	for _, r := range stats.Rows {
+		if r.Preemptions > 0 {
+			counts = append(counts, fmt.Sprintf("P%d %d", r.ProcessID, r.Preemptions))
+		}
+	}
+	_, _ = fmt.Fprintf(w, "Preemptions: %d", stats.Total)
+	if len(counts) > 0 {
+		_, _ = fmt.Fprintf(w, " (%s)", strings.Join(counts, ", "))
+	}
+	_, _ = fmt.Fprintln(w)
+	if stats.Migrations == 0 {
+		return
+	}
+	counts = counts[:0]
+	for _, r := range stats.Rows {
+		if r.Migrations > 0 {
+			counts = append(counts, fmt.Sprintf("P%d %d", r.ProcessID, r.Migrations))
+		}
+	}
+	_, _ = fmt.Fprintf(w, "Migrations: %d (%s)\n", stats.Migrations, strings.Join(counts, ", "))
+}
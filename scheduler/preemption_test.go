@@ -0,0 +1,75 @@
+package scheduler
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestSimulate_preemptions(t *testing.T) {
+	t.Parallel()
+	processes := []Process{NewProcess(1, 0, 4, 2), NewProcess(2, 1, 2, 1), NewProcess(3, 8, 2, 0)}
+	tests := []struct {
+		name   string
+		policy Policy
+		want   PreemptionStats
+	}{
+		{name: "fcfs", policy: fcfsPolicy{}},
+		{
+			name:   "priority",
+			policy: priorityPolicy{},
+			want:   PreemptionStats{Total: 1, Rows: []ProcessPreemptions{{ProcessID: 1, Preemptions: 1}}},
+		},
+		{
+			// P3 runs alone from t=8, so its expiries are not preemptions.
+			name:   "round robin",
+			policy: newRRPolicy(nil, RROptions{Quantum: 1}),
+			want: PreemptionStats{Total: 3, Rows: []ProcessPreemptions{
+				{ProcessID: 1, Preemptions: 2},
+				{ProcessID: 2, Preemptions: 1},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := Simulate(processes, tt.policy, EngineOptions{}).Preemptions
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Preemptions = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_outputPreemptions(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name  string
+		stats PreemptionStats
+		want  string
+	}{
+		{name: "none"},
+		{
+			name:  "preempted",
+			stats: PreemptionStats{Total: 3, Rows: []ProcessPreemptions{{ProcessID: 1, Preemptions: 2}, {ProcessID: 2, Preemptions: 1}}},
+			want:  "Preemptions: 3 (P1 2, P2 1)\n",
+		},
+		{
+			name:  "migrated",
+			stats: PreemptionStats{Total: 1, Migrations: 1, Rows: []ProcessPreemptions{{ProcessID: 1, Preemptions: 1}, {ProcessID: 2, Migrations: 1}}},
+			want:  "Preemptions: 1 (P1 1)\nMigrations: 1 (P2 1)\n",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var w bytes.Buffer
+			outputPreemptions(&w, tt.stats)
+			if w.String() != tt.want {
+				t.Errorf("outputPreemptions() = %q, want %q", w.String(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package scheduler
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// progressThreshold is how many processes a workload needs for its runs to
+// report their progress.
+const progressThreshold = 10000
+
+// Progress is how far a run has got: its clock, and how many of its
+// processes have completed out of all it will run, counting every job a
+// closed run's users will submit. Over is set once the run has ended, which
+// may leave processes incomplete.
+type Progress struct {
+	Time  int64
+	Done  int
+	Total int
+	Over  bool
+}
+
+// progress is where the run has got to.
+func (e *engine) progress() Progress {
+	total := len(e.tasks)
+	if e.opts.Closed.closed() {
+		total = e.users * e.opts.Closed.Jobs
+	}
+	return Progress{Time: e.now, Done: e.done, Total: total, Over: e.over}
+}
+
+// progressReporter writes a status line for runs going on at once: the
+// processes they have completed, the slowest clock and an estimate of
+// the time left. On a terminal the line is redrawn in place; elsewhere a
+// line is written now and then. Nothing is written for runs that finish
+// within the first interval.
+type progressReporter struct {
+	w        io.Writer
+	redraw   bool
+	interval time.Duration
+	now      func() time.Time
+
+	mu      sync.Mutex
+	names   []string
+	runs    []Progress
+	started time.Time
+	last    time.Time
+	drawn   bool
+}
+
+// newProgressReporter reports on runs of the algorithms named, each
+// expected to complete total processes, to w.
+func newProgressReporter(w io.Writer, names []string, total int) *progressReporter {
+	p := &progressReporter{w: w, redraw: ansiTerminal(w), interval: 5 * time.Second, now: time.Now, names: names}
+	if p.redraw {
+		p.interval = 250 * time.Millisecond
+	}
+	p.runs = make([]Progress, len(names))
+	for i := range p.runs {
+		p.runs[i].Total = total
+	}
+	p.started = p.now()
+	p.last = p.started
+	return p
+}
+
+// track is the OnProgress of the i'th run. It only looks at the clock every
+// so many events, to keep the cost off the simulation.
+func (p *progressReporter) track(i int) func(Progress) {
+	events := 0
+	return func(pr Progress) {
+		events++
+		if events%1024 != 0 && !pr.Over {
+			return
+		}
+		p.update(i, pr)
+	}
+}
+
+func (p *progressReporter) update(i int, pr Progress) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.runs[i] = pr
+	if now := p.now(); now.Sub(p.last) >= p.interval {
+		p.last = now
+		p.draw(now)
+	}
+}
+
+func (p *progressReporter) draw(now time.Time) {
+	line := p.status(now.Sub(p.started))
+	if p.redraw {
+		_, _ = fmt.Fprintf(p.w, "\r\x1b[K%s", line)
+	} else {
+		_, _ = fmt.Fprintln(p.w, line)
+	}
+	p.drawn = true
+}
+
+// status sums up the runs after elapsed wall time, estimating the time
+// left from the rate processes have completed at so far.
+func (p *progressReporter) status(elapsed time.Duration) string {
+	var (
+		done, total, finished int
+		clock                 int64 = -1
+	)
+	for _, r := range p.runs {
+		done += r.Done
+		// A run that has ended has no more to do, and one yet to start,
+		// waiting for a free worker, has no clock.
+		switch {
+		case r.Over:
+			finished++
+			total += r.Done
+		case r.Time > 0 && (clock < 0 || r.Time < clock):
+			total += r.Total
+			clock = r.Time
+		default:
+			total += r.Total
+		}
+	}
+	var b strings.Builder
+	b.WriteString("Simulating ")
+	if len(p.names) == 1 {
+		b.WriteString(p.names[0])
+	} else {
+		fmt.Fprintf(&b, "%d algorithms (%d done)", len(p.names), finished)
+	}
+	fmt.Fprintf(&b, ": %d/%d processes", done, total)
+	if total > 0 {
+		fmt.Fprintf(&b, " (%.0f%%)", float64(done)/float64(total)*100)
+	}
+	if clock >= 0 {
+		fmt.Fprintf(&b, ", t=%d", clock)
+	}
+	if done > 0 && done < total {
+		eta := time.Duration(float64(elapsed) * float64(total-done) / float64(done))
+		fmt.Fprintf(&b, ", ETA %s", eta.Round(time.Second))
+	} else if done < total {
+		b.WriteString(", ETA unknown")
+	}
+	return b.String()
+}
+
+// finish clears a redrawn status line, so the results start on a clean one.
+func (p *progressReporter) finish() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.drawn && p.redraw {
+		_, _ = fmt.Fprint(p.w, "\r\x1b[K")
+	}
+}
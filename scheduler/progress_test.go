@@ -0,0 +1,86 @@
+package scheduler
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEngineOptions_OnProgress(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 5, ArrivalTime: 0},
+		{ProcessID: 2, BurstDuration: 3, ArrivalTime: 1},
+		{ProcessID: 3, BurstDuration: 2, ArrivalTime: 2},
+	}
+	var got []Progress
+	Simulate(processes, fcfsPolicy{}, EngineOptions{OnProgress: func(p Progress) { got = append(got, p) }})
+	if len(got) == 0 {
+		t.Fatal("OnProgress was never called")
+	}
+	for i := 1; i < len(got); i++ {
+		if got[i].Done < got[i-1].Done || got[i].Time < got[i-1].Time {
+			t.Errorf("progress went backwards: %+v after %+v", got[i], got[i-1])
+		}
+	}
+	if last := got[len(got)-1]; last != (Progress{Time: 10, Done: 3, Total: 3, Over: true}) {
+		t.Errorf("last progress = %+v, want {Time:10 Done:3 Total:3 Over:true}", last)
+	}
+
+	// A closed run counts every job its users will submit.
+	got = nil
+	Simulate(processes[:1], fcfsPolicy{}, EngineOptions{Closed: ClosedOptions{Jobs: 4}, OnProgress: func(p Progress) { got = append(got, p) }})
+	if last := got[len(got)-1]; last.Done != 4 || last.Total != 4 {
+		t.Errorf("closed run's last progress = %+v, want 4 of 4 done", last)
+	}
+}
+
+func Test_progressReporter(t *testing.T) {
+	t.Parallel()
+	var out bytes.Buffer
+	p := newProgressReporter(&out, []string{"fcfs", "rr"}, 100)
+	clock := p.started
+	p.now = func() time.Time { return clock }
+
+	// Nothing is written within the first interval.
+	p.update(0, Progress{Time: 40, Done: 20, Total: 100})
+	if out.Len() > 0 {
+		t.Errorf("wrote %q before the first interval", out.String())
+	}
+	clock = clock.Add(p.interval)
+	p.update(1, Progress{Time: 30, Done: 30, Total: 100})
+	want := "Simulating 2 algorithms (0 done): 50/200 processes (25%), t=30, ETA 15s\n"
+	if out.String() != want {
+		t.Errorf("status = %q, want %q", out.String(), want)
+	}
+
+	// A run that has ended counts as done, even with processes left.
+	out.Reset()
+	clock = clock.Add(p.interval)
+	p.update(1, Progress{Time: 60, Done: 80, Total: 100, Over: true})
+	if got := out.String(); !strings.HasPrefix(got, "Simulating 2 algorithms (1 done): 100/180 processes (56%), t=40,") {
+		t.Errorf("status = %q", got)
+	}
+	p.finish()
+}
+
+func Test_progressReporter_single(t *testing.T) {
+	t.Parallel()
+	p := newProgressReporter(&bytes.Buffer{}, []string{"sjf"}, 10)
+	tests := []struct {
+		name string
+		run  Progress
+		want string
+	}{
+		{name: "not started", run: Progress{Total: 10}, want: "Simulating sjf: 0/10 processes (0%), ETA unknown"},
+		{name: "running", run: Progress{Time: 7, Done: 5, Total: 10}, want: "Simulating sjf: 5/10 processes (50%), t=7, ETA 4s"},
+		{name: "over", run: Progress{Time: 9, Done: 10, Total: 10, Over: true}, want: "Simulating sjf: 10/10 processes (100%)"},
+	}
+	for _, tt := range tests {
+		p.runs[0] = tt.run
+		if got := p.status(4 * time.Second); got != tt.want {
+			t.Errorf("%s: status() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package scheduler
+
+import "container/heap"
+
+// StableOrder is implemented by policies whose Less gives the same answer for
+// two tasks for as long as both wait in the ready queue. The engine keeps
+// their ready queue in a heap, making each dispatch O(log n) rather than a
+// scan of the whole queue.
+type StableOrder interface {
+	StableOrder() bool
+}
+
+// readyQueue holds the tasks waiting for the CPU in policy order.
+type readyQueue interface {
+	Len() int
+	Add(t *Task)
+	// Best is the task the policy would dispatch next; the queue must not
+	// be empty.
+	Best() *Task
+	// Take removes and returns Best.
+	Take() *Task
+	// Fix restores the order after a queued task's priority changed.
+	Fix(t *Task)
+}
+
+func newReadyQueue(policy Policy) readyQueue {
+	if s, ok := policy.(StableOrder); ok && s.StableOrder() {
+		return &heapQueue{less: policy.Less}
+	}
+	return &scanQueue{less: policy.Less}
+}
+
+// scanQueue finds the best task by scanning, for policies whose order changes
+// while tasks wait.
+type scanQueue struct {
+	tasks []*Task
+	less  func(a, b *Task) bool
+}
+
+func (q *scanQueue) Len() int    { return len(q.tasks) }
+func (q *scanQueue) Add(t *Task) { q.tasks = append(q.tasks, t) }
+func (q *scanQueue) Best() *Task { return q.tasks[q.best()] }
+func (q *scanQueue) Fix(*Task)   {}
+func (q *scanQueue) Take() *Task {
+	i := q.best()
+	t := q.tasks[i]
+	q.tasks = append(q.tasks[:i], q.tasks[i+1:]...)
+	return t
+}
+
+func (q *scanQueue) best() int {
+	best := 0
+	for i := 1; i < len(q.tasks); i++ {
+		if q.less(q.tasks[i], q.tasks[best]) {
+			best = i
+		}
+	}
+	return best
+}
+
+// heapQueue is a binary heap ordered by the policy.
+type heapQueue struct {
+	tasks []*Task
+	less  func(a, b *Task) bool
+}
+
+func (q *heapQueue) Add(t *Task) { heap.Push(q, t) }
+func (q *heapQueue) Best() *Task { return q.tasks[0] }
+func (q *heapQueue) Take() *Task { return heap.Pop(q).(*Task) }
+func (q *heapQueue) Fix(t *Task) { heap.Fix(q, t.index) }
+
+func (q *heapQueue) Len() int           { return len(q.tasks) }
+func (q *heapQueue) Less(i, j int) bool { return q.less(q.tasks[i], q.tasks[j]) }
+func (q *heapQueue) Swap(i, j int) {
+	q.tasks[i], q.tasks[j] = q.tasks[j], q.tasks[i]
+	q.tasks[i].index, q.tasks[j].index = i, j
+}
+func (q *heapQueue) Push(x interface{}) {
+	t := x.(*Task)
+	t.index = len(q.tasks)
+	q.tasks = append(q.tasks, t)
+}
+func (q *heapQueue) Pop() interface{} {
+	n := len(q.tasks) - 1
+	t := q.tasks[n]
+	q.tasks[n] = nil
+	q.tasks = q.tasks[:n]
+	return t
+}
@@ -0,0 +1,39 @@
+package scheduler
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// scanned hides a policy's StableOrder so the engine scans its queue.
+type scanned struct{ Policy }
+
+func Test_newReadyQueue_heapMatchesScan(t *testing.T) {
+	t.Parallel()
+	processes := GenerateProcesses(GenerateOptions{
+		Count:       500,
+		MaxBurst:    10,
+		MaxArrival:  2000,
+		MaxPriority: 5,
+		Interactive: 0.3,
+		SplitProb:   0.2,
+		ThinkMean:   3,
+	}, rand.New(rand.NewSource(3)))
+	for _, name := range []string{"fcfs", "sjf", "priority", "rr"} {
+		name := name
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			algorithm, _ := LookupAlgorithm(name)
+			policy := algorithm.New(processes, AlgorithmOptions{})
+			if _, ok := newReadyQueue(policy).(*heapQueue); !ok {
+				t.Fatalf("%s does not use a heap", name)
+			}
+			heaped := Simulate(processes, policy, EngineOptions{})
+			scan := Simulate(processes, scanned{algorithm.New(processes, AlgorithmOptions{})}, EngineOptions{})
+			if !reflect.DeepEqual(heaped.Gantt, scan.Gantt) || !reflect.DeepEqual(heaped.Schedule, scan.Schedule) {
+				t.Error("heap and scan schedules differ")
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package scheduler
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"os"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+// QueueingReport compares a workload's simulated FCFS wait with what
+// queueing theory predicts from its arrival rate and service times. A
+// workload drawn with Poisson arrivals and exponential service is an M/M/1
+// queue; rounding to whole ticks makes it M/G/1 strictly, so the
+// Pollaczek-Khinchine wait from the measured service times is given too.
+type QueueingReport struct {
+	Processes int
+	// ArrivalRate is the processes arriving a tick over the span of the
+	// arrivals, ServiceMean their average burst, and Utilization their
+	// product.
+	ArrivalRate float64
+	ServiceMean float64
+	// ServiceSquare is the mean of the squared bursts.
+	ServiceSquare float64
+	Utilization   float64
+	// Stable is unset when utilization is 1 or more, when the queue grows
+	// without bound and there is no steady-state wait to predict.
+	Stable bool
+	// MM1 and MG1 are the predicted average waits in the queue.
+	MM1       float64
+	MG1       float64
+	Simulated float64
+}
+
+// AnalyzeQueueing measures processes' arrival rate and service times and
+// simulates them under FCFS. The arrival rate needs at least two processes
+// arriving at different times.
+func AnalyzeQueueing(processes []Process) (QueueingReport, error) {
+	if len(processes) < 2 {
+		return QueueingReport{}, fmt.Errorf("%w: a queueing comparison needs at least 2 processes", ErrInvalidProcesses)
+	}
+	first, last := processes[0].ArrivalTime, processes[0].ArrivalTime
+	var sum, square float64
+	for _, p := range processes {
+		first, last = minInt64(first, p.ArrivalTime), maxInt64(last, p.ArrivalTime)
+		b := float64(p.BurstDuration)
+		sum += b
+		square += b * b
+	}
+	if last == first {
+		return QueueingReport{}, fmt.Errorf("%w: every process arrives at %d, so there is no arrival rate", ErrInvalidProcesses, first)
+	}
+	n := float64(len(processes))
+	r := QueueingReport{
+		Processes: len(processes),
+		// n arrivals make n-1 gaps between first and last.
+		ArrivalRate:   (n - 1) / float64(last-first),
+		ServiceMean:   sum / n,
+		ServiceSquare: square / n,
+	}
+	r.Utilization = r.ArrivalRate * r.ServiceMean
+	if r.Stable = r.Utilization < 1; r.Stable {
+		r.MM1 = r.Utilization * r.ServiceMean / (1 - r.Utilization)
+		r.MG1 = r.ArrivalRate * r.ServiceSquare / (2 * (1 - r.Utilization))
+	}
+	r.Simulated = Simulate(processes, fcfsPolicy{}, EngineOptions{}).AverageWait
+	return r, nil
+}
+
+func outputQueueing(w io.Writer, r QueueingReport) {
+	outputTitle(w, "M/M/1 comparison")
+	_, _ = fmt.Fprintf(w, "%d processes; arrival rate %.3f, mean service %.2f (rate %.3f), utilization %.3f\n",
+		r.Processes, r.ArrivalRate, r.ServiceMean, 1/r.ServiceMean, r.Utilization)
+	if !r.Stable {
+		_, _ = fmt.Fprintf(w, "Utilization is at least 1: the queue grows without bound, so there is no prediction. FCFS simulated average wait %.2f\n", r.Simulated)
+		return
+	}
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Model", "Average wait", "Simulated - model", "Relative"})
+	for _, m := range []struct {
+		name string
+		wait float64
+	}{{"M/M/1", r.MM1}, {"M/G/1 (Pollaczek-Khinchine)", r.MG1}} {
+		relative := "-"
+		if m.wait > 0 {
+			relative = fmt.Sprintf("%+.1f%%", (r.Simulated-m.wait)/m.wait*100)
+		}
+		table.Append([]string{m.name, fmt.Sprintf("%.2f", m.wait), fmt.Sprintf("%+.2f", r.Simulated-m.wait), relative})
+	}
+	table.SetFooter([]string{"FCFS simulated", fmt.Sprintf("%.2f", r.Simulated), "", ""})
+	table.Render()
+	// A finite run starts from an empty queue, so it waits less than the
+	// steady state does, the more so the busier the system.
+	_, _ = fmt.Fprintln(w, "The models are for the steady state; a finite run starting empty waits less, more so near full utilization")
+}
+
+// poissonArrivals draws n arrival times of a Poisson process with rate
+// arrivals a tick, rounded to whole ticks.
+func poissonArrivals(n int, rate float64, rng *rand.Rand) []int64 {
+	arrivals := make([]int64, n)
+	var t float64
+	for i := range arrivals {
+		arrivals[i] = int64(math.Round(t))
+		t += rng.ExpFloat64() / rate
+	}
+	return arrivals
+}
+
+// geometricBurst draws a burst of the given mean from the geometric
+// distribution, the whole-tick counterpart of the exponential: memoryless,
+// and at least one tick.
+func geometricBurst(mean float64, rng *rand.Rand) int64 {
+	if mean <= 1 {
+		return 1
+	}
+	return int64(math.Ceil(rng.ExpFloat64() / -math.Log1p(-1/mean)))
+}
+
+// runQueueing is the mm1 subcommand: compare a workload's FCFS average wait
+// with the M/M/1 and M/G/1 predictions for its measured rates.
+func runQueueing(w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("mm1", flag.ContinueOnError)
+	lenient := fs.Bool("lenient", false, "fix invalid processes with a warning instead of rejecting the workload")
+	if err := parseFlags(fs, "mm1", args); err != nil {
+		return err
+	}
+	if fs.NArg() > 1 {
+		return fmt.Errorf("%w: usage: mm1 file", ErrInvalidArgs)
+	}
+	f, closeFile, err := openProcessingFile(append([]string{"mm1"}, fs.Args()...)...)
+	if err != nil {
+		return err
+	}
+	defer closeFile()
+	processes, err := loadProcesses(f)
+	if err != nil {
+		return err
+	}
+	if processes, err = checkProcesses(processes, *lenient, os.Stderr); err != nil {
+		return err
+	}
+	report, err := AnalyzeQueueing(processes)
+	if err != nil {
+		return err
+	}
+	outputQueueing(w, report)
+	return nil
+}
@@ -0,0 +1,76 @@
+package scheduler
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestAnalyzeQueueing(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		processes []Process
+		want      QueueingReport
+		wantErr   bool
+	}{
+		{
+			// Rate 1/2 of unit bursts: utilization 1/2, so M/M/1 predicts
+			// rho*S/(1-rho) = 1 and M/G/1, knowing the bursts are constant,
+			// half that. Evenly spaced, nothing waits.
+			name:      "evenly spaced",
+			processes: []Process{NewProcess(1, 0, 1, 0), NewProcess(2, 2, 1, 0), NewProcess(3, 4, 1, 0)},
+			want: QueueingReport{
+				Processes: 3, ArrivalRate: 0.5, ServiceMean: 1, ServiceSquare: 1, Utilization: 0.5,
+				Stable: true, MM1: 1, MG1: 0.5,
+			},
+		},
+		{
+			name:      "saturated",
+			processes: []Process{NewProcess(1, 0, 4, 0), NewProcess(2, 2, 4, 0)},
+			want: QueueingReport{
+				Processes: 2, ArrivalRate: 0.5, ServiceMean: 4, ServiceSquare: 16, Utilization: 2, Simulated: 1,
+			},
+		},
+		{
+			name:      "simultaneous",
+			processes: []Process{NewProcess(1, 3, 1, 0), NewProcess(2, 3, 1, 0)},
+			wantErr:   true,
+		},
+		{
+			name:      "alone",
+			processes: []Process{NewProcess(1, 0, 1, 0)},
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := AnalyzeQueueing(tt.processes)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidProcesses) {
+					t.Errorf("AnalyzeQueueing() err = %v, want ErrInvalidProcesses", err)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("AnalyzeQueueing() = %+v, %v, want %+v", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateProcesses_poisson(t *testing.T) {
+	t.Parallel()
+	processes := GenerateProcesses(GenerateOptions{
+		Count: 20000, MaxBurst: 10, MaxArrival: 20, MaxPriority: 5, ArrivalRate: 0.25, ServiceMean: 3,
+	}, newRand(1))
+	r, err := AnalyzeQueueing(processes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(r.ArrivalRate-0.25) > 0.01 || math.Abs(r.ServiceMean-3) > 0.1 {
+		t.Errorf("arrival rate %.3f, mean service %.3f, want about 0.25 and 3", r.ArrivalRate, r.ServiceMean)
+	}
+}
@@ -0,0 +1,40 @@
+package scheduler
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"math/rand"
+	"time"
+)
+
+// newRand is where every stochastic feature gets its random numbers:
+// lottery scheduling, generated workloads, perturbation and benchmarks. Each
+// draws from its own source seeded from -seed, so the same seed repeats a
+// run exactly, whatever else runs alongside it, and its output can be
+// checked against a golden file. Code that takes a *rand.Rand can be handed
+// any rand.Source instead.
+func newRand(seed int64) *rand.Rand {
+	return rand.New(rand.NewSource(seed))
+}
+
+// defaultSeed is -seed when it is not given, so that runs repeat unless
+// asked not to.
+const defaultSeed = 1
+
+// addSeedFlag adds the -seed flag every command with random draws shares:
+// defaultSeed unless given, and 0 to pick one from the clock with pickSeed.
+func addSeedFlag(fs *flag.FlagSet, usage string) *int64 {
+	return fs.Int64("seed", defaultSeed, usage+" (0 picks one from the clock)")
+}
+
+// pickSeed is seed, or when it is 0 one picked from the clock and written
+// to w, so the run can be made again with it.
+func pickSeed(w io.Writer, seed int64) int64 {
+	if seed != 0 {
+		return seed
+	}
+	seed = time.Now().UnixNano()
+	_, _ = fmt.Fprintf(w, "-seed %d picked from the clock\n", seed)
+	return seed
+}
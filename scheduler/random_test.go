@@ -0,0 +1,34 @@
+package scheduler
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func Test_pickSeed(t *testing.T) {
+	t.Parallel()
+	var w bytes.Buffer
+	if got := pickSeed(&w, 7); got != 7 || w.Len() != 0 {
+		t.Errorf("pickSeed(7) = %d, wrote %q, want 7 and nothing", got, w.String())
+	}
+	got := pickSeed(&w, 0)
+	if got == 0 || !strings.Contains(w.String(), fmt.Sprintf("-seed %d", got)) {
+		t.Errorf("pickSeed(0) = %d, wrote %q, want a seed from the clock, reported", got, w.String())
+	}
+}
+
+func Test_runGenerate_defaultSeed(t *testing.T) {
+	t.Parallel()
+	var a, b bytes.Buffer
+	if err := runGenerate(&a, []string{"-n", "5"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := runGenerate(&b, []string{"-n", "5", "-seed", fmt.Sprint(defaultSeed)}); err != nil {
+		t.Fatal(err)
+	}
+	if a.String() != b.String() {
+		t.Errorf("runGenerate() without -seed =\n%s\nwant the workload of -seed %d\n%s", a.String(), defaultSeed, b.String())
+	}
+}
@@ -0,0 +1,234 @@
+package scheduler
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+type (
+	// Factory describes a scheduling algorithm and builds its policy for a
+	// workload.
+	Factory struct {
+		Title       string
+		Description string
+		// Default algorithms run when -algo is not given.
+		Default bool
+		New     func(processes []Process, opts AlgorithmOptions) Policy
+	}
+	// AlgorithmOptions holds the parameters of every algorithm that has any;
+	// each factory reads its own.
+	AlgorithmOptions struct {
+		RR       RROptions       `json:"rr"`
+		MLFQ     MLFQOptions     `json:"mlfq"`
+		MinShare MinShareOptions `json:"min_share"`
+		SPN      SPNOptions      `json:"spn"`
+		// Containers configures the container algorithm.
+		Containers ContainerOptions `json:"containers"`
+		// Seed seeds the random draws of stochastic algorithms such as
+		// lottery.
+		Seed int64 `json:"seed"`
+	}
+	// Algorithm is a registered Factory and the name it is selected by.
+	Algorithm struct {
+		Name string
+		Factory
+	}
+)
+
+// registry holds every algorithm in registration order, which is also the
+// order they run and are listed in. The built-in algorithms come first since
+// package variables are initialized before any init function calls Register.
+var registry = []Algorithm{
+	{"fcfs", Factory{
+		Title:       "First-come, first-serve",
+		Description: "runs processes to completion in arrival order",
+		Default:     true,
+		New:         func([]Process, AlgorithmOptions) Policy { return fcfsPolicy{} },
+	}},
+	{"sjf", Factory{
+		Title:       "Shortest-job-first",
+		Description: "preemptively runs the process with the least CPU time left",
+		Default:     true,
+		New:         func([]Process, AlgorithmOptions) Policy { return sjfPolicy{} },
+	}},
+	{"priority", Factory{
+		Title:       "Priority",
+		Description: "preemptively runs the process with the lowest priority value",
+		Default:     true,
+		New:         func([]Process, AlgorithmOptions) Policy { return priorityPolicy{} },
+	}},
+	{"rr", Factory{
+		Title:       "Round-robin",
+		Description: "cycles through ready processes, by default with a quantum of the shortest burst",
+		Default:     true,
+		New:         func(p []Process, o AlgorithmOptions) Policy { return newRRPolicy(p, o.RR) },
+	}},
+	{"minshare", Factory{
+		Title:       "Guaranteed minimum share",
+		Description: "each tick runs the process furthest below its guaranteed CPU share",
+		New:         func(p []Process, o AlgorithmOptions) Policy { return newMinSharePolicy(p, o.MinShare) },
+	}},
+	{"mlfq", Factory{
+		Title:       "Multilevel feedback queue",
+		Description: "demotes processes that use up their quantum to longer-quantum, lower queues",
+		New:         func(_ []Process, o AlgorithmOptions) Policy { return newMLFQPolicy(o.MLFQ) },
+	}},
+	{"fairshare", Factory{
+		Title:       "Guaranteed scheduling",
+		Description: "each tick runs the process furthest below an equal 1/n share of the CPU since it arrived",
+		New:         func(p []Process, _ AlgorithmOptions) Policy { return newFairSharePolicy(p, false) },
+	}},
+	{"groupshare", Factory{
+		Title:       "Group fair-share",
+		Description: "splits the CPU equally between groups, then between each group's processes",
+		New:         func(p []Process, _ AlgorithmOptions) Policy { return newFairSharePolicy(p, true) },
+	}},
+	{"spn", Factory{
+		Title:       "Shortest process next",
+		Description: "runs the process whose next CPU burst is predicted shortest by an exponential average",
+		New:         func(_ []Process, o AlgorithmOptions) Policy { return newSPNPolicy(o.SPN) },
+	}},
+}
+
+// Register makes an algorithm available by name to -algo, rubrics, the step
+// and serve subcommands and the list subcommand. Call it from an init
+// function in the package defining the policy, which may be one importing
+// this one. It panics if the name is taken or the factory cannot build a
+// policy.
+func Register(name string, factory Factory) {
+	if factory.New == nil {
+		panic("scheduler: Register factory for " + name + " is missing New")
+	}
+	if _, dup := LookupAlgorithm(name); dup {
+		panic("scheduler: Register called twice for " + name)
+	}
+	registry = append(registry, Algorithm{Name: name, Factory: factory})
+}
+
+// LookupAlgorithm finds the algorithm registered as name.
+func LookupAlgorithm(name string) (Algorithm, bool) {
+	for _, algorithm := range registry {
+		if algorithm.Name == name {
+			return algorithm, true
+		}
+	}
+	return Algorithm{}, false
+}
+
+func defaultAlgorithms() []Algorithm {
+	var defaults []Algorithm
+	for _, algorithm := range registry {
+		if algorithm.Default {
+			defaults = append(defaults, algorithm)
+		}
+	}
+	return defaults
+}
+
+// algorithmFlags are the command line flags for AlgorithmOptions.
+type algorithmFlags struct {
+	quantum, switchCost  *int64
+	quanta               *string
+	mlfqLevels           *int
+	mlfqQuanta           *string
+	mlfqBoost            *int64
+	spnAlpha, spnInitial *float64
+	containers           *string
+	containerSlice       *int64
+}
+
+func addAlgorithmFlags(fs *flag.FlagSet) algorithmFlags {
+	return algorithmFlags{
+		quantum:        fs.Int64("quantum", 0, "round-robin quantum (default the shortest burst)"),
+		switchCost:     fs.Int64("switch-cost", 0, "ticks lost to every round-robin context switch"),
+		quanta:         fs.String("quanta", "", "comma-separated quantum per priority level, best first, for rr and, unless -mlfq-quanta is given, mlfq"),
+		mlfqLevels:     fs.Int("mlfq-levels", 0, "number of MLFQ queues (default 3)"),
+		mlfqQuanta:     fs.String("mlfq-quanta", "", "comma-separated MLFQ quantum per level, top first (default doubling from 2)"),
+		mlfqBoost:      fs.Int64("mlfq-boost", 0, "move every MLFQ process back to the top queue this often"),
+		spnAlpha:       fs.Float64("spn-alpha", 0, "weight of the last burst in SPN's prediction, 0 to 1 (default 0.5)"),
+		spnInitial:     fs.Float64("spn-initial", 0, "SPN's guess for a process's first burst (default 10)"),
+		containers:     fs.String("containers", "", "comma-separated name=weight[:algorithm] containers for the container algorithm, named by the group column"),
+		containerSlice: fs.Int64("container-slice", 0, "longest a container keeps the CPU before the others get a turn (default the round-robin quantum)"),
+	}
+}
+
+// options returns the parsed flags; MinShare is left to the caller.
+func (f algorithmFlags) options() (AlgorithmOptions, error) {
+	opts := AlgorithmOptions{
+		RR:   RROptions{Quantum: *f.quantum, SwitchCost: *f.switchCost},
+		MLFQ: MLFQOptions{Levels: *f.mlfqLevels, BoostInterval: *f.mlfqBoost},
+		SPN:  SPNOptions{Alpha: *f.spnAlpha, Initial: *f.spnInitial},
+	}
+	opts.Containers.Slice = *f.containerSlice
+	var err error
+	if opts.Containers.Containers, err = parseContainers(*f.containers); err != nil {
+		return AlgorithmOptions{}, err
+	}
+	if opts.RR.Quanta, err = parseQuanta("quanta", *f.quanta); err != nil {
+		return AlgorithmOptions{}, err
+	}
+	opts.MLFQ.Quanta = opts.RR.Quanta
+	if *f.mlfqQuanta != "" {
+		if opts.MLFQ.Quanta, err = parseQuanta("mlfq-quanta", *f.mlfqQuanta); err != nil {
+			return AlgorithmOptions{}, err
+		}
+	}
+	if *f.spnAlpha < 0 || *f.spnAlpha > 1 {
+		return AlgorithmOptions{}, fmt.Errorf("%w: -spn-alpha must be between 0 and 1", ErrInvalidArgs)
+	}
+	if *f.quantum < 0 || *f.switchCost < 0 || *f.mlfqLevels < 0 || *f.mlfqBoost < 0 || *f.spnInitial < 0 || *f.containerSlice < 0 {
+		return AlgorithmOptions{}, fmt.Errorf("%w: algorithm options must not be negative", ErrInvalidArgs)
+	}
+	return opts, nil
+}
+
+// parseQuanta reads the comma-separated quanta of flag name, or returns nil
+// for an empty list.
+func parseQuanta(name, s string) ([]int64, error) {
+	if s == "" {
+		return nil, nil
+	}
+	var quanta []int64
+	for _, q := range strings.Split(s, ",") {
+		n, err := strconv.ParseInt(strings.TrimSpace(q), 10, 64)
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("%w: bad -%s entry %q", ErrInvalidArgs, name, q)
+		}
+		quanta = append(quanta, n)
+	}
+	return quanta, nil
+}
+
+func outputAlgorithms(w io.Writer, algorithms []Algorithm) {
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Name", "Title", "Default", "Description"})
+	for _, algorithm := range algorithms {
+		isDefault := ""
+		if algorithm.Default {
+			isDefault = "yes"
+		}
+		table.Append([]string{algorithm.Name, algorithm.Title, isDefault, algorithm.Description})
+	}
+	table.Render()
+}
+
+func runList(w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("list", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return flagError(err)
+	}
+	if fs.NArg() != 0 {
+		return fmt.Errorf("%w: usage: list", ErrInvalidArgs)
+	}
+	outputAlgorithms(w, registry)
+	if len(metricRegistry) > 0 {
+		outputMetrics(w, metricRegistry)
+	}
+
+	return nil
+}
@@ -0,0 +1,113 @@
+package scheduler
+
+import (
+	"errors"
+	"flag"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestRegister is not parallel because it changes the shared registry.
+func TestRegister(t *testing.T) {
+	saved := registry
+	t.Cleanup(func() { registry = saved })
+	registry = append([]Algorithm(nil), registry...)
+
+	Register("lifo", Factory{
+		Title:       "Last-in, first-out",
+		Description: "runs the newest ready process",
+		New:         func([]Process, AlgorithmOptions) Policy { return lifoPolicy{} },
+	})
+	runs, err := selectRuns("lifo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := Simulate([]Process{
+		{ProcessID: 1, BurstDuration: 2},
+		{ProcessID: 2, ArrivalTime: 1, BurstDuration: 2},
+		{ProcessID: 3, ArrivalTime: 1, BurstDuration: 2},
+	}, runs[0].New(nil, AlgorithmOptions{}), EngineOptions{})
+	want := []TimeSlice{{PID: 1, Start: 0, Stop: 2}, {PID: 3, Start: 2, Stop: 4}, {PID: 2, Start: 4, Stop: 6}}
+	if !reflect.DeepEqual(result.Gantt, want) {
+		t.Errorf("Gantt = %v, want %v", result.Gantt, want)
+	}
+
+	var w strings.Builder
+	if err := runList(&w, nil); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(w.String(), "runs the newest ready process") {
+		t.Errorf("list output missing registered algorithm:\n%s", w.String())
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("registering a taken name did not panic")
+		}
+	}()
+	Register("fcfs", Factory{New: func([]Process, AlgorithmOptions) Policy { return fcfsPolicy{} }})
+}
+
+func Test_algorithmFlags(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		args    []string
+		want    AlgorithmOptions
+		wantErr error
+	}{
+		{name: "defaults"},
+		{
+			name: "all set",
+			args: []string{"-quantum", "3", "-switch-cost", "1", "-mlfq-levels", "2", "-mlfq-quanta", "1, 4", "-mlfq-boost", "50"},
+			want: AlgorithmOptions{
+				RR:   RROptions{Quantum: 3, SwitchCost: 1},
+				MLFQ: MLFQOptions{Levels: 2, Quanta: []int64{1, 4}, BoostInterval: 50},
+			},
+		},
+		{
+			name: "quanta per priority",
+			args: []string{"-quanta", "2,4,8"},
+			want: AlgorithmOptions{
+				RR:   RROptions{Quanta: []int64{2, 4, 8}},
+				MLFQ: MLFQOptions{Quanta: []int64{2, 4, 8}},
+			},
+		},
+		{
+			name: "mlfq quanta win",
+			args: []string{"-quanta", "2,4", "-mlfq-quanta", "3"},
+			want: AlgorithmOptions{
+				RR:   RROptions{Quanta: []int64{2, 4}},
+				MLFQ: MLFQOptions{Quanta: []int64{3}},
+			},
+		},
+		{name: "bad quanta", args: []string{"-mlfq-quanta", "1,x"}, wantErr: ErrInvalidArgs},
+		{name: "zero quantum", args: []string{"-quanta", "2,0"}, wantErr: ErrInvalidArgs},
+		{name: "negative", args: []string{"-switch-cost", "-1"}, wantErr: ErrInvalidArgs},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			f := addAlgorithmFlags(fs)
+			if err := fs.Parse(tt.args); err != nil {
+				t.Fatal(err)
+			}
+			got, err := f.options()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("options() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("options() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+type lifoPolicy struct{}
+
+func (lifoPolicy) Less(a, b *Task) bool { return a.Seq > b.Seq }
+func (lifoPolicy) Preemptive() bool     { return false }
+func (lifoPolicy) Quantum() int64       { return 0 }
@@ -0,0 +1,286 @@
+package scheduler
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+const replHelp = `commands:
+  add pid=N burst=B [arrival=T] [priority=P] [nice=N] [deadline=D]
+  rm <pid>            remove a process
+  list                show the workload
+  clear               remove every process
+  load <file>         replace the workload with a process file
+  save <file>         write the workload as a process file
+  run <algorithm> [option=value ...]
+                      simulate, e.g. run rr quantum=2; options are the
+                      algorithm flags without their dash, and seed
+  show gantt|table|all
+                      show more of the last run
+  help, quit`
+
+// repl is an interactive session: a workload built up one command at a
+// time, and the last run of it.
+type repl struct {
+	w         io.Writer
+	gantt     GanttOptions
+	processes []Process
+	// last is the latest run, titled lastTitle; it is cleared when the
+	// workload changes.
+	last      *Result
+	lastTitle string
+}
+
+// errQuit ends the session.
+var errQuit = errors.New("quit")
+
+// loop reads commands one per line from r until quit or the end of input.
+// A command that fails prints why and the session carries on.
+func (s *repl) loop(r io.Reader) {
+	in := bufio.NewScanner(r)
+	prompt := func() { _, _ = fmt.Fprint(s.w, "> ") }
+	for prompt(); in.Scan(); prompt() {
+		fields := strings.Fields(in.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		err := s.exec(fields[0], fields[1:])
+		if errors.Is(err, errQuit) {
+			return
+		}
+		if err != nil {
+			_, _ = fmt.Fprintln(s.w, err)
+		}
+	}
+	_, _ = fmt.Fprintln(s.w)
+}
+
+func (s *repl) exec(cmd string, args []string) error {
+	switch cmd {
+	case "add":
+		return s.add(args)
+	case "rm", "remove":
+		return s.remove(args)
+	case "list", "ls":
+		outputWorkload(s.w, s.processes)
+		return nil
+	case "clear":
+		s.setProcesses(nil)
+		return nil
+	case "load":
+		return s.load(args)
+	case "save":
+		if len(args) != 1 {
+			return fmt.Errorf("%w: usage: save <file>", ErrInvalidArgs)
+		}
+		if err := writeFile(args[0], func(w io.Writer) error { return writeProcesses(w, s.processes) }); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(s.w, "Saved %d processes to %s\n", len(s.processes), args[0])
+		return nil
+	case "run":
+		return s.run(args)
+	case "show":
+		return s.show(args)
+	case "help", "?":
+		_, _ = fmt.Fprintln(s.w, replHelp)
+		return nil
+	case "quit", "exit", "q":
+		return errQuit
+	default:
+		return fmt.Errorf("%w: unknown command %q; try help", ErrInvalidArgs, cmd)
+	}
+}
+
+// setProcesses replaces the workload, forgetting the last run of the old
+// one.
+func (s *repl) setProcesses(processes []Process) {
+	s.processes, s.last = processes, nil
+}
+
+// add parses a process from key=value pairs and adds it if the workload is
+// still valid with it.
+func (s *repl) add(args []string) error {
+	var (
+		p    Process
+		seen = map[string]bool{}
+	)
+	for _, arg := range args {
+		key, value, ok := strings.Cut(arg, "=")
+		if !ok {
+			return fmt.Errorf("%w: %q is not key=value", ErrInvalidArgs, arg)
+		}
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("%w: %s %q is not a number", ErrInvalidArgs, key, value)
+		}
+		switch key {
+		case "pid":
+			p.ProcessID = n
+		case "burst":
+			p.BurstDuration = n
+		case "arrival":
+			p.ArrivalTime = n
+		case "priority":
+			p.Priority = n
+		case "nice":
+			p.Nice = n
+		case "deadline":
+			p.Deadline = n
+		default:
+			return fmt.Errorf("%w: unknown process field %q", ErrInvalidArgs, key)
+		}
+		seen[key] = true
+	}
+	for _, key := range []string{"pid", "burst"} {
+		if !seen[key] {
+			return fmt.Errorf("%w: add needs %s=", ErrInvalidArgs, key)
+		}
+	}
+	processes := append(append([]Process(nil), s.processes...), p)
+	if _, err := checkProcesses(processes, false, nil); err != nil {
+		return err
+	}
+	s.setProcesses(processes)
+	return nil
+}
+
+func (s *repl) remove(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("%w: usage: rm <pid>", ErrInvalidArgs)
+	}
+	pid, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: %q is not a PID", ErrInvalidArgs, args[0])
+	}
+	for i := range s.processes {
+		if s.processes[i].ProcessID == pid {
+			s.setProcesses(append(s.processes[:i:i], s.processes[i+1:]...))
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: no process %d", ErrInvalidArgs, pid)
+}
+
+func (s *repl) load(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("%w: usage: load <file>", ErrInvalidArgs)
+	}
+	f, err := os.Open(args[0])
+	if err != nil {
+		return fmt.Errorf("%v: error opening %s", err, args[0])
+	}
+	defer func() { _ = f.Close() }()
+	processes, err := loadProcesses(f)
+	if err != nil {
+		return err
+	}
+	if processes, err = checkProcesses(processes, false, nil); err != nil {
+		return err
+	}
+	s.setProcesses(processes)
+	_, _ = fmt.Fprintf(s.w, "Loaded %d processes\n", len(processes))
+	return nil
+}
+
+// run simulates the workload with an algorithm, whose options are given as
+// option=value pairs named like its command-line flags.
+func (s *repl) run(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%w: usage: run <algorithm> [option=value ...]", ErrInvalidArgs)
+	}
+	if len(s.processes) == 0 {
+		return fmt.Errorf("%w: the workload is empty; add some processes first", ErrInvalidProcesses)
+	}
+	algorithm, ok := LookupAlgorithm(args[0])
+	if !ok {
+		return fmt.Errorf("%w: unknown algorithm %q", ErrInvalidArgs, args[0])
+	}
+	fs := flag.NewFlagSet("run", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	algoFlags := addAlgorithmFlags(fs)
+	seed := addSeedFlag(fs, "")
+	flags := make([]string, len(args)-1)
+	for i, arg := range args[1:] {
+		flags[i] = "-" + arg
+	}
+	if err := fs.Parse(flags); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidArgs, err)
+	}
+	opts, err := algoFlags.options()
+	if err != nil {
+		return err
+	}
+	opts.Seed = pickSeed(os.Stderr, *seed)
+	result := Simulate(s.processes, algorithm.New(s.processes, opts), EngineOptions{})
+	s.last, s.lastTitle = &result, algorithm.Title
+	Render(s.w, result, RenderOptions{Title: algorithm.Title, Gantt: s.gantt, Quiet: true})
+	return nil
+}
+
+func (s *repl) show(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("%w: usage: show gantt|table|all", ErrInvalidArgs)
+	}
+	if s.last == nil {
+		return fmt.Errorf("%w: nothing has run since the workload last changed", ErrInvalidArgs)
+	}
+	switch args[0] {
+	case "gantt":
+		outputGantt(s.w, s.last.Gantt, s.gantt)
+	case "table":
+		outputSchedule(s.w, s.last.Schedule, s.last.AverageWait, s.last.AverageTurnaround, s.last.Throughput, s.last.Slowdown.Mean, false, s.gantt.Color)
+	case "all":
+		Render(s.w, *s.last, RenderOptions{Title: s.lastTitle, Gantt: s.gantt})
+	default:
+		return fmt.Errorf("%w: usage: show gantt|table|all", ErrInvalidArgs)
+	}
+	return nil
+}
+
+// outputWorkload lists the processes in PID order.
+func outputWorkload(w io.Writer, processes []Process) {
+	if len(processes) == 0 {
+		_, _ = fmt.Fprintln(w, "no processes")
+		return
+	}
+	sorted := append([]Process(nil), processes...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].ProcessID < sorted[j].ProcessID })
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"ID", "Arrival", "Burst", "Priority"})
+	for _, p := range sorted {
+		table.Append([]string{fmt.Sprint(p.ProcessID), fmt.Sprint(p.ArrivalTime), fmt.Sprint(p.BurstDuration), fmt.Sprint(p.Priority)})
+	}
+	table.Render()
+}
+
+// runRepl is the repl subcommand: build a workload and run algorithms on
+// it interactively, starting from a process file if one is given.
+func runRepl(w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("repl", flag.ContinueOnError)
+	ganttFlags := addGanttFlags(fs)
+	if err := parseFlags(fs, "repl", args); err != nil {
+		return err
+	}
+	if fs.NArg() > 1 {
+		return fmt.Errorf("%w: usage: repl [file]", ErrInvalidArgs)
+	}
+	s := &repl{w: w, gantt: ganttFlags.options(w)}
+	if fs.NArg() == 1 {
+		if err := s.load(fs.Args()); err != nil {
+			return err
+		}
+	}
+	_, _ = fmt.Fprintln(w, `type "help" for commands`)
+	s.loop(stdin)
+	return nil
+}
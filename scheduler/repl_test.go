@@ -0,0 +1,68 @@
+package scheduler
+
+import (
+	"bytes"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_repl(t *testing.T) {
+	t.Parallel()
+	saved := filepath.Join(t.TempDir(), "workload.csv")
+	tests := []struct {
+		name      string
+		script    string
+		want      []string
+		processes []Process
+	}{
+		{
+			name:      "add and run",
+			script:    "add pid=1 burst=5\nadd pid=2 burst=3 arrival=1 priority=2\nrun rr quantum=2\nshow gantt\n",
+			want:      []string{"Round-robin", "|  1 |        0 |     5 |       0 |       3 |          8 |          8 |", "Gantt schedule"},
+			processes: []Process{NewProcess(1, 0, 5, 0), NewProcess(2, 1, 3, 2)},
+		},
+		{
+			name:      "errors carry on",
+			script:    "add pid=1\nadd pid=1 burst=x\nrun\nrun fcfs\nshow gantt\nfrobnicate\nadd pid=1 burst=2\n",
+			want:      []string{"add needs burst=", `burst "x" is not a number`, "the workload is empty", "nothing has run", `unknown command "frobnicate"`},
+			processes: []Process{NewProcess(1, 0, 2, 0)},
+		},
+		{
+			name:      "duplicate PID",
+			script:    "add pid=1 burst=2\nadd pid=1 burst=3\n",
+			want:      []string{"duplicate PID"},
+			processes: []Process{NewProcess(1, 0, 2, 0)},
+		},
+		{
+			name:      "remove forgets the run",
+			script:    "add pid=1 burst=2\nadd pid=2 burst=1\nrun fcfs\nrm 1\nshow table\nrm 7\n",
+			want:      []string{"nothing has run", "no process 7"},
+			processes: []Process{NewProcess(2, 0, 1, 0)},
+		},
+		{
+			name:      "save, clear and load",
+			script:    "add pid=3 burst=4 arrival=2\nsave " + saved + "\nclear\nlist\nload " + saved + "\nquit\nadd pid=4 burst=1\n",
+			want:      []string{"Saved 1 processes", "no processes", "Loaded 1 processes"},
+			processes: []Process{NewProcess(3, 2, 4, 0)},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var w bytes.Buffer
+			s := &repl{w: &w}
+			s.loop(strings.NewReader(tt.script))
+			for _, want := range tt.want {
+				if !strings.Contains(w.String(), want) {
+					t.Errorf("output lacks %q:\n%s", want, w.String())
+				}
+			}
+			if !reflect.DeepEqual(s.processes, tt.processes) {
+				t.Errorf("processes = %v, want %v", s.processes, tt.processes)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package scheduler
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+)
+
+// ReportSections picks the optional sections Render prints under each
+// schedule table. Without any the report is the Gantt chart and schedule
+// table, plus whatever only applies to some runs, such as deadlines or
+// locks, for the runs it applies to.
+type ReportSections uint
+
+const (
+	// ReportPercentiles is the p50/p95/p99 of wait, turnaround and response
+	// time.
+	ReportPercentiles ReportSections = 1 << iota
+	// ReportSlowdown adds each process's slowdown to the schedule table,
+	// and their mean and maximum under it.
+	ReportSlowdown
+	// ReportConvoys lists the convoys DetectConvoys finds.
+	ReportConvoys
+	// ReportQueue adds the longest and average ready and blocked queues.
+	ReportQueue
+	// ReportFairness adds Jain's fairness index and the spread of waits.
+	ReportFairness
+	// ReportPreemptions counts preemptions and migrations per process.
+	ReportPreemptions
+)
+
+// ReportAll selects every section.
+const ReportAll = ^ReportSections(0)
+
+// reportSections names the sections for -report, in the order they are
+// printed.
+var reportSections = []struct {
+	name    string
+	section ReportSections
+}{
+	{"percentiles", ReportPercentiles},
+	{"slowdown", ReportSlowdown},
+	{"convoys", ReportConvoys},
+	{"queue", ReportQueue},
+	{"fairness", ReportFairness},
+	{"preemptions", ReportPreemptions},
+}
+
+// Has reports whether s selects section.
+func (s ReportSections) Has(section ReportSections) bool { return s&section != 0 }
+
+// parseReportSections reads a comma-separated list of section names, or
+// "all" for every one.
+func parseReportSections(spec string) (ReportSections, error) {
+	if spec == "" {
+		return 0, nil
+	}
+	if spec == "all" {
+		return ReportAll, nil
+	}
+	var sections ReportSections
+	for _, name := range strings.Split(spec, ",") {
+		name = strings.TrimSpace(name)
+		found := false
+		for _, s := range reportSections {
+			if s.name == name {
+				sections, found = sections|s.section, true
+			}
+		}
+		if !found {
+			return 0, fmt.Errorf("%w: unknown -report section %q; sections are %s or all", ErrInvalidArgs, name, reportSectionNames())
+		}
+	}
+	return sections, nil
+}
+
+func reportSectionNames() string {
+	names := make([]string, len(reportSections))
+	for i, s := range reportSections {
+		names[i] = s.name
+	}
+	return strings.Join(names, ", ")
+}
+
+// addReportFlag adds the -report flag to fs.
+func addReportFlag(fs *flag.FlagSet) *string {
+	return fs.String("report", "", "comma-separated extra sections to print under each schedule table ("+reportSectionNames()+"), or all")
+}
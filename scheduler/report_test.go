@@ -0,0 +1,59 @@
+package scheduler
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func Test_parseReportSections(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		spec    string
+		want    ReportSections
+		wantErr error
+	}{
+		{name: "none"},
+		{name: "all", spec: "all", want: ReportAll},
+		{name: "one", spec: " percentiles", want: ReportPercentiles},
+		{name: "unknown", spec: "percentiles,tails", wantErr: ErrInvalidArgs},
+	}
+	for _, tt := range tests {
+		got, err := parseReportSections(tt.spec)
+		if got != tt.want || !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: parseReportSections(%q) = %v, %v, want %v, %v", tt.name, tt.spec, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestRender_report(t *testing.T) {
+	t.Parallel()
+	processes := []Process{NewProcess(1, 0, 5, 2), NewProcess(2, 3, 9, 1), NewProcess(3, 6, 6, 3)}
+	fcfs, rr := FCFS(processes), RR(processes)
+	tests := []struct {
+		result  Result
+		section ReportSections
+		line    string
+	}{
+		{result: fcfs, section: ReportPercentiles, line: "Percentiles p50/p95/p99: wait 2/8/8, turnaround 11/14/14, response 2/8/8\n"},
+		{result: fcfs, section: ReportSlowdown, line: "| SLOWDOWN |"},
+		{result: fcfs, section: ReportSlowdown, line: "Slowdown (turnaround / burst): mean 1.52, max 2.33 (P3)\n"},
+		{result: fcfs, section: ReportConvoys, line: "  t=5-14 P2 (burst 9) holds up P3: 8 ticks of waiting\n"},
+		{result: fcfs, section: ReportQueue, line: "Queue length: ready max 1, average 0.50; blocked max 0, average 0.00\n"},
+		{result: fcfs, section: ReportFairness, line: "Fairness: Jain's index 0.91; wait std dev 3.40, min 0, max 8\n"},
+		{result: rr, section: ReportPreemptions, line: "Preemptions: 2 (P2 1, P3 1)\n"},
+	}
+	for _, tt := range tests {
+		var plain, selected bytes.Buffer
+		Render(&plain, tt.result, RenderOptions{Report: ReportAll &^ tt.section})
+		Render(&selected, tt.result, RenderOptions{Report: tt.section})
+		if strings.Contains(plain.String(), tt.line) {
+			t.Errorf("Render() without section %d =\n%s\nwant no %q", tt.section, plain.String(), tt.line)
+		}
+		if !strings.Contains(selected.String(), tt.line) {
+			t.Errorf("Render() with section %d =\n%s\nwant %q", tt.section, selected.String(), tt.line)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+// resultFileVersion is the version of the result file format written. A
+// change that older readers would misread must bump it.
+const resultFileVersion = 1
+
+// ErrInvalidResultFile is returned when a result file cannot be read.
+var ErrInvalidResultFile = errors.New("invalid result file")
+
+// ResultFile is a simulated schedule saved with -save, to be rendered again
+// by load without simulating it anew.
+type ResultFile struct {
+	Version   int    `json:"version"`
+	Algorithm string `json:"algorithm"`
+	Title     string `json:"title"`
+	Result    Result `json:"result"`
+}
+
+func writeResultFile(w io.Writer, f ResultFile) error {
+	f.Version = resultFileVersion
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(f)
+}
+
+func readResultFile(r io.Reader) (ResultFile, error) {
+	var f ResultFile
+	if err := json.NewDecoder(r).Decode(&f); err != nil {
+		return ResultFile{}, fmt.Errorf("%w: %v", ErrInvalidResultFile, err)
+	}
+	switch {
+	case f.Version == 0:
+		return ResultFile{}, fmt.Errorf("%w: no version; was it written by -save?", ErrInvalidResultFile)
+	case f.Version > resultFileVersion:
+		return ResultFile{}, fmt.Errorf("%w: version %d is newer than the %d this build reads", ErrInvalidResultFile, f.Version, resultFileVersion)
+	}
+	return f, nil
+}
+
+// loadFormats are the ways load can render a saved schedule besides the
+// text output, by -format name.
+var loadFormats = map[string]func(w io.Writer, title string, result Result) error{
+	"mermaid":      writeMermaid,
+	"dot":          writeDot,
+	"svg":          writeSVG,
+	"chrome":       writeChromeTrace,
+	"switch-trace": func(w io.Writer, _ string, result Result) error { return writeSwitchTrace(w, result) },
+}
+
+// runLoad is the load subcommand: it renders a schedule saved with -save,
+// as text or in another format, without simulating it again.
+func runLoad(w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("load", flag.ContinueOnError)
+	format := fs.String("format", "text", "render as text, lanes, mermaid, dot, svg, chrome or switch-trace")
+	mergeGantt := fs.Bool("merge-gantt", false, "merge consecutive Gantt slices of the same process")
+	ganttFlags := addGanttFlags(fs)
+	reportSpec := addReportFlag(fs)
+	if err := parseFlags(fs, "load", args); err != nil {
+		return err
+	}
+	report, err := parseReportSections(*reportSpec)
+	if err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("%w: usage: load [-format text] result.json", ErrInvalidArgs)
+	}
+	in, err := os.Open(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	f, err := readResultFile(in)
+	if err != nil {
+		return fmt.Errorf("%s: %w", fs.Arg(0), err)
+	}
+	switch *format {
+	case "text", "lanes":
+		Render(w, f.Result, RenderOptions{
+			Title:      f.Title,
+			MergeGantt: *mergeGantt,
+			Lanes:      *format == "lanes",
+			Gantt:      ganttFlags.options(w),
+			Report:     report,
+		})
+		return nil
+	}
+	write, ok := loadFormats[*format]
+	if !ok {
+		return fmt.Errorf("%w: unknown -format %q", ErrInvalidArgs, *format)
+	}
+	return write(w, f.Title, f.Result)
+}
@@ -0,0 +1,93 @@
+package scheduler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestResultFile_roundTrip(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 5, Priority: 2},
+		{ProcessID: 2, ArrivalTime: 3, BurstDuration: 9, Priority: 1},
+		{ProcessID: 3, ArrivalTime: 6, BurstDuration: 6, Priority: 3, Bursts: []int64{2, 3, 4}},
+	}
+	result := Simulate(processes, newRRPolicy(processes, RROptions{}), EngineOptions{})
+	var b bytes.Buffer
+	if err := writeResultFile(&b, ResultFile{Algorithm: "rr", Title: "Round-robin", Result: result}); err != nil {
+		t.Fatal(err)
+	}
+	f, err := readResultFile(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Version != resultFileVersion || f.Algorithm != "rr" || f.Title != "Round-robin" {
+		t.Errorf("readResultFile() = version %d, %q, %q", f.Version, f.Algorithm, f.Title)
+	}
+	want, _ := json.Marshal(result)
+	got, _ := json.Marshal(f.Result)
+	if !bytes.Equal(got, want) {
+		t.Errorf("loaded result =\n%s\nwant\n%s", got, want)
+	}
+	var saved, loaded bytes.Buffer
+	Render(&saved, result, RenderOptions{Title: "Round-robin", Gantt: GanttOptions{Width: 80}})
+	Render(&loaded, f.Result, RenderOptions{Title: f.Title, Gantt: GanttOptions{Width: 80}})
+	if saved.String() != loaded.String() {
+		t.Errorf("loaded result renders as\n%s\nwant\n%s", loaded.String(), saved.String())
+	}
+}
+
+func TestReadResultFile_errors(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "not JSON", in: "{", want: "unexpected EOF"},
+		{name: "no version", in: `{"algorithm":"rr"}`, want: "no version"},
+		{name: "newer version", in: `{"version":99}`, want: "version 99 is newer"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := readResultFile(strings.NewReader(tt.in))
+			if !errors.Is(err, ErrInvalidResultFile) || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("readResultFile() error = %v, want %v containing %q", err, ErrInvalidResultFile, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunLoad(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "fcfs.json")
+	result := Result{Gantt: []TimeSlice{{PID: 1, Start: 0, Stop: 2}, {PID: 2, Start: 2, Stop: 3}}}
+	var b bytes.Buffer
+	if err := writeResultFile(&b, ResultFile{Algorithm: "fcfs", Title: "FCFS", Result: result}); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, b.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var want bytes.Buffer
+	if err := writeMermaid(&want, "FCFS", result); err != nil {
+		t.Fatal(err)
+	}
+	var got bytes.Buffer
+	if err := runLoad(&got, []string{"-format", "mermaid", path}); err != nil {
+		t.Fatal(err)
+	}
+	if got.String() != want.String() {
+		t.Errorf("load -format mermaid =\n%s\nwant\n%s", got.String(), want.String())
+	}
+	if err := runLoad(&got, []string{"-format", "png", path}); !errors.Is(err, ErrInvalidArgs) {
+		t.Errorf("load -format png error = %v, want %v", err, ErrInvalidArgs)
+	}
+}
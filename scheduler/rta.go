@@ -0,0 +1,347 @@
+package scheduler
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+type (
+	// TaskResponse is a periodic task's worst-case response time, worked out
+	// by response-time analysis and observed in simulation.
+	TaskResponse struct {
+		ProcessID int64
+		// WCET is the task's CPU time per job, its BurstDuration.
+		WCET     int64
+		Period   int64
+		Deadline int64
+		Priority int64
+		// Analyzed is the response time analysis bounds every job by. If
+		// the analysis passed the deadline it stopped there, Schedulable is
+		// unset, and Analyzed is the first bound past the deadline.
+		Analyzed    int64
+		Schedulable bool
+		// Simulated is the longest response of the task's Jobs in a
+		// fixed-priority simulation, of which Missed finished late.
+		Simulated int64
+		Jobs      int
+		Missed    int
+	}
+	// RTAReport is the analysis of a periodic task set.
+	RTAReport struct {
+		// Tasks are in priority order, highest first.
+		Tasks       []TaskResponse
+		Utilization float64
+		// Bound is Liu and Layland's utilization bound for rate-monotonic
+		// priorities: at or below it the set is schedulable.
+		Bound float64
+		// Expansion is how the tasks were turned into jobs to simulate.
+		Expansion Expansion
+		// Assignment is how the tasks' priorities were chosen.
+		Assignment PriorityAssignment
+	}
+)
+
+// PriorityAssignment chooses the fixed priorities of periodic tasks.
+type PriorityAssignment int
+
+const (
+	// AssignGiven keeps the priorities in the process file.
+	AssignGiven PriorityAssignment = iota
+	// AssignRateMonotonic ranks tasks by period, shortest first, which is
+	// optimal among fixed priorities when deadlines equal periods.
+	AssignRateMonotonic
+	// AssignDeadlineMonotonic ranks tasks by relative deadline, shortest
+	// first, which stays optimal when deadlines are shorter than periods.
+	AssignDeadlineMonotonic
+)
+
+func (a PriorityAssignment) String() string {
+	switch a {
+	case AssignRateMonotonic:
+		return "rm"
+	case AssignDeadlineMonotonic:
+		return "dm"
+	default:
+		return "given"
+	}
+}
+
+// Title names a for report headings.
+func (a PriorityAssignment) Title() string {
+	switch a {
+	case AssignRateMonotonic:
+		return "rate-monotonic"
+	case AssignDeadlineMonotonic:
+		return "deadline-monotonic"
+	default:
+		return "given"
+	}
+}
+
+func parsePriorityAssignment(s string) (PriorityAssignment, error) {
+	switch s {
+	case "", "given":
+		return AssignGiven, nil
+	case "rm":
+		return AssignRateMonotonic, nil
+	case "dm":
+		return AssignDeadlineMonotonic, nil
+	default:
+		return AssignGiven, fmt.Errorf("%w: unknown priority assignment %q; use given, rm or dm", ErrInvalidArgs, s)
+	}
+}
+
+// assignPriorities returns a copy of processes with the periodic tasks
+// ranked 1, 2, ... by a, ties keeping their order in the file. Other
+// processes are left as they are.
+func assignPriorities(processes []Process, a PriorityAssignment) []Process {
+	processes = append([]Process(nil), processes...)
+	if a == AssignGiven {
+		return processes
+	}
+	var tasks []*Process
+	for i := range processes {
+		if processes[i].periodic() {
+			tasks = append(tasks, &processes[i])
+		}
+	}
+	key := func(p *Process) int64 {
+		if a == AssignRateMonotonic {
+			return p.Period
+		}
+		return p.relativeDeadline()
+	}
+	sort.SliceStable(tasks, func(i, j int) bool { return key(tasks[i]) < key(tasks[j]) })
+	for rank, t := range tasks {
+		t.Priority = int64(rank + 1)
+	}
+	return processes
+}
+
+// responseTime solves the response-time recurrence for task i among tasks:
+// R = C_i + sum over tasks j of at least i's priority of ceil(R/T_j) * C_j.
+// Tasks of equal priority are counted as interfering, which is safe
+// whichever of them runs first. The result is exact for deadlines no longer
+// than periods, with every task released at once; it stops once R passes
+// the deadline.
+func responseTime(tasks []Process, i int) (int64, bool) {
+	task := &tasks[i]
+	deadline := task.relativeDeadline()
+	r := task.BurstDuration
+	for {
+		next := task.BurstDuration
+		for j := range tasks {
+			if j != i && tasks[j].Priority <= task.Priority {
+				next += (r + tasks[j].Period - 1) / tasks[j].Period * tasks[j].BurstDuration
+			}
+		}
+		if next > deadline {
+			return next, false
+		}
+		if next == r {
+			return r, true
+		}
+		r = next
+	}
+}
+
+// AnalyzeTasks runs response-time analysis on the periodic processes, with
+// priorities chosen by assign, and compares it with a simulation that
+// schedules their jobs preemptively by priority, over a hyperperiod or
+// periods jobs per task. Processes that are not periodic are left out of
+// both.
+func AnalyzeTasks(processes []Process, periods int, assign PriorityAssignment) (RTAReport, error) {
+	processes = assignPriorities(processes, assign)
+	var tasks []Process
+	for i := range processes {
+		if processes[i].periodic() {
+			tasks = append(tasks, processes[i])
+		}
+	}
+	if len(tasks) == 0 {
+		return RTAReport{}, fmt.Errorf("%w: no periodic tasks to analyze", ErrInvalidProcesses)
+	}
+	sort.SliceStable(tasks, func(i, j int) bool { return tasks[i].Priority < tasks[j].Priority })
+	jobs, jobOf, x, err := expandPeriodic(tasks, periods)
+	if err != nil {
+		return RTAReport{}, err
+	}
+	report := RTAReport{Expansion: x, Assignment: assign}
+	index := map[int64]int{}
+	for i := range tasks {
+		t := &tasks[i]
+		r, ok := responseTime(tasks, i)
+		report.Tasks = append(report.Tasks, TaskResponse{
+			ProcessID:   t.ProcessID,
+			WCET:        t.BurstDuration,
+			Period:      t.Period,
+			Deadline:    t.relativeDeadline(),
+			Priority:    t.Priority,
+			Analyzed:    r,
+			Schedulable: ok,
+		})
+		index[t.ProcessID] = i
+		report.Utilization += float64(t.BurstDuration) / float64(t.Period)
+	}
+	n := float64(len(tasks))
+	report.Bound = n * (math.Pow(2, 1/n) - 1)
+
+	result := Simulate(jobs, priorityPolicy{}, EngineOptions{})
+	for _, row := range result.Schedule {
+		tr := &report.Tasks[index[jobOf[row.ProcessID]]]
+		tr.Jobs++
+		if row.Turnaround > tr.Simulated {
+			tr.Simulated = row.Turnaround
+		}
+		if row.Turnaround > tr.Deadline {
+			tr.Missed++
+		}
+	}
+	return report, nil
+}
+
+func outputRTA(w io.Writer, report RTAReport) {
+	title := "Response-time analysis"
+	if report.Assignment != AssignGiven {
+		title += ", " + report.Assignment.Title() + " priorities"
+	}
+	outputTitle(w, title)
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"ID", "C", "T", "D", "Priority", "R analyzed", "R simulated", "Jobs", "Missed", "Schedulable"})
+	for _, t := range report.Tasks {
+		analyzed, schedulable := fmt.Sprint(t.Analyzed), "yes"
+		if !t.Schedulable {
+			analyzed, schedulable = fmt.Sprintf(">%d", t.Deadline), "no"
+		}
+		table.Append([]string{
+			fmt.Sprint(t.ProcessID), fmt.Sprint(t.WCET), fmt.Sprint(t.Period), fmt.Sprint(t.Deadline), fmt.Sprint(t.Priority),
+			analyzed, fmt.Sprint(t.Simulated), fmt.Sprint(t.Jobs), fmt.Sprint(t.Missed), schedulable,
+		})
+	}
+	table.Render()
+	verdict := "above it, so only the response times decide"
+	if report.Utilization <= report.Bound {
+		verdict = "within it"
+	}
+	// The bound assumes every deadline is a full period.
+	for _, t := range report.Tasks {
+		if t.Deadline < t.Period {
+			verdict = "which holds only when deadlines equal periods"
+			break
+		}
+	}
+	_, _ = fmt.Fprintf(w, "Utilization %.3f; Liu and Layland bound for %d tasks %.3f, %s\n",
+		report.Utilization, len(report.Tasks), report.Bound, verdict)
+	_, _ = fmt.Fprintf(w, "Simulated %d jobs released up to t=%d by fixed priority\n", report.Expansion.Jobs, report.Expansion.Horizon)
+}
+
+// schedulable reports whether the analysis found every task schedulable,
+// and counts the jobs the simulation saw miss.
+func (r RTAReport) schedulable() (ok bool, missed int) {
+	ok = true
+	for _, t := range r.Tasks {
+		ok = ok && t.Schedulable
+		missed += t.Missed
+	}
+	return ok, missed
+}
+
+// outputAssignments sets analyses of the same tasks under different
+// priority assignments side by side.
+func outputAssignments(w io.Writer, reports []RTAReport) {
+	outputTitle(w, "Priority assignments")
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Assignment", "Priority order", "Schedulable tasks", "Schedulable", "Jobs", "Missed"})
+	for _, r := range reports {
+		order := make([]string, len(r.Tasks))
+		tasks, jobs := 0, 0
+		for i, t := range r.Tasks {
+			order[i] = fmt.Sprintf("P%d", t.ProcessID)
+			jobs += t.Jobs
+			if t.Schedulable {
+				tasks++
+			}
+		}
+		ok, missed := r.schedulable()
+		verdict := "yes"
+		if !ok {
+			verdict = "no"
+		}
+		table.Append([]string{
+			r.Assignment.Title(), strings.Join(order, " "), fmt.Sprintf("%d/%d", tasks, len(r.Tasks)), verdict,
+			fmt.Sprint(jobs), fmt.Sprint(missed),
+		})
+	}
+	table.Render()
+}
+
+// runAnalyze is the analyze subcommand: response-time analysis of the
+// periodic tasks in a process file.
+func runAnalyze(w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("analyze", flag.ContinueOnError)
+	periods := fs.Int("periods", 0, "simulate this many jobs of each task instead of a hyperperiod")
+	assign := fs.String("assign", "given", "task priorities: given in the file, rm (rate-monotonic) or dm (deadline-monotonic)")
+	compare := fs.Bool("compare", false, "analyze under both rate- and deadline-monotonic priorities and compare them")
+	var server ServerOptions
+	fs.Int64Var(&server.Budget, "server-budget", 0, "serve the processes that are not periodic with a server of this budget, comparing background, polling and sporadic service")
+	fs.Int64Var(&server.Period, "server-period", 0, "period the server's budget is replenished over (default the budget)")
+	fs.Int64Var(&server.Priority, "server-priority", 0, "the server's priority among the tasks, winning ties (default above them all)")
+	lenient := fs.Bool("lenient", false, "fix invalid processes with a warning instead of rejecting the workload")
+	if err := parseFlags(fs, "analyze", args); err != nil {
+		return err
+	}
+	if fs.NArg() > 1 {
+		return fmt.Errorf("%w: usage: analyze file", ErrInvalidArgs)
+	}
+	f, closeFile, err := openProcessingFile(append([]string{"analyze"}, fs.Args()...)...)
+	if err != nil {
+		return err
+	}
+	defer closeFile()
+	processes, err := loadProcesses(f)
+	if err != nil {
+		return err
+	}
+	if processes, err = checkProcesses(processes, *lenient, os.Stderr); err != nil {
+		return err
+	}
+	assignments := []PriorityAssignment{AssignGiven}
+	if *compare {
+		assignments = []PriorityAssignment{AssignRateMonotonic, AssignDeadlineMonotonic}
+	} else if assignments[0], err = parsePriorityAssignment(*assign); err != nil {
+		return err
+	}
+	reports := make([]RTAReport, len(assignments))
+	for i, a := range assignments {
+		if reports[i], err = AnalyzeTasks(processes, *periods, a); err != nil {
+			return err
+		}
+		outputRTA(w, reports[i])
+	}
+	warnExpansion(os.Stderr, reports[0].Expansion)
+	if *compare {
+		outputAssignments(w, reports)
+	}
+	if server.Budget > 0 {
+		if server.Period == 0 {
+			server.Period = server.Budget
+		}
+		given, err := parsePriorityAssignment(*assign)
+		if err != nil {
+			return err
+		}
+		runs, err := SimulateServers(assignPriorities(processes, given), *periods, server)
+		if err != nil {
+			return err
+		}
+		outputServers(w, server, runs)
+	}
+	return nil
+}
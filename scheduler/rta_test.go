@@ -0,0 +1,115 @@
+package scheduler
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestAnalyzeTasks(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name  string
+		tasks []Process
+		// want are the analyzed and simulated response times and whether
+		// each task is schedulable, highest priority first.
+		want []TaskResponse
+	}{
+		{
+			name: "schedulable above the utilization bound",
+			tasks: []Process{
+				{ProcessID: 3, BurstDuration: 3, Priority: 3, Period: 12},
+				{ProcessID: 1, BurstDuration: 1, Priority: 1, Period: 4},
+				{ProcessID: 2, BurstDuration: 2, Priority: 2, Period: 6},
+			},
+			want: []TaskResponse{
+				{ProcessID: 1, Analyzed: 1, Schedulable: true, Simulated: 1, Jobs: 3},
+				{ProcessID: 2, Analyzed: 3, Schedulable: true, Simulated: 3, Jobs: 2},
+				// 3 + ⌈10/4⌉·1 + ⌈10/6⌉·2 = 10.
+				{ProcessID: 3, Analyzed: 10, Schedulable: true, Simulated: 10, Jobs: 1},
+			},
+		},
+		{
+			name: "deadline shorter than the period",
+			tasks: []Process{
+				{ProcessID: 1, BurstDuration: 2, Priority: 1, Period: 4},
+				{ProcessID: 2, BurstDuration: 2, Priority: 2, Period: 8, Deadline: 3},
+			},
+			want: []TaskResponse{
+				{ProcessID: 1, Analyzed: 2, Schedulable: true, Simulated: 2, Jobs: 2},
+				{ProcessID: 2, Analyzed: 4, Schedulable: false, Simulated: 4, Jobs: 1, Missed: 1},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			report, err := AnalyzeTasks(tt.tasks, 0, AssignGiven)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(report.Tasks) != len(tt.want) {
+				t.Fatalf("got %d tasks, want %d", len(report.Tasks), len(tt.want))
+			}
+			for i, want := range tt.want {
+				got := report.Tasks[i]
+				if got.ProcessID != want.ProcessID || got.Analyzed != want.Analyzed || got.Schedulable != want.Schedulable ||
+					got.Simulated != want.Simulated || got.Jobs != want.Jobs || got.Missed != want.Missed {
+					t.Errorf("task %d = %+v, want %+v", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestAnalyzeTasks_noPeriodicTasks(t *testing.T) {
+	t.Parallel()
+	_, err := AnalyzeTasks([]Process{{ProcessID: 1, BurstDuration: 3}}, 0, AssignGiven)
+	if !errors.Is(err, ErrInvalidProcesses) {
+		t.Errorf("error = %v, want %v", err, ErrInvalidProcesses)
+	}
+}
+
+func TestAnalyzeTasks_assignments(t *testing.T) {
+	t.Parallel()
+	// P2's deadline is shorter than P1's though its period is longer, so
+	// rate-monotonic ranks it second and it misses; deadline-monotonic
+	// ranks it first and both make it.
+	tasks := []Process{
+		{ProcessID: 1, BurstDuration: 2, Priority: 5, Period: 4},
+		{ProcessID: 2, BurstDuration: 1, Priority: 5, Period: 10, Deadline: 2},
+	}
+	tests := []struct {
+		assign      PriorityAssignment
+		order       []int64
+		schedulable bool
+		missed      int
+	}{
+		{assign: AssignRateMonotonic, order: []int64{1, 2}, missed: 1},
+		{assign: AssignDeadlineMonotonic, order: []int64{2, 1}, schedulable: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.assign.String(), func(t *testing.T) {
+			t.Parallel()
+			report, err := AnalyzeTasks(tasks, 0, tt.assign)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var order []int64
+			for _, task := range report.Tasks {
+				order = append(order, task.ProcessID)
+			}
+			if !reflect.DeepEqual(order, tt.order) {
+				t.Errorf("priority order = %v, want %v", order, tt.order)
+			}
+			if ok, missed := report.schedulable(); ok != tt.schedulable || missed != tt.missed {
+				t.Errorf("schedulable() = %v, %d missed, want %v, %d", ok, missed, tt.schedulable, tt.missed)
+			}
+		})
+	}
+	if tasks[0].Priority != 5 || tasks[1].Priority != 5 {
+		t.Errorf("AnalyzeTasks changed the given priorities: %+v", tasks)
+	}
+}
@@ -0,0 +1,177 @@
+package scheduler
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+type (
+	// ScenarioOptions size the classic synchronization problems scenario
+	// writes workloads for. Processes arrive Gap ticks apart.
+	ScenarioOptions struct {
+		// Producers each make Items items for the Consumers to share out,
+		// through a buffer of Buffer slots. Making an item takes Produce
+		// ticks and using one Consume, outside the buffer's critical
+		// section, which takes a tick.
+		Producers, Consumers, Items int
+		Buffer, Produce, Consume    int64
+		// Readers may read at once, for Read ticks, but each of the
+		// Writers needs the data to itself for Write ticks.
+		Readers, Writers int
+		Read, Write      int64
+		Gap              int64
+	}
+	// Scenario is a workload of processes synchronizing over semaphores,
+	// with the values the semaphores start at.
+	Scenario struct {
+		Processes  []Process
+		Semaphores map[string]int64
+	}
+)
+
+// scenarios are the workloads scenario writes, by name.
+var scenarios = map[string]func(ScenarioOptions) (Scenario, error){
+	"producer-consumer": ProducerConsumer,
+	"readers-writers":   ReadersWriters,
+}
+
+// ProducerConsumer is the bounded-buffer problem: producers wait for an
+// empty slot and consumers for a full one, and both take the mutex to
+// touch the buffer. The items are dealt out between the consumers as
+// evenly as they go.
+func ProducerConsumer(opts ScenarioOptions) (Scenario, error) {
+	if opts.Producers < 1 || opts.Consumers < 1 || opts.Items < 1 || opts.Buffer < 1 || opts.Produce < 0 || opts.Consume < 0 {
+		return Scenario{}, fmt.Errorf("%w: producer-consumer needs a producer, a consumer, an item and a buffer slot", ErrInvalidArgs)
+	}
+	var processes []Process
+	add := func(items int, work int64, step func(base int64) []SemOp) {
+		p := Process{ProcessID: int64(len(processes) + 1), ArrivalTime: int64(len(processes)) * opts.Gap, Priority: 1}
+		for k := 0; k < items; k++ {
+			p.Semaphores = append(p.Semaphores, step(int64(k)*(work+1))...)
+		}
+		p.BurstDuration = int64(items) * (work + 1)
+		processes = append(processes, p)
+	}
+	produce := func(base int64) []SemOp {
+		put := base + opts.Produce
+		return []SemOp{
+			{Semaphore: "empty", At: put}, {Semaphore: "mutex", At: put},
+			{Semaphore: "mutex", Signal: true, At: put + 1}, {Semaphore: "full", Signal: true, At: put + 1},
+		}
+	}
+	consume := func(base int64) []SemOp {
+		return []SemOp{
+			{Semaphore: "full", At: base}, {Semaphore: "mutex", At: base},
+			{Semaphore: "mutex", Signal: true, At: base + 1}, {Semaphore: "empty", Signal: true, At: base + 1},
+		}
+	}
+	for i := 0; i < opts.Producers; i++ {
+		add(opts.Items, opts.Produce, produce)
+	}
+	total := opts.Producers * opts.Items
+	for i := 0; i < opts.Consumers; i++ {
+		items := total / opts.Consumers
+		if i < total%opts.Consumers {
+			items++
+		}
+		if items > 0 {
+			add(items, opts.Consume, consume)
+		}
+	}
+	return Scenario{Processes: processes, Semaphores: map[string]int64{"empty": opts.Buffer, "full": 0, "mutex": 1}}, nil
+}
+
+// ReadersWriters shares data between readers, any number of whom may read
+// at once, and writers, who need it to themselves. The db semaphore counts
+// a permit for every reader; a reader takes one and a writer takes all of
+// them. Readers and writers arrive alternately while both are left, and
+// are served in the order they wait, so neither starves.
+func ReadersWriters(opts ScenarioOptions) (Scenario, error) {
+	if opts.Readers < 1 || opts.Writers < 0 || opts.Read < 1 || opts.Write < 1 {
+		return Scenario{}, fmt.Errorf("%w: readers-writers needs a reader, and reads and writes of at least a tick", ErrInvalidArgs)
+	}
+	permits := int64(opts.Readers)
+	var processes []Process
+	add := func(work, count int64) {
+		processes = append(processes, Process{
+			ProcessID:     int64(len(processes) + 1),
+			ArrivalTime:   int64(len(processes)) * opts.Gap,
+			BurstDuration: work,
+			Priority:      1,
+			Semaphores:    []SemOp{{Semaphore: "db", Count: count, At: 0}, {Semaphore: "db", Count: count, Signal: true, At: work}},
+		})
+	}
+	for r, w := 0, 0; r < opts.Readers || w < opts.Writers; {
+		if r < opts.Readers {
+			add(opts.Read, 0)
+			r++
+		}
+		if w < opts.Writers {
+			add(opts.Write, permits)
+			w++
+		}
+	}
+	return Scenario{Processes: processes, Semaphores: map[string]int64{"db": permits}}, nil
+}
+
+// formatSemaphores writes semaphore values as -semaphores takes them.
+func formatSemaphores(values map[string]int64) string {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fields := make([]string, len(names))
+	for i, name := range names {
+		fields[i] = fmt.Sprintf("%s=%d", name, values[name])
+	}
+	return strings.Join(fields, ",")
+}
+
+// runScenario is the scenario subcommand: it writes the workload of a
+// classic synchronization problem, headed by a comment giving the
+// -semaphores to run it with.
+func runScenario(w io.Writer, args []string) error {
+	var opts ScenarioOptions
+	fs := flag.NewFlagSet("scenario", flag.ContinueOnError)
+	fs.IntVar(&opts.Producers, "producers", 2, "producer-consumer: producer processes")
+	fs.IntVar(&opts.Consumers, "consumers", 2, "producer-consumer: consumer processes")
+	fs.IntVar(&opts.Items, "items", 3, "producer-consumer: items each producer makes")
+	fs.Int64Var(&opts.Buffer, "buffer", 2, "producer-consumer: slots in the buffer")
+	fs.Int64Var(&opts.Produce, "produce", 2, "producer-consumer: ticks to make an item")
+	fs.Int64Var(&opts.Consume, "consume", 2, "producer-consumer: ticks to use an item")
+	fs.IntVar(&opts.Readers, "readers", 3, "readers-writers: reader processes")
+	fs.IntVar(&opts.Writers, "writers", 2, "readers-writers: writer processes")
+	fs.Int64Var(&opts.Read, "read", 3, "readers-writers: ticks a read takes")
+	fs.Int64Var(&opts.Write, "write", 2, "readers-writers: ticks a write takes")
+	fs.Int64Var(&opts.Gap, "gap", 1, "ticks between arrivals")
+	if err := parseFlags(fs, "scenario", args); err != nil {
+		return err
+	}
+	names := make([]string, 0, len(scenarios))
+	for name := range scenarios {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	if fs.NArg() != 1 {
+		return fmt.Errorf("%w: usage: scenario [flags] %s", ErrInvalidArgs, strings.Join(names, "|"))
+	}
+	build, ok := scenarios[fs.Arg(0)]
+	if !ok {
+		return fmt.Errorf("%w: unknown scenario %q; choose from %s", ErrInvalidArgs, fs.Arg(0), strings.Join(names, ", "))
+	}
+	if opts.Gap < 0 {
+		return fmt.Errorf("%w: -gap cannot be negative", ErrInvalidArgs)
+	}
+	s, err := build(opts)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "# %s: run with -semaphores %s\n", fs.Arg(0), formatSemaphores(s.Semaphores)); err != nil {
+		return err
+	}
+	return writeProcesses(w, s.Processes)
+}
@@ -0,0 +1,63 @@
+package scheduler
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestScenarios(t *testing.T) {
+	t.Parallel()
+	opts := ScenarioOptions{Producers: 2, Consumers: 3, Items: 4, Buffer: 2, Produce: 2, Consume: 1, Readers: 3, Writers: 2, Read: 3, Write: 2, Gap: 1}
+	policies := map[string]Policy{"fcfs": fcfsPolicy{}, "rr": rrPolicy{quantum: 2}}
+	for name, build := range scenarios {
+		name, build := name, build
+		for algo, policy := range policies {
+			algo, policy := algo, policy
+			t.Run(name+"/"+algo, func(t *testing.T) {
+				t.Parallel()
+				s, err := build(opts)
+				if err != nil {
+					t.Fatal(err)
+				}
+				for _, p := range s.Processes {
+					if problem := semaphoreProblem(p); problem != "" {
+						t.Fatalf("P%d: %s", p.ProcessID, problem)
+					}
+				}
+				for _, cpus := range []int{1, 2} {
+					got := Simulate(s.Processes, policy, EngineOptions{CPUs: cpus, Semaphores: s.Semaphores})
+					if got.Deadlocked || len(got.Schedule) != len(s.Processes) {
+						t.Errorf("%d CPUs: not every process finished: %+v", cpus, got.Incomplete)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestScenarios_invalid(t *testing.T) {
+	t.Parallel()
+	if _, err := ProducerConsumer(ScenarioOptions{Producers: 1, Consumers: 1, Items: 1}); !errors.Is(err, ErrInvalidArgs) {
+		t.Errorf("ProducerConsumer() without a buffer error = %v, want ErrInvalidArgs", err)
+	}
+	if _, err := ReadersWriters(ScenarioOptions{Writers: 1, Read: 1, Write: 1}); !errors.Is(err, ErrInvalidArgs) {
+		t.Errorf("ReadersWriters() without a reader error = %v, want ErrInvalidArgs", err)
+	}
+}
+
+func Test_runScenario(t *testing.T) {
+	t.Parallel()
+	var w strings.Builder
+	if err := runScenario(&w, []string{"-readers", "1", "-writers", "1", "readers-writers"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.SplitN(w.String(), "\n", 2)[0]; got != "# readers-writers: run with -semaphores db=1" {
+		t.Errorf("runScenario() header = %q", got)
+	}
+	for _, args := range [][]string{nil, {"dining-philosophers"}} {
+		if err := runScenario(&w, args); !errors.Is(err, ErrInvalidArgs) {
+			t.Errorf("runScenario(%q) error = %v, want ErrInvalidArgs", args, err)
+		}
+	}
+}
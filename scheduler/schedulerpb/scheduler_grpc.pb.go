@@ -0,0 +1,185 @@
+// scheduler.proto describes the simulation API as a gRPC service, for
+// front-ends that animate a schedule while it is computed. The server is
+// started by serve -grpc-addr; the REST server offers the same event stream
+// as newline-delimited JSON at POST /simulate/stream.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: scheduler.proto
+
+package schedulerpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	Scheduler_Simulate_FullMethodName   = "/scheduler.Scheduler/Simulate"
+	Scheduler_Algorithms_FullMethodName = "/scheduler.Scheduler/Algorithms"
+)
+
+// SchedulerClient is the client API for Scheduler service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type SchedulerClient interface {
+	// Simulate runs a workload and streams each scheduling event as the
+	// engine records it, ending when the run does.
+	Simulate(ctx context.Context, in *SimulateRequest, opts ...grpc.CallOption) (Scheduler_SimulateClient, error)
+	// Algorithms lists the registered algorithms, as GET /algorithms does.
+	Algorithms(ctx context.Context, in *AlgorithmsRequest, opts ...grpc.CallOption) (*AlgorithmsResponse, error)
+}
+
+type schedulerClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSchedulerClient(cc grpc.ClientConnInterface) SchedulerClient {
+	return &schedulerClient{cc}
+}
+
+func (c *schedulerClient) Simulate(ctx context.Context, in *SimulateRequest, opts ...grpc.CallOption) (Scheduler_SimulateClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Scheduler_ServiceDesc.Streams[0], Scheduler_Simulate_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &schedulerSimulateClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Scheduler_SimulateClient interface {
+	Recv() (*Event, error)
+	grpc.ClientStream
+}
+
+type schedulerSimulateClient struct {
+	grpc.ClientStream
+}
+
+func (x *schedulerSimulateClient) Recv() (*Event, error) {
+	m := new(Event)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *schedulerClient) Algorithms(ctx context.Context, in *AlgorithmsRequest, opts ...grpc.CallOption) (*AlgorithmsResponse, error) {
+	out := new(AlgorithmsResponse)
+	err := c.cc.Invoke(ctx, Scheduler_Algorithms_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SchedulerServer is the server API for Scheduler service.
+// All implementations must embed UnimplementedSchedulerServer
+// for forward compatibility
+type SchedulerServer interface {
+	// Simulate runs a workload and streams each scheduling event as the
+	// engine records it, ending when the run does.
+	Simulate(*SimulateRequest, Scheduler_SimulateServer) error
+	// Algorithms lists the registered algorithms, as GET /algorithms does.
+	Algorithms(context.Context, *AlgorithmsRequest) (*AlgorithmsResponse, error)
+	mustEmbedUnimplementedSchedulerServer()
+}
+
+// UnimplementedSchedulerServer must be embedded to have forward compatible implementations.
+type UnimplementedSchedulerServer struct {
+}
+
+func (UnimplementedSchedulerServer) Simulate(*SimulateRequest, Scheduler_SimulateServer) error {
+	return status.Errorf(codes.Unimplemented, "method Simulate not implemented")
+}
+func (UnimplementedSchedulerServer) Algorithms(context.Context, *AlgorithmsRequest) (*AlgorithmsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Algorithms not implemented")
+}
+func (UnimplementedSchedulerServer) mustEmbedUnimplementedSchedulerServer() {}
+
+// UnsafeSchedulerServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to SchedulerServer will
+// result in compilation errors.
+type UnsafeSchedulerServer interface {
+	mustEmbedUnimplementedSchedulerServer()
+}
+
+func RegisterSchedulerServer(s grpc.ServiceRegistrar, srv SchedulerServer) {
+	s.RegisterService(&Scheduler_ServiceDesc, srv)
+}
+
+func _Scheduler_Simulate_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SimulateRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SchedulerServer).Simulate(m, &schedulerSimulateServer{stream})
+}
+
+type Scheduler_SimulateServer interface {
+	Send(*Event) error
+	grpc.ServerStream
+}
+
+type schedulerSimulateServer struct {
+	grpc.ServerStream
+}
+
+func (x *schedulerSimulateServer) Send(m *Event) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Scheduler_Algorithms_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AlgorithmsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SchedulerServer).Algorithms(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Scheduler_Algorithms_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SchedulerServer).Algorithms(ctx, req.(*AlgorithmsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Scheduler_ServiceDesc is the grpc.ServiceDesc for Scheduler service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Scheduler_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "scheduler.Scheduler",
+	HandlerType: (*SchedulerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Algorithms",
+			Handler:    _Scheduler_Algorithms_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Simulate",
+			Handler:       _Scheduler_Simulate_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "scheduler.proto",
+}
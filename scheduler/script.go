@@ -0,0 +1,419 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// A policy file defines a scheduler without recompiling. Every line that is
+// not a directive is part of one expression computing a key for a ready
+// process; the process with the lowest key is dispatched next, ties going to
+// the one queued first. For example shortest-remaining-time with aging:
+//
+//	# run the shortest job, but let waiting processes catch up
+//	preemptive
+//	quantum 4
+//	left - wait / 2
+//
+// Directives are "preemptive", which lets a process with a lower key take the
+// CPU as soon as it is ready, and "quantum N". Expressions use numbers, the
+// variables in scriptVars, + - * / %, comparisons and && || ! (true is 1),
+// c ? a : b, and the functions in scriptFuncs. "#" starts a comment.
+
+var ErrInvalidPolicy = errors.New("invalid policy")
+
+// scriptVars are the variables a policy expression can read for a process.
+var scriptVars = map[string]func(t *Task, now int64) float64{
+	"pid":         func(t *Task, _ int64) float64 { return float64(t.ProcessID) },
+	"arrival":     func(t *Task, _ int64) float64 { return float64(t.ArrivalTime) },
+	"burst":       func(t *Task, _ int64) float64 { return float64(t.BurstDuration) },
+	"priority":    func(t *Task, _ int64) float64 { return float64(t.Priority) },
+	"remaining":   func(t *Task, _ int64) float64 { return float64(t.Remaining) },
+	"left":        func(t *Task, _ int64) float64 { return float64(t.remainingCPU()) },
+	"wait":        func(t *Task, now int64) float64 { return float64(t.waited(now)) },
+	"seq":         func(t *Task, _ int64) float64 { return float64(t.Seq) },
+	"now":         func(_ *Task, now int64) float64 { return float64(now) },
+	"age":         func(t *Task, now int64) float64 { return float64(now - t.ArrivalTime) },
+	"interactive": func(t *Task, _ int64) float64 { return scriptBool(t.Class == ClassInteractive) },
+}
+
+var scriptFuncs = map[string]struct {
+	args int
+	call func(args []float64) float64
+}{
+	"min":   {2, func(a []float64) float64 { return math.Min(a[0], a[1]) }},
+	"max":   {2, func(a []float64) float64 { return math.Max(a[0], a[1]) }},
+	"abs":   {1, func(a []float64) float64 { return math.Abs(a[0]) }},
+	"sqrt":  {1, func(a []float64) float64 { return math.Sqrt(a[0]) }},
+	"log":   {1, func(a []float64) float64 { return math.Log(a[0]) }},
+	"floor": {1, func(a []float64) float64 { return math.Floor(a[0]) }},
+}
+
+// scriptExpr evaluates a compiled expression for a process at time now.
+type scriptExpr func(t *Task, now int64) float64
+
+// ScriptPolicy is a compiled policy file.
+type ScriptPolicy struct {
+	Name       string
+	key        scriptExpr
+	preemptive bool
+	quantum    int64
+}
+
+// LoadScriptPolicy reads and compiles a policy file.
+func LoadScriptPolicy(p string) (ScriptPolicy, error) {
+	b, err := os.ReadFile(p)
+	if err != nil {
+		return ScriptPolicy{}, fmt.Errorf("%v: error reading %s", err, p)
+	}
+	policy, err := ParseScriptPolicy(string(b))
+	if err != nil {
+		return ScriptPolicy{}, fmt.Errorf("%w: %s", err, p)
+	}
+	policy.Name = strings.TrimSuffix(filepath.Base(p), filepath.Ext(p))
+
+	return policy, nil
+}
+
+// ParseScriptPolicy compiles the text of a policy file.
+func ParseScriptPolicy(src string) (ScriptPolicy, error) {
+	var policy ScriptPolicy
+	// Directive lines are blanked rather than dropped so errors in the
+	// expression report the right line.
+	lines := strings.Split(src, "\n")
+	for i, line := range lines {
+		if c := strings.IndexByte(line, '#'); c >= 0 {
+			line = line[:c]
+		}
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) == 1 && fields[0] == "preemptive":
+			policy.preemptive = true
+			line = ""
+		case len(fields) > 0 && fields[0] == "quantum":
+			if len(fields) != 2 {
+				return ScriptPolicy{}, fmt.Errorf("%w: line %d: usage: quantum N", ErrInvalidPolicy, i+1)
+			}
+			q, err := strconv.ParseInt(fields[1], 10, 64)
+			if err != nil || q < 0 {
+				return ScriptPolicy{}, fmt.Errorf("%w: line %d: bad quantum %q", ErrInvalidPolicy, i+1, fields[1])
+			}
+			policy.quantum = q
+			line = ""
+		}
+		lines[i] = line
+	}
+
+	tokens, err := lexScript(strings.Join(lines, "\n"))
+	if err != nil {
+		return ScriptPolicy{}, err
+	}
+	if len(tokens) == 1 {
+		return ScriptPolicy{}, fmt.Errorf("%w: no key expression", ErrInvalidPolicy)
+	}
+	p := &scriptParser{tokens: tokens}
+	if policy.key, err = p.ternary(); err != nil {
+		return ScriptPolicy{}, err
+	}
+	if tok := p.peek(); tok.kind != scriptEOF {
+		return ScriptPolicy{}, p.errorf("unexpected %q", tok.text)
+	}
+
+	return policy, nil
+}
+
+// New returns a fresh policy for one simulation.
+func (s ScriptPolicy) New([]Process) Policy {
+	return &scriptPolicy{ScriptPolicy: s}
+}
+
+type scriptPolicy struct {
+	ScriptPolicy
+	now int64
+}
+
+func (p *scriptPolicy) Observe(ev Event) { p.now = ev.Time }
+
+func (p *scriptPolicy) Less(a, b *Task) bool {
+	if ka, kb := p.key(a, p.now), p.key(b, p.now); ka != kb {
+		return ka < kb
+	}
+	return a.Seq < b.Seq
+}
+func (p *scriptPolicy) Preemptive() bool { return p.preemptive }
+func (p *scriptPolicy) Quantum() int64   { return p.quantum }
+
+func scriptBool(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+type scriptTokenKind int
+
+const (
+	scriptEOF scriptTokenKind = iota
+	scriptNumber
+	scriptIdent
+	scriptOp
+)
+
+type scriptToken struct {
+	kind scriptTokenKind
+	text string
+	num  float64
+	line int
+}
+
+var scriptOps = []string{"&&", "||", "<=", ">=", "==", "!=", "+", "-", "*", "/", "%", "<", ">", "!", "(", ")", ",", "?", ":"}
+
+func lexScript(src string) ([]scriptToken, error) {
+	var tokens []scriptToken
+	line := 1
+	for i := 0; i < len(src); {
+		c := rune(src[i])
+		switch {
+		case c == '\n':
+			line++
+			i++
+		case unicode.IsSpace(c):
+			i++
+		case unicode.IsDigit(c) || c == '.':
+			j := i
+			for j < len(src) && (unicode.IsDigit(rune(src[j])) || src[j] == '.') {
+				j++
+			}
+			n, err := strconv.ParseFloat(src[i:j], 64)
+			if err != nil {
+				return nil, fmt.Errorf("%w: line %d: bad number %q", ErrInvalidPolicy, line, src[i:j])
+			}
+			tokens = append(tokens, scriptToken{kind: scriptNumber, text: src[i:j], num: n, line: line})
+			i = j
+		case unicode.IsLetter(c) || c == '_':
+			j := i
+			for j < len(src) && (unicode.IsLetter(rune(src[j])) || unicode.IsDigit(rune(src[j])) || src[j] == '_') {
+				j++
+			}
+			tokens = append(tokens, scriptToken{kind: scriptIdent, text: src[i:j], line: line})
+			i = j
+		default:
+			op := ""
+			for _, o := range scriptOps {
+				if strings.HasPrefix(src[i:], o) {
+					op = o
+					break
+				}
+			}
+			if op == "" {
+				return nil, fmt.Errorf("%w: line %d: unexpected %q", ErrInvalidPolicy, line, c)
+			}
+			tokens = append(tokens, scriptToken{kind: scriptOp, text: op, line: line})
+			i += len(op)
+		}
+	}
+
+	return append(tokens, scriptToken{kind: scriptEOF, text: "end of file", line: line}), nil
+}
+
+// scriptParser compiles tokens by recursive descent, one method per
+// precedence level from loosest to tightest.
+type scriptParser struct {
+	tokens []scriptToken
+	pos    int
+}
+
+func (p *scriptParser) peek() scriptToken { return p.tokens[p.pos] }
+
+func (p *scriptParser) next() scriptToken {
+	tok := p.tokens[p.pos]
+	if tok.kind != scriptEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *scriptParser) accept(op string) bool {
+	if tok := p.peek(); tok.kind == scriptOp && tok.text == op {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *scriptParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: line %d: %s", ErrInvalidPolicy, p.peek().line, fmt.Sprintf(format, args...))
+}
+
+func (p *scriptParser) ternary() (scriptExpr, error) {
+	cond, err := p.binary(0)
+	if err != nil || !p.accept("?") {
+		return cond, err
+	}
+	then, err := p.ternary()
+	if err != nil {
+		return nil, err
+	}
+	if !p.accept(":") {
+		return nil, p.errorf("expected ':'")
+	}
+	otherwise, err := p.ternary()
+	if err != nil {
+		return nil, err
+	}
+	return func(t *Task, now int64) float64 {
+		if cond(t, now) != 0 {
+			return then(t, now)
+		}
+		return otherwise(t, now)
+	}, nil
+}
+
+// scriptLevels are the binary operators from loosest to tightest binding.
+var scriptLevels = [][]string{
+	{"||"},
+	{"&&"},
+	{"==", "!=", "<", "<=", ">", ">="},
+	{"+", "-"},
+	{"*", "/", "%"},
+}
+
+func (p *scriptParser) binary(level int) (scriptExpr, error) {
+	if level == len(scriptLevels) {
+		return p.unary()
+	}
+	left, err := p.binary(level + 1)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op := ""
+		for _, o := range scriptLevels[level] {
+			if p.accept(o) {
+				op = o
+				break
+			}
+		}
+		if op == "" {
+			return left, nil
+		}
+		right, err := p.binary(level + 1)
+		if err != nil {
+			return nil, err
+		}
+		left = scriptBinary(op, left, right)
+	}
+}
+
+func scriptBinary(op string, l, r scriptExpr) scriptExpr {
+	var f func(a, b float64) float64
+	switch op {
+	case "||":
+		return func(t *Task, now int64) float64 { return scriptBool(l(t, now) != 0 || r(t, now) != 0) }
+	case "&&":
+		return func(t *Task, now int64) float64 { return scriptBool(l(t, now) != 0 && r(t, now) != 0) }
+	case "==":
+		f = func(a, b float64) float64 { return scriptBool(a == b) }
+	case "!=":
+		f = func(a, b float64) float64 { return scriptBool(a != b) }
+	case "<":
+		f = func(a, b float64) float64 { return scriptBool(a < b) }
+	case "<=":
+		f = func(a, b float64) float64 { return scriptBool(a <= b) }
+	case ">":
+		f = func(a, b float64) float64 { return scriptBool(a > b) }
+	case ">=":
+		f = func(a, b float64) float64 { return scriptBool(a >= b) }
+	case "+":
+		f = func(a, b float64) float64 { return a + b }
+	case "-":
+		f = func(a, b float64) float64 { return a - b }
+	case "*":
+		f = func(a, b float64) float64 { return a * b }
+	case "/":
+		f = func(a, b float64) float64 { return a / b }
+	default:
+		f = math.Mod
+	}
+	return func(t *Task, now int64) float64 { return f(l(t, now), r(t, now)) }
+}
+
+func (p *scriptParser) unary() (scriptExpr, error) {
+	switch {
+	case p.accept("-"):
+		x, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return func(t *Task, now int64) float64 { return -x(t, now) }, nil
+	case p.accept("!"):
+		x, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return func(t *Task, now int64) float64 { return scriptBool(x(t, now) == 0) }, nil
+	}
+	return p.primary()
+}
+
+func (p *scriptParser) primary() (scriptExpr, error) {
+	tok := p.peek()
+	switch tok.kind {
+	case scriptNumber:
+		p.next()
+		return func(*Task, int64) float64 { return tok.num }, nil
+	case scriptIdent:
+		p.next()
+		if !p.accept("(") {
+			v, ok := scriptVars[tok.text]
+			if !ok {
+				return nil, fmt.Errorf("%w: line %d: unknown variable %q", ErrInvalidPolicy, tok.line, tok.text)
+			}
+			return scriptExpr(v), nil
+		}
+		fn, ok := scriptFuncs[tok.text]
+		if !ok {
+			return nil, fmt.Errorf("%w: line %d: unknown function %q", ErrInvalidPolicy, tok.line, tok.text)
+		}
+		var args []scriptExpr
+		for !p.accept(")") {
+			if len(args) > 0 && !p.accept(",") {
+				return nil, p.errorf("expected ',' or ')'")
+			}
+			arg, err := p.ternary()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+		}
+		if len(args) != fn.args {
+			return nil, fmt.Errorf("%w: line %d: %s takes %d arguments, got %d", ErrInvalidPolicy, tok.line, tok.text, fn.args, len(args))
+		}
+		return func(t *Task, now int64) float64 {
+			values := make([]float64, len(args))
+			for i, arg := range args {
+				values[i] = arg(t, now)
+			}
+			return fn.call(values)
+		}, nil
+	case scriptOp:
+		if p.accept("(") {
+			x, err := p.ternary()
+			if err != nil {
+				return nil, err
+			}
+			if !p.accept(")") {
+				return nil, p.errorf("expected ')'")
+			}
+			return x, nil
+		}
+	}
+
+	return nil, p.errorf("unexpected %q", tok.text)
+}
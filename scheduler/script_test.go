@@ -0,0 +1,68 @@
+package scheduler
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseScriptPolicy(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 6},
+		{ProcessID: 2, ArrivalTime: 1, BurstDuration: 2, Priority: 3},
+		{ProcessID: 3, ArrivalTime: 2, BurstDuration: 1, Priority: 1},
+	}
+	tests := []struct {
+		name      string
+		src       string
+		wantGantt []TimeSlice
+		wantErr   error
+	}{
+		{
+			name:      "fcfs by arrival",
+			src:       "arrival",
+			wantGantt: []TimeSlice{{PID: 1, Start: 0, Stop: 6}, {PID: 2, Start: 6, Stop: 8}, {PID: 3, Start: 8, Stop: 9}},
+		},
+		{
+			name:      "preemptive shortest remaining",
+			src:       "# srtf\npreemptive\nleft\n",
+			wantGantt: []TimeSlice{{PID: 1, Start: 0, Stop: 1}, {PID: 2, Start: 1, Stop: 3}, {PID: 3, Start: 3, Stop: 4}, {PID: 1, Start: 4, Stop: 9}},
+		},
+		{
+			name: "round robin with quantum",
+			src:  "quantum 2\nseq",
+			wantGantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 2}, {PID: 2, Start: 2, Stop: 4}, {PID: 3, Start: 4, Stop: 5}, {PID: 1, Start: 5, Stop: 7}, {PID: 1, Start: 7, Stop: 9},
+			},
+		},
+		{
+			name:      "functions, ternary and precedence",
+			src:       "preemptive\npid == 1 ? 100 : -max(priority, 2 + 3 * 0) % 4",
+			wantGantt: []TimeSlice{{PID: 1, Start: 0, Stop: 1}, {PID: 2, Start: 1, Stop: 3}, {PID: 3, Start: 3, Stop: 4}, {PID: 1, Start: 4, Stop: 9}},
+		},
+		{name: "unknown variable", src: "\nburst + nice", wantErr: ErrInvalidPolicy},
+		{name: "wrong arity", src: "min(pid)", wantErr: ErrInvalidPolicy},
+		{name: "trailing tokens", src: "pid pid", wantErr: ErrInvalidPolicy},
+		{name: "unbalanced", src: "(pid", wantErr: ErrInvalidPolicy},
+		{name: "bad quantum", src: "quantum x\npid", wantErr: ErrInvalidPolicy},
+		{name: "empty", src: "preemptive # nothing else", wantErr: ErrInvalidPolicy},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			policy, err := ParseScriptPolicy(tt.src)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseScriptPolicy() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			got := Simulate(processes, policy.New(processes), EngineOptions{})
+			if !reflect.DeepEqual(got.Gantt, tt.wantGantt) {
+				t.Errorf("Gantt = %v, want %v", got.Gantt, tt.wantGantt)
+			}
+		})
+	}
+}
@@ -0,0 +1,244 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SemOp is a process waiting on (P) or signalling (V) a counting semaphore
+// once it has received At ticks of CPU. Unlike a lock, a semaphore is not
+// held: any process may signal it, which is how one process tells another
+// that something it is waiting for is ready.
+type SemOp struct {
+	Semaphore string `json:"semaphore"`
+	// Count is how much the operation takes from or adds to the semaphore,
+	// when more than one.
+	Count int64 `json:"count,omitempty"`
+	// Signal is set for a signal; otherwise the operation is a wait.
+	Signal bool  `json:"signal,omitempty"`
+	At     int64 `json:"at"`
+}
+
+var ErrInvalidSemaphore = errors.New("invalid semaphore operation")
+
+// amount is how much o takes or adds.
+func (o SemOp) amount() int64 {
+	if o.Count > 1 {
+		return o.Count
+	}
+	return 1
+}
+
+// parseSemOps reads the semaphores column: space-separated name:wait@at or
+// name:signal@at, with name*count to take or add more than one.
+func parseSemOps(s string) ([]SemOp, error) {
+	var ops []SemOp
+	for _, f := range strings.Fields(s) {
+		name, rest, ok := strings.Cut(f, ":")
+		action, at, ok2 := strings.Cut(rest, "@")
+		if !ok || !ok2 || name == "" || (action != "wait" && action != "signal") {
+			return nil, fmt.Errorf("%w: %q is not semaphore:wait@at or semaphore:signal@at", ErrInvalidSemaphore, f)
+		}
+		op := SemOp{Signal: action == "signal"}
+		name, n, counted := strings.Cut(name, "*")
+		if counted {
+			c, err := strconv.ParseInt(n, 10, 64)
+			if err != nil || c < 1 || name == "" {
+				return nil, fmt.Errorf("%w: %q is not semaphore*count with a positive count", ErrInvalidSemaphore, f)
+			}
+			op.Count = c
+		}
+		op.Semaphore = name
+		var err error
+		if op.At, err = strconv.ParseInt(at, 10, 64); err != nil {
+			return nil, fmt.Errorf("%w: %q: %v", ErrInvalidSemaphore, f, errors.Unwrap(err))
+		}
+		ops = append(ops, op)
+	}
+	return ops, nil
+}
+
+func formatSemOps(ops []SemOp) string {
+	fields := make([]string, len(ops))
+	for i, o := range ops {
+		name, action := o.Semaphore, "wait"
+		if o.Count > 1 {
+			name += "*" + strconv.FormatInt(o.Count, 10)
+		}
+		if o.Signal {
+			action = "signal"
+		}
+		fields[i] = fmt.Sprintf("%s:%s@%d", name, action, o.At)
+	}
+	return strings.Join(fields, " ")
+}
+
+// semaphoreProblem describes what is wrong with a process's semaphore
+// operations, if anything.
+func semaphoreProblem(p Process) string {
+	var cpu int64
+	if len(p.Bursts) == 0 {
+		cpu = p.BurstDuration
+	}
+	for i := 0; i < len(p.Bursts); i += 2 {
+		cpu += p.Bursts[i]
+	}
+	for _, o := range p.Semaphores {
+		if o.Count < 0 {
+			return fmt.Sprintf("semaphore %s operation of %d", o.Semaphore, o.Count)
+		}
+		if o.At < 0 || o.At > cpu {
+			return fmt.Sprintf("semaphore %s operation at %d, outside its %d ticks of CPU", o.Semaphore, o.At, cpu)
+		}
+	}
+	return ""
+}
+
+// parseSemaphores reads -semaphores: comma-separated name=initial value.
+func parseSemaphores(s string) (map[string]int64, error) {
+	if s == "" {
+		return nil, nil
+	}
+	values := map[string]int64{}
+	for _, f := range strings.Split(s, ",") {
+		name, n, ok := strings.Cut(strings.TrimSpace(f), "=")
+		value, err := strconv.ParseInt(n, 10, 64)
+		if !ok || name == "" || err != nil || value < 0 {
+			return nil, fmt.Errorf("%w: semaphore %q is not name=value with a value of 0 or more", ErrInvalidArgs, f)
+		}
+		values[name] = value
+	}
+	return values, nil
+}
+
+// semaphore is a counting semaphore: its value, and the tasks blocked on it
+// in the order they arrived, each to be woken once the value covers what it
+// waits for.
+type semaphore struct {
+	value   int64
+	waiters []*Task
+}
+
+func (e *engine) initSemaphores() {
+	e.semaphores = map[string]*semaphore{}
+	for _, t := range e.tasks {
+		for _, o := range t.Semaphores {
+			if _, ok := e.semaphores[o.Semaphore]; !ok {
+				e.semaphores[o.Semaphore] = &semaphore{value: e.opts.Semaphores[o.Semaphore]}
+			}
+		}
+		// Operations at the same moment keep the order they were given in.
+		t.semOps = append([]SemOp(nil), t.Semaphores...)
+		sort.SliceStable(t.semOps, func(i, j int) bool { return t.semOps[i].At < t.semOps[j].At })
+	}
+}
+
+// runSemaphores runs the semaphore operations running tasks have reached.
+// A task that must wait leaves the CPU until it is signalled.
+func (e *engine) runSemaphores() {
+	running := e.running[:0]
+	for _, t := range e.running {
+		if e.runTaskSemaphores(t) {
+			running = append(running, t)
+		}
+	}
+	e.running = running
+}
+
+// runTaskSemaphores runs t's semaphore operations due now and reports
+// whether it is still running afterwards.
+func (e *engine) runTaskSemaphores(t *Task) bool {
+	for t.nextSem < len(t.semOps) && t.semOps[t.nextSem].At == t.cpuDone {
+		o := t.semOps[t.nextSem]
+		s := e.semaphores[o.Semaphore]
+		if o.Signal {
+			t.nextSem++
+			e.signal(t, o)
+			continue
+		}
+		// Waiters are woken in turn, so nobody overtakes one already blocked.
+		if len(s.waiters) == 0 && s.value >= o.amount() {
+			t.nextSem++
+			s.value -= o.amount()
+			e.recordEvent(Event{Time: e.now, Kind: EventSemDown, PID: t.ProcessID, Resource: o.Semaphore, Count: countOf(o.Count)})
+			continue
+		}
+		e.endSlice(t)
+		e.freeCPUs(t)
+		t.semSince = e.now
+		s.waiters = append(s.waiters, t)
+		e.recordEvent(Event{Time: e.now, Kind: EventSemWait, PID: t.ProcessID, CPU: t.cpu, Resource: o.Semaphore, Count: countOf(o.Count)})
+		e.opts.Log.Log(e.now, "wait on semaphore", "pid", t.ProcessID, "semaphore", o.Semaphore, "value", s.value)
+		return false
+	}
+	return true
+}
+
+// signal adds to a semaphore and wakes the waiters it now covers, first
+// blocked first.
+func (e *engine) signal(t *Task, o SemOp) {
+	s := e.semaphores[o.Semaphore]
+	s.value += o.amount()
+	e.recordEvent(Event{Time: e.now, Kind: EventSemUp, PID: t.ProcessID, Resource: o.Semaphore, Count: countOf(o.Count)})
+	for len(s.waiters) > 0 {
+		w := s.waiters[0]
+		wo := w.semOps[w.nextSem]
+		if wo.amount() > s.value {
+			return
+		}
+		s.waiters = s.waiters[1:]
+		s.value -= wo.amount()
+		w.nextSem++
+		w.semWait += e.now - w.semSince
+		e.recordEvent(Event{Time: e.now, Kind: EventSemDown, PID: w.ProcessID, Resource: wo.Semaphore, Count: countOf(wo.Count)})
+		e.record(EventWake, w)
+		e.opts.Log.Log(e.now, "signalled", "pid", w.ProcessID, "semaphore", wo.Semaphore, "by", t.ProcessID)
+		e.enqueue(w)
+	}
+}
+
+func (e *engine) semaphoreWaiters() int {
+	n := 0
+	for _, s := range e.semaphores {
+		n += len(s.waiters)
+	}
+	return n
+}
+
+// outputSemaphores lists the semaphore operations, then how long each
+// process that blocked on a semaphore spent blocked, apart from its wait in
+// the ready queue.
+func outputSemaphores(w io.Writer, events []Event, rows []ProcessResult) {
+	header := false
+	for _, ev := range events {
+		semaphore := ev.Resource
+		if ev.Count > 1 {
+			semaphore = fmt.Sprintf("%d of %s", ev.Count, ev.Resource)
+		}
+		var line string
+		switch ev.Kind {
+		case EventSemDown:
+			line = fmt.Sprintf("P%d takes %s", ev.PID, semaphore)
+		case EventSemWait:
+			line = fmt.Sprintf("P%d blocks on %s", ev.PID, semaphore)
+		case EventSemUp:
+			line = fmt.Sprintf("P%d signals %s", ev.PID, semaphore)
+		default:
+			continue
+		}
+		if !header {
+			_, _ = fmt.Fprintln(w, "Semaphores:")
+			header = true
+		}
+		_, _ = fmt.Fprintf(w, "  t=%d %s\n", ev.Time, line)
+	}
+	for _, row := range rows {
+		if row.SemaphoreWait > 0 {
+			_, _ = fmt.Fprintf(w, "  P%d blocked %d on semaphores, waited %d ready\n", row.ProcessID, row.SemaphoreWait, row.Wait)
+		}
+	}
+}
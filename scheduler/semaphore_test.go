@@ -0,0 +1,88 @@
+package scheduler
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_parseSemOps(t *testing.T) {
+	t.Parallel()
+	ops, err := parseSemOps("full:wait@0 db*3:signal@2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []SemOp{{Semaphore: "full", At: 0}, {Semaphore: "db", Count: 3, Signal: true, At: 2}}
+	if !reflect.DeepEqual(ops, want) {
+		t.Errorf("parseSemOps() = %+v, want %+v", ops, want)
+	}
+	if got := formatSemOps(ops); got != "full:wait@0 db*3:signal@2" {
+		t.Errorf("formatSemOps() = %q", got)
+	}
+	for _, bad := range []string{"full@0", "full:take@0", ":wait@0", "full:wait@x", "db*0:wait@0", "*2:wait@0"} {
+		if _, err := parseSemOps(bad); !errors.Is(err, ErrInvalidSemaphore) {
+			t.Errorf("parseSemOps(%q) error = %v, want ErrInvalidSemaphore", bad, err)
+		}
+	}
+}
+
+func Test_parseSemaphores(t *testing.T) {
+	t.Parallel()
+	got, err := parseSemaphores("empty=2, full=0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]int64{"empty": 2, "full": 0}; !reflect.DeepEqual(got, want) {
+		t.Errorf("parseSemaphores() = %v, want %v", got, want)
+	}
+	for _, bad := range []string{"empty", "empty=-1", "=2", "empty=x"} {
+		if _, err := parseSemaphores(bad); !errors.Is(err, ErrInvalidArgs) {
+			t.Errorf("parseSemaphores(%q) error = %v, want ErrInvalidArgs", bad, err)
+		}
+	}
+}
+
+func TestSimulate_semaphores(t *testing.T) {
+	t.Parallel()
+	// 2 waits on full at once, but 1 only signals it after two ticks, so
+	// 2 spends those blocked rather than waiting to run.
+	got := Simulate([]Process{
+		{ProcessID: 1, BurstDuration: 3, Semaphores: []SemOp{{Semaphore: "full", Signal: true, At: 2}}},
+		{ProcessID: 2, BurstDuration: 2, Semaphores: []SemOp{{Semaphore: "full", At: 0}}},
+	}, rrPolicy{quantum: 1}, EngineOptions{CPUs: 2})
+	if got.Deadlocked || len(got.Schedule) != 2 {
+		t.Fatalf("not every process finished: %+v", got.Incomplete)
+	}
+	waits := map[int64][2]int64{}
+	for _, r := range got.Schedule {
+		waits[r.ProcessID] = [2]int64{r.SemaphoreWait, r.Wait}
+	}
+	if want := map[int64][2]int64{1: {0, 0}, 2: {2, 0}}; !reflect.DeepEqual(waits, want) {
+		t.Errorf("semaphore and ready waits = %v, want %v", waits, want)
+	}
+}
+
+func TestSimulate_semaphoreNeverSignalled(t *testing.T) {
+	t.Parallel()
+	got := Simulate([]Process{
+		{ProcessID: 1, BurstDuration: 2, Semaphores: []SemOp{{Semaphore: "s", At: 1}}},
+	}, fcfsPolicy{}, EngineOptions{})
+	if !got.Deadlocked {
+		t.Errorf("Deadlocked = false, want true; Gantt %v", got.Gantt)
+	}
+}
+
+func Test_outputSemaphores(t *testing.T) {
+	t.Parallel()
+	var w strings.Builder
+	outputSemaphores(&w, []Event{
+		{Time: 0, Kind: EventSemWait, PID: 2, Resource: "full"},
+		{Time: 2, Kind: EventSemUp, PID: 1, Resource: "full"},
+		{Time: 2, Kind: EventSemDown, PID: 2, Resource: "db", Count: 3},
+	}, []ProcessResult{{ProcessID: 1}, {ProcessID: 2, SemaphoreWait: 2, Wait: 1}})
+	want := "Semaphores:\n  t=0 P2 blocks on full\n  t=2 P1 signals full\n  t=2 P2 takes 3 of db\n  P2 blocked 2 on semaphores, waited 1 ready\n"
+	if got := w.String(); got != want {
+		t.Errorf("outputSemaphores() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,138 @@
+package scheduler
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+)
+
+// SeriesPoint is one window of a run's time series: how many processes
+// completed in it, and how long the queues were on average, so a plot shows
+// how the backlog builds and drains under a policy rather than only where
+// it ended.
+type SeriesPoint struct {
+	Start int64 `json:"start"`
+	End   int64 `json:"end"`
+	// Completions is the processes completing in the window, Completed
+	// those completed by its end, and Throughput completions a tick.
+	Completions int     `json:"completions"`
+	Completed   int     `json:"completed"`
+	Throughput  float64 `json:"throughput"`
+	// Ready and Blocked are the time-weighted average queue lengths over
+	// the window, and MaxReady the longest the ready queue got.
+	Ready    float64 `json:"ready"`
+	Blocked  float64 `json:"blocked"`
+	MaxReady int     `json:"max_ready"`
+}
+
+// Series is the time series of one algorithm's run.
+type Series struct {
+	Algorithm string        `json:"algorithm"`
+	Window    int64         `json:"window"`
+	Points    []SeriesPoint `json:"points"`
+}
+
+// TimeSeries cuts result into windows of the given number of ticks, from
+// 0 to the last exit or scheduling event; the last window may be shorter.
+func TimeSeries(result Result, window int64) []SeriesPoint {
+	if window < 1 {
+		window = 1
+	}
+	samples := result.QueueLength
+	var end int64
+	for _, row := range result.Schedule {
+		end = maxInt64(end, row.Exit)
+	}
+	if n := len(samples); n > 0 {
+		end = maxInt64(end, samples[n-1].Time)
+	}
+	if end <= 0 {
+		return nil
+	}
+	points := make([]SeriesPoint, (end+window-1)/window)
+	for i := range points {
+		points[i].Start = int64(i) * window
+		points[i].End = minInt64(points[i].Start+window, end)
+	}
+	for _, row := range result.Schedule {
+		// A process exiting at t ran during the tick before it.
+		if row.Exit > 0 {
+			points[(row.Exit-1)/window].Completions++
+		}
+	}
+	var (
+		current   QueueSample
+		next      int
+		completed int
+	)
+	for i := range points {
+		p := &points[i]
+		var ready, blocked int64
+		// The queues keep each sample's lengths until the next sample.
+		for t := p.Start; t < p.End; {
+			for next < len(samples) && samples[next].Time <= t {
+				current = samples[next]
+				next++
+			}
+			until := p.End
+			if next < len(samples) && samples[next].Time < until {
+				until = samples[next].Time
+			}
+			ready += int64(current.Ready) * (until - t)
+			blocked += int64(current.Blocked) * (until - t)
+			if current.Ready > p.MaxReady {
+				p.MaxReady = current.Ready
+			}
+			t = until
+		}
+		length := float64(p.End - p.Start)
+		completed += p.Completions
+		p.Completed = completed
+		p.Throughput = float64(p.Completions) / length
+		p.Ready = float64(ready) / length
+		p.Blocked = float64(blocked) / length
+	}
+	return points
+}
+
+// writeSeries writes the time series of each named result to path, as JSON
+// if it ends in .json and as CSV otherwise.
+func writeSeries(path string, names []string, results []Result, window int64) error {
+	series := make([]Series, len(results))
+	for i := range results {
+		series[i] = Series{Algorithm: names[i], Window: window, Points: TimeSeries(results[i], window)}
+	}
+	return writeFile(path, func(w io.Writer) error {
+		if filepath.Ext(path) == ".json" {
+			return writeSeriesJSON(w, series)
+		}
+		return writeSeriesCSV(w, series)
+	})
+}
+
+// writeSeriesCSV writes series as CSV rows, one for each window of each
+// algorithm.
+func writeSeriesCSV(w io.Writer, series []Series) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"algorithm", "start", "end", "completions", "completed", "throughput", "ready", "blocked", "max_ready"})
+	for _, s := range series {
+		for _, p := range s.Points {
+			_ = cw.Write([]string{
+				s.Algorithm, fmt.Sprint(p.Start), fmt.Sprint(p.End), fmt.Sprint(p.Completions), fmt.Sprint(p.Completed),
+				fmt.Sprintf("%.4f", p.Throughput), fmt.Sprintf("%.4f", p.Ready), fmt.Sprintf("%.4f", p.Blocked), fmt.Sprint(p.MaxReady),
+			})
+		}
+	}
+	cw.Flush()
+
+	return cw.Error()
+}
+
+// writeSeriesJSON writes series as a JSON array, one object per algorithm.
+func writeSeriesJSON(w io.Writer, series []Series) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(series)
+}
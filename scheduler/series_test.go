@@ -0,0 +1,78 @@
+package scheduler
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTimeSeries(t *testing.T) {
+	t.Parallel()
+	result := Result{
+		Schedule: []ProcessResult{{ProcessID: 1, Exit: 3}, {ProcessID: 2, Exit: 4}, {ProcessID: 3, Exit: 9}},
+		QueueLength: []QueueSample{
+			{Time: 0, Ready: 2}, {Time: 3, Ready: 1, Blocked: 1}, {Time: 4, Ready: 0, Blocked: 1}, {Time: 6},
+		},
+	}
+	tests := []struct {
+		name   string
+		window int64
+		want   []SeriesPoint
+	}{
+		{
+			name:   "windows of 4",
+			window: 4,
+			want: []SeriesPoint{
+				{Start: 0, End: 4, Completions: 2, Completed: 2, Throughput: 0.5, Ready: 7.0 / 4, Blocked: 0.25, MaxReady: 2},
+				{Start: 4, End: 8, Completed: 2, Blocked: 0.5},
+				// The last window ends with the run.
+				{Start: 8, End: 9, Completions: 1, Completed: 3, Throughput: 1},
+			},
+		},
+		{
+			name:   "one window",
+			window: 10,
+			want:   []SeriesPoint{{Start: 0, End: 9, Completions: 3, Completed: 3, Throughput: 3.0 / 9, Ready: 7.0 / 9, Blocked: 3.0 / 9, MaxReady: 2}},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := TimeSeries(result, tt.window); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("TimeSeries() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_writeSeries(t *testing.T) {
+	t.Parallel()
+	processes := []Process{NewProcess(1, 0, 2, 0), NewProcess(2, 0, 2, 0)}
+	series := []Series{{Algorithm: "fcfs", Window: 2, Points: TimeSeries(Simulate(processes, fcfsPolicy{}, EngineOptions{}), 2)}}
+
+	var csv bytes.Buffer
+	if err := writeSeriesCSV(&csv, series); err != nil {
+		t.Fatal(err)
+	}
+	want := "algorithm,start,end,completions,completed,throughput,ready,blocked,max_ready\n" +
+		"fcfs,0,2,1,1,0.5000,1.0000,0.0000,1\n" +
+		"fcfs,2,4,1,2,0.5000,0.0000,0.0000,0\n"
+	if csv.String() != want {
+		t.Errorf("writeSeriesCSV() =\n%s\nwant\n%s", csv.String(), want)
+	}
+
+	var js bytes.Buffer
+	if err := writeSeriesJSON(&js, series); err != nil {
+		t.Fatal(err)
+	}
+	var got []Series
+	if err := json.NewDecoder(strings.NewReader(js.String())).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, series) {
+		t.Errorf("writeSeriesJSON() round trip = %+v, want %+v", got, series)
+	}
+}
@@ -0,0 +1,331 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"time"
+)
+
+type (
+	// SimulateRequest is the body of POST /simulate.
+	SimulateRequest struct {
+		Algorithm string    `json:"algorithm"`
+		Processes []Process `json:"processes"`
+		// Inject are processes added at their ArrivalTime that the
+		// algorithm is not told about in advance.
+		Inject  []Process `json:"inject,omitempty"`
+		MaxTime int64     `json:"max_time,omitempty"`
+		// CPUs is the number of processors; zero means one.
+		CPUs int `json:"cpus,omitempty"`
+		// Balance is "global" or "percore".
+		Balance string `json:"balance,omitempty"`
+		// Periods caps each periodic task at this many jobs; zero releases
+		// jobs over a hyperperiod.
+		Periods int `json:"periods,omitempty"`
+		// OnMiss is "continue" or "abort", what to do when a process
+		// misses its deadline.
+		OnMiss string `json:"on_miss,omitempty"`
+		// LockProtocol is "none", "inherit" or "ceiling".
+		LockProtocol string `json:"lock_protocol,omitempty"`
+		// Resources is how many instances each resource has, if more than
+		// one, and Deadlock "ignore", "detect" or "banker".
+		Resources map[string]int64 `json:"resources,omitempty"`
+		Deadlock  string           `json:"deadlock,omitempty"`
+		// Semaphores is the initial value of each semaphore not starting
+		// at zero.
+		Semaphores map[string]int64 `json:"semaphores,omitempty"`
+		// Closed makes each process a user resubmitting jobs, as with
+		// -closed-jobs and -think.
+		Closed ClosedOptions `json:"closed,omitempty"`
+		// Warmup leaves the start of the run out of the averages, as with
+		// -warmup and -warmup-jobs.
+		Warmup Warmup `json:"warmup,omitempty"`
+		// Aging raises a waiting process's priority a level every this
+		// many ticks, as with -aging.
+		Aging int64 `json:"aging,omitempty"`
+		// IOBoost raises a process's priority this many levels when it
+		// comes back from I/O, as with -io-boost.
+		IOBoost int64 `json:"io_boost,omitempty"`
+		// DispatchCost is charged on every dispatch, as with
+		// -dispatch-cost.
+		DispatchCost int64 `json:"dispatch_cost,omitempty"`
+		// Options configures the algorithm; each reads only its own part.
+		Options AlgorithmOptions `json:"options"`
+		// MinShare overrides Options.MinShare, as accepted before Options
+		// existed.
+		MinShare *MinShareOptions `json:"min_share,omitempty"`
+	}
+	// StreamEvent is one line of POST /simulate/stream, shaped like the
+	// Event message of schedulerpb/scheduler.proto.
+	StreamEvent struct {
+		Time     int64  `json:"time"`
+		Kind     string `json:"kind"`
+		PID      int64  `json:"pid"`
+		CPU      int    `json:"cpu"`
+		Resource string `json:"resource,omitempty"`
+		Count    int64  `json:"count,omitempty"`
+		Priority int64  `json:"priority,omitempty"`
+	}
+	// AlgorithmInfo describes one entry of GET /algorithms.
+	AlgorithmInfo struct {
+		Name        string `json:"name"`
+		Title       string `json:"title"`
+		Description string `json:"description"`
+		Default     bool   `json:"default"`
+	}
+)
+
+// maxRequestBytes bounds the size of a workload posted to the server.
+const maxRequestBytes = 1 << 20
+
+// newServeMux routes the REST API. allowOrigin, when set, is sent as the
+// CORS Access-Control-Allow-Origin header so a front-end served from
+// elsewhere can call the API. A positive timeout bounds each simulation;
+// one is also cancelled when its client goes away.
+func newServeMux(allowOrigin string, timeout time.Duration) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/algorithms", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			methodNotAllowed(w, http.MethodGet)
+			return
+		}
+		var list []AlgorithmInfo
+		for _, algorithm := range registry {
+			list = append(list, AlgorithmInfo{
+				Name:        algorithm.Name,
+				Title:       algorithm.Title,
+				Description: algorithm.Description,
+				Default:     algorithm.Default,
+			})
+		}
+		writeJSON(w, http.StatusOK, list)
+	})
+	mux.HandleFunc("/simulate", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			methodNotAllowed(w, http.MethodPost)
+			return
+		}
+		var req SimulateRequest
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBytes)).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("%w: parsing request", err))
+			return
+		}
+		ctx, cancel := simulationContext(r.Context(), timeout)
+		defer cancel()
+		result, err := simulateRequest(ctx, req, nil)
+		if err != nil {
+			writeError(w, simulationStatus(err), err)
+			return
+		}
+		writeJSON(w, http.StatusOK, result)
+	})
+	// Events are written, one JSON object per line, as the engine records
+	// them. A bad request is refused before the first one.
+	mux.HandleFunc("/simulate/stream", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			methodNotAllowed(w, http.MethodPost)
+			return
+		}
+		var req SimulateRequest
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBytes)).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("%w: parsing request", err))
+			return
+		}
+		ctx, cancel := simulationContext(r.Context(), timeout)
+		defer cancel()
+		enc := json.NewEncoder(w)
+		flusher, _ := w.(http.Flusher)
+		started := false
+		_, err := simulateRequest(ctx, req, func(ev Event) {
+			if !started {
+				w.Header().Set("Content-Type", "application/x-ndjson")
+				started = true
+			}
+			_ = enc.Encode(StreamEvent{
+				Time:     ev.Time,
+				Kind:     ev.Kind.String(),
+				PID:      ev.PID,
+				CPU:      ev.CPU,
+				Resource: ev.Resource,
+				Count:    ev.Count,
+				Priority: ev.Priority,
+			})
+			if flusher != nil {
+				flusher.Flush()
+			}
+		})
+		switch {
+		case err == nil:
+		case started:
+			// The status has gone; say why the stream ends early.
+			_ = enc.Encode(struct {
+				Error string `json:"error"`
+			}{err.Error()})
+		default:
+			writeError(w, simulationStatus(err), err)
+		}
+	})
+	if allowOrigin == "" {
+		return mux
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Access-Control-Allow-Origin", allowOrigin)
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+		if r.Method == http.MethodOptions {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// simulationContext bounds a request's simulation by timeout, if positive.
+func simulationContext(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout > 0 {
+		return context.WithTimeout(ctx, timeout)
+	}
+	return context.WithCancel(ctx)
+}
+
+// simulationStatus is the HTTP status for a failed simulation: the request
+// was bad unless the simulation ran out of time.
+func simulationStatus(err error) int {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusBadRequest
+}
+
+// simulateRequest runs req until ctx is done, handing each event to onEvent
+// if it is set.
+func simulateRequest(ctx context.Context, req SimulateRequest, onEvent func(Event)) (Result, error) {
+	algorithm, ok := LookupAlgorithm(req.Algorithm)
+	if !ok {
+		return Result{}, fmt.Errorf("%w: unknown algorithm %q", ErrInvalidArgs, req.Algorithm)
+	}
+	if problems := validateProcesses(injectProcesses(req.Processes, req.Inject)); len(problems) > 0 {
+		return Result{}, &ValidationError{Problems: problems}
+	}
+	processes, _, _, err := expandPeriodic(req.Processes, req.Periods)
+	if err != nil {
+		return Result{}, err
+	}
+	workload := injectProcesses(processes, req.Inject)
+	if err := checkTimeRange(workload); err != nil {
+		return Result{}, err
+	}
+	if req.MinShare != nil {
+		req.Options.MinShare = *req.MinShare
+	}
+	locking, err := parseLockProtocol(req.LockProtocol)
+	if err != nil {
+		return Result{}, err
+	}
+	deadlock, err := parseDeadlockMode(req.Deadlock)
+	if err != nil {
+		return Result{}, err
+	}
+	for name, n := range req.Resources {
+		if n < 1 {
+			return Result{}, fmt.Errorf("%w: resource %q needs at least one instance", ErrInvalidArgs, name)
+		}
+	}
+	if err := checkResources(workload, req.Resources); err != nil {
+		return Result{}, err
+	}
+	for name, n := range req.Semaphores {
+		if n < 0 {
+			return Result{}, fmt.Errorf("%w: semaphore %q cannot start below zero", ErrInvalidArgs, name)
+		}
+	}
+	balance, err := parseBalance(req.Balance)
+	if err != nil {
+		return Result{}, err
+	}
+	abortOnMiss, err := parseOnMiss(req.OnMiss)
+	if err != nil {
+		return Result{}, err
+	}
+	if req.Closed.Jobs < 0 || req.Closed.Think < 0 {
+		return Result{}, fmt.Errorf("%w: closed jobs and think cannot be negative", ErrInvalidArgs)
+	}
+	if req.Warmup.Time < 0 || req.Warmup.Jobs < 0 {
+		return Result{}, fmt.Errorf("%w: warmup cannot be negative", ErrInvalidArgs)
+	}
+	if req.Aging < 0 || req.IOBoost < 0 || req.DispatchCost < 0 {
+		return Result{}, fmt.Errorf("%w: aging, I/O boost and dispatch cost cannot be negative", ErrInvalidArgs)
+	}
+
+	result, err := SimulateContext(ctx, workload, algorithm.New(processes, req.Options), EngineOptions{
+		MaxTime:      req.MaxTime,
+		Locking:      locking,
+		Resources:    req.Resources,
+		Deadlock:     deadlock,
+		Semaphores:   req.Semaphores,
+		CPUs:         req.CPUs,
+		Balance:      balance,
+		OnEvent:      onEvent,
+		AbortOnMiss:  abortOnMiss,
+		Closed:       req.Closed,
+		Warmup:       req.Warmup,
+		Aging:        req.Aging,
+		IOBoost:      req.IOBoost,
+		DispatchCost: req.DispatchCost,
+	})
+	if err != nil {
+		return Result{}, fmt.Errorf("simulation stopped with %d processes unfinished: %w", len(result.Incomplete), err)
+	}
+	return result, nil
+}
+
+func methodNotAllowed(w http.ResponseWriter, allow string) {
+	w.Header().Set("Allow", allow)
+	writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("use %s", allow))
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, struct {
+		Error string `json:"error"`
+	}{err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func runServe(w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	addr := fs.String("addr", "localhost:8080", "address to listen on")
+	allowOrigin := fs.String("allow-origin", "", "CORS origin allowed to call the API, e.g. * or http://localhost:3000")
+	timeout := fs.Duration("timeout", 0, "stop each simulation after this long, e.g. 10s; 0 means no limit")
+	grpcAddr := fs.String("grpc-addr", "", "also serve the gRPC API on this address, e.g. localhost:9090")
+	if err := parseFlags(fs, "serve", args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		return fmt.Errorf("%w: usage: serve [-addr host:port] [-grpc-addr host:port]", ErrInvalidArgs)
+	}
+
+	// Whichever server stops first, with its error, stops the command.
+	errc := make(chan error, 2)
+	if *grpcAddr != "" {
+		lis, err := net.Listen("tcp", *grpcAddr)
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(w, "Serving gRPC on %s\n", lis.Addr())
+		go func() { errc <- newGRPCServer(*timeout).Serve(lis) }()
+	}
+	_, _ = fmt.Fprintf(w, "Listening on http://%s\n", *addr)
+	go func() { errc <- http.ListenAndServe(*addr, newServeMux(*allowOrigin, *timeout)) }()
+	return <-errc
+}
@@ -0,0 +1,153 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_newServeMux(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "lists algorithms",
+			method:     http.MethodGet,
+			path:       "/algorithms",
+			wantStatus: http.StatusOK,
+			wantBody:   `{"name":"minshare","title":"Guaranteed minimum share","description":`,
+		},
+		{
+			name:       "simulates workload",
+			method:     http.MethodPost,
+			path:       "/simulate",
+			body:       `{"algorithm":"fcfs","processes":[{"pid":1,"burst":3},{"pid":2,"arrival":1,"burst":2}]}`,
+			wantStatus: http.StatusOK,
+			wantBody:   `"gantt":[{"pid":1,"start":0,"stop":3},{"pid":2,"start":3,"stop":5}]`,
+		},
+		{
+			name:       "injects process",
+			method:     http.MethodPost,
+			path:       "/simulate",
+			body:       `{"algorithm":"priority","processes":[{"pid":1,"burst":4,"priority":2}],"inject":[{"pid":9,"arrival":1,"burst":2,"priority":1}]}`,
+			wantStatus: http.StatusOK,
+			wantBody:   `"gantt":[{"pid":1,"start":0,"stop":1},{"pid":9,"start":1,"stop":3},{"pid":1,"start":3,"stop":6}]`,
+		},
+		{
+			name:       "unknown algorithm",
+			method:     http.MethodPost,
+			path:       "/simulate",
+			body:       `{"algorithm":"stride"}`,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"error":"invalid args: unknown algorithm \"stride\""}`,
+		},
+		{
+			name:       "invalid workload",
+			method:     http.MethodPost,
+			path:       "/simulate",
+			body:       `{"algorithm":"fcfs","processes":[{"pid":1,"burst":0}]}`,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `zero burst`,
+		},
+		{
+			name:       "malformed body",
+			method:     http.MethodPost,
+			path:       "/simulate",
+			body:       `{`,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `"error"`,
+		},
+		{
+			name:       "wrong method",
+			method:     http.MethodGet,
+			path:       "/simulate",
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			name:       "streams events",
+			method:     http.MethodPost,
+			path:       "/simulate/stream",
+			body:       `{"algorithm":"fcfs","processes":[{"pid":1,"burst":3}]}`,
+			wantStatus: http.StatusOK,
+			wantBody: `{"time":0,"kind":"arrive","pid":1,"cpu":0}
+{"time":0,"kind":"dispatch","pid":1,"cpu":0}
+{"time":3,"kind":"complete","pid":1,"cpu":0}
+`,
+		},
+		{
+			name:       "refuses stream of invalid workload",
+			method:     http.MethodPost,
+			path:       "/simulate/stream",
+			body:       `{"algorithm":"fcfs","processes":[{"pid":1,"burst":0}]}`,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `zero burst`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			rec := httptest.NewRecorder()
+			newServeMux("", 0).ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body)))
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if got := rec.Body.String(); !strings.Contains(got, tt.wantBody) {
+				t.Errorf("body = %s, want it to contain %s", got, tt.wantBody)
+			}
+		})
+	}
+}
+
+func Test_newServeMux_deadline(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+	body := `{"algorithm":"fcfs","processes":[{"pid":1,"burst":3},{"pid":2,"burst":2}]}`
+	rec := httptest.NewRecorder()
+	newServeMux("", 0).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/simulate", strings.NewReader(body)).WithContext(ctx))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusServiceUnavailable)
+	}
+	if got := rec.Body.String(); !strings.Contains(got, "deadline exceeded") {
+		t.Errorf("body = %s, want a deadline error", got)
+	}
+}
+
+func Test_newServeMux_resultRoundTrip(t *testing.T) {
+	t.Parallel()
+	f, err := os.Open("../example_processes.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	processes, err := loadProcesses(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := json.Marshal(SimulateRequest{Algorithm: "rr", Processes: processes})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := httptest.NewRecorder()
+	newServeMux("", 0).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/simulate", strings.NewReader(string(body))))
+	var got Result
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if want := RR(processes); !reflect.DeepEqual(got.Gantt, want.Gantt) {
+		t.Errorf("Gantt = %v, want %v", got.Gantt, want.Gantt)
+	}
+}
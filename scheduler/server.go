@@ -0,0 +1,259 @@
+package scheduler
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+// ServerKind is how aperiodic jobs get CPU time in a fixed-priority
+// periodic system.
+type ServerKind int
+
+const (
+	// ServerBackground runs aperiodic jobs only when no periodic job is
+	// ready: safe for the tasks, but slow to respond.
+	ServerBackground ServerKind = iota
+	// ServerPolling is a periodic task that, at the start of each period,
+	// serves the aperiodic jobs then waiting with up to its budget. Budget
+	// it does not use then is lost until the next period.
+	ServerPolling
+	// ServerSporadic keeps its budget until aperiodic work arrives, and
+	// gets back what it used one period after it started using it, so it
+	// responds at once yet interferes no more than a periodic task.
+	ServerSporadic
+)
+
+func (k ServerKind) String() string {
+	switch k {
+	case ServerPolling:
+		return "polling"
+	case ServerSporadic:
+		return "sporadic"
+	default:
+		return "background"
+	}
+}
+
+type (
+	// ServerOptions size the server: Budget ticks of aperiodic service
+	// every Period, at Priority among the periodic tasks, winning ties.
+	ServerOptions struct {
+		Budget   int64
+		Period   int64
+		Priority int64
+	}
+	// ServerRun is how the aperiodic jobs, and the periodic tasks beside
+	// them, fared under one kind of server.
+	ServerRun struct {
+		Kind ServerKind
+		// Aperiodic counts the aperiodic jobs; Response and MaxResponse
+		// are their average and longest times from arrival to completion.
+		Aperiodic   int
+		Response    float64
+		MaxResponse int64
+		// Jobs counts the periodic jobs, and Missed those that finished
+		// past their deadlines.
+		Jobs   int
+		Missed int
+		// Schedulable is whether response-time analysis finds every
+		// periodic task meets its deadline with the server counted as a
+		// periodic task of its budget and period. Background service
+		// never interferes.
+		Schedulable bool
+	}
+)
+
+// rtJob is a job in the fixed-priority simulation of servers: a periodic
+// job, or an aperiodic one when priority is unused.
+type rtJob struct {
+	release, deadline, left, priority, finish int64
+}
+
+// SimulateServers runs the periodic tasks in processes by fixed priority,
+// releasing jobs as expandPeriodic would, with the other processes served
+// as aperiodic jobs in arrival order by each kind of server in turn.
+func SimulateServers(processes []Process, periods int, opts ServerOptions) ([]ServerRun, error) {
+	if opts.Budget < 1 || opts.Period < opts.Budget {
+		return nil, fmt.Errorf("%w: a server needs a budget of at least 1 and no longer than its period", ErrInvalidArgs)
+	}
+	var tasks, aperiodic []Process
+	for _, p := range processes {
+		if p.periodic() {
+			tasks = append(tasks, p)
+		} else {
+			aperiodic = append(aperiodic, p)
+		}
+	}
+	if len(tasks) == 0 || len(aperiodic) == 0 {
+		return nil, fmt.Errorf("%w: a server needs both periodic tasks and aperiodic processes", ErrInvalidProcesses)
+	}
+	sort.SliceStable(aperiodic, func(i, j int) bool { return aperiodic[i].ArrivalTime < aperiodic[j].ArrivalTime })
+	jobs, _, _, err := expandPeriodic(tasks, periods)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(jobs, func(i, j int) bool { return jobs[i].ArrivalTime < jobs[j].ArrivalTime })
+
+	// For the analysis the server is one more task, ahead of those of its
+	// priority.
+	alone := tasksSchedulable(tasks)
+	withServer := tasksSchedulable(append([]Process{{BurstDuration: opts.Budget, Period: opts.Period, Priority: opts.Priority}}, tasks...))
+
+	var runs []ServerRun
+	for _, kind := range []ServerKind{ServerBackground, ServerPolling, ServerSporadic} {
+		periodic := make([]rtJob, len(jobs))
+		for i, j := range jobs {
+			periodic[i] = rtJob{release: j.ArrivalTime, deadline: j.ArrivalTime + j.Deadline, left: j.BurstDuration, priority: j.Priority}
+		}
+		served := make([]rtJob, len(aperiodic))
+		for i, p := range aperiodic {
+			served[i] = rtJob{release: p.ArrivalTime, left: p.BurstDuration}
+		}
+		simulateServer(kind, opts, periodic, served)
+		run := ServerRun{Kind: kind, Aperiodic: len(served), Jobs: len(periodic), Schedulable: withServer}
+		if kind == ServerBackground {
+			run.Schedulable = alone
+		}
+		var total int64
+		for _, a := range served {
+			r := a.finish - a.release
+			total += r
+			run.MaxResponse = maxInt64(run.MaxResponse, r)
+		}
+		run.Response = float64(total) / float64(len(served))
+		for _, j := range periodic {
+			if j.finish > j.deadline {
+				run.Missed++
+			}
+		}
+		runs = append(runs, run)
+	}
+	return runs, nil
+}
+
+// tasksSchedulable reports whether response-time analysis finds every task
+// meets its deadline.
+func tasksSchedulable(tasks []Process) bool {
+	for i := range tasks {
+		if _, ok := responseTime(tasks, i); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// simulateServer steps one tick at a time until every job has finished,
+// setting each one's finish. Periodic jobs, which must be in order of
+// release, run by priority, then release; aperiodic ones are served first
+// come, first served.
+func simulateServer(kind ServerKind, opts ServerOptions, periodic, aperiodic []rtJob) {
+	var (
+		budget = opts.Budget
+		// A sporadic server that has started using its budget is active
+		// since activated, having used used of it; each replenishment
+		// gives amount back at its time.
+		active         bool
+		activated      int64
+		used           int64
+		replenishments []struct{ at, amount int64 }
+		next           int // first unfinished aperiodic job
+		released       int // periodic jobs released so far
+		ready          []*rtJob
+		left           = len(periodic) + len(aperiodic)
+	)
+	for now := int64(0); left > 0; now++ {
+		pending := next < len(aperiodic) && aperiodic[next].release <= now
+		switch kind {
+		case ServerPolling:
+			// Each period starts with a full budget, kept only if there is
+			// work to poll.
+			if now%opts.Period == 0 {
+				budget = opts.Budget
+				if !pending {
+					budget = 0
+				}
+			}
+		case ServerSporadic:
+			kept := replenishments[:0]
+			for _, r := range replenishments {
+				if r.at == now {
+					budget += r.amount
+				} else {
+					kept = append(kept, r)
+				}
+			}
+			replenishments = kept
+		}
+
+		for ; released < len(periodic) && periodic[released].release <= now; released++ {
+			ready = append(ready, &periodic[released])
+		}
+		best := -1
+		for i, j := range ready {
+			if best < 0 || j.priority < ready[best].priority {
+				best = i
+			}
+		}
+		serve := false
+		switch kind {
+		case ServerBackground:
+			serve = pending && best < 0
+		default:
+			serve = pending && budget > 0 && (best < 0 || opts.Priority <= ready[best].priority)
+		}
+		if !serve {
+			if best >= 0 {
+				j := ready[best]
+				if j.left--; j.left == 0 {
+					j.finish = now + 1
+					ready = append(ready[:best], ready[best+1:]...)
+					left--
+				}
+			}
+			continue
+		}
+		a := &aperiodic[next]
+		if kind == ServerSporadic && !active {
+			active, activated, used = true, now, 0
+		}
+		if kind != ServerBackground {
+			budget--
+			used++
+		}
+		if a.left--; a.left == 0 {
+			a.finish = now + 1
+			next++
+			left--
+		}
+		more := next < len(aperiodic) && aperiodic[next].release <= now
+		switch {
+		case kind == ServerPolling && !more:
+			// Nothing left to poll: the rest of the budget is lost.
+			budget = 0
+		case kind == ServerSporadic && (!more || budget == 0):
+			replenishments = append(replenishments, struct{ at, amount int64 }{activated + opts.Period, used})
+			active = false
+		}
+	}
+}
+
+// outputServers compares the aperiodic response times under each server.
+func outputServers(w io.Writer, opts ServerOptions, runs []ServerRun) {
+	outputTitle(w, fmt.Sprintf("Aperiodic service, server budget %d every %d at priority %d", opts.Budget, opts.Period, opts.Priority))
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Server", "Aperiodic jobs", "Average response", "Longest response", "Periodic jobs", "Missed", "Tasks schedulable"})
+	for _, r := range runs {
+		schedulable := "yes"
+		if !r.Schedulable {
+			schedulable = "no"
+		}
+		table.Append([]string{
+			r.Kind.String(), fmt.Sprint(r.Aperiodic), fmt.Sprintf("%.2f", r.Response), fmt.Sprint(r.MaxResponse),
+			fmt.Sprint(r.Jobs), fmt.Sprint(r.Missed), schedulable,
+		})
+	}
+	table.Render()
+}
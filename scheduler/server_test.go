@@ -0,0 +1,44 @@
+package scheduler
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSimulateServers(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 2, Priority: 1, Period: 4},
+		{ProcessID: 2, BurstDuration: 2, ArrivalTime: 1},
+	}
+	runs, err := SimulateServers(processes, 3, ServerOptions{Budget: 1, Period: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// In the background P2 waits for the first job, then runs before the
+	// second. The polling server finds nothing at t=0 and loses its budget
+	// till t=5, then serves a tick there and at t=10. The sporadic server
+	// serves a tick on arrival and the other when that tick comes back, at
+	// t=6.
+	want := map[ServerKind]int64{ServerBackground: 3, ServerPolling: 10, ServerSporadic: 6}
+	for _, r := range runs {
+		if r.MaxResponse != want[r.Kind] || r.Missed != 0 || r.Jobs != 3 || !r.Schedulable {
+			t.Errorf("%s server = %+v, want response %d and no misses", r.Kind, r, want[r.Kind])
+		}
+	}
+	if len(runs) != len(want) {
+		t.Errorf("got %d runs, want %d", len(runs), len(want))
+	}
+}
+
+func TestSimulateServers_invalid(t *testing.T) {
+	t.Parallel()
+	periodic := Process{ProcessID: 1, BurstDuration: 1, Period: 4}
+	aperiodic := Process{ProcessID: 2, BurstDuration: 1}
+	if _, err := SimulateServers([]Process{periodic, aperiodic}, 0, ServerOptions{Budget: 3, Period: 2}); !errors.Is(err, ErrInvalidArgs) {
+		t.Errorf("budget past the period: error = %v, want %v", err, ErrInvalidArgs)
+	}
+	if _, err := SimulateServers([]Process{periodic}, 0, ServerOptions{Budget: 1, Period: 2}); !errors.Is(err, ErrInvalidProcesses) {
+		t.Errorf("no aperiodic processes: error = %v, want %v", err, ErrInvalidProcesses)
+	}
+}
@@ -0,0 +1,51 @@
+package scheduler
+
+import (
+	"fmt"
+	"io"
+)
+
+// Slowdown is r's turnaround over its burst, also called stretch: how many
+// times longer the process took than it would have alone on the CPU. Unlike
+// turnaround it allows for job size, so a short job kept waiting counts for
+// as much as a long one kept waiting as many times its length.
+func (r ProcessResult) Slowdown() float64 {
+	if r.Burst <= 0 {
+		return 0
+	}
+	return float64(r.Turnaround) / float64(r.Burst)
+}
+
+// SlowdownStats is the mean and worst slowdown of a run's processes.
+type SlowdownStats struct {
+	Mean float64 `json:"mean"`
+	Max  float64 `json:"max"`
+	// MaxPID is the process slowed down the most, the first if several
+	// were.
+	MaxPID int64 `json:"max_pid"`
+}
+
+// NewSlowdownStats summarizes the slowdown of rows.
+func NewSlowdownStats(rows []ProcessResult) SlowdownStats {
+	var s SlowdownStats
+	if len(rows) == 0 {
+		return s
+	}
+	var sum float64
+	for _, r := range rows {
+		slowdown := r.Slowdown()
+		sum += slowdown
+		if slowdown > s.Max {
+			s.Max, s.MaxPID = slowdown, r.ProcessID
+		}
+	}
+	s.Mean = sum / float64(len(rows))
+	return s
+}
+
+func outputSlowdown(w io.Writer, s SlowdownStats) {
+	if s.Max == 0 {
+		return
+	}
+	_, _ = fmt.Fprintf(w, "Slowdown (turnaround / burst): mean %.2f, max %.2f (P%d)\n", s.Mean, s.Max, s.MaxPID)
+}
@@ -0,0 +1,41 @@
+package scheduler
+
+import "testing"
+
+func TestProcessResult_Slowdown(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		row  ProcessResult
+		want float64
+	}{
+		{"alone", ProcessResult{Burst: 4, Turnaround: 4}, 1},
+		{"waited", ProcessResult{Burst: 2, Turnaround: 7}, 3.5},
+		{"no burst", ProcessResult{Turnaround: 3}, 0},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := tt.row.Slowdown(); got != tt.want {
+				t.Errorf("Slowdown() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewSlowdownStats(t *testing.T) {
+	t.Parallel()
+	rows := []ProcessResult{
+		{ProcessID: 1, Burst: 4, Turnaround: 4},
+		{ProcessID: 2, Burst: 2, Turnaround: 8},
+		{ProcessID: 3, Burst: 1, Turnaround: 4},
+	}
+	want := SlowdownStats{Mean: 3, Max: 4, MaxPID: 2}
+	if got := NewSlowdownStats(rows); got != want {
+		t.Errorf("NewSlowdownStats() = %+v, want %+v", got, want)
+	}
+	if got := NewSlowdownStats(nil); got != (SlowdownStats{}) {
+		t.Errorf("NewSlowdownStats(nil) = %+v, want zero", got)
+	}
+}
@@ -0,0 +1,121 @@
+package scheduler
+
+import (
+	"fmt"
+	"io"
+	"math"
+)
+
+// SPNOptions configures shortest-process-next burst prediction.
+type SPNOptions struct {
+	// Alpha weighs the last burst against the history, from 0 (never learn)
+	// to 1 (only the last burst counts); zero uses 0.5.
+	Alpha float64
+	// Initial is the guess for a process's first burst; zero uses 10.
+	Initial float64
+}
+
+const (
+	defaultSPNAlpha   = 0.5
+	defaultSPNInitial = 10
+)
+
+func (o SPNOptions) withDefaults() SPNOptions {
+	if o.Alpha <= 0 {
+		o.Alpha = defaultSPNAlpha
+	}
+	if o.Alpha > 1 {
+		o.Alpha = 1
+	}
+	if o.Initial <= 0 {
+		o.Initial = defaultSPNInitial
+	}
+	return o
+}
+
+func newSPNPolicy(opts SPNOptions) Policy {
+	return &spnPolicy{opts: opts.withDefaults(), guess: map[int64]float64{}, ran: map[int64]int64{}, started: map[int64]int64{}}
+}
+
+// spnPolicy runs the ready process with the shortest predicted next CPU
+// burst, without looking at the true one. After each burst its guess becomes
+// alpha*burst + (1-alpha)*guess, the exponential average real kernels use.
+type spnPolicy struct {
+	opts    SPNOptions
+	guess   map[int64]float64
+	ran     map[int64]int64
+	started map[int64]int64
+	history []spnError
+}
+
+// spnError is how far one burst's prediction was off.
+type spnError struct {
+	pid           int64
+	guess, actual float64
+}
+
+func (p *spnPolicy) predicted(pid int64) float64 {
+	if g, ok := p.guess[pid]; ok {
+		return g
+	}
+	return p.opts.Initial
+}
+
+func (p *spnPolicy) Observe(ev Event) {
+	switch ev.Kind {
+	case EventDispatch:
+		p.started[ev.PID] = ev.Time
+	case EventPreempt, EventExpire, EventLockWait, EventSemWait, EventSuspend:
+		// A process suspended off the CPU has no run to add.
+		if start, ok := p.started[ev.PID]; ok {
+			p.ran[ev.PID] += ev.Time - start
+			delete(p.started, ev.PID)
+		}
+	case EventBlock, EventComplete:
+		// The CPU burst is over: learn from it.
+		actual := float64(p.ran[ev.PID] + ev.Time - p.started[ev.PID])
+		guess := p.predicted(ev.PID)
+		p.history = append(p.history, spnError{pid: ev.PID, guess: guess, actual: actual})
+		p.guess[ev.PID] = p.opts.Alpha*actual + (1-p.opts.Alpha)*guess
+		delete(p.ran, ev.PID)
+	}
+}
+
+func (p *spnPolicy) Less(a, b *Task) bool {
+	if ga, gb := p.predicted(a.ProcessID), p.predicted(b.ProcessID); ga != gb {
+		return ga < gb
+	}
+	return a.Seq < b.Seq
+}
+func (p *spnPolicy) Preemptive() bool { return false }
+func (p *spnPolicy) Quantum() int64   { return 0 }
+
+// StableOrder holds because a guess only changes when its process ends a
+// burst, while it is off the ready queue.
+func (p *spnPolicy) StableOrder() bool { return true }
+
+// Report prints the mean absolute prediction error overall and per process.
+func (p *spnPolicy) Report(w io.Writer) {
+	if len(p.history) == 0 {
+		return
+	}
+	var (
+		order = []int64{}
+		sum   = map[int64]float64{}
+		count = map[int64]int{}
+		total float64
+	)
+	for _, e := range p.history {
+		if count[e.pid] == 0 {
+			order = append(order, e.pid)
+		}
+		off := math.Abs(e.guess - e.actual)
+		sum[e.pid] += off
+		count[e.pid]++
+		total += off
+	}
+	_, _ = fmt.Fprintf(w, "Burst prediction: mean absolute error %.2f over %d bursts\n", total/float64(len(p.history)), len(p.history))
+	for _, pid := range order {
+		_, _ = fmt.Fprintf(w, "  P%d: %.2f over %d\n", pid, sum[pid]/float64(count[pid]), count[pid])
+	}
+}
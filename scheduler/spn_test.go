@@ -0,0 +1,48 @@
+package scheduler
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestSPN(t *testing.T) {
+	t.Parallel()
+	// Everyone starts with a guess of 10. After their first bursts 1 is
+	// guessed at 6 and 2 at 8, so both go ahead of 3, which has no history.
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 4, Bursts: []int64{2, 1, 2}},
+		{ProcessID: 2, BurstDuration: 12, Bursts: []int64{6, 1, 6}},
+		{ProcessID: 3, ArrivalTime: 1, BurstDuration: 4},
+	}
+	policy := newSPNPolicy(SPNOptions{})
+	got := Simulate(processes, policy, EngineOptions{})
+	want := []TimeSlice{
+		{PID: 1, Start: 0, Stop: 2}, {PID: 2, Start: 2, Stop: 8}, {PID: 1, Start: 8, Stop: 10},
+		{PID: 2, Start: 10, Stop: 16}, {PID: 3, Start: 16, Stop: 20},
+	}
+	if !reflect.DeepEqual(got.Gantt, want) {
+		t.Errorf("Gantt = %v, want %v", got.Gantt, want)
+	}
+
+	var w bytes.Buffer
+	policy.(Reporter).Report(&w)
+	wantReport := `Burst prediction: mean absolute error 4.80 over 5 bursts
+  P1: 6.00 over 2
+  P2: 3.00 over 2
+  P3: 6.00 over 1
+`
+	if w.String() != wantReport {
+		t.Errorf("Report() =\n%s\nwant\n%s", w.String(), wantReport)
+	}
+}
+
+func TestSPNOptions_withDefaults(t *testing.T) {
+	t.Parallel()
+	if got, want := (SPNOptions{}).withDefaults(), (SPNOptions{Alpha: 0.5, Initial: 10}); got != want {
+		t.Errorf("withDefaults() = %+v, want %+v", got, want)
+	}
+	if got := (SPNOptions{Alpha: 2}).withDefaults().Alpha; got != 1 {
+		t.Errorf("Alpha 2 became %v, want 1", got)
+	}
+}
@@ -0,0 +1,7 @@
+//go:build sqlite
+
+package scheduler
+
+// Building with -tags sqlite links in the SQLite driver -db and history
+// need; it is a cgo package, so it is left out of default builds.
+import _ "github.com/mattn/go-sqlite3"
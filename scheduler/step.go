@@ -0,0 +1,291 @@
+package scheduler
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Snapshot is the scheduler state at a moment, rebuilt from the event log.
+type Snapshot struct {
+	Time int64
+	// Running lists the processes on a CPU, in the order they were
+	// dispatched.
+	Running []int64
+	// Ready is in the order processes joined the queue.
+	Ready   []int64
+	Blocked []int64
+	// Suspended processes are held from outside, wherever they were.
+	Suspended []int64
+	Done      []int64
+	Gantt     []TimeSlice
+}
+
+// SnapshotAt replays result's events up to and including time t.
+func SnapshotAt(result Result, t int64) Snapshot {
+	snap := Snapshot{Time: t}
+	remove := func(list []int64, pid int64) []int64 {
+		for i := range list {
+			if list[i] == pid {
+				return append(list[:i:i], list[i+1:]...)
+			}
+		}
+		return list
+	}
+	contains := func(list []int64, pid int64) bool {
+		for _, p := range list {
+			if p == pid {
+				return true
+			}
+		}
+		return false
+	}
+	// resumeTo is the list each suspended process goes back to when it is
+	// resumed, nil if it has yet to arrive; it follows the events that
+	// happen to the process while it is suspended.
+	resumeTo := map[int64]*[]int64{}
+	for _, ev := range result.Events {
+		if ev.Time > t {
+			break
+		}
+		if to, ok := resumeTo[ev.PID]; ok {
+			switch ev.Kind {
+			case EventArrive, EventWake:
+				resumeTo[ev.PID] = &snap.Ready
+			case EventDepWait:
+				resumeTo[ev.PID] = &snap.Blocked
+			case EventResume:
+				snap.Suspended = remove(snap.Suspended, ev.PID)
+				if to != nil {
+					*to = append(*to, ev.PID)
+				}
+				delete(resumeTo, ev.PID)
+			}
+			continue
+		}
+		switch ev.Kind {
+		case EventArrive, EventWake:
+			snap.Blocked = remove(snap.Blocked, ev.PID)
+			snap.Ready = append(snap.Ready, ev.PID)
+		case EventDispatch:
+			snap.Ready = remove(snap.Ready, ev.PID)
+			snap.Running = append(snap.Running, ev.PID)
+		case EventPreempt, EventExpire:
+			snap.Running = remove(snap.Running, ev.PID)
+			snap.Ready = append(snap.Ready, ev.PID)
+		case EventDepWait:
+			snap.Ready = remove(snap.Ready, ev.PID)
+			snap.Blocked = append(snap.Blocked, ev.PID)
+		case EventBlock, EventLockWait, EventSemWait:
+			snap.Running = remove(snap.Running, ev.PID)
+			snap.Blocked = append(snap.Blocked, ev.PID)
+		case EventComplete:
+			snap.Running = remove(snap.Running, ev.PID)
+			snap.Done = append(snap.Done, ev.PID)
+		case EventSuspend:
+			var to *[]int64
+			switch {
+			case contains(snap.Running, ev.PID), contains(snap.Ready, ev.PID):
+				to = &snap.Ready
+			case contains(snap.Blocked, ev.PID):
+				to = &snap.Blocked
+			}
+			snap.Running = remove(snap.Running, ev.PID)
+			snap.Ready = remove(snap.Ready, ev.PID)
+			snap.Blocked = remove(snap.Blocked, ev.PID)
+			snap.Suspended = append(snap.Suspended, ev.PID)
+			resumeTo[ev.PID] = to
+		}
+	}
+	for _, s := range result.Gantt {
+		if s.Start >= t {
+			break
+		}
+		if s.Stop > t {
+			s.Stop = t
+		}
+		snap.Gantt = append(snap.Gantt, s)
+	}
+
+	return snap
+}
+
+func outputSnapshot(w io.Writer, snap Snapshot, gantt GanttOptions) {
+	pids := func(list []int64) string {
+		names := make([]string, len(list))
+		for i, pid := range list {
+			names[i] = fmt.Sprint(pid)
+		}
+		return "[" + strings.Join(names, " ") + "]"
+	}
+	running := "idle"
+	if len(snap.Running) > 0 {
+		running = strings.Trim(pids(snap.Running), "[]")
+	}
+	suspended := ""
+	if len(snap.Suspended) > 0 {
+		suspended = " suspended " + pids(snap.Suspended)
+	}
+	_, _ = fmt.Fprintf(w, "t=%d running %s ready %s blocked %s%s done %s\n",
+		snap.Time, running, pids(snap.Ready), pids(snap.Blocked), suspended, pids(snap.Done))
+	if len(snap.Gantt) > 0 {
+		outputGantt(w, snap.Gantt, gantt)
+	}
+}
+
+// eventTimes are the distinct times at which something happened.
+func eventTimes(result Result) []int64 {
+	var times []int64
+	for _, ev := range result.Events {
+		if n := len(times); n == 0 || times[n-1] != ev.Time {
+			times = append(times, ev.Time)
+		}
+	}
+	return times
+}
+
+const stepHelp = "commands: n(ext), p(rev), g(oto) <tick>, f(irst), l(ast), q(uit)"
+
+// stepThrough lets the user move forwards and backwards through the events of
+// result, reading one command per line from r.
+func stepThrough(r io.Reader, w io.Writer, result Result, gantt GanttOptions) {
+	times := eventTimes(result)
+	if len(times) == 0 {
+		_, _ = fmt.Fprintln(w, "no events")
+		return
+	}
+	_, _ = fmt.Fprintln(w, stepHelp)
+	pos := 0
+	outputSnapshot(w, SnapshotAt(result, times[pos]), gantt)
+	in := bufio.NewScanner(r)
+	prompt := func() { _, _ = fmt.Fprint(w, "> ") }
+	for prompt(); in.Scan(); prompt() {
+		fields := strings.Fields(in.Text())
+		cmd := "n"
+		if len(fields) > 0 {
+			cmd = fields[0]
+		}
+		switch cmd {
+		case "n", "next":
+			if pos < len(times)-1 {
+				pos++
+			}
+		case "p", "prev":
+			if pos > 0 {
+				pos--
+			}
+		case "f", "first":
+			pos = 0
+		case "l", "last":
+			pos = len(times) - 1
+		case "g", "goto":
+			if len(fields) != 2 {
+				_, _ = fmt.Fprintln(w, "usage: g <tick>")
+				continue
+			}
+			tick, err := strconv.ParseInt(fields[1], 10, 64)
+			if err != nil {
+				_, _ = fmt.Fprintln(w, err)
+				continue
+			}
+			// Park on the last event at or before the tick, but show the
+			// state at the tick itself.
+			pos = sort.Search(len(times), func(i int) bool { return times[i] > tick }) - 1
+			if pos < 0 {
+				pos = 0
+			}
+			outputSnapshot(w, SnapshotAt(result, tick), gantt)
+			continue
+		case "q", "quit":
+			return
+		default:
+			_, _ = fmt.Fprintln(w, stepHelp)
+			continue
+		}
+		outputSnapshot(w, SnapshotAt(result, times[pos]), gantt)
+	}
+}
+
+func runStep(w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("step", flag.ContinueOnError)
+	algo := fs.String("algo", "fcfs", "algorithm to step through")
+	maxTime := fs.Int64("max-time", 0, "stop the simulation at this tick")
+	lockProtocol := fs.String("lock-protocol", "none", "raise lock holders' priority: none, inherit or ceiling")
+	resources := fs.String("resources", "", "instances of each resource with more than one, as name=N,...")
+	deadlock := fs.String("deadlock", "ignore", "deal with deadlocks over resources: ignore, detect or banker to avoid them")
+	semaphores := fs.String("semaphores", "", "initial value of each semaphore not starting at 0, as name=N,...")
+	cpus := fs.Int("cpus", 1, "number of CPUs; processes with several threads are gang scheduled")
+	balance := fs.String("balance", "global", "share processes between CPUs with one global queue or percore queues")
+	stealThreshold := fs.Int("steal-threshold", 1, "with percore queues, only steal from a queue holding at least this many processes")
+	steal := fs.String("steal", "one", "with percore queues, an idle CPU steals one process or half of a queue")
+	dispatchCost := fs.Int64("dispatch-cost", 0, "ticks the scheduler takes to decide, charged on every dispatch")
+	aging := fs.Int64("aging", 0, "raise a waiting process's priority a level every this many ticks, for priority and mlfq")
+	ioBoost := fs.Int64("io-boost", 0, "raise a process's priority this many levels when it comes back from I/O, until it has run, for priority")
+	lenient := fs.Bool("lenient", false, "fix invalid processes with a warning instead of rejecting the workload")
+	ganttFlags := addGanttFlags(fs)
+	algoFlags := addAlgorithmFlags(fs)
+	if err := parseFlags(fs, "step", args); err != nil {
+		return err
+	}
+	opts, err := algoFlags.options()
+	if err != nil {
+		return err
+	}
+	if *cpus < 1 {
+		return fmt.Errorf("%w: -cpus must be at least 1", ErrInvalidArgs)
+	}
+	if *aging < 0 || *ioBoost < 0 || *dispatchCost < 0 {
+		return fmt.Errorf("%w: -aging, -io-boost and -dispatch-cost cannot be negative", ErrInvalidArgs)
+	}
+	engineOpts := EngineOptions{MaxTime: *maxTime, CPUs: *cpus, Aging: *aging, IOBoost: *ioBoost, DispatchCost: *dispatchCost}
+	if engineOpts.Locking, err = parseLockProtocol(*lockProtocol); err != nil {
+		return err
+	}
+	if engineOpts.Resources, err = parseResources(*resources); err != nil {
+		return err
+	}
+	if engineOpts.Deadlock, err = parseDeadlockMode(*deadlock); err != nil {
+		return err
+	}
+	if engineOpts.Semaphores, err = parseSemaphores(*semaphores); err != nil {
+		return err
+	}
+	if engineOpts.Balance, err = parseBalance(*balance); err != nil {
+		return err
+	}
+	engineOpts.Steal.Threshold = *stealThreshold
+	if engineOpts.Steal.Half, err = parseSteal(*steal); err != nil {
+		return err
+	}
+	algorithm, ok := LookupAlgorithm(*algo)
+	if !ok {
+		return fmt.Errorf("%w: unknown algorithm %q", ErrInvalidArgs, *algo)
+	}
+	// Commands come from stdin, so the workload has to be a named file.
+	if fs.NArg() != 1 || fs.Arg(0) == "-" {
+		return fmt.Errorf("%w: usage: step [-algo name] file", ErrInvalidArgs)
+	}
+	f, closeFile, err := openProcessingFile("step", fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer closeFile()
+	processes, err := loadProcesses(f)
+	if err != nil {
+		return err
+	}
+	if processes, err = checkProcesses(processes, *lenient, os.Stderr); err != nil {
+		return err
+	}
+	if err := checkResources(processes, engineOpts.Resources); err != nil {
+		return err
+	}
+
+	stepThrough(stdin, w, Simulate(processes, algorithm.New(processes, opts), engineOpts), ganttFlags.options(w))
+	return nil
+}
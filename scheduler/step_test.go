@@ -0,0 +1,79 @@
+package scheduler
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSnapshotAt(t *testing.T) {
+	t.Parallel()
+	result := Simulate([]Process{
+		{ProcessID: 1, BurstDuration: 4, Bursts: []int64{2, 3, 2}},
+		{ProcessID: 2, ArrivalTime: 1, BurstDuration: 4},
+	}, fcfsPolicy{}, EngineOptions{})
+	tests := []struct {
+		name string
+		time int64
+		want Snapshot
+	}{
+		{
+			name: "second arrival waits",
+			time: 1,
+			want: Snapshot{Time: 1, Running: []int64{1}, Ready: []int64{2}, Gantt: []TimeSlice{{PID: 1, Start: 0, Stop: 1}}},
+		},
+		{
+			name: "first blocked on I/O",
+			time: 4,
+			want: Snapshot{Time: 4, Running: []int64{2}, Ready: []int64{}, Blocked: []int64{1}, Gantt: []TimeSlice{{PID: 1, Start: 0, Stop: 2}, {PID: 2, Start: 2, Stop: 4}}},
+		},
+		{
+			name: "everything done",
+			time: 8,
+			want: Snapshot{
+				Time:    8,
+				Running: []int64{},
+				Ready:   []int64{},
+				Blocked: []int64{},
+				Done:    []int64{2, 1},
+				Gantt:   []TimeSlice{{PID: 1, Start: 0, Stop: 2}, {PID: 2, Start: 2, Stop: 6}, {PID: 1, Start: 6, Stop: 8}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := SnapshotAt(result, tt.time); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SnapshotAt() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_stepThrough(t *testing.T) {
+	t.Parallel()
+	result := FCFS([]Process{
+		{ProcessID: 1, BurstDuration: 5},
+		{ProcessID: 2, ArrivalTime: 3, BurstDuration: 2},
+	})
+	var w bytes.Buffer
+	stepThrough(strings.NewReader("n\nn\np\ng 4\nq\nn\n"), &w, result, GanttOptions{})
+	var states []string
+	for _, line := range strings.Split(w.String(), "\n") {
+		if i := strings.Index(line, "t="); i >= 0 {
+			states = append(states, line[i:])
+		}
+	}
+	want := []string{
+		"t=0 running 1 ready [] blocked [] done []",
+		"t=3 running 1 ready [2] blocked [] done []",
+		"t=5 running 2 ready [] blocked [] done [1]",
+		"t=3 running 1 ready [2] blocked [] done []",
+		"t=4 running 1 ready [2] blocked [] done []",
+	}
+	if !reflect.DeepEqual(states, want) {
+		t.Errorf("stepThrough() states = %q, want %q", states, want)
+	}
+}
@@ -0,0 +1,52 @@
+package scheduler
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// writeSummaryTSV writes result as one tab-separated line with no header,
+// for shell loops and awk to pick apart: the algorithm, processes completed,
+// average wait, turnaround and response, throughput, makespan, 95th
+// percentile turnaround, dispatches, preemptions and mean normalized
+// turnaround. A run stopped early, by its time limit, a timeout or a
+// deadlock, has a last field of truncated.
+func writeSummaryTSV(w io.Writer, name string, result Result) error {
+	averageResponse, makespan := responseAndMakespan(result)
+	fields := []string{
+		name,
+		fmt.Sprint(len(result.Schedule)),
+		fmt.Sprintf("%.2f", result.AverageWait),
+		fmt.Sprintf("%.2f", result.AverageTurnaround),
+		fmt.Sprintf("%.2f", averageResponse),
+		fmt.Sprintf("%.4f", result.Throughput),
+		fmt.Sprint(makespan),
+		fmt.Sprint(result.Percentiles.Turnaround.P95),
+		fmt.Sprint(result.Dispatches),
+		fmt.Sprint(result.Preemptions.Total),
+		fmt.Sprintf("%.2f", result.Slowdown.Mean),
+	}
+	if result.Truncated || result.Deadlocked {
+		fields = append(fields, "truncated")
+	}
+	_, err := fmt.Fprintln(w, strings.Join(fields, "\t"))
+	return err
+}
+
+// responseAndMakespan is result's average response time and the exit of its
+// last process. Like the other averages, response leaves out a warmup.
+func responseAndMakespan(result Result) (float64, int64) {
+	var response, makespan int64
+	for _, r := range result.Schedule {
+		makespan = maxInt64(makespan, r.Exit)
+	}
+	measured, _ := steadyState(result.Schedule, result.Warmup.Warmup)
+	for _, r := range measured {
+		response += r.Response
+	}
+	if len(measured) == 0 {
+		return 0, makespan
+	}
+	return float64(response) / float64(len(measured)), makespan
+}
@@ -0,0 +1,32 @@
+package scheduler
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_writeSummaryTSV(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 5, ArrivalTime: 0},
+		{ProcessID: 2, BurstDuration: 9, ArrivalTime: 3},
+		{ProcessID: 3, BurstDuration: 6, ArrivalTime: 6},
+	}
+	var w strings.Builder
+	if err := writeSummaryTSV(&w, "fcfs", Simulate(processes, fcfsPolicy{}, EngineOptions{})); err != nil {
+		t.Fatal(err)
+	}
+	want := "fcfs\t3\t3.33\t10.00\t3.33\t0.1500\t20\t14\t3\t0\t1.52\n"
+	if got := w.String(); got != want {
+		t.Errorf("writeSummaryTSV() = %q, want %q", got, want)
+	}
+
+	// A run stopped early says so in a last field.
+	w.Reset()
+	if err := writeSummaryTSV(&w, "fcfs", Simulate(processes, fcfsPolicy{}, EngineOptions{MaxTime: 12})); err != nil {
+		t.Fatal(err)
+	}
+	if got := w.String(); !strings.HasSuffix(got, "\ttruncated\n") {
+		t.Errorf("writeSummaryTSV() of a truncated run = %q, want a truncated field", got)
+	}
+}
@@ -0,0 +1,176 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Suspension is an outside suspend of a process at Start, such as a user
+// pressing Ctrl+Z, resumed at Stop.
+type Suspension struct {
+	Start int64 `json:"start"`
+	Stop  int64 `json:"stop"`
+}
+
+// parseSuspensions reads a process file's suspend column: space-separated
+// start-stop pairs.
+func parseSuspensions(s string) ([]Suspension, error) {
+	var suspensions []Suspension
+	for _, f := range strings.Fields(s) {
+		start, stop, ok := strings.Cut(f, "-")
+		if !ok {
+			return nil, fmt.Errorf("suspension %q is not start-stop", f)
+		}
+		a, err := strconv.ParseInt(start, 10, 64)
+		if err != nil {
+			return nil, errors.Unwrap(err)
+		}
+		b, err := strconv.ParseInt(stop, 10, 64)
+		if err != nil {
+			return nil, errors.Unwrap(err)
+		}
+		suspensions = append(suspensions, Suspension{Start: a, Stop: b})
+	}
+	return suspensions, nil
+}
+
+func formatSuspensions(suspensions []Suspension) string {
+	fields := make([]string, len(suspensions))
+	for i, s := range suspensions {
+		fields[i] = fmt.Sprintf("%d-%d", s.Start, s.Stop)
+	}
+	return strings.Join(fields, " ")
+}
+
+// suspensionProblem describes what is wrong with a process's suspensions,
+// or returns "": each must start at or after zero and before it stops, and
+// after the one before it stopped.
+func suspensionProblem(p Process) string {
+	var last int64 = -1
+	for _, s := range p.Suspend {
+		switch {
+		case s.Start < 0:
+			return fmt.Sprintf("suspension %d-%d starts before 0", s.Start, s.Stop)
+		case s.Stop <= s.Start:
+			return fmt.Sprintf("suspension %d-%d does not stop after it starts", s.Start, s.Stop)
+		case s.Start <= last:
+			return fmt.Sprintf("suspension %d-%d is out of order or overlaps the one before", s.Start, s.Stop)
+		}
+		last = s.Stop
+	}
+	return ""
+}
+
+// suspendEvent is a pending suspend, or resume, of a task.
+type suspendEvent struct {
+	time   int64
+	task   *Task
+	resume bool
+}
+
+// initSuspensions lines up every task's suspends and resumes in time order.
+func (e *engine) initSuspensions() {
+	for _, t := range e.tasks {
+		for _, s := range t.Suspend {
+			e.suspends = append(e.suspends, suspendEvent{time: s.Start, task: t}, suspendEvent{time: s.Stop, task: t, resume: true})
+		}
+	}
+	sort.SliceStable(e.suspends, func(i, j int) bool { return e.suspends[i].time < e.suspends[j].time })
+}
+
+// suspendAndResume handles the suspends and resumes due by now. A process
+// suspended while running or ready is held off the CPU and out of the
+// queue until it resumes; one suspended while blocked, or before it
+// arrives, is held when it would have become ready. Held time is not wait.
+func (e *engine) suspendAndResume() {
+	for len(e.suspends) > 0 && e.suspends[0].time <= e.now {
+		ev := e.suspends[0]
+		e.suspends = e.suspends[1:]
+		t := ev.task
+		if t.phase == len(t.bursts) {
+			continue
+		}
+		if ev.resume {
+			e.resume(t)
+		} else {
+			e.suspend(t)
+		}
+	}
+}
+
+func (e *engine) suspend(t *Task) {
+	t.suspended, t.suspendedAt = true, e.now
+	switch {
+	case len(t.cpus) > 0:
+		e.endSlice(t)
+		e.freeCPUs(t)
+		e.stopped(t)
+		t.parked = true
+	case t.queued:
+		e.unqueue(t)
+		t.parked = true
+	}
+	e.record(EventSuspend, t)
+	e.opts.Log.Log(e.now, "suspend", "pid", t.ProcessID)
+}
+
+func (e *engine) resume(t *Task) {
+	if !t.suspended {
+		return
+	}
+	t.suspended = false
+	if s, ok := t.suspension(e.now); ok {
+		e.suspensions = append(e.suspensions, s)
+	}
+	e.record(EventResume, t)
+	e.opts.Log.Log(e.now, "resume", "pid", t.ProcessID)
+	if t.parked {
+		t.parked = false
+		e.enqueue(t)
+	}
+}
+
+// suspension is t's current suspension up to now, from when it was
+// suspended or arrived, whichever is later, or false if that is not before
+// now.
+func (t *Task) suspension(now int64) (TimeSlice, bool) {
+	start := maxInt64(t.suspendedAt, t.ArrivalTime)
+	return TimeSlice{PID: t.ProcessID, Start: start, Stop: now}, now > start
+}
+
+// unqueue takes t out of its ready queue, counting its wait so far.
+func (e *engine) unqueue(t *Task) {
+	q := e.queues[t.queue]
+	var others []*Task
+	for q.Len() > 0 {
+		if u := q.Take(); u != t {
+			others = append(others, u)
+			continue
+		}
+		break
+	}
+	for _, u := range others {
+		q.Add(u)
+	}
+	t.queued = false
+	t.wait += e.now - t.readySince
+	if t.threads > 1 {
+		e.readyGangs--
+	}
+}
+
+// openSuspensions closes the suspensions still in force when the run
+// stopped, at the time it did.
+func (e *engine) openSuspensions() []TimeSlice {
+	suspensions := e.suspensions
+	for _, t := range e.tasks {
+		if s, ok := t.suspension(e.now); ok && t.suspended {
+			suspensions = append(suspensions, s)
+		}
+	}
+	sort.SliceStable(suspensions, func(i, j int) bool { return suspensions[i].Start < suspensions[j].Start })
+	return suspensions
+}
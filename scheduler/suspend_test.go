@@ -0,0 +1,142 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSimulate_suspend(t *testing.T) {
+	t.Parallel()
+	suspended := func(p Process, s ...Suspension) Process {
+		p.Suspend = s
+		return p
+	}
+	tests := []struct {
+		name        string
+		processes   []Process
+		gantt       []TimeSlice
+		wait        map[int64]int64
+		suspensions []TimeSlice
+	}{
+		{
+			name:        "while running",
+			processes:   []Process{suspended(NewProcess(1, 0, 4, 0), Suspension{2, 5}), NewProcess(2, 1, 2, 0)},
+			gantt:       []TimeSlice{{PID: 1, Start: 0, Stop: 2}, {PID: 2, Start: 2, Stop: 4}, {PID: 1, Start: 5, Stop: 7}},
+			wait:        map[int64]int64{1: 0, 2: 1},
+			suspensions: []TimeSlice{{PID: 1, Start: 2, Stop: 5}},
+		},
+		{
+			// Suspended time in the ready queue is not wait.
+			name:        "while ready",
+			processes:   []Process{NewProcess(1, 0, 4, 0), suspended(NewProcess(2, 0, 2, 0), Suspension{1, 6})},
+			gantt:       []TimeSlice{{PID: 1, Start: 0, Stop: 4}, {PID: 2, Start: 6, Stop: 8}},
+			wait:        map[int64]int64{1: 0, 2: 1},
+			suspensions: []TimeSlice{{PID: 2, Start: 1, Stop: 6}},
+		},
+		{
+			name:        "before arriving",
+			processes:   []Process{suspended(NewProcess(1, 2, 1, 0), Suspension{0, 4})},
+			gantt:       []TimeSlice{{PID: 1, Start: 4, Stop: 5}},
+			wait:        map[int64]int64{1: 0},
+			suspensions: []TimeSlice{{PID: 1, Start: 2, Stop: 4}},
+		},
+		{
+			// The I/O goes on, but the process is held once it is done.
+			name: "while blocked",
+			processes: []Process{
+				suspended(Process{ProcessID: 1, BurstDuration: 2, Bursts: []int64{1, 2, 1}}, Suspension{2, 5}),
+			},
+			gantt:       []TimeSlice{{PID: 1, Start: 0, Stop: 1}, {PID: 1, Start: 5, Stop: 6}},
+			wait:        map[int64]int64{1: 0},
+			suspensions: []TimeSlice{{PID: 1, Start: 2, Stop: 5}},
+		},
+		{
+			name:      "after finishing",
+			processes: []Process{suspended(NewProcess(1, 0, 1, 0), Suspension{3, 4})},
+			gantt:     []TimeSlice{{PID: 1, Start: 0, Stop: 1}},
+			wait:      map[int64]int64{1: 0},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := Simulate(tt.processes, fcfsPolicy{}, EngineOptions{})
+			if !reflect.DeepEqual(got.Gantt, tt.gantt) {
+				t.Errorf("Gantt = %v, want %v", got.Gantt, tt.gantt)
+			}
+			for _, row := range got.Schedule {
+				if row.Wait != tt.wait[row.ProcessID] {
+					t.Errorf("PID %d wait = %d, want %d", row.ProcessID, row.Wait, tt.wait[row.ProcessID])
+				}
+			}
+			if !reflect.DeepEqual(got.Suspensions, tt.suspensions) {
+				t.Errorf("Suspensions = %v, want %v", got.Suspensions, tt.suspensions)
+			}
+		})
+	}
+}
+
+func TestSnapshotAt_suspended(t *testing.T) {
+	t.Parallel()
+	p := NewProcess(1, 0, 4, 0)
+	p.Suspend = []Suspension{{2, 5}}
+	result := Simulate([]Process{p, NewProcess(2, 1, 4, 0)}, fcfsPolicy{}, EngineOptions{})
+	tests := []struct {
+		at   int64
+		want Snapshot
+	}{
+		{at: 3, want: Snapshot{Running: []int64{2}, Suspended: []int64{1}}},
+		// Resumed, P1 waits behind P2 rather than taking the CPU back.
+		{at: 5, want: Snapshot{Running: []int64{2}, Ready: []int64{1}}},
+	}
+	for _, tt := range tests {
+		got := SnapshotAt(result, tt.at)
+		gotState := fmt.Sprint(got.Running, got.Ready, got.Suspended)
+		if want := fmt.Sprint(tt.want.Running, tt.want.Ready, tt.want.Suspended); gotState != want {
+			t.Errorf("SnapshotAt(%d) running, ready, suspended = %s, want %s", tt.at, gotState, want)
+		}
+	}
+}
+
+func Test_parseSuspensions(t *testing.T) {
+	t.Parallel()
+	got, err := parseSuspensions(" 2-5  8-9 ")
+	if want := []Suspension{{2, 5}, {8, 9}}; err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("parseSuspensions() = %v, %v, want %v", got, err, want)
+	}
+	if s := formatSuspensions(got); s != "2-5 8-9" {
+		t.Errorf("formatSuspensions() = %q", s)
+	}
+	for _, bad := range []string{"2", "a-5", "2-b"} {
+		if _, err := parseSuspensions(bad); err == nil {
+			t.Errorf("parseSuspensions(%q) succeeded", bad)
+		}
+	}
+	if _, err := loadProcesses(strings.NewReader("1,5,0,0,batch,,,,0,,0,,0,0,3\n")); !errors.Is(err, ErrInvalidProcesses) {
+		t.Errorf("loadProcesses() with a bad suspend column: err = %v, want ErrInvalidProcesses", err)
+	}
+}
+
+func Test_suspensionProblem(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		suspend []Suspension
+		want    string
+	}{
+		{suspend: []Suspension{{0, 1}, {2, 3}}},
+		{suspend: []Suspension{{-1, 1}}, want: "starts before 0"},
+		{suspend: []Suspension{{3, 3}}, want: "does not stop after it starts"},
+		{suspend: []Suspension{{2, 5}, {4, 6}}, want: "overlaps"},
+		{suspend: []Suspension{{2, 5}, {5, 6}}, want: "overlaps"},
+	}
+	for _, tt := range tests {
+		got := suspensionProblem(Process{Suspend: tt.suspend})
+		if (tt.want == "") != (got == "") || !strings.Contains(got, tt.want) {
+			t.Errorf("suspensionProblem(%v) = %q, want %q", tt.suspend, got, tt.want)
+		}
+	}
+}
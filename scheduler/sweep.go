@@ -0,0 +1,251 @@
+package scheduler
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+// sweepParam is a setting a sweep can vary: the least value it takes, and
+// how to apply a value to a run's options.
+type sweepParam struct {
+	min int64
+	set func(a *AlgorithmOptions, e *EngineOptions, v int64)
+}
+
+// sweepParams are the settings sweep can vary, by flag name.
+var sweepParams = map[string]sweepParam{
+	"quantum":       {1, func(a *AlgorithmOptions, _ *EngineOptions, v int64) { a.RR.Quantum = v }},
+	"switch-cost":   {0, func(a *AlgorithmOptions, _ *EngineOptions, v int64) { a.RR.SwitchCost = v }},
+	"mlfq-levels":   {1, func(a *AlgorithmOptions, _ *EngineOptions, v int64) { a.MLFQ.Levels = int(v) }},
+	"mlfq-boost":    {0, func(a *AlgorithmOptions, _ *EngineOptions, v int64) { a.MLFQ.BoostInterval = v }},
+	"cpus":          {1, func(_ *AlgorithmOptions, e *EngineOptions, v int64) { e.CPUs = int(v) }},
+	"aging":         {0, func(_ *AlgorithmOptions, e *EngineOptions, v int64) { e.Aging = v }},
+	"io-boost":      {0, func(_ *AlgorithmOptions, e *EngineOptions, v int64) { e.IOBoost = v }},
+	"dispatch-cost": {0, func(_ *AlgorithmOptions, e *EngineOptions, v int64) { e.DispatchCost = v }},
+}
+
+// sweepMetrics are the measures a sweep can pick its best value by, and
+// whether more is better.
+var sweepMetrics = map[string]bool{
+	"wait":       false,
+	"turnaround": false,
+	"response":   false,
+	"makespan":   false,
+	"throughput": true,
+	"normalized": false,
+}
+
+type (
+	// SweepOptions varies Param from From to To in steps of Step, running
+	// each of Algorithms with the other settings in Options and Engine.
+	SweepOptions struct {
+		Param          string
+		From, To, Step int64
+		Algorithms     []Algorithm
+		Options        AlgorithmOptions
+		Engine         EngineOptions
+	}
+	// SweepPoint is how one algorithm fared with the parameter at Value.
+	SweepPoint struct {
+		Algorithm string
+		Value     int64
+		Metrics
+		Response float64
+		// Normalized is the mean turnaround over burst, which shows how
+		// much a policy favours short processes over long ones.
+		Normalized float64
+		Makespan   int64
+		Dispatches int
+	}
+)
+
+// Sweep runs every algorithm on processes once for each value of the
+// parameter, in order of algorithm and then value.
+func Sweep(processes []Process, opts SweepOptions) ([]SweepPoint, error) {
+	param, ok := sweepParams[opts.Param]
+	if !ok {
+		return nil, fmt.Errorf("%w: cannot sweep %q; choose from %s", ErrInvalidArgs, opts.Param, strings.Join(sweepParamNames(), ", "))
+	}
+	if opts.From < param.min || opts.To < opts.From || opts.Step < 1 {
+		return nil, fmt.Errorf("%w: sweep %s from %d to %d in steps of %d: the range must start at %d or more and not run backwards",
+			ErrInvalidArgs, opts.Param, opts.From, opts.To, opts.Step, param.min)
+	}
+	var points []SweepPoint
+	for _, a := range opts.Algorithms {
+		for v := opts.From; v <= opts.To; v += opts.Step {
+			algoOpts, engineOpts := opts.Options, opts.Engine
+			param.set(&algoOpts, &engineOpts, v)
+			result := Simulate(processes, a.New(processes, algoOpts), engineOpts)
+			response, makespan := responseAndMakespan(result)
+			points = append(points, SweepPoint{
+				Algorithm:  a.Name,
+				Value:      v,
+				Metrics:    result.Metrics,
+				Response:   response,
+				Normalized: result.Slowdown.Mean,
+				Makespan:   makespan,
+				Dispatches: result.Dispatches,
+			})
+		}
+	}
+	return points, nil
+}
+
+func sweepParamNames() []string {
+	names := make([]string, 0, len(sweepParams))
+	for name := range sweepParams {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// measure is p's value of metric, which must be in sweepMetrics.
+func (p SweepPoint) measure(metric string) float64 {
+	switch metric {
+	case "wait":
+		return p.AverageWait
+	case "turnaround":
+		return p.AverageTurnaround
+	case "response":
+		return p.Response
+	case "makespan":
+		return float64(p.Makespan)
+	case "normalized":
+		return p.Normalized
+	default:
+		return p.Throughput
+	}
+}
+
+// bestSweepPoints marks, for each algorithm, the first point with the best
+// value of metric.
+func bestSweepPoints(points []SweepPoint, metric string) map[int]bool {
+	higher := sweepMetrics[metric]
+	best := map[string]int{}
+	for i, p := range points {
+		j, ok := best[p.Algorithm]
+		if m, b := p.measure(metric), points[j].measure(metric); !ok || (higher && m > b) || (!higher && m < b) {
+			best[p.Algorithm] = i
+		}
+	}
+	marked := map[int]bool{}
+	for _, i := range best {
+		marked[i] = true
+	}
+	return marked
+}
+
+func outputSweep(w io.Writer, param, metric string, points []SweepPoint) {
+	outputTitle(w, fmt.Sprintf("Sweep of %s, best by %s", param, metric))
+	best := bestSweepPoints(points, metric)
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Algorithm", param, "Wait", "Turnaround", "Response", "Normalized turnaround", "Throughput", "Makespan", "Dispatches", "Best"})
+	for i, p := range points {
+		mark := ""
+		if best[i] {
+			mark = "*"
+		}
+		table.Append([]string{
+			p.Algorithm, fmt.Sprint(p.Value), fmt.Sprintf("%.2f", p.AverageWait), fmt.Sprintf("%.2f", p.AverageTurnaround),
+			fmt.Sprintf("%.2f", p.Response), fmt.Sprintf("%.2f", p.Normalized), fmt.Sprintf("%.4f", p.Throughput), fmt.Sprint(p.Makespan),
+			fmt.Sprint(p.Dispatches), mark,
+		})
+	}
+	table.Render()
+}
+
+// writeSweepCSV writes one row per point, with a header, for plotting.
+func writeSweepCSV(w io.Writer, param string, points []SweepPoint) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"algorithm", param, "wait", "turnaround", "response", "normalized_turnaround", "throughput", "makespan", "dispatches"})
+	for _, p := range points {
+		_ = cw.Write([]string{
+			p.Algorithm, fmt.Sprint(p.Value), fmt.Sprintf("%.2f", p.AverageWait), fmt.Sprintf("%.2f", p.AverageTurnaround),
+			fmt.Sprintf("%.2f", p.Response), fmt.Sprintf("%.2f", p.Normalized), fmt.Sprintf("%.4f", p.Throughput), fmt.Sprint(p.Makespan),
+			fmt.Sprint(p.Dispatches),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// parseSweepRange reads a range "from..to", or a single value.
+func parseSweepRange(s string) (from, to int64, err error) {
+	lo, hi, found := strings.Cut(s, "..")
+	if from, err = strconv.ParseInt(strings.TrimSpace(lo), 10, 64); err != nil {
+		return 0, 0, fmt.Errorf("%w: bad -range %q, want from..to", ErrInvalidArgs, s)
+	}
+	if !found {
+		return from, from, nil
+	}
+	if to, err = strconv.ParseInt(strings.TrimSpace(hi), 10, 64); err != nil {
+		return 0, 0, fmt.Errorf("%w: bad -range %q, want from..to", ErrInvalidArgs, s)
+	}
+	return from, to, nil
+}
+
+// runSweep is the sweep subcommand: one workload run with a parameter at
+// each value of a range, to find the best setting.
+func runSweep(w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("sweep", flag.ContinueOnError)
+	param := fs.String("param", "quantum", "setting to vary: "+strings.Join(sweepParamNames(), ", "))
+	span := fs.String("range", "1..20", "values to try, from..to")
+	step := fs.Int64("step", 1, "difference between successive values")
+	algo := fs.String("algo", "rr", "comma-separated algorithms to run at each value")
+	metric := fs.String("metric", "turnaround", "measure the best value is chosen by: wait, turnaround, response, normalized, makespan or throughput")
+	asCSV := fs.Bool("csv", false, "write CSV for plotting instead of a table")
+	lenient := fs.Bool("lenient", false, "fix invalid processes with a warning instead of rejecting the workload")
+	seed := addSeedFlag(fs, "random seed for stochastic algorithms")
+	algoFlags := addAlgorithmFlags(fs)
+	if err := parseFlags(fs, "sweep", args); err != nil {
+		return err
+	}
+	if fs.NArg() > 1 {
+		return fmt.Errorf("%w: usage: sweep [-param quantum] [-range 1..20] file", ErrInvalidArgs)
+	}
+	if _, ok := sweepMetrics[*metric]; !ok {
+		return fmt.Errorf("%w: unknown -metric %q", ErrInvalidArgs, *metric)
+	}
+	opts := SweepOptions{Param: *param, Step: *step}
+	var err error
+	if opts.From, opts.To, err = parseSweepRange(*span); err != nil {
+		return err
+	}
+	if opts.Algorithms, err = selectRuns(*algo); err != nil {
+		return err
+	}
+	if opts.Options, err = algoFlags.options(); err != nil {
+		return err
+	}
+	opts.Options.Seed = pickSeed(os.Stderr, *seed)
+	f, closeFile, err := openProcessingFile(append([]string{"sweep"}, fs.Args()...)...)
+	if err != nil {
+		return err
+	}
+	defer closeFile()
+	processes, err := loadProcesses(f)
+	if err != nil {
+		return err
+	}
+	if processes, err = checkProcesses(processes, *lenient, os.Stderr); err != nil {
+		return err
+	}
+	points, err := Sweep(processes, opts)
+	if err != nil {
+		return err
+	}
+	if *asCSV {
+		return writeSweepCSV(w, *param, points)
+	}
+	outputSweep(w, *param, *metric, points)
+	return nil
+}
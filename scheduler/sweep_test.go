@@ -0,0 +1,76 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestSweep(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 5, ArrivalTime: 0},
+		{ProcessID: 2, BurstDuration: 9, ArrivalTime: 3},
+		{ProcessID: 3, BurstDuration: 6, ArrivalTime: 6},
+	}
+	rr, _ := LookupAlgorithm("rr")
+	points, err := Sweep(processes, SweepOptions{Param: "quantum", From: 2, To: 6, Step: 2, Algorithms: []Algorithm{rr}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, p := range points {
+		got = append(got, fmt.Sprintf("%d:%.2f/%d", p.Value, p.AverageTurnaround, p.Dispatches))
+	}
+	if want := "2:11.67/11 4:13.00/7 6:11.00/4"; strings.Join(got, " ") != want {
+		t.Errorf("Sweep() = %v, want %s", got, want)
+	}
+	if best := bestSweepPoints(points, "turnaround"); !best[2] || len(best) != 1 {
+		t.Errorf("bestSweepPoints() = %v, want the quantum of 6", best)
+	}
+	var w strings.Builder
+	if err := writeSweepCSV(&w, "quantum", points[:1]); err != nil {
+		t.Fatal(err)
+	}
+	if want := "algorithm,quantum,wait,turnaround,response,normalized_turnaround,throughput,makespan,dispatches\nrr,2,5.00,11.67,0.67,1.71,0.1500,20,11\n"; w.String() != want {
+		t.Errorf("writeSweepCSV() = %q, want %q", w.String(), want)
+	}
+}
+
+func TestSweep_invalid(t *testing.T) {
+	t.Parallel()
+	rr, _ := LookupAlgorithm("rr")
+	processes := []Process{{ProcessID: 1, BurstDuration: 1}}
+	for _, opts := range []SweepOptions{
+		{Param: "nice", From: 1, To: 2, Step: 1},
+		{Param: "cpus", From: 0, To: 2, Step: 1},
+		{Param: "quantum", From: 5, To: 2, Step: 1},
+		{Param: "quantum", From: 1, To: 2, Step: 0},
+	} {
+		opts.Algorithms = []Algorithm{rr}
+		if _, err := Sweep(processes, opts); !errors.Is(err, ErrInvalidArgs) {
+			t.Errorf("Sweep(%+v) error = %v, want ErrInvalidArgs", opts, err)
+		}
+	}
+}
+
+func Test_parseSweepRange(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		in       string
+		from, to int64
+		wantErr  bool
+	}{
+		{in: "1..20", from: 1, to: 20},
+		{in: "4", from: 4, to: 4},
+		{in: "1..x", wantErr: true},
+		{in: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		from, to, err := parseSweepRange(tt.in)
+		if (err != nil) != tt.wantErr || from != tt.from || to != tt.to {
+			t.Errorf("parseSweepRange(%q) = %d, %d, %v", tt.in, from, to, err)
+		}
+	}
+}
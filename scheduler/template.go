@@ -0,0 +1,67 @@
+package scheduler
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/template"
+)
+
+// TemplateData is what a -template file is executed with, once per
+// algorithm.
+type TemplateData struct {
+	// Algorithm is the name the algorithm is selected by, and Title its
+	// heading.
+	Algorithm string
+	Title     string
+	// Processes is the simulated workload, after any periodic tasks were
+	// expanded into jobs and processes injected.
+	Processes []Process
+	// Gantt is the schedule's slices in time order, with CPU set on runs
+	// with several CPUs.
+	Gantt []TimeSlice
+	// Schedule has one row per finished process, as in the schedule table.
+	Schedule []ProcessResult
+	// Metrics are AverageWait, AverageTurnaround and Throughput, the
+	// figures under the schedule table.
+	Metrics
+	// Result holds every other metric: Queue, Fairness, Shares, Cores,
+	// Energy, Deadlines, Events and so on.
+	Result Result
+}
+
+// templateFuncs are the functions -template files may call besides the
+// text/template built-ins.
+var templateFuncs = template.FuncMap{
+	"add":    func(a, b int64) int64 { return a + b },
+	"sub":    func(a, b int64) int64 { return a - b },
+	"repeat": func(s string, n int64) string { return strings.Repeat(s, int(n)) },
+	"pad":    func(width int, v interface{}) string { return fmt.Sprintf("%*v", width, v) },
+}
+
+// loadTemplate parses a -template file.
+func loadTemplate(p string) (*template.Template, error) {
+	b, err := os.ReadFile(p)
+	if err != nil {
+		return nil, fmt.Errorf("%v: error reading %s", err, p)
+	}
+	tmpl, err := template.New(p).Funcs(templateFuncs).Parse(string(b))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidArgs, err)
+	}
+	return tmpl, nil
+}
+
+// renderTemplate writes one algorithm's result through tmpl.
+func renderTemplate(w io.Writer, tmpl *template.Template, run Algorithm, workload []Process, result Result) error {
+	return tmpl.Execute(w, TemplateData{
+		Algorithm: run.Name,
+		Title:     run.Title,
+		Processes: workload,
+		Gantt:     result.Gantt,
+		Schedule:  result.Schedule,
+		Metrics:   result.Metrics,
+		Result:    result,
+	})
+}
@@ -0,0 +1,69 @@
+package scheduler
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_renderTemplate(t *testing.T) {
+	t.Parallel()
+	processes := []Process{{ProcessID: 1, BurstDuration: 3}, {ProcessID: 2, ArrivalTime: 1, BurstDuration: 2}}
+	result := Simulate(processes, fcfsPolicy{}, EngineOptions{})
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{
+			name: "schedule",
+			text: "{{.Algorithm}}:{{range .Schedule}} {{.ProcessID}}/{{.Wait}}{{end}} avg {{printf \"%.1f\" .AverageWait}}\n",
+			want: "fcfs: 1/0 2/2 avg 1.0\n",
+		},
+		{
+			name: "gantt",
+			text: "{{range .Gantt}}|{{repeat \"#\" (sub .Stop .Start)}}{{end}}| {{len .Processes}} processes, max ready {{.Result.Queue.MaxReady}}",
+			want: "|###|##| 2 processes, max ready 1",
+		},
+		{
+			name: "functions",
+			text: "[{{pad 3 .Title}}] {{add 1 2}}",
+			want: "[First come first served] 3",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			path := filepath.Join(t.TempDir(), "report.tmpl")
+			if err := os.WriteFile(path, []byte(tt.text), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			tmpl, err := loadTemplate(path)
+			if err != nil {
+				t.Fatalf("loadTemplate() error = %v", err)
+			}
+			var b bytes.Buffer
+			run := Algorithm{Name: "fcfs", Factory: Factory{Title: "First come first served"}}
+			if err := renderTemplate(&b, tmpl, run, processes, result); err != nil {
+				t.Fatalf("renderTemplate() error = %v", err)
+			}
+			if b.String() != tt.want {
+				t.Errorf("renderTemplate() = %q, want %q", b.String(), tt.want)
+			}
+		})
+	}
+}
+
+func Test_loadTemplate_invalid(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "bad.tmpl")
+	if err := os.WriteFile(path, []byte("{{range .Gantt}}"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadTemplate(path); !errors.Is(err, ErrInvalidArgs) {
+		t.Errorf("loadTemplate() error = %v, want %v", err, ErrInvalidArgs)
+	}
+}
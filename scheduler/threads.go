@@ -0,0 +1,174 @@
+package scheduler
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+// ThreadMode is how the threads of a multithreaded process are scheduled.
+type ThreadMode int
+
+const (
+	// ThreadsGang runs all of a process's threads at once, as one task
+	// holding a CPU per thread until its longest thread finishes.
+	ThreadsGang ThreadMode = iota
+	// ThreadsIndependent schedules each thread as a process of its own;
+	// the process completes when its last thread does.
+	ThreadsIndependent
+)
+
+func parseThreadMode(s string) (ThreadMode, error) {
+	switch s {
+	case "gang":
+		return ThreadsGang, nil
+	case "independent":
+		return ThreadsIndependent, nil
+	}
+	return ThreadsGang, fmt.Errorf("%w: -thread-mode must be gang or independent, not %q", ErrInvalidArgs, s)
+}
+
+// threadBursts is the CPU burst of each of p's threads: ThreadBursts, or
+// BurstDuration for each of Threads, or nil if p has one thread.
+func (p Process) threadBursts() []int64 {
+	if len(p.ThreadBursts) > 0 {
+		return p.ThreadBursts
+	}
+	if p.Threads <= 1 {
+		return nil
+	}
+	bursts := make([]int64, p.Threads)
+	for i := range bursts {
+		bursts[i] = p.BurstDuration
+	}
+	return bursts
+}
+
+// ganged is p as the engine gang schedules it: with thread bursts, as
+// many threads as it has bursts, running as long as the longest.
+func ganged(p Process) Process {
+	if len(p.ThreadBursts) == 0 {
+		return p
+	}
+	p.Threads, p.BurstDuration = int64(len(p.ThreadBursts)), 0
+	for _, b := range p.ThreadBursts {
+		p.BurstDuration = maxInt64(p.BurstDuration, b)
+	}
+	return p
+}
+
+// splitThreads schedules the threads of multithreaded processes
+// independently: each thread becomes a process with a PID above every PID
+// in processes, taking its process's arrival, priority and other settings,
+// and dependencies on the process wait for all of its threads. threadsOf
+// maps each multithreaded process's PID to its threads' PIDs, in order.
+func splitThreads(processes []Process) (expanded []Process, threadsOf map[int64][]int64) {
+	var next int64
+	for i := range processes {
+		next = maxInt64(next, processes[i].ProcessID)
+	}
+	next++
+	threadsOf = map[int64][]int64{}
+	for i := range processes {
+		p := processes[i]
+		bursts := p.threadBursts()
+		if bursts == nil {
+			expanded = append(expanded, p)
+			continue
+		}
+		for _, b := range bursts {
+			thread := p
+			thread.ProcessID, thread.Threads, thread.ThreadBursts = next, 0, nil
+			thread.BurstDuration = b
+			expanded = append(expanded, thread)
+			threadsOf[p.ProcessID] = append(threadsOf[p.ProcessID], next)
+			next++
+		}
+	}
+	if len(threadsOf) == 0 {
+		return expanded, nil
+	}
+	for i := range expanded {
+		var after []int64
+		for _, pid := range expanded[i].DependsOn {
+			if threads, ok := threadsOf[pid]; ok {
+				after = append(after, threads...)
+			} else {
+				after = append(after, pid)
+			}
+		}
+		expanded[i].DependsOn = after
+	}
+	return expanded, threadsOf
+}
+
+// ThreadedProcess is how a process whose threads were scheduled
+// independently fared: it completes when its last thread does.
+type ThreadedProcess struct {
+	ProcessID int64
+	Arrival   int64
+	Threads   []ProcessResult
+	// Exit is the last thread's exit, and Turnaround runs from Arrival to
+	// it. Both are zero if a thread did not finish.
+	Exit       int64
+	Turnaround int64
+}
+
+// threadedProcesses gathers the threads in schedule back into their
+// processes, in the order of threadsOf's processes' PIDs.
+func threadedProcesses(schedule []ProcessResult, processes []Process, threadsOf map[int64][]int64) []ThreadedProcess {
+	rows := make(map[int64]ProcessResult, len(schedule))
+	for _, r := range schedule {
+		rows[r.ProcessID] = r
+	}
+	var threaded []ThreadedProcess
+	for i := range processes {
+		threads, ok := threadsOf[processes[i].ProcessID]
+		if !ok {
+			continue
+		}
+		tp := ThreadedProcess{ProcessID: processes[i].ProcessID, Arrival: processes[i].ArrivalTime}
+		finished := true
+		for _, pid := range threads {
+			r, ok := rows[pid]
+			if !ok {
+				finished = false
+				continue
+			}
+			tp.Threads = append(tp.Threads, r)
+			tp.Exit = maxInt64(tp.Exit, r.Exit)
+		}
+		if finished {
+			tp.Turnaround = tp.Exit - tp.Arrival
+		} else {
+			tp.Exit = 0
+		}
+		threaded = append(threaded, tp)
+	}
+	return threaded
+}
+
+// outputThreads lists each independently scheduled process with its
+// threads' exits and its own completion.
+func outputThreads(w io.Writer, threaded []ThreadedProcess) {
+	if len(threaded) == 0 {
+		return
+	}
+	outputTitle(w, "Threads")
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"PID", "Arrival", "Thread exits", "Completion", "Turnaround"})
+	for _, tp := range threaded {
+		exits := make([]string, len(tp.Threads))
+		for i, r := range tp.Threads {
+			exits[i] = fmt.Sprintf("%d@%d", r.ProcessID, r.Exit)
+		}
+		completion, turnaround := "-", "-"
+		if tp.Exit > 0 {
+			completion, turnaround = fmt.Sprint(tp.Exit), fmt.Sprint(tp.Turnaround)
+		}
+		table.Append([]string{fmt.Sprint(tp.ProcessID), fmt.Sprint(tp.Arrival), strings.Join(exits, " "), completion, turnaround})
+	}
+	table.Render()
+}
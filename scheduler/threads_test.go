@@ -0,0 +1,91 @@
+package scheduler
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_ganged(t *testing.T) {
+	t.Parallel()
+	got := ganged(Process{ProcessID: 1, BurstDuration: 9, ThreadBursts: []int64{3, 5, 2}})
+	want := Process{ProcessID: 1, BurstDuration: 5, Threads: 3, ThreadBursts: []int64{3, 5, 2}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ganged() = %+v, want %+v", got, want)
+	}
+}
+
+func Test_splitThreads(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 1, BurstDuration: 9, Priority: 2, ThreadBursts: []int64{2, 3}},
+		{ProcessID: 2, BurstDuration: 4, Threads: 2, Bursts: []int64{1, 2, 3}},
+		{ProcessID: 5, BurstDuration: 1, DependsOn: []int64{1}},
+	}
+	got, threadsOf := splitThreads(processes)
+	want := []Process{
+		{ProcessID: 6, ArrivalTime: 1, BurstDuration: 2, Priority: 2},
+		{ProcessID: 7, ArrivalTime: 1, BurstDuration: 3, Priority: 2},
+		{ProcessID: 8, BurstDuration: 4, Bursts: []int64{1, 2, 3}},
+		{ProcessID: 9, BurstDuration: 4, Bursts: []int64{1, 2, 3}},
+		{ProcessID: 5, BurstDuration: 1, DependsOn: []int64{6, 7}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("splitThreads() = %+v, want %+v", got, want)
+	}
+	if wantOf := map[int64][]int64{1: {6, 7}, 2: {8, 9}}; !reflect.DeepEqual(threadsOf, wantOf) {
+		t.Errorf("threadsOf = %v, want %v", threadsOf, wantOf)
+	}
+	if processes[2].DependsOn[0] != 1 {
+		t.Errorf("splitThreads() changed its input: %+v", processes[2])
+	}
+}
+
+func TestThreads_independent(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 1, ThreadBursts: []int64{4, 1}},
+		{ProcessID: 2, BurstDuration: 2},
+	}
+	// Ganged, 1 holds both CPUs for 4 ticks; independent, its short thread
+	// makes way for 2.
+	gang := Simulate(processes, fcfsPolicy{}, EngineOptions{CPUs: 2})
+	if got := gang.Schedule[1].Exit; got != 6 {
+		t.Errorf("ganged, P2 exits at %d, want 6", got)
+	}
+	split, threadsOf := splitThreads(processes)
+	result := Simulate(split, fcfsPolicy{}, EngineOptions{CPUs: 2})
+	threaded := threadedProcesses(result.Schedule, processes, threadsOf)
+	if len(threaded) != 1 || threaded[0].Exit != 4 || threaded[0].Turnaround != 4 || len(threaded[0].Threads) != 2 {
+		t.Fatalf("threadedProcesses() = %+v, want P1 completing at 4 with two threads", threaded)
+	}
+	var w bytes.Buffer
+	outputThreads(&w, threaded)
+	if row := "|   1 |       0 | 3@4 4@1      |          4 |          4 |"; !strings.Contains(w.String(), row) {
+		t.Errorf("outputThreads() is missing %q in\n%s", row, w.String())
+	}
+}
+
+func TestThreadBursts_file(t *testing.T) {
+	t.Parallel()
+	in := "1,4,0,1,batch,,,,0,,0,,0,0,,4 2 3\n2,3,1,1,batch,,,,0,,0,,0,0,,\n"
+	processes, err := loadProcesses(strings.NewReader(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int64{4, 2, 3}; !reflect.DeepEqual(processes[0].ThreadBursts, want) {
+		t.Errorf("ThreadBursts = %v, want %v", processes[0].ThreadBursts, want)
+	}
+	var w bytes.Buffer
+	if err := writeProcesses(&w, processes); err != nil {
+		t.Fatal(err)
+	}
+	if w.String() != in {
+		t.Errorf("writeProcesses() =\n%s\nwant\n%s", w.String(), in)
+	}
+	problems := validateProcesses([]Process{{ProcessID: 1, BurstDuration: 3, Bursts: []int64{1, 1, 2}, ThreadBursts: []int64{2, 2}}})
+	if len(problems) != 1 || problems[0].Fix != "thread bursts dropped" {
+		t.Errorf("validateProcesses() = %v, want thread bursts dropped", problems)
+	}
+}
@@ -0,0 +1,20 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+)
+
+// SimulateTimeout is Simulate, giving up once timeout of real time has
+// passed, if it is positive, to stop a runaway simulation. A run that
+// times out keeps the partial schedule computed so far, marked Truncated
+// and TimedOut, with the processes left listed in Incomplete.
+func SimulateTimeout(processes []Process, policy Policy, opts EngineOptions, timeout time.Duration) Result {
+	if timeout <= 0 {
+		return Simulate(processes, policy, opts)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	result, _ := SimulateContext(ctx, processes, policy, opts)
+	return result
+}
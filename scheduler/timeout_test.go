@@ -0,0 +1,48 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSimulateTimeout(t *testing.T) {
+	t.Parallel()
+	processes := make([]Process, 100)
+	for i := range processes {
+		processes[i] = Process{ProcessID: int64(i + 1), BurstDuration: 2, ArrivalTime: int64(i)}
+	}
+	// Every event takes a millisecond, so the run cannot finish in time.
+	slow := EngineOptions{OnProgress: func(Progress) { time.Sleep(time.Millisecond) }}
+	tests := []struct {
+		name         string
+		timeout      time.Duration
+		wantTimedOut bool
+	}{
+		{name: "no timeout", timeout: 0},
+		{name: "ample", timeout: time.Hour},
+		{name: "runaway", timeout: 20 * time.Millisecond, wantTimedOut: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			result := SimulateTimeout(processes, fcfsPolicy{}, slow, tt.timeout)
+			if result.TimedOut != tt.wantTimedOut || result.Truncated != tt.wantTimedOut || result.Cancelled != tt.wantTimedOut {
+				t.Fatalf("TimedOut, Truncated, Cancelled = %v, %v, %v, want %v", result.TimedOut, result.Truncated, result.Cancelled, tt.wantTimedOut)
+			}
+			if !tt.wantTimedOut {
+				if len(result.Schedule) != len(processes) {
+					t.Errorf("%d processes completed, want %d", len(result.Schedule), len(processes))
+				}
+				return
+			}
+			// The partial schedule is kept, with the rest left incomplete.
+			if len(result.Schedule) == 0 || len(result.Schedule) == len(processes) {
+				t.Errorf("%d processes completed, want some but not all %d", len(result.Schedule), len(processes))
+			}
+			if len(result.Incomplete) == 0 {
+				t.Error("no processes are listed as incomplete")
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package scheduler
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// traceTickMicros is how long one simulated tick lasts in exported traces.
+const traceTickMicros = 1000
+
+// traceState is the ftrace prev_state recorded when a process leaves the CPU.
+var traceState = map[EventKind]string{
+	EventPreempt:  "R",
+	EventExpire:   "R",
+	EventBlock:    "S",
+	EventComplete: "X",
+	EventLockWait: "D",
+	EventSemWait:  "D",
+}
+
+// writeSwitchTrace writes result's events as ftrace text output with
+// sched_switch and sched_wakeup records, the format read by Perfetto and
+// systrace-based kernel-trace viewers. One tick is exported as a millisecond.
+func writeSwitchTrace(w io.Writer, result Result) error {
+	type switchOut struct {
+		pid   int64
+		state string
+		time  int64
+	}
+	var (
+		bw      = bufio.NewWriter(w)
+		running = map[int]int64{}
+		pending = map[int]*switchOut{}
+	)
+	line := func(cpu int, time int64, format string, args ...interface{}) {
+		_, _ = fmt.Fprintf(bw, "%16s-%-5d [%03d] d..3 %6d.%06d: ", traceComm(running[cpu]), running[cpu], cpu,
+			time*traceTickMicros/1e6, time*traceTickMicros%1e6)
+		_, _ = fmt.Fprintf(bw, format, args...)
+		_, _ = fmt.Fprintln(bw)
+	}
+	switchTo := func(cpu int, time int64, prevState string, next int64) {
+		line(cpu, time, "sched_switch: prev_comm=%s prev_pid=%d prev_prio=120 prev_state=%s ==> next_comm=%s next_pid=%d next_prio=120",
+			traceComm(running[cpu]), running[cpu], prevState, traceComm(next), next)
+		running[cpu] = next
+	}
+	// A process leaving the CPU only becomes a switch once we know whether
+	// something else is dispatched at the same moment.
+	flush := func(before int64) {
+		cpus := make([]int, 0, len(pending))
+		for cpu := range pending {
+			cpus = append(cpus, cpu)
+		}
+		sort.Ints(cpus)
+		for _, cpu := range cpus {
+			if out := pending[cpu]; out.time < before {
+				switchTo(cpu, out.time, out.state, 0)
+				delete(pending, cpu)
+			}
+		}
+	}
+
+	_, _ = fmt.Fprintln(bw, "# tracer: nop")
+	_, _ = fmt.Fprintln(bw, "#")
+	_, _ = fmt.Fprintln(bw, "#           TASK-PID     CPU#  ||||    TIMESTAMP  FUNCTION")
+	_, _ = fmt.Fprintln(bw, "#              | |         |   ||||       |         |")
+	for _, ev := range result.Events {
+		flush(ev.Time)
+		switch ev.Kind {
+		case EventArrive:
+			line(ev.CPU, ev.Time, "sched_wakeup_new: comm=%s pid=%d prio=120 target_cpu=%03d", traceComm(ev.PID), ev.PID, ev.CPU)
+		case EventWake:
+			line(ev.CPU, ev.Time, "sched_wakeup: comm=%s pid=%d prio=120 target_cpu=%03d", traceComm(ev.PID), ev.PID, ev.CPU)
+		case EventDispatch:
+			out := pending[ev.CPU]
+			delete(pending, ev.CPU)
+			switch {
+			case out == nil:
+				switchTo(ev.CPU, ev.Time, "R", ev.PID)
+			case out.pid != ev.PID:
+				switchTo(ev.CPU, ev.Time, out.state, ev.PID)
+			}
+		default:
+			// Lock and priority bookkeeping is not a switch.
+			if state, ok := traceState[ev.Kind]; ok {
+				pending[ev.CPU] = &switchOut{pid: ev.PID, state: state, time: ev.Time}
+			}
+		}
+	}
+	flush(1<<63 - 1)
+
+	return bw.Flush()
+}
+
+func traceComm(pid int64) string {
+	if pid == 0 {
+		return "<idle>"
+	}
+	return fmt.Sprintf("P%d", pid)
+}
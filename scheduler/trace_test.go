@@ -0,0 +1,83 @@
+package scheduler
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func Test_writeSwitchTrace(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		processes []Process
+		policy    Policy
+		want      []string
+	}{
+		{
+			name: "switch on completion",
+			processes: []Process{
+				{ProcessID: 1, BurstDuration: 2},
+				{ProcessID: 2, ArrivalTime: 1, BurstDuration: 1},
+			},
+			policy: fcfsPolicy{},
+			want: []string{
+				"<idle>-0     [000] d..3      0.000000: sched_wakeup_new: comm=P1 pid=1 prio=120 target_cpu=000",
+				"<idle>-0     [000] d..3      0.000000: sched_switch: prev_comm=<idle> prev_pid=0 prev_prio=120 prev_state=R ==> next_comm=P1 next_pid=1 next_prio=120",
+				"P1-1     [000] d..3      0.001000: sched_wakeup_new: comm=P2 pid=2 prio=120 target_cpu=000",
+				"P1-1     [000] d..3      0.002000: sched_switch: prev_comm=P1 prev_pid=1 prev_prio=120 prev_state=X ==> next_comm=P2 next_pid=2 next_prio=120",
+				"P2-2     [000] d..3      0.003000: sched_switch: prev_comm=P2 prev_pid=2 prev_prio=120 prev_state=X ==> next_comm=<idle> next_pid=0 next_prio=120",
+			},
+		},
+		{
+			name: "blocked then woken",
+			processes: []Process{
+				{ProcessID: 1, BurstDuration: 2, Bursts: []int64{1, 2, 1}},
+			},
+			policy: fcfsPolicy{},
+			want: []string{
+				"<idle>-0     [000] d..3      0.000000: sched_wakeup_new: comm=P1 pid=1 prio=120 target_cpu=000",
+				"<idle>-0     [000] d..3      0.000000: sched_switch: prev_comm=<idle> prev_pid=0 prev_prio=120 prev_state=R ==> next_comm=P1 next_pid=1 next_prio=120",
+				"P1-1     [000] d..3      0.001000: sched_switch: prev_comm=P1 prev_pid=1 prev_prio=120 prev_state=S ==> next_comm=<idle> next_pid=0 next_prio=120",
+				"<idle>-0     [000] d..3      0.003000: sched_wakeup: comm=P1 pid=1 prio=120 target_cpu=000",
+				"<idle>-0     [000] d..3      0.003000: sched_switch: prev_comm=<idle> prev_pid=0 prev_prio=120 prev_state=R ==> next_comm=P1 next_pid=1 next_prio=120",
+				"P1-1     [000] d..3      0.004000: sched_switch: prev_comm=P1 prev_pid=1 prev_prio=120 prev_state=X ==> next_comm=<idle> next_pid=0 next_prio=120",
+			},
+		},
+		{
+			name: "quantum renewed without a switch",
+			processes: []Process{
+				{ProcessID: 1, BurstDuration: 4},
+			},
+			policy: rrPolicy{quantum: 2},
+			want: []string{
+				"<idle>-0     [000] d..3      0.000000: sched_wakeup_new: comm=P1 pid=1 prio=120 target_cpu=000",
+				"<idle>-0     [000] d..3      0.000000: sched_switch: prev_comm=<idle> prev_pid=0 prev_prio=120 prev_state=R ==> next_comm=P1 next_pid=1 next_prio=120",
+				"P1-1     [000] d..3      0.004000: sched_switch: prev_comm=P1 prev_pid=1 prev_prio=120 prev_state=X ==> next_comm=<idle> next_pid=0 next_prio=120",
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var w bytes.Buffer
+			if err := writeSwitchTrace(&w, Simulate(tt.processes, tt.policy, EngineOptions{})); err != nil {
+				t.Fatal(err)
+			}
+			lines := strings.Split(strings.TrimRight(w.String(), "\n"), "\n")
+			if lines[0] != "# tracer: nop" {
+				t.Errorf("missing ftrace header, got %q", lines[0])
+			}
+			var got []string
+			for _, l := range lines {
+				if !strings.HasPrefix(l, "#") {
+					got = append(got, strings.TrimSpace(l))
+				}
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("writeSwitchTrace() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
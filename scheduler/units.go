@@ -0,0 +1,49 @@
+package scheduler
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultTickUnit is the real time a tick stands for unless -tick-unit
+// says otherwise.
+const defaultTickUnit = time.Millisecond
+
+// roundTicks is how a time with a unit that is not a whole number of ticks
+// becomes one.
+type roundTicks int
+
+const (
+	// roundDown puts an instant, such as an arrival, in the tick it falls
+	// within.
+	roundDown roundTicks = iota
+	// roundUp gives a duration, such as a burst, every tick it uses part
+	// of, as the import subcommand does.
+	roundUp
+)
+
+// parseTicks reads a process file time: a plain number of ticks, or a time
+// with a unit, such as 5ms, 2s or 100us, in ticks of tick.
+func parseTicks(s string, tick time.Duration, round roundTicks) (int64, error) {
+	s = strings.TrimSpace(s)
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err == nil {
+		return n, nil
+	}
+	if !strings.ContainsAny(s, "nuµmsh") {
+		return 0, errors.Unwrap(err)
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, errors.New("not a number of ticks or a time such as 5ms")
+	}
+	n = int64(d / tick)
+	if round == roundUp && d%tick > 0 {
+		n++
+	} else if round == roundDown && d%tick < 0 {
+		n--
+	}
+	return n, nil
+}
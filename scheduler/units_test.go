@@ -0,0 +1,60 @@
+package scheduler
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_parseTicks(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		in    string
+		tick  time.Duration
+		round roundTicks
+		want  int64
+		err   bool
+	}{
+		{in: "7", tick: time.Millisecond, want: 7},
+		{in: " 5ms", tick: time.Millisecond, want: 5},
+		{in: "2s", tick: time.Millisecond, want: 2000},
+		{in: "100us", tick: time.Millisecond, round: roundUp, want: 1},
+		{in: "100µs", tick: time.Millisecond, round: roundDown, want: 0},
+		{in: "1.5ms", tick: time.Millisecond, round: roundUp, want: 2},
+		{in: "1.5ms", tick: time.Millisecond, round: roundDown, want: 1},
+		{in: "3s", tick: 10 * time.Millisecond, want: 300},
+		{in: "-1.5ms", tick: time.Millisecond, round: roundDown, want: -2},
+		{in: "5 ms", tick: time.Millisecond, err: true},
+		{in: "5days", tick: time.Millisecond, err: true},
+		{in: "x", tick: time.Millisecond, err: true},
+	}
+	for _, tt := range tests {
+		got, err := parseTicks(tt.in, tt.tick, tt.round)
+		if (err != nil) != tt.err || got != tt.want {
+			t.Errorf("parseTicks(%q, %v) = %d, %v, want %d (error %t)", tt.in, tt.tick, got, err, tt.want, tt.err)
+		}
+	}
+}
+
+func TestLoadProcessFile_units(t *testing.T) {
+	t.Parallel()
+	in := "1,5ms,0,2\n2,1.2ms,2500us,1,batch,400us 1ms 400us,,,0,,0,,10ms,20ms\n"
+	got, err := loadProcessFile(strings.NewReader(in), ProcessFileOptions{Tick: 500 * time.Microsecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Process{
+		{ProcessID: 1, BurstDuration: 10, Priority: 2},
+		{ProcessID: 2, BurstDuration: 3, ArrivalTime: 5, Priority: 1, Bursts: []int64{1, 2, 1}, Deadline: 20, Period: 40},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("loadProcessFile() = %+v, want %+v", got, want)
+	}
+	_, err = loadProcesses(strings.NewReader("1,5 ms,0\n"))
+	var bad *ProcessFileError
+	if !errors.As(err, &bad) || len(bad.Errors) != 1 || bad.Errors[0].Column != "burst" {
+		t.Errorf("loadProcesses() error = %v, want a bad burst", err)
+	}
+}
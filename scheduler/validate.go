@@ -0,0 +1,211 @@
+package scheduler
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+type (
+	// Problem is something wrong with one process of a workload.
+	Problem struct {
+		// Row is the process's 1-based position in the workload.
+		Row     int
+		PID     int64
+		Message string
+		// Fix is what -lenient does about it.
+		Fix string
+	}
+	// ValidationError lists every problem found in a workload.
+	ValidationError struct {
+		Problems []Problem
+	}
+)
+
+func (p Problem) String() string {
+	return fmt.Sprintf("row %d (PID %d): %s", p.Row, p.PID, p.Message)
+}
+
+func (e *ValidationError) Error() string {
+	var b strings.Builder
+	_, _ = fmt.Fprintf(&b, "%v: %d problems", ErrInvalidProcesses, len(e.Problems))
+	for _, p := range e.Problems {
+		_, _ = fmt.Fprintf(&b, "\n\t%v", p)
+	}
+	return b.String()
+}
+
+func (e *ValidationError) Is(target error) bool { return target == ErrInvalidProcesses }
+
+// validateProcesses finds the processes no scheduler can make sense of.
+func validateProcesses(processes []Process) []Problem {
+	var (
+		problems []Problem
+		seen     = make(map[int64]int, len(processes))
+	)
+	for i := range processes {
+		p := &processes[i]
+		add := func(message, fix string) {
+			problems = append(problems, Problem{Row: i + 1, PID: p.ProcessID, Message: message, Fix: fix})
+		}
+		switch {
+		case p.BurstDuration < 0:
+			add(fmt.Sprintf("negative burst %d", p.BurstDuration), "dropped")
+		case p.BurstDuration == 0:
+			add("zero burst", "dropped")
+		}
+		for _, b := range p.Bursts {
+			if b <= 0 {
+				add(fmt.Sprintf("burst list has non-positive entry %d", b), "burst list replaced by the total burst")
+				break
+			}
+		}
+		if problem := lockProblem(*p); problem != "" {
+			add(problem, "locks dropped")
+		}
+		if problem := semaphoreProblem(*p); problem != "" {
+			add(problem, "semaphore operations dropped")
+		}
+		if p.Nice < minNice || p.Nice > maxNice {
+			add(fmt.Sprintf("nice %d outside %d to %d", p.Nice, minNice, maxNice), "nice clamped")
+		}
+		if p.Threads < 0 {
+			add(fmt.Sprintf("negative thread count %d", p.Threads), "runs as one thread")
+		}
+		for _, b := range p.ThreadBursts {
+			if b <= 0 {
+				add(fmt.Sprintf("thread burst list has non-positive entry %d", b), "thread bursts dropped")
+				break
+			}
+		}
+		if len(p.ThreadBursts) > 0 && (len(p.Bursts) > 0 || len(p.Locks) > 0) {
+			add("thread bursts with a burst list or locks", "thread bursts dropped")
+		}
+		for _, c := range p.Affinity {
+			if c < 0 {
+				add(fmt.Sprintf("negative CPU %d in affinity", c), "affinity dropped")
+				break
+			}
+		}
+		if p.Deadline < 0 {
+			add(fmt.Sprintf("negative deadline %d", p.Deadline), "deadline dropped")
+		}
+		if p.Period < 0 {
+			add(fmt.Sprintf("negative period %d", p.Period), "runs once")
+		}
+		if p.ArrivalTime < 0 {
+			add(fmt.Sprintf("negative arrival %d", p.ArrivalTime), "arrives at 0")
+		}
+		if problem := suspensionProblem(*p); problem != "" {
+			add(problem, "suspensions dropped")
+		}
+		if row, dup := seen[p.ProcessID]; dup {
+			add(fmt.Sprintf("duplicate PID, first used on row %d", row), "given an unused PID")
+		} else {
+			seen[p.ProcessID] = i + 1
+		}
+	}
+	deps, _ := dependencyProblems(processes)
+	problems = append(problems, deps...)
+	sort.SliceStable(problems, func(i, j int) bool { return problems[i].Row < problems[j].Row })
+	return problems
+}
+
+// fixProcesses returns a copy of processes with every problem
+// validateProcesses reports fixed as its Problem.Fix describes.
+func fixProcesses(processes []Process) ([]Process, []Problem) {
+	problems := validateProcesses(processes)
+	var maxPID int64
+	for i := range processes {
+		if processes[i].ProcessID > maxPID {
+			maxPID = processes[i].ProcessID
+		}
+	}
+	fixed := make([]Process, 0, len(processes))
+	seen := make(map[int64]bool, len(processes))
+	for _, p := range processes {
+		if p.BurstDuration <= 0 {
+			continue
+		}
+		for _, b := range p.Bursts {
+			if b <= 0 {
+				p.Bursts = nil
+				break
+			}
+		}
+		if p.ArrivalTime < 0 {
+			p.ArrivalTime = 0
+		}
+		if p.Nice < minNice {
+			p.Nice = minNice
+		} else if p.Nice > maxNice {
+			p.Nice = maxNice
+		}
+		if p.Threads < 0 {
+			p.Threads = 1
+		}
+		for _, b := range p.ThreadBursts {
+			if b <= 0 || len(p.Bursts) > 0 || len(p.Locks) > 0 {
+				p.ThreadBursts = nil
+				break
+			}
+		}
+		if p.Deadline < 0 {
+			p.Deadline = 0
+		}
+		if p.Period < 0 {
+			p.Period = 0
+		}
+		for _, c := range p.Affinity {
+			if c < 0 {
+				p.Affinity = nil
+				break
+			}
+		}
+		if lockProblem(p) != "" {
+			p.Locks = nil
+		}
+		if semaphoreProblem(p) != "" {
+			p.Semaphores = nil
+		}
+		if suspensionProblem(p) != "" {
+			p.Suspend = nil
+		}
+		if seen[p.ProcessID] {
+			maxPID++
+			p.ProcessID = maxPID
+		}
+		seen[p.ProcessID] = true
+		fixed = append(fixed, p)
+	}
+	// Dependencies are checked last, against the processes that were kept.
+	_, keep := dependencyProblems(fixed)
+	for i := range fixed {
+		fixed[i].DependsOn = keep[i]
+	}
+	return fixed, problems
+}
+
+// checkProcesses rejects a workload with problems, or with lenient fixes
+// them and reports each fix to warn. A workload whose times could overflow
+// is rejected either way.
+func checkProcesses(processes []Process, lenient bool, warn io.Writer) ([]Process, error) {
+	if !lenient {
+		if problems := validateProcesses(processes); len(problems) > 0 {
+			return nil, &ValidationError{Problems: problems}
+		}
+		if err := checkTimeRange(processes); err != nil {
+			return nil, err
+		}
+		return processes, nil
+	}
+	fixed, problems := fixProcesses(processes)
+	for _, p := range problems {
+		_, _ = fmt.Fprintf(warn, "warning: %v: %s\n", p, p.Fix)
+	}
+	if err := checkTimeRange(fixed); err != nil {
+		return nil, err
+	}
+	return fixed, nil
+}
@@ -0,0 +1,77 @@
+package scheduler
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_checkProcesses(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 5},
+		{ProcessID: 2, BurstDuration: -3, ArrivalTime: 1},
+		{ProcessID: 1, BurstDuration: 2, ArrivalTime: -4},
+		{ProcessID: 4, BurstDuration: 0},
+		{ProcessID: 5, BurstDuration: 3, Bursts: []int64{1, 0, 2}},
+	}
+	tests := []struct {
+		name      string
+		lenient   bool
+		want      []Process
+		wantErr   error
+		wantLines []string
+	}{
+		{
+			name:    "strict reports every problem",
+			wantErr: ErrInvalidProcesses,
+			wantLines: []string{
+				"row 2 (PID 2): negative burst -3",
+				"row 3 (PID 1): negative arrival -4",
+				"row 3 (PID 1): duplicate PID, first used on row 1",
+				"row 4 (PID 4): zero burst",
+				"row 5 (PID 5): burst list has non-positive entry 0",
+			},
+		},
+		{
+			name:    "lenient fixes them",
+			lenient: true,
+			want: []Process{
+				{ProcessID: 1, BurstDuration: 5},
+				{ProcessID: 6, BurstDuration: 2},
+				{ProcessID: 5, BurstDuration: 3},
+			},
+			wantLines: []string{
+				"warning: row 2 (PID 2): negative burst -3: dropped",
+				"warning: row 3 (PID 1): negative arrival -4: arrives at 0",
+				"warning: row 3 (PID 1): duplicate PID, first used on row 1: given an unused PID",
+				"warning: row 4 (PID 4): zero burst: dropped",
+				"warning: row 5 (PID 5): burst list has non-positive entry 0: burst list replaced by the total burst",
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var warn strings.Builder
+			got, err := checkProcesses(processes, tt.lenient, &warn)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("checkProcesses() = %v, want %v", got, tt.want)
+			}
+			out := warn.String()
+			if err != nil {
+				out = err.Error()
+			}
+			for _, line := range tt.wantLines {
+				if !strings.Contains(out, line) {
+					t.Errorf("output missing %q:\n%s", line, out)
+				}
+			}
+		})
+	}
+}
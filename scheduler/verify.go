@@ -0,0 +1,231 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strings"
+)
+
+type (
+	// ExpectedResults is a verify file: what each algorithm is expected to
+	// make of a workload, and the options it runs with.
+	ExpectedResults struct {
+		Options AlgorithmOptions `json:"options"`
+		Runs    []ExpectedRun    `json:"runs"`
+	}
+	// ExpectedRun is one algorithm's expected schedule and averages.
+	ExpectedRun struct {
+		Algorithm string          `json:"algorithm"`
+		Schedule  []ProcessResult `json:"schedule"`
+		Gantt     []TimeSlice     `json:"gantt"`
+		Metrics
+	}
+	// VerifyOutcome is how one algorithm's run compared with what was
+	// expected; it passed if Diffs is empty.
+	VerifyOutcome struct {
+		Algorithm string
+		Diffs     []string
+	}
+)
+
+var ErrMismatch = errors.New("results differ from expected")
+
+// metricTolerance absorbs rounding in stored averages.
+const metricTolerance = 1e-9
+
+func expectRun(algorithm string, result Result) ExpectedRun {
+	return ExpectedRun{Algorithm: algorithm, Schedule: result.Schedule, Gantt: result.Gantt, Metrics: result.Metrics}
+}
+
+// ExpectResults runs every algorithm on processes to make a verify file.
+func ExpectResults(processes []Process, algorithms []Algorithm, opts AlgorithmOptions) ExpectedResults {
+	expected := ExpectedResults{Options: opts}
+	for _, a := range algorithms {
+		expected.Runs = append(expected.Runs, expectRun(a.Name, Simulate(processes, a.New(processes, opts), EngineOptions{})))
+	}
+	return expected
+}
+
+// Verify runs each algorithm in expected on processes and lists how its
+// results differ.
+func Verify(processes []Process, expected ExpectedResults) ([]VerifyOutcome, error) {
+	outcomes := make([]VerifyOutcome, 0, len(expected.Runs))
+	for _, want := range expected.Runs {
+		a, ok := LookupAlgorithm(want.Algorithm)
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown algorithm %q", ErrInvalidArgs, want.Algorithm)
+		}
+		got := expectRun(a.Name, Simulate(processes, a.New(processes, expected.Options), EngineOptions{}))
+		outcomes = append(outcomes, VerifyOutcome{Algorithm: a.Name, Diffs: diffRun(want, got)})
+	}
+	return outcomes, nil
+}
+
+// diffRun describes each way got differs from want: schedule rows by PID,
+// the first Gantt slice that differs, and the averages.
+func diffRun(want, got ExpectedRun) []string {
+	var diffs []string
+	rows := make(map[int64]ProcessResult, len(got.Schedule))
+	for _, r := range got.Schedule {
+		rows[r.ProcessID] = r
+	}
+	for _, w := range want.Schedule {
+		g, ok := rows[w.ProcessID]
+		if !ok {
+			diffs = append(diffs, fmt.Sprintf("PID %d did not finish", w.ProcessID))
+			continue
+		}
+		delete(rows, w.ProcessID)
+		var fields []string
+		for _, f := range []struct {
+			name      string
+			got, want int64
+		}{
+			{"priority", g.Priority, w.Priority},
+			{"burst", g.Burst, w.Burst},
+			{"arrival", g.Arrival, w.Arrival},
+			{"wait", g.Wait, w.Wait},
+			{"turnaround", g.Turnaround, w.Turnaround},
+			{"exit", g.Exit, w.Exit},
+		} {
+			if f.got != f.want {
+				fields = append(fields, fmt.Sprintf("%s %d, want %d", f.name, f.got, f.want))
+			}
+		}
+		if len(fields) > 0 {
+			diffs = append(diffs, fmt.Sprintf("PID %d: %s", w.ProcessID, strings.Join(fields, "; ")))
+		}
+	}
+	for _, g := range got.Schedule {
+		if _, ok := rows[g.ProcessID]; ok {
+			diffs = append(diffs, fmt.Sprintf("PID %d finished but was not expected to", g.ProcessID))
+		}
+	}
+
+	for i := 0; i < len(want.Gantt) || i < len(got.Gantt); i++ {
+		switch {
+		case i >= len(got.Gantt):
+			diffs = append(diffs, fmt.Sprintf("Gantt has %d slices, want %d", len(got.Gantt), len(want.Gantt)))
+		case i >= len(want.Gantt):
+			diffs = append(diffs, fmt.Sprintf("Gantt has %d slices, want %d; slice %d is %s", len(got.Gantt), len(want.Gantt), i, formatSlice(got.Gantt[i])))
+		case got.Gantt[i] != want.Gantt[i]:
+			diffs = append(diffs, fmt.Sprintf("Gantt slice %d is %s, want %s", i, formatSlice(got.Gantt[i]), formatSlice(want.Gantt[i])))
+		default:
+			continue
+		}
+		break
+	}
+
+	for _, m := range []struct {
+		name      string
+		got, want float64
+	}{
+		{"average wait", got.AverageWait, want.AverageWait},
+		{"average turnaround", got.AverageTurnaround, want.AverageTurnaround},
+		{"throughput", got.Throughput, want.Throughput},
+	} {
+		if math.Abs(m.got-m.want) > metricTolerance {
+			diffs = append(diffs, fmt.Sprintf("%s %.4f, want %.4f", m.name, m.got, m.want))
+		}
+	}
+	return diffs
+}
+
+func formatSlice(s TimeSlice) string {
+	if s.CPU != 0 {
+		return fmt.Sprintf("P%d %d-%d on CPU %d", s.PID, s.Start, s.Stop, s.CPU)
+	}
+	return fmt.Sprintf("P%d %d-%d", s.PID, s.Start, s.Stop)
+}
+
+func outputVerify(w io.Writer, outcomes []VerifyOutcome) {
+	for _, o := range outcomes {
+		if len(o.Diffs) == 0 {
+			_, _ = fmt.Fprintf(w, "%s: ok\n", o.Algorithm)
+			continue
+		}
+		noun := "differences"
+		if len(o.Diffs) == 1 {
+			noun = "difference"
+		}
+		_, _ = fmt.Fprintf(w, "%s: %d %s\n", o.Algorithm, len(o.Diffs), noun)
+		for _, d := range o.Diffs {
+			_, _ = fmt.Fprintf(w, "  %s\n", d)
+		}
+	}
+}
+
+// runVerify is the verify subcommand: it checks a workload's results against
+// an expected-results file, or with -write makes one.
+func runVerify(w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("verify", flag.ContinueOnError)
+	write := fs.Bool("write", false, "write the expected results of the -algo algorithms instead of checking them")
+	algo := fs.String("algo", "", "with -write, comma-separated algorithms to record (default all the defaults)")
+	algoFlags := addAlgorithmFlags(fs)
+	seed := addSeedFlag(fs, "with -write, random seed for stochastic algorithms")
+	if err := parseFlags(fs, "verify", args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		return fmt.Errorf("%w: usage: verify [-write] expected.json workload.csv", ErrInvalidArgs)
+	}
+	f, closeFile, err := openProcessingFile("verify", fs.Arg(1))
+	if err != nil {
+		return err
+	}
+	defer closeFile()
+	processes, err := loadProcesses(f)
+	if err != nil {
+		return err
+	}
+	if processes, err = checkProcesses(processes, false, nil); err != nil {
+		return err
+	}
+
+	if *write {
+		runs, err := selectRuns(*algo)
+		if err != nil {
+			return err
+		}
+		opts, err := algoFlags.options()
+		if err != nil {
+			return err
+		}
+		opts.Seed = pickSeed(os.Stderr, *seed)
+		expected := ExpectResults(processes, runs, opts)
+		if err := writeFile(fs.Arg(0), func(w io.Writer) error {
+			enc := json.NewEncoder(w)
+			enc.SetIndent("", "  ")
+			return enc.Encode(expected)
+		}); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(w, "Wrote the expected results of %d algorithms to %s\n", len(runs), fs.Arg(0))
+		return nil
+	}
+
+	var expected ExpectedResults
+	if err := readJSONFile(fs.Arg(0), &expected); err != nil {
+		return err
+	}
+	outcomes, err := Verify(processes, expected)
+	if err != nil {
+		return err
+	}
+	outputVerify(w, outcomes)
+	failed := 0
+	for _, o := range outcomes {
+		if len(o.Diffs) > 0 {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%w: %d of %d algorithms", ErrMismatch, failed, len(outcomes))
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package scheduler
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_diffRun(t *testing.T) {
+	t.Parallel()
+	want := ExpectedRun{
+		Algorithm: "fcfs",
+		Schedule: []ProcessResult{
+			{ProcessID: 1, Burst: 3, Turnaround: 3, Exit: 3},
+			{ProcessID: 2, Arrival: 1, Burst: 2, Wait: 2, Turnaround: 4, Exit: 5},
+		},
+		Gantt:   []TimeSlice{{PID: 1, Start: 0, Stop: 3}, {PID: 2, Start: 3, Stop: 5}},
+		Metrics: Metrics{AverageWait: 1, AverageTurnaround: 3.5, Throughput: 0.4},
+	}
+	tests := []struct {
+		name   string
+		change func(r *ExpectedRun)
+		want   []string
+	}{
+		{name: "same", change: func(*ExpectedRun) {}},
+		{
+			name: "row",
+			change: func(r *ExpectedRun) {
+				r.Schedule = []ProcessResult{r.Schedule[0], {ProcessID: 2, Arrival: 1, Burst: 2, Wait: 1, Turnaround: 3, Exit: 4}}
+			},
+			want: []string{"PID 2: wait 1, want 2; turnaround 3, want 4; exit 4, want 5"},
+		},
+		{
+			name:   "missing and extra rows",
+			change: func(r *ExpectedRun) { r.Schedule = []ProcessResult{r.Schedule[0], {ProcessID: 9}} },
+			want:   []string{"PID 2 did not finish", "PID 9 finished but was not expected to"},
+		},
+		{
+			name:   "first slice that differs",
+			change: func(r *ExpectedRun) { r.Gantt = []TimeSlice{{PID: 2, Start: 0, Stop: 2}, {PID: 1, Start: 2, Stop: 5}} },
+			want:   []string{"Gantt slice 0 is P2 0-2, want P1 0-3"},
+		},
+		{
+			name:   "fewer slices",
+			change: func(r *ExpectedRun) { r.Gantt = r.Gantt[:1] },
+			want:   []string{"Gantt has 1 slices, want 2"},
+		},
+		{
+			name:   "metric",
+			change: func(r *ExpectedRun) { r.Throughput = 0.5 },
+			want:   []string{"throughput 0.5000, want 0.4000"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := want
+			got.Schedule = append([]ProcessResult(nil), want.Schedule...)
+			tt.change(&got)
+			if diffs := diffRun(want, got); !reflect.DeepEqual(diffs, tt.want) {
+				t.Errorf("diffRun() = %q, want %q", diffs, tt.want)
+			}
+		})
+	}
+}
+
+func Test_runVerify(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	expected := filepath.Join(dir, "expected.json")
+	var w bytes.Buffer
+	if err := runVerify(&w, []string{"-write", "-algo", "fcfs,rr,lottery", "-seed", "3", expected, "../example_processes.csv"}); err != nil {
+		t.Fatalf("runVerify(-write) error = %v", err)
+	}
+	w.Reset()
+	if err := runVerify(&w, []string{expected, "../example_processes.csv"}); err != nil {
+		t.Fatalf("runVerify() error = %v\n%s", err, w.String())
+	}
+	if want := "fcfs: ok\nrr: ok\nlottery: ok\n"; w.String() != want {
+		t.Errorf("runVerify() output = %q, want %q", w.String(), want)
+	}
+
+	b, err := os.ReadFile(expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tampered := filepath.Join(dir, "tampered.json")
+	if err := os.WriteFile(tampered, bytes.Replace(b, []byte(`"exit": 20`), []byte(`"exit": 21`), 1), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	w.Reset()
+	if err := runVerify(&w, []string{tampered, "../example_processes.csv"}); !errors.Is(err, ErrMismatch) {
+		t.Errorf("runVerify() error = %v, want %v", err, ErrMismatch)
+	}
+	if !strings.Contains(w.String(), "fcfs: 1 difference\n  PID 3: exit 20, want 21") {
+		t.Errorf("runVerify() output = %q, want fcfs's exit difference", w.String())
+	}
+}
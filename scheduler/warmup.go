@@ -0,0 +1,78 @@
+package scheduler
+
+import (
+	"fmt"
+	"io"
+	"sort"
+)
+
+type (
+	// Warmup leaves the start of a run out of its averages, percentiles and
+	// throughput, which an empty queue at time zero would otherwise flatter:
+	// the processes arriving before Time, or the first Jobs to complete.
+	Warmup struct {
+		Time int64 `json:"time,omitempty"`
+		Jobs int   `json:"jobs,omitempty"`
+	}
+	// WarmupStats is what a warmup left out: Excluded processes, with
+	// throughput measured from Start.
+	WarmupStats struct {
+		Warmup
+		Start    int64
+		Excluded int
+	}
+)
+
+func (w Warmup) set() bool { return w.Time > 0 || w.Jobs > 0 }
+
+// steadyState drops the rows in w's warmup, returning the rest and when the
+// measurement starts: Time, or the exit of the last of the first Jobs.
+func steadyState(rows []ProcessResult, w Warmup) ([]ProcessResult, int64) {
+	if !w.set() {
+		return rows, 0
+	}
+	measured := make([]ProcessResult, 0, len(rows))
+	for _, r := range rows {
+		if r.Arrival >= w.Time {
+			measured = append(measured, r)
+		}
+	}
+	start := w.Time
+	if w.Jobs > 0 {
+		byExit := append([]ProcessResult(nil), rows...)
+		sort.SliceStable(byExit, func(i, j int) bool { return byExit[i].Exit < byExit[j].Exit })
+		if w.Jobs > len(byExit) {
+			return nil, start
+		}
+		warm := map[int64]bool{}
+		for _, r := range byExit[:w.Jobs] {
+			warm[r.ProcessID] = true
+		}
+		start = maxInt64(start, byExit[w.Jobs-1].Exit)
+		kept := measured[:0]
+		for _, r := range measured {
+			if !warm[r.ProcessID] {
+				kept = append(kept, r)
+			}
+		}
+		measured = kept
+	}
+	return measured, start
+}
+
+// outputWarmup says what the averages below the schedule leave out.
+func outputWarmup(w io.Writer, s WarmupStats) {
+	if !s.set() {
+		return
+	}
+	var what string
+	switch {
+	case s.Time > 0 && s.Jobs > 0:
+		what = fmt.Sprintf("arriving before t=%d or among the first %d to complete", s.Time, s.Jobs)
+	case s.Time > 0:
+		what = fmt.Sprintf("arriving before t=%d", s.Time)
+	default:
+		what = fmt.Sprintf("the first %d to complete", s.Jobs)
+	}
+	_, _ = fmt.Fprintf(w, "Warmup: averages and percentiles leave out %d processes %s; throughput is from t=%d\n", s.Excluded, what, s.Start)
+}
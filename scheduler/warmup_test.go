@@ -0,0 +1,43 @@
+package scheduler
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSimulate_warmup(t *testing.T) {
+	t.Parallel()
+	// Under FCFS P1 exits at 4, P2 at 6 after waiting 4, and P3 at 7 after
+	// waiting 1.
+	processes := []Process{NewProcess(1, 0, 4, 0), NewProcess(2, 0, 2, 0), NewProcess(3, 5, 1, 0)}
+	tests := []struct {
+		name       string
+		warmup     Warmup
+		wait       float64
+		throughput float64
+		excluded   int
+		p50        int64
+	}{
+		{name: "none", wait: 5.0 / 3, throughput: 3.0 / 7, p50: 1},
+		{name: "time", warmup: Warmup{Time: 5}, wait: 1, throughput: 0.5, excluded: 2, p50: 1},
+		{name: "jobs", warmup: Warmup{Jobs: 1}, wait: 2.5, throughput: 2.0 / 3, excluded: 1, p50: 1},
+		{name: "both", warmup: Warmup{Time: 1, Jobs: 1}, wait: 1, throughput: 1.0 / 3, excluded: 2, p50: 1},
+		{name: "every job", warmup: Warmup{Jobs: 5}, excluded: 3},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := Simulate(processes, fcfsPolicy{}, EngineOptions{Warmup: tt.warmup})
+			if len(got.Schedule) != 3 {
+				t.Errorf("schedule has %d rows, want all 3", len(got.Schedule))
+			}
+			if math.Abs(got.AverageWait-tt.wait) > 1e-9 || math.Abs(got.Throughput-tt.throughput) > 1e-9 {
+				t.Errorf("average wait %v, throughput %v, want %v and %v", got.AverageWait, got.Throughput, tt.wait, tt.throughput)
+			}
+			if got.Warmup.Excluded != tt.excluded || got.Percentiles.Wait.P50 != tt.p50 {
+				t.Errorf("excluded %d, p50 wait %d, want %d and %d", got.Warmup.Excluded, got.Percentiles.Wait.P50, tt.excluded, tt.p50)
+			}
+		})
+	}
+}
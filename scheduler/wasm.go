@@ -0,0 +1,60 @@
+//go:build js && wasm
+
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"syscall/js"
+)
+
+func init() {
+	platformMain = serveJS
+}
+
+// serveJS exports simulate(workload, options) to JavaScript and keeps the
+// program alive to answer calls. workload is a JSON array of processes;
+// options is an object or JSON string with the other fields of
+// SimulateRequest. The result, or {"error": ...}, is
+// returned as a JSON string.
+func serveJS() {
+	js.Global().Set("simulate", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		out, err := simulateJS(args)
+		if err != nil {
+			out, _ = json.Marshal(struct {
+				Error string `json:"error"`
+			}{err.Error()})
+		}
+		return string(out)
+	}))
+	select {}
+}
+
+func simulateJS(args []js.Value) ([]byte, error) {
+	if len(args) == 0 || args[0].Type() != js.TypeString {
+		return nil, fmt.Errorf("%w: usage: simulate(workload, options)", ErrInvalidArgs)
+	}
+	var req SimulateRequest
+	if len(args) > 1 && args[1].Truthy() {
+		options := args[1]
+		if options.Type() != js.TypeString {
+			options = js.Global().Get("JSON").Call("stringify", options)
+		}
+		if err := json.Unmarshal([]byte(options.String()), &req); err != nil {
+			return nil, fmt.Errorf("%w: parsing options", err)
+		}
+	}
+	if req.Algorithm == "" {
+		req.Algorithm = "fcfs"
+	}
+	if err := json.Unmarshal([]byte(args[0].String()), &req.Processes); err != nil {
+		return nil, fmt.Errorf("%w: parsing workload", err)
+	}
+
+	result, err := simulateRequest(context.Background(), req, nil)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(result)
+}